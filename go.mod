@@ -6,15 +6,15 @@ go 1.16
 
 require (
 	github.com/MicahParks/keyfunc v1.0.0
+	github.com/beevik/etree v1.1.0
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/derekstavis/go-qs v0.0.0-20180720192143-9eef69e6c4e7
 	github.com/golang-jwt/jwt/v4 v4.1.0
 	github.com/joho/godotenv v1.3.0
-	github.com/kr/pretty v0.1.0 // indirect
 	github.com/nyaruka/phonenumbers v1.0.73
+	github.com/russellhaering/goxmldsig v1.4.0
 	github.com/stretchr/testify v1.7.0
 	golang.org/x/net v0.0.0-20210614182718-04defd469f4e
-	gopkg.in/check.v1 v1.0.0-20180628173108-788fd7840127 // indirect
 	gopkg.in/h2non/gock.v1 v1.1.2
 	gopkg.in/yaml.v3 v3.0.0-20210107192922-496545a6307b // indirect
 )
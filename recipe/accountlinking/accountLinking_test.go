@@ -0,0 +1,225 @@
+/*
+ * Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package accountlinking
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/accountlinking/almodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func makeInMemoryAccountLinkingStoreForTest() almodels.AccountLinkingStore {
+	var lock sync.Mutex
+	primaryUserIDs := map[string]string{}
+	recipeUserIDs := map[string][]string{}
+
+	getPrimaryUserID := func(recipeUserID string, userContext supertokens.UserContext) (*string, error) {
+		lock.Lock()
+		defer lock.Unlock()
+		primaryUserID, ok := primaryUserIDs[recipeUserID]
+		if !ok {
+			return nil, nil
+		}
+		return &primaryUserID, nil
+	}
+
+	getRecipeUserIDs := func(primaryUserID string, userContext supertokens.UserContext) ([]string, error) {
+		lock.Lock()
+		defer lock.Unlock()
+		return append([]string{}, recipeUserIDs[primaryUserID]...), nil
+	}
+
+	link := func(primaryUserID string, recipeUserID string, userContext supertokens.UserContext) error {
+		lock.Lock()
+		defer lock.Unlock()
+		primaryUserIDs[recipeUserID] = primaryUserID
+		recipeUserIDs[primaryUserID] = append(recipeUserIDs[primaryUserID], recipeUserID)
+		return nil
+	}
+
+	unlink := func(recipeUserID string, userContext supertokens.UserContext) error {
+		lock.Lock()
+		defer lock.Unlock()
+		primaryUserID, ok := primaryUserIDs[recipeUserID]
+		if !ok {
+			return nil
+		}
+		delete(primaryUserIDs, recipeUserID)
+		linkedUserIDs := []string{}
+		for _, linkedUserID := range recipeUserIDs[primaryUserID] {
+			if linkedUserID != recipeUserID {
+				linkedUserIDs = append(linkedUserIDs, linkedUserID)
+			}
+		}
+		if len(linkedUserIDs) == 0 {
+			delete(recipeUserIDs, primaryUserID)
+		} else {
+			recipeUserIDs[primaryUserID] = linkedUserIDs
+		}
+		return nil
+	}
+
+	return almodels.AccountLinkingStore{
+		GetPrimaryUserID: getPrimaryUserID,
+		GetRecipeUserIDs: getRecipeUserIDs,
+		Link:             link,
+		Unlink:           unlink,
+	}
+}
+
+func TestAccountLinkingRequiresAStore(t *testing.T) {
+	_, err := MakeRecipe(RECIPE_ID, supertokens.NormalisedAppinfo{}, &almodels.TypeInput{}, func(err error, req *http.Request, res http.ResponseWriter) {})
+	assert.Error(t, err)
+}
+
+func TestLinkingAndUnlinkingAccounts(t *testing.T) {
+	recipeImpl := MakeRecipeImplementation(makeInMemoryAccountLinkingStoreForTest())
+	userContext := &map[string]interface{}{}
+
+	response, err := (*recipeImpl.LinkAccounts)("user-1", "user-2", userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, "user-1", response.OK.PrimaryUserID)
+	assert.False(t, response.OK.AccountsAlreadyLinked)
+
+	// linking to a recipe user links to its primary user instead
+	response, err = (*recipeImpl.LinkAccounts)("user-2", "user-3", userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, "user-1", response.OK.PrimaryUserID)
+
+	response, err = (*recipeImpl.LinkAccounts)("user-1", "user-3", userContext)
+	assert.NoError(t, err)
+	assert.True(t, response.OK.AccountsAlreadyLinked)
+
+	response, err = (*recipeImpl.LinkAccounts)("user-4", "user-3", userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, "user-1", response.AccountLinkedToAnotherUserError.PrimaryUserID)
+
+	response, err = (*recipeImpl.LinkAccounts)("user-4", "user-1", userContext)
+	assert.NoError(t, err)
+	assert.NotNil(t, response.RecipeUserIsPrimaryUserError)
+
+	primaryUserID, err := (*recipeImpl.GetPrimaryUserID)("user-3", userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, "user-1", primaryUserID)
+
+	linkedUserIDs, err := (*recipeImpl.GetLinkedUserIDs)("user-1", userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"user-1", "user-2", "user-3"}, linkedUserIDs)
+
+	unlinkResponse, err := (*recipeImpl.UnlinkAccount)("user-1", userContext)
+	assert.NoError(t, err)
+	assert.NotNil(t, unlinkResponse.PrimaryUserHasLinkedAccountsError)
+
+	unlinkResponse, err = (*recipeImpl.UnlinkAccount)("user-2", userContext)
+	assert.NoError(t, err)
+	assert.True(t, unlinkResponse.OK.WasLinked)
+
+	unlinkResponse, err = (*recipeImpl.UnlinkAccount)("user-2", userContext)
+	assert.NoError(t, err)
+	assert.False(t, unlinkResponse.OK.WasLinked)
+
+	linkedUserIDs, err = (*recipeImpl.GetLinkedUserIDs)("user-1", userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"user-1", "user-3"}, linkedUserIDs)
+}
+
+func TestAccountsAreOnlyLinkedAutomaticallyIfVerified(t *testing.T) {
+	store := makeInMemoryAccountLinkingStoreForTest()
+	recipe, err := MakeRecipe(RECIPE_ID, supertokens.NormalisedAppinfo{}, &almodels.TypeInput{
+		Store: &store,
+		ShouldDoAutomaticAccountLinking: func(newAccount almodels.AccountInfo, primaryUserID string, userContext supertokens.UserContext) (bool, error) {
+			return newAccount.ThirdParty != nil, nil
+		},
+	}, func(err error, req *http.Request, res http.ResponseWriter) {})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	singletonInstance = &recipe
+	defer ResetForTest()
+	userContext := &map[string]interface{}{}
+
+	email := "johndoe@gmail.com"
+	thirdPartyAccount := almodels.AccountInfo{
+		RecipeUserID: "user-2",
+		Email:        &email,
+		ThirdParty: &struct {
+			ID     string
+			UserID string
+		}{ID: "google", UserID: "google-user"},
+		IsVerified: false,
+	}
+
+	primaryUserID, err := GetPrimaryUserIDForSignInUp(thirdPartyAccount, []string{"user-1"}, userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, "user-2", primaryUserID)
+
+	primaryUserID, err = GetPrimaryUserIDForSignInUp(almodels.AccountInfo{
+		RecipeUserID: "user-3",
+		Email:        &email,
+		IsVerified:   true,
+	}, []string{"user-1"}, userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, "user-3", primaryUserID)
+
+	thirdPartyAccount.IsVerified = true
+	primaryUserID, err = GetPrimaryUserIDForSignInUp(thirdPartyAccount, []string{"user-1"}, userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, "user-1", primaryUserID)
+
+	// once linked, the account resolves to its primary user even if it is no longer verified
+	thirdPartyAccount.IsVerified = false
+	primaryUserID, err = GetPrimaryUserIDForSignInUp(thirdPartyAccount, []string{}, userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, "user-1", primaryUserID)
+}
+
+func TestLinksAreRemovedWhenAUserIsDeleted(t *testing.T) {
+	store := makeInMemoryAccountLinkingStoreForTest()
+	recipe, err := MakeRecipe(RECIPE_ID, supertokens.NormalisedAppinfo{}, &almodels.TypeInput{
+		Store: &store,
+	}, func(err error, req *http.Request, res http.ResponseWriter) {})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer supertokens.ResetForTest()
+	userContext := &map[string]interface{}{}
+
+	for _, recipeUserID := range []string{"user-2", "user-3", "user-4"} {
+		_, err = (*recipe.RecipeImpl.LinkAccounts)("user-1", recipeUserID, userContext)
+		assert.NoError(t, err)
+	}
+
+	err = recipe.unlinkDeletedUser("user-4")
+	assert.NoError(t, err)
+	primaryUserID, err := (*recipe.RecipeImpl.GetPrimaryUserID)("user-4", userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, "user-4", primaryUserID)
+
+	// the accounts linked to a deleted primary user become separate users again
+	err = recipe.unlinkDeletedUser("user-1")
+	assert.NoError(t, err)
+	linkedUserIDs, err := (*recipe.RecipeImpl.GetLinkedUserIDs)("user-1", userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"user-1"}, linkedUserIDs)
+	primaryUserID, err = (*recipe.RecipeImpl.GetPrimaryUserID)("user-2", userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, "user-2", primaryUserID)
+}
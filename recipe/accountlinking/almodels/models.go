@@ -0,0 +1,61 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package almodels
+
+import "github.com/supertokens/supertokens-golang/supertokens"
+
+// AccountInfo describes the login method of a user that signs in
+type AccountInfo struct {
+	RecipeUserID string
+	Email        *string
+	PhoneNumber  *string
+	ThirdParty   *struct {
+		ID     string
+		UserID string
+	}
+	// IsVerified is true if the user has proven that they own the email or phone number
+	IsVerified bool
+}
+
+type AccountLinkingStore struct {
+	// GetPrimaryUserID returns nil if the recipe user is not linked to a primary user
+	GetPrimaryUserID func(recipeUserID string, userContext supertokens.UserContext) (*string, error)
+	// GetRecipeUserIDs returns the recipe users linked to the primary user, without the primary user itself
+	GetRecipeUserIDs func(primaryUserID string, userContext supertokens.UserContext) ([]string, error)
+	Link             func(primaryUserID string, recipeUserID string, userContext supertokens.UserContext) error
+	Unlink           func(recipeUserID string, userContext supertokens.UserContext) error
+}
+
+type TypeInput struct {
+	// ShouldDoAutomaticAccountLinking is called when a verified account signs in and there is an older
+	// account with the same verified email. Returning true links the new account to the primary user of
+	// the older one. Accounts are never linked automatically if it is nil.
+	ShouldDoAutomaticAccountLinking func(newAccount AccountInfo, primaryUserID string, userContext supertokens.UserContext) (bool, error)
+	// Store is used to save which users are linked. It is required and must be persistent and shared
+	// by all your API instances.
+	Store    *AccountLinkingStore
+	Override *OverrideStruct
+}
+
+type TypeNormalisedInput struct {
+	ShouldDoAutomaticAccountLinking func(newAccount AccountInfo, primaryUserID string, userContext supertokens.UserContext) (bool, error)
+	Store                           AccountLinkingStore
+	Override                        OverrideStruct
+}
+
+type OverrideStruct struct {
+	Functions func(originalImplementation RecipeInterface) RecipeInterface
+}
@@ -0,0 +1,41 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package almodels
+
+import "github.com/supertokens/supertokens-golang/supertokens"
+
+type RecipeInterface struct {
+	// GetPrimaryUserID returns the recipe user ID itself if it is not linked
+	GetPrimaryUserID *func(recipeUserID string, userContext supertokens.UserContext) (string, error)
+	// GetLinkedUserIDs returns the primary user ID followed by the IDs of the recipe users linked to it
+	GetLinkedUserIDs *func(primaryUserID string, userContext supertokens.UserContext) ([]string, error)
+	LinkAccounts     *func(primaryUserID string, recipeUserID string, userContext supertokens.UserContext) (LinkAccountsResponse, error)
+	UnlinkAccount    *func(recipeUserID string, userContext supertokens.UserContext) (UnlinkAccountResponse, error)
+}
+
+type LinkAccountsResponse struct {
+	OK *struct {
+		PrimaryUserID         string
+		AccountsAlreadyLinked bool
+	}
+	AccountLinkedToAnotherUserError *struct{ PrimaryUserID string }
+	RecipeUserIsPrimaryUserError    *struct{}
+}
+
+type UnlinkAccountResponse struct {
+	OK                                *struct{ WasLinked bool }
+	PrimaryUserHasLinkedAccountsError *struct{}
+}
@@ -0,0 +1,136 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package accountlinking
+
+import (
+	"github.com/supertokens/supertokens-golang/recipe/accountlinking/almodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func Init(config *almodels.TypeInput) supertokens.Recipe {
+	return recipeInit(config)
+}
+
+func GetPrimaryUserIDWithContext(recipeUserID string, userContext supertokens.UserContext) (string, error) {
+	instance, err := getRecipeInstanceOrThrowError()
+	if err != nil {
+		return "", err
+	}
+	return (*instance.RecipeImpl.GetPrimaryUserID)(recipeUserID, userContext)
+}
+
+func GetLinkedUserIDsWithContext(primaryUserID string, userContext supertokens.UserContext) ([]string, error) {
+	instance, err := getRecipeInstanceOrThrowError()
+	if err != nil {
+		return nil, err
+	}
+	return (*instance.RecipeImpl.GetLinkedUserIDs)(primaryUserID, userContext)
+}
+
+func LinkAccountsWithContext(primaryUserID string, recipeUserID string, userContext supertokens.UserContext) (almodels.LinkAccountsResponse, error) {
+	instance, err := getRecipeInstanceOrThrowError()
+	if err != nil {
+		return almodels.LinkAccountsResponse{}, err
+	}
+	return (*instance.RecipeImpl.LinkAccounts)(primaryUserID, recipeUserID, userContext)
+}
+
+func UnlinkAccountWithContext(recipeUserID string, userContext supertokens.UserContext) (almodels.UnlinkAccountResponse, error) {
+	instance, err := getRecipeInstanceOrThrowError()
+	if err != nil {
+		return almodels.UnlinkAccountResponse{}, err
+	}
+	return (*instance.RecipeImpl.UnlinkAccount)(recipeUserID, userContext)
+}
+
+// GetPrimaryUserIDForSignInUp returns the user ID that a session should be created for when the
+// account signs in. candidateUserIDs are the verified recipe users with the same email as the account,
+// oldest first. The account is linked to the primary user of the first candidate if
+// ShouldDoAutomaticAccountLinking allows it. If this recipe is not initialised, the ID of the account is returned.
+func GetPrimaryUserIDForSignInUp(account almodels.AccountInfo, candidateUserIDs []string, userContext supertokens.UserContext) (string, error) {
+	instance, err := getRecipeInstanceOrThrowError()
+	if err != nil {
+		return account.RecipeUserID, nil
+	}
+
+	primaryUserID, err := (*instance.RecipeImpl.GetPrimaryUserID)(account.RecipeUserID, userContext)
+	if err != nil {
+		return "", err
+	}
+	if primaryUserID != account.RecipeUserID {
+		return primaryUserID, nil
+	}
+
+	// accounts are only linked automatically if the user has proven that they own the email,
+	// otherwise anyone could take over an account by signing up with its email
+	if !account.IsVerified || instance.Config.ShouldDoAutomaticAccountLinking == nil || len(candidateUserIDs) == 0 {
+		return account.RecipeUserID, nil
+	}
+
+	candidatePrimaryUserID, err := (*instance.RecipeImpl.GetPrimaryUserID)(candidateUserIDs[0], userContext)
+	if err != nil {
+		return "", err
+	}
+	shouldLink, err := instance.Config.ShouldDoAutomaticAccountLinking(account, candidatePrimaryUserID, userContext)
+	if err != nil {
+		return "", err
+	}
+	if !shouldLink {
+		return account.RecipeUserID, nil
+	}
+
+	response, err := (*instance.RecipeImpl.LinkAccounts)(candidatePrimaryUserID, account.RecipeUserID, userContext)
+	if err != nil {
+		return "", err
+	}
+	if response.OK == nil {
+		// the account is already the primary user of other accounts
+		return account.RecipeUserID, nil
+	}
+	return response.OK.PrimaryUserID, nil
+}
+
+// IsInitialised returns whether this recipe is in the recipe list, so that the recipes that sign users in
+// can skip looking for accounts to link when it is not.
+func IsInitialised() bool {
+	return singletonInstance != nil
+}
+
+// GetPrimaryUserIDOfRecipeUser returns the primary user of the recipe user. The recipe user ID itself is
+// returned if it is not linked or this recipe is not initialised.
+func GetPrimaryUserIDOfRecipeUser(recipeUserID string, userContext supertokens.UserContext) (string, error) {
+	instance, err := getRecipeInstanceOrThrowError()
+	if err != nil {
+		return recipeUserID, nil
+	}
+	return (*instance.RecipeImpl.GetPrimaryUserID)(recipeUserID, userContext)
+}
+
+func GetPrimaryUserID(recipeUserID string) (string, error) {
+	return GetPrimaryUserIDWithContext(recipeUserID, &map[string]interface{}{})
+}
+
+func GetLinkedUserIDs(primaryUserID string) ([]string, error) {
+	return GetLinkedUserIDsWithContext(primaryUserID, &map[string]interface{}{})
+}
+
+func LinkAccounts(primaryUserID string, recipeUserID string) (almodels.LinkAccountsResponse, error) {
+	return LinkAccountsWithContext(primaryUserID, recipeUserID, &map[string]interface{}{})
+}
+
+func UnlinkAccount(recipeUserID string) (almodels.UnlinkAccountResponse, error) {
+	return UnlinkAccountWithContext(recipeUserID, &map[string]interface{}{})
+}
@@ -0,0 +1,115 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package accountlinking
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/supertokens/supertokens-golang/recipe/accountlinking/almodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+const RECIPE_ID = "accountlinking"
+
+type Recipe struct {
+	RecipeModule supertokens.RecipeModule
+	Config       almodels.TypeNormalisedInput
+	RecipeImpl   almodels.RecipeInterface
+}
+
+var singletonInstance *Recipe
+
+func MakeRecipe(recipeId string, appInfo supertokens.NormalisedAppinfo, config *almodels.TypeInput, onGeneralError func(err error, req *http.Request, res http.ResponseWriter)) (Recipe, error) {
+	r := &Recipe{}
+	verifiedConfig, err := validateAndNormaliseUserInput(config)
+	if err != nil {
+		return Recipe{}, err
+	}
+	r.Config = verifiedConfig
+	r.RecipeImpl = verifiedConfig.Override.Functions(MakeRecipeImplementation(verifiedConfig.Store))
+	supertokens.AddBeforeUserDeletedHook(recipeId, r.unlinkDeletedUser)
+
+	recipeModuleInstance := supertokens.MakeRecipeModule(recipeId, appInfo, r.handleAPIRequest, r.getAllCORSHeaders, r.getAPIsHandled, r.handleError, onGeneralError)
+	r.RecipeModule = recipeModuleInstance
+
+	return *r, nil
+}
+
+func getRecipeInstanceOrThrowError() (*Recipe, error) {
+	if singletonInstance != nil {
+		return singletonInstance, nil
+	}
+	return nil, errors.New("Initialisation not done. Did you forget to call the init function?")
+}
+
+func recipeInit(config *almodels.TypeInput) supertokens.Recipe {
+	return func(appInfo supertokens.NormalisedAppinfo, onGeneralError func(err error, req *http.Request, res http.ResponseWriter)) (*supertokens.RecipeModule, error) {
+		if singletonInstance == nil {
+			recipe, err := MakeRecipe(RECIPE_ID, appInfo, config, onGeneralError)
+			if err != nil {
+				return nil, err
+			}
+			singletonInstance = &recipe
+			return &singletonInstance.RecipeModule, nil
+		}
+		return nil, errors.New("AccountLinking recipe has already been initialised. Please check your code for bugs.")
+	}
+}
+
+// unlinkDeletedUser removes the links of a user that is deleted, so that its linked accounts become
+// separate users again and no link points to a user that does not exist
+func (r *Recipe) unlinkDeletedUser(userID string) error {
+	userContext := &map[string]interface{}{}
+	linkedUserIDs, err := (*r.RecipeImpl.GetLinkedUserIDs)(userID, userContext)
+	if err != nil {
+		return err
+	}
+	for _, linkedUserID := range linkedUserIDs {
+		if linkedUserID == userID {
+			continue
+		}
+		_, err = (*r.RecipeImpl.UnlinkAccount)(linkedUserID, userContext)
+		if err != nil {
+			return err
+		}
+	}
+	_, err = (*r.RecipeImpl.UnlinkAccount)(userID, userContext)
+	return err
+}
+
+// implement RecipeModule
+
+// this recipe has no APIs, it is used by the recipes that sign users in
+func (r *Recipe) getAPIsHandled() ([]supertokens.APIHandled, error) {
+	return []supertokens.APIHandled{}, nil
+}
+
+func (r *Recipe) handleAPIRequest(id string, req *http.Request, res http.ResponseWriter, theirHandler http.HandlerFunc, _ supertokens.NormalisedURLPath, _ string) error {
+	return errors.New("should never come here")
+}
+
+func (r *Recipe) getAllCORSHeaders() []string {
+	return []string{}
+}
+
+func (r *Recipe) handleError(err error, req *http.Request, res http.ResponseWriter) (bool, error) {
+	return false, nil
+}
+
+func ResetForTest() {
+	singletonInstance = nil
+}
@@ -0,0 +1,132 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package accountlinking
+
+import (
+	"github.com/supertokens/supertokens-golang/recipe/accountlinking/almodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func MakeRecipeImplementation(store almodels.AccountLinkingStore) almodels.RecipeInterface {
+	getPrimaryUserID := func(recipeUserID string, userContext supertokens.UserContext) (string, error) {
+		primaryUserID, err := store.GetPrimaryUserID(recipeUserID, userContext)
+		if err != nil {
+			return "", err
+		}
+		if primaryUserID == nil {
+			return recipeUserID, nil
+		}
+		return *primaryUserID, nil
+	}
+
+	getLinkedUserIDs := func(primaryUserID string, userContext supertokens.UserContext) ([]string, error) {
+		recipeUserIDs, err := store.GetRecipeUserIDs(primaryUserID, userContext)
+		if err != nil {
+			return nil, err
+		}
+		return append([]string{primaryUserID}, recipeUserIDs...), nil
+	}
+
+	linkAccounts := func(primaryUserID string, recipeUserID string, userContext supertokens.UserContext) (almodels.LinkAccountsResponse, error) {
+		// accounts are always linked to the root of a group, so that there is only one level of linking
+		primaryUserID, err := getPrimaryUserID(primaryUserID, userContext)
+		if err != nil {
+			return almodels.LinkAccountsResponse{}, err
+		}
+
+		currentPrimaryUserID, err := getPrimaryUserID(recipeUserID, userContext)
+		if err != nil {
+			return almodels.LinkAccountsResponse{}, err
+		}
+		if currentPrimaryUserID == primaryUserID {
+			return almodels.LinkAccountsResponse{
+				OK: &struct {
+					PrimaryUserID         string
+					AccountsAlreadyLinked bool
+				}{
+					PrimaryUserID:         primaryUserID,
+					AccountsAlreadyLinked: true,
+				},
+			}, nil
+		}
+		if currentPrimaryUserID != recipeUserID {
+			return almodels.LinkAccountsResponse{
+				AccountLinkedToAnotherUserError: &struct{ PrimaryUserID string }{
+					PrimaryUserID: currentPrimaryUserID,
+				},
+			}, nil
+		}
+
+		linkedUserIDs, err := store.GetRecipeUserIDs(recipeUserID, userContext)
+		if err != nil {
+			return almodels.LinkAccountsResponse{}, err
+		}
+		if len(linkedUserIDs) != 0 {
+			return almodels.LinkAccountsResponse{
+				RecipeUserIsPrimaryUserError: &struct{}{},
+			}, nil
+		}
+
+		err = store.Link(primaryUserID, recipeUserID, userContext)
+		if err != nil {
+			return almodels.LinkAccountsResponse{}, err
+		}
+		return almodels.LinkAccountsResponse{
+			OK: &struct {
+				PrimaryUserID         string
+				AccountsAlreadyLinked bool
+			}{
+				PrimaryUserID:         primaryUserID,
+				AccountsAlreadyLinked: false,
+			},
+		}, nil
+	}
+
+	unlinkAccount := func(recipeUserID string, userContext supertokens.UserContext) (almodels.UnlinkAccountResponse, error) {
+		linkedUserIDs, err := store.GetRecipeUserIDs(recipeUserID, userContext)
+		if err != nil {
+			return almodels.UnlinkAccountResponse{}, err
+		}
+		if len(linkedUserIDs) != 0 {
+			return almodels.UnlinkAccountResponse{
+				PrimaryUserHasLinkedAccountsError: &struct{}{},
+			}, nil
+		}
+
+		primaryUserID, err := store.GetPrimaryUserID(recipeUserID, userContext)
+		if err != nil {
+			return almodels.UnlinkAccountResponse{}, err
+		}
+		if primaryUserID != nil {
+			err = store.Unlink(recipeUserID, userContext)
+			if err != nil {
+				return almodels.UnlinkAccountResponse{}, err
+			}
+		}
+		return almodels.UnlinkAccountResponse{
+			OK: &struct{ WasLinked bool }{
+				WasLinked: primaryUserID != nil,
+			},
+		}, nil
+	}
+
+	return almodels.RecipeInterface{
+		GetPrimaryUserID: &getPrimaryUserID,
+		GetLinkedUserIDs: &getLinkedUserIDs,
+		LinkAccounts:     &linkAccounts,
+		UnlinkAccount:    &unlinkAccount,
+	}
+}
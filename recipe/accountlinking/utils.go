@@ -0,0 +1,50 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package accountlinking
+
+import (
+	"errors"
+
+	"github.com/supertokens/supertokens-golang/recipe/accountlinking/almodels"
+)
+
+func validateAndNormaliseUserInput(config *almodels.TypeInput) (almodels.TypeNormalisedInput, error) {
+	typeNormalisedInput := makeTypeNormalisedInput()
+
+	// users would sign in to a different account if the links were lost or not shared by all API instances
+	if config == nil || config.Store == nil {
+		return almodels.TypeNormalisedInput{}, errors.New("please provide a Store in the account linking config")
+	}
+	typeNormalisedInput.ShouldDoAutomaticAccountLinking = config.ShouldDoAutomaticAccountLinking
+	typeNormalisedInput.Store = *config.Store
+
+	if config.Override != nil && config.Override.Functions != nil {
+		typeNormalisedInput.Override.Functions = config.Override.Functions
+	}
+
+	return typeNormalisedInput, nil
+}
+
+func makeTypeNormalisedInput() almodels.TypeNormalisedInput {
+	return almodels.TypeNormalisedInput{
+		ShouldDoAutomaticAccountLinking: nil,
+		Override: almodels.OverrideStruct{
+			Functions: func(originalImplementation almodels.RecipeInterface) almodels.RecipeInterface {
+				return originalImplementation
+			},
+		},
+	}
+}
@@ -90,6 +90,13 @@ func MakeAPIImplementation() epmodels.APIInterface {
 			return epmodels.ResetPasswordUsingTokenResponse{}, err
 		}
 
+		if response.OK != nil && response.OK.UserId != nil && options.Config.ResetPasswordUsingTokenFeature.SignInAfterReset {
+			_, err = session.CreateNewSessionWithContext(options.Res, *response.OK.UserId, map[string]interface{}{}, map[string]interface{}{}, userContext)
+			if err != nil {
+				return epmodels.ResetPasswordUsingTokenResponse{}, err
+			}
+		}
+
 		return response, nil
 	}
 
@@ -160,6 +167,15 @@ func MakeAPIImplementation() epmodels.APIInterface {
 
 		user := response.OK.User
 
+		if options.Config.SignUpFeature.PersistFormFieldsInUserMetadata {
+			metadata := getUserMetadataFromFormFields(options.Config.SignUpFeature.FormFields, formFields)
+			err = options.Config.SignUpFeature.UserMetadataStore.Update(user.ID, metadata, userContext)
+			if err != nil {
+				return epmodels.SignUpPOSTResponse{}, err
+			}
+			user.Metadata = metadata
+		}
+
 		session, err := session.CreateNewSessionWithContext(options.Res, user.ID, map[string]interface{}{}, map[string]interface{}{}, userContext)
 		if err != nil {
 			return epmodels.SignUpPOSTResponse{}, err
@@ -170,16 +186,147 @@ func MakeAPIImplementation() epmodels.APIInterface {
 				User    epmodels.User
 				Session sessmodels.SessionContainer
 			}{
-				User:    response.OK.User,
+				User:    user,
 				Session: session,
 			},
 		}, nil
 	}
+
+	changeEmailPOST := func(formFields []epmodels.TypeFormField, options epmodels.APIOptions, userContext supertokens.UserContext) (epmodels.ChangeEmailPOSTResponse, error) {
+		var newEmail string
+		for _, formField := range formFields {
+			if formField.ID == "email" {
+				newEmail = formField.Value
+			}
+		}
+
+		sessionContainer, err := session.GetSessionWithContext(options.Req, options.Res, nil, userContext)
+		if err != nil {
+			return epmodels.ChangeEmailPOSTResponse{}, err
+		}
+		if sessionContainer == nil {
+			return epmodels.ChangeEmailPOSTResponse{}, supertokens.BadInputError{Msg: "Session is undefined. Should not come here."}
+		}
+
+		userID := sessionContainer.GetUserIDWithContext(userContext)
+		user, err := (*options.RecipeImplementation.GetUserByID)(userID, userContext)
+		if err != nil {
+			return epmodels.ChangeEmailPOSTResponse{}, err
+		}
+		if user == nil {
+			return epmodels.ChangeEmailPOSTResponse{}, supertokens.BadInputError{Msg: "Unknown User ID provided"}
+		}
+
+		response, err := (*options.RecipeImplementation.CreateEmailChangeToken)(userID, newEmail, userContext)
+		if err != nil {
+			return epmodels.ChangeEmailPOSTResponse{}, err
+		}
+		if response.UnknownUserIdError != nil {
+			return epmodels.ChangeEmailPOSTResponse{}, supertokens.BadInputError{Msg: "Unknown User ID provided"}
+		}
+		if response.EmailAlreadyExistsError != nil {
+			return epmodels.ChangeEmailPOSTResponse{
+				EmailAlreadyExistsError: &struct{}{},
+			}, nil
+		}
+
+		emailChangeLink, err := options.Config.ChangeEmailFeature.GetEmailChangeURL(*user, userContext)
+		if err != nil {
+			return epmodels.ChangeEmailPOSTResponse{}, err
+		}
+
+		emailChangeLink = emailChangeLink + "?token=" + response.OK.Token + "&rid=" + options.RecipeID
+
+		options.Config.ChangeEmailFeature.CreateAndSendCustomEmail(*user, newEmail, emailChangeLink, userContext)
+
+		return epmodels.ChangeEmailPOSTResponse{
+			OK: &struct{}{},
+		}, nil
+	}
+
+	verifyEmailChangePOST := func(token string, options epmodels.APIOptions, userContext supertokens.UserContext) (epmodels.ChangeEmailUsingTokenResponse, error) {
+		return (*options.RecipeImplementation.ChangeEmailUsingToken)(token, userContext)
+	}
+
+	changePasswordPOST := func(formFields []epmodels.TypeFormField, options epmodels.APIOptions, userContext supertokens.UserContext) (epmodels.ChangePasswordPOSTResponse, error) {
+		var oldPassword string
+		var newPassword string
+		for _, formField := range formFields {
+			if formField.ID == "oldPassword" {
+				oldPassword = formField.Value
+			} else if formField.ID == "newPassword" {
+				newPassword = formField.Value
+			}
+		}
+
+		sessionContainer, err := session.GetSessionWithContext(options.Req, options.Res, nil, userContext)
+		if err != nil {
+			return epmodels.ChangePasswordPOSTResponse{}, err
+		}
+		if sessionContainer == nil {
+			return epmodels.ChangePasswordPOSTResponse{}, supertokens.BadInputError{Msg: "Session is undefined. Should not come here."}
+		}
+
+		userID := sessionContainer.GetUserIDWithContext(userContext)
+		user, err := (*options.RecipeImplementation.GetUserByID)(userID, userContext)
+		if err != nil {
+			return epmodels.ChangePasswordPOSTResponse{}, err
+		}
+		if user == nil {
+			return epmodels.ChangePasswordPOSTResponse{}, supertokens.BadInputError{Msg: "Unknown User ID provided"}
+		}
+
+		signInResponse, err := (*options.RecipeImplementation.SignIn)(user.Email, oldPassword, userContext)
+		if err != nil {
+			return epmodels.ChangePasswordPOSTResponse{}, err
+		}
+		if signInResponse.WrongCredentialsError != nil {
+			return epmodels.ChangePasswordPOSTResponse{
+				WrongCredentialsError: &struct{}{},
+			}, nil
+		}
+
+		updateResponse, err := (*options.RecipeImplementation.UpdateEmailOrPassword)(userID, nil, &newPassword, userContext)
+		if err != nil {
+			return epmodels.ChangePasswordPOSTResponse{}, err
+		}
+		if updateResponse.OK == nil {
+			return epmodels.ChangePasswordPOSTResponse{}, supertokens.BadInputError{Msg: "Unknown User ID provided"}
+		}
+
+		if options.Config.ChangePasswordFeature.RevokeOtherSessions {
+			sessionHandles, err := session.GetAllSessionHandlesForUserWithContext(userID, userContext)
+			if err != nil {
+				return epmodels.ChangePasswordPOSTResponse{}, err
+			}
+			currentSessionHandle := sessionContainer.GetHandleWithContext(userContext)
+			otherSessionHandles := []string{}
+			for _, sessionHandle := range sessionHandles {
+				if sessionHandle != currentSessionHandle {
+					otherSessionHandles = append(otherSessionHandles, sessionHandle)
+				}
+			}
+			if len(otherSessionHandles) > 0 {
+				_, err = session.RevokeMultipleSessionsWithContext(otherSessionHandles, userContext)
+				if err != nil {
+					return epmodels.ChangePasswordPOSTResponse{}, err
+				}
+			}
+		}
+
+		return epmodels.ChangePasswordPOSTResponse{
+			OK: &struct{}{},
+		}, nil
+	}
+
 	return epmodels.APIInterface{
 		EmailExistsGET:                 &emailExistsGET,
 		GeneratePasswordResetTokenPOST: &generatePasswordResetTokenPOST,
 		PasswordResetPOST:              &passwordResetPOST,
 		SignInPOST:                     &signInPOST,
 		SignUpPOST:                     &signUpPOST,
+		ChangeEmailPOST:                &changeEmailPOST,
+		VerifyEmailChangePOST:          &verifyEmailChangePOST,
+		ChangePasswordPOST:             &changePasswordPOST,
 	}
 }
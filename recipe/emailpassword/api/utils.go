@@ -18,10 +18,12 @@ package api
 import (
 	"encoding/json"
 	defaultErrors "errors"
+	"strconv"
 	"strings"
 
 	"github.com/supertokens/supertokens-golang/recipe/emailpassword/epmodels"
 	"github.com/supertokens/supertokens-golang/recipe/emailpassword/errors"
+	"github.com/supertokens/supertokens-golang/supertokens"
 )
 
 func validateFormFieldsOrThrowError(configFormFields []epmodels.NormalisedFormField, formFieldsRaw []interface{}) ([]epmodels.TypeFormField, error) {
@@ -39,28 +41,138 @@ func validateFormFieldsOrThrowError(configFormFields []epmodels.NormalisedFormFi
 		if err != nil {
 			return nil, err
 		}
-		var formField epmodels.TypeFormField
+		var formField struct {
+			ID    string      `json:"id"`
+			Value interface{} `json:"value"`
+		}
 		err = json.Unmarshal(jsonformField, &formField)
 		if err != nil {
 			return nil, err
 		}
 
+		value, err := formFieldValueToString(formField.Value)
+		if err != nil {
+			return nil, err
+		}
+
 		if formField.ID == "email" {
-			formFields = append(formFields, epmodels.TypeFormField{
-				ID:    formField.ID,
-				Value: strings.TrimSpace(formField.Value),
-			})
-		} else {
-			formFields = append(formFields, epmodels.TypeFormField{
-				ID:    formField.ID,
-				Value: formField.Value,
-			})
+			value = strings.TrimSpace(value)
 		}
+		formFields = append(formFields, epmodels.TypeFormField{
+			ID:    formField.ID,
+			Value: value,
+		})
 	}
 
+	formFields = applyDefaultFormFieldValues(configFormFields, formFields)
+
 	return formFields, validateFormOrThrowError(configFormFields, formFields)
 }
 
+// formFieldValueToString accepts the JSON types that typed form fields can be sent as.
+func formFieldValueToString(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	default:
+		return "", supertokens.BadInputError{Msg: "formFields values must be a string, number or boolean"}
+	}
+}
+
+func applyDefaultFormFieldValues(configFormFields []epmodels.NormalisedFormField, inputs []epmodels.TypeFormField) []epmodels.TypeFormField {
+	for _, field := range configFormFields {
+		if field.DefaultValue == nil {
+			continue
+		}
+		found := false
+		for i, input := range inputs {
+			if input.ID == field.ID {
+				found = true
+				if input.Value == "" {
+					inputs[i].Value = *field.DefaultValue
+				}
+				break
+			}
+		}
+		if !found {
+			inputs = append(inputs, epmodels.TypeFormField{
+				ID:    field.ID,
+				Value: *field.DefaultValue,
+			})
+		}
+	}
+	return inputs
+}
+
+// parseFormFieldValue converts the raw value of a form field into the Go type matching its
+// configured type. A non nil string is returned if the value is not valid for that type.
+func parseFormFieldValue(field epmodels.NormalisedFormField, value string) (interface{}, *string) {
+	switch field.Type {
+	case "", epmodels.FormFieldTypeString:
+		return value, nil
+	case epmodels.FormFieldTypeNumber:
+		parsedValue, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			msg := "Field must be a number"
+			return nil, &msg
+		}
+		return parsedValue, nil
+	case epmodels.FormFieldTypeBool:
+		parsedValue, err := strconv.ParseBool(strings.TrimSpace(value))
+		if err != nil {
+			msg := "Field must be true or false"
+			return nil, &msg
+		}
+		return parsedValue, nil
+	case epmodels.FormFieldTypeEnum:
+		if len(field.EnumValues) == 0 {
+			msg := "Development bug: enum form field has no EnumValues configured"
+			return nil, &msg
+		}
+		for _, enumValue := range field.EnumValues {
+			if enumValue == value {
+				return value, nil
+			}
+		}
+		msg := "Field must be one of: " + strings.Join(field.EnumValues, ", ")
+		return nil, &msg
+	default:
+		msg := "Development bug: unknown form field type " + string(field.Type)
+		return nil, &msg
+	}
+}
+
+// getUserMetadataFromFormFields returns the typed values of all sign up form fields other
+// than email and password.
+func getUserMetadataFromFormFields(configFormFields []epmodels.NormalisedFormField, inputs []epmodels.TypeFormField) map[string]interface{} {
+	metadata := map[string]interface{}{}
+	for _, field := range configFormFields {
+		if field.ID == "email" || field.ID == "password" {
+			continue
+		}
+		for _, input := range inputs {
+			if input.ID != field.ID {
+				continue
+			}
+			if input.Value == "" {
+				break
+			}
+			parsedValue, err := parseFormFieldValue(field, input.Value)
+			if err == nil {
+				metadata[field.ID] = parsedValue
+			}
+			break
+		}
+	}
+	return metadata
+}
+
 func validateFormOrThrowError(configFormFields []epmodels.NormalisedFormField, inputs []epmodels.TypeFormField) error {
 	var validationErrors []errors.ErrorPayload
 	if len(configFormFields) != len(inputs) {
@@ -76,8 +188,19 @@ func validateFormOrThrowError(configFormFields []epmodels.NormalisedFormField, i
 		}
 		if input.Value == "" && !field.Optional {
 			validationErrors = append(validationErrors, errors.ErrorPayload{ID: field.ID, ErrorMsg: "Field is not optional"})
+		} else if input.Value == "" && field.Type != "" && field.Type != epmodels.FormFieldTypeString {
+			// an empty optional typed field has nothing to parse or validate
+			continue
 		} else {
-			err := field.Validate(input.Value)
+			parsedValue, parseErr := parseFormFieldValue(field, input.Value)
+			if parseErr != nil {
+				validationErrors = append(validationErrors, errors.ErrorPayload{
+					ID:       field.ID,
+					ErrorMsg: *parseErr,
+				})
+				continue
+			}
+			err := field.Validate(parsedValue)
 			if err != nil {
 				validationErrors = append(validationErrors, errors.ErrorPayload{
 					ID:       field.ID,
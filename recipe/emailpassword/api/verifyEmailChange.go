@@ -0,0 +1,69 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package api
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"reflect"
+
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/epmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func VerifyEmailChange(apiImplementation epmodels.APIInterface, options epmodels.APIOptions) error {
+	if apiImplementation.VerifyEmailChangePOST == nil || (*apiImplementation.VerifyEmailChangePOST) == nil {
+		options.OtherHandler(options.Res, options.Req)
+		return nil
+	}
+
+	body, err := ioutil.ReadAll(options.Req.Body)
+	if err != nil {
+		return err
+	}
+	var readBody map[string]interface{}
+	err = json.Unmarshal(body, &readBody)
+	if err != nil {
+		return err
+	}
+
+	token, ok := readBody["token"]
+	if !ok {
+		return supertokens.BadInputError{Msg: "Please provide the email change token"}
+	}
+	if reflect.TypeOf(token).Kind() != reflect.String {
+		return supertokens.BadInputError{Msg: "The email change token must be a string"}
+	}
+
+	result, err := (*apiImplementation.VerifyEmailChangePOST)(token.(string), options, &map[string]interface{}{})
+	if err != nil {
+		return err
+	}
+	if result.OK != nil {
+		return supertokens.Send200Response(options.Res, map[string]interface{}{
+			"status": "OK",
+			"user":   result.OK.User,
+		})
+	} else if result.EmailAlreadyExistsError != nil {
+		return supertokens.Send200Response(options.Res, map[string]interface{}{
+			"status": "EMAIL_ALREADY_EXISTS_ERROR",
+		})
+	} else {
+		return supertokens.Send200Response(options.Res, map[string]interface{}{
+			"status": "EMAIL_CHANGE_INVALID_TOKEN_ERROR",
+		})
+	}
+}
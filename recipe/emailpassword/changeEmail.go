@@ -0,0 +1,146 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package emailpassword
+
+import (
+	"errors"
+
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/epmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// makeChangeEmailRecipeImplementation adds the email change functions to the recipe interface. They
+// go through the (possibly overridden) recipe and email verification implementations of the recipe
+// instance, which are only set once MakeRecipe is done.
+func makeChangeEmailRecipeImplementation(originalImplementation epmodels.RecipeInterface, recipeInstance *Recipe) epmodels.RecipeInterface {
+	createEmailChangeToken := func(userID string, newEmail string, userContext supertokens.UserContext) (epmodels.CreateEmailChangeTokenResponse, error) {
+		if recipeInstance.Config.ChangeEmailFeature.CreateAndSendEmailChangedNotification == nil {
+			return epmodels.CreateEmailChangeTokenResponse{}, errors.New("please provide ChangeEmailFeature in the emailpassword config to change the email of users")
+		}
+		recipeImplementation := recipeInstance.RecipeImpl
+		emailVerificationRecipeImplementation := recipeInstance.EmailVerificationRecipe.RecipeImpl
+
+		user, err := (*recipeImplementation.GetUserByID)(userID, userContext)
+		if err != nil {
+			return epmodels.CreateEmailChangeTokenResponse{}, err
+		}
+		if user == nil {
+			return epmodels.CreateEmailChangeTokenResponse{
+				UnknownUserIdError: &struct{}{},
+			}, nil
+		}
+		existingUser, err := (*recipeImplementation.GetUserByEmail)(newEmail, userContext)
+		if err != nil {
+			return epmodels.CreateEmailChangeTokenResponse{}, err
+		}
+		if existingUser != nil {
+			return epmodels.CreateEmailChangeTokenResponse{
+				EmailAlreadyExistsError: &struct{}{},
+			}, nil
+		}
+		response, err := (*emailVerificationRecipeImplementation.CreateEmailVerificationToken)(userID, newEmail, userContext)
+		if err != nil {
+			return epmodels.CreateEmailChangeTokenResponse{}, err
+		}
+		if response.EmailAlreadyVerifiedError != nil {
+			// the new email was verified for this user in the past. We still want
+			// the user to confirm the change, so we reset its verification status.
+			_, err = (*emailVerificationRecipeImplementation.UnverifyEmail)(userID, newEmail, userContext)
+			if err != nil {
+				return epmodels.CreateEmailChangeTokenResponse{}, err
+			}
+			response, err = (*emailVerificationRecipeImplementation.CreateEmailVerificationToken)(userID, newEmail, userContext)
+			if err != nil {
+				return epmodels.CreateEmailChangeTokenResponse{}, err
+			}
+			if response.OK == nil {
+				return epmodels.CreateEmailChangeTokenResponse{}, errors.New("should never come here")
+			}
+		}
+		return epmodels.CreateEmailChangeTokenResponse{
+			OK: &struct{ Token string }{Token: response.OK.Token},
+		}, nil
+	}
+
+	changeEmailUsingToken := func(token string, userContext supertokens.UserContext) (epmodels.ChangeEmailUsingTokenResponse, error) {
+		config := recipeInstance.Config.ChangeEmailFeature
+		if config.CreateAndSendEmailChangedNotification == nil {
+			return epmodels.ChangeEmailUsingTokenResponse{}, errors.New("please provide ChangeEmailFeature in the emailpassword config to change the email of users")
+		}
+		recipeImplementation := recipeInstance.RecipeImpl
+		emailVerificationRecipeImplementation := recipeInstance.EmailVerificationRecipe.RecipeImpl
+
+		response, err := (*emailVerificationRecipeImplementation.VerifyEmailUsingToken)(token, userContext)
+		if err != nil {
+			return epmodels.ChangeEmailUsingTokenResponse{}, err
+		}
+		if response.EmailVerificationInvalidTokenError != nil {
+			return epmodels.ChangeEmailUsingTokenResponse{
+				EmailChangeInvalidTokenError: &struct{}{},
+			}, nil
+		}
+		userID := response.OK.User.ID
+		newEmail := response.OK.User.Email
+
+		user, err := (*recipeImplementation.GetUserByID)(userID, userContext)
+		if err != nil {
+			return epmodels.ChangeEmailUsingTokenResponse{}, err
+		}
+		if user == nil {
+			return epmodels.ChangeEmailUsingTokenResponse{
+				EmailChangeInvalidTokenError: &struct{}{},
+			}, nil
+		}
+		if user.Email == newEmail {
+			return epmodels.ChangeEmailUsingTokenResponse{
+				OK: &struct{ User epmodels.User }{User: *user},
+			}, nil
+		}
+
+		updateResponse, err := (*recipeImplementation.UpdateEmailOrPassword)(userID, &newEmail, nil, userContext)
+		if err != nil {
+			return epmodels.ChangeEmailUsingTokenResponse{}, err
+		}
+		if updateResponse.EmailAlreadyExistsError != nil {
+			// someone signed up with the new email after the token was created.
+			// We undo the verification so that it does not linger for this user.
+			_, err = (*emailVerificationRecipeImplementation.UnverifyEmail)(userID, newEmail, userContext)
+			if err != nil {
+				return epmodels.ChangeEmailUsingTokenResponse{}, err
+			}
+			return epmodels.ChangeEmailUsingTokenResponse{
+				EmailAlreadyExistsError: &struct{}{},
+			}, nil
+		}
+		if updateResponse.UnknownUserIdError != nil {
+			return epmodels.ChangeEmailUsingTokenResponse{
+				EmailChangeInvalidTokenError: &struct{}{},
+			}, nil
+		}
+
+		config.CreateAndSendEmailChangedNotification(*user, newEmail, userContext)
+
+		updatedUser := *user
+		updatedUser.Email = newEmail
+		return epmodels.ChangeEmailUsingTokenResponse{
+			OK: &struct{ User epmodels.User }{User: updatedUser},
+		}, nil
+	}
+
+	originalImplementation.CreateEmailChangeToken = &createEmailChangeToken
+	originalImplementation.ChangeEmailUsingToken = &changeEmailUsingToken
+	return originalImplementation
+}
@@ -0,0 +1,41 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package emailpassword
+
+import (
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/epmodels"
+	"github.com/supertokens/supertokens-golang/recipe/emailverification"
+	"github.com/supertokens/supertokens-golang/recipe/emailverification/evmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func defaultGetEmailChangeURL(appInfo supertokens.NormalisedAppinfo) func(_ epmodels.User, userContext supertokens.UserContext) (string, error) {
+	return func(_ epmodels.User, userContext supertokens.UserContext) (string, error) {
+		return appInfo.WebsiteDomain.GetAsStringDangerous() + appInfo.WebsiteBasePath.GetAsStringDangerous() + "/change-email", nil
+	}
+}
+
+// the link sent to the new address is an email verification link, so we reuse the
+// email verification mail by default.
+func defaultCreateAndSendCustomEmailChangeEmail(appInfo supertokens.NormalisedAppinfo) func(user epmodels.User, newEmail string, emailChangeURLWithToken string, userContext supertokens.UserContext) {
+	sendEmailVerificationEmail := emailverification.DefaultCreateAndSendCustomEmail(appInfo)
+	return func(user epmodels.User, newEmail string, emailChangeURLWithToken string, userContext supertokens.UserContext) {
+		sendEmailVerificationEmail(evmodels.User{
+			ID:    user.ID,
+			Email: newEmail,
+		}, emailChangeURLWithToken, userContext)
+	}
+}
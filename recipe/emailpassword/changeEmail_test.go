@@ -0,0 +1,221 @@
+/*
+ * Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package emailpassword
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/epmodels"
+	"github.com/supertokens/supertokens-golang/recipe/session"
+	"github.com/supertokens/supertokens-golang/supertokens"
+	"github.com/supertokens/supertokens-golang/test/unittesting"
+)
+
+func TestChangeEmailIsAppliedOnlyAfterVerification(t *testing.T) {
+	notifiedEmail := ""
+	configValue := supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: "http://localhost:8080",
+		},
+		AppInfo: supertokens.AppInfo{
+			APIDomain:     "api.supertokens.io",
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{
+			Init(&epmodels.TypeInput{
+				ChangeEmailFeature: &epmodels.TypeInputChangeEmailFeature{
+					CreateAndSendEmailChangedNotification: func(user epmodels.User, newEmail string, userContext supertokens.UserContext) {
+						notifiedEmail = user.Email
+					},
+				},
+			}),
+			session.Init(nil),
+		},
+	}
+
+	BeforeEach()
+	unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+	err := supertokens.Init(configValue)
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	signUpResponse, err := SignUp("old@gmail.com", "validpass123")
+	if err != nil {
+		t.Error(err.Error())
+	}
+	userID := signUpResponse.OK.User.ID
+
+	tokenResponse, err := CreateEmailChangeToken(userID, "new@gmail.com")
+	if err != nil {
+		t.Error(err.Error())
+	}
+	assert.NotNil(t, tokenResponse.OK)
+
+	user, err := GetUserByID(userID)
+	if err != nil {
+		t.Error(err.Error())
+	}
+	assert.Equal(t, "old@gmail.com", user.Email)
+
+	changeResponse, err := ChangeEmailUsingToken(tokenResponse.OK.Token)
+	if err != nil {
+		t.Error(err.Error())
+	}
+	assert.NotNil(t, changeResponse.OK)
+	assert.Equal(t, "new@gmail.com", changeResponse.OK.User.Email)
+	assert.Equal(t, "old@gmail.com", notifiedEmail)
+
+	user, err = GetUserByID(userID)
+	if err != nil {
+		t.Error(err.Error())
+	}
+	assert.Equal(t, "new@gmail.com", user.Email)
+
+	isVerified, err := IsEmailVerified(userID)
+	if err != nil {
+		t.Error(err.Error())
+	}
+	assert.True(t, isVerified)
+
+	changeResponse, err = ChangeEmailUsingToken(tokenResponse.OK.Token)
+	if err != nil {
+		t.Error(err.Error())
+	}
+	assert.NotNil(t, changeResponse.EmailChangeInvalidTokenError)
+}
+
+func TestChangeEmailFailsIfEmailIsTakenBeforeVerification(t *testing.T) {
+	configValue := supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: "http://localhost:8080",
+		},
+		AppInfo: supertokens.AppInfo{
+			APIDomain:     "api.supertokens.io",
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{
+			Init(nil),
+			session.Init(nil),
+		},
+	}
+
+	BeforeEach()
+	unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+	err := supertokens.Init(configValue)
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	signUpResponse, err := SignUp("old@gmail.com", "validpass123")
+	if err != nil {
+		t.Error(err.Error())
+	}
+	userID := signUpResponse.OK.User.ID
+
+	tokenResponse, err := CreateEmailChangeToken(userID, "new@gmail.com")
+	if err != nil {
+		t.Error(err.Error())
+	}
+	assert.NotNil(t, tokenResponse.OK)
+
+	_, err = SignUp("new@gmail.com", "validpass123")
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	tokenResponse2, err := CreateEmailChangeToken(userID, "new@gmail.com")
+	if err != nil {
+		t.Error(err.Error())
+	}
+	assert.NotNil(t, tokenResponse2.EmailAlreadyExistsError)
+
+	changeResponse, err := ChangeEmailUsingToken(tokenResponse.OK.Token)
+	if err != nil {
+		t.Error(err.Error())
+	}
+	assert.NotNil(t, changeResponse.EmailAlreadyExistsError)
+
+	user, err := GetUserByID(userID)
+	if err != nil {
+		t.Error(err.Error())
+	}
+	assert.Equal(t, "old@gmail.com", user.Email)
+}
+
+func TestChangeEmailRequiresTheEmailChangedNotification(t *testing.T) {
+	configValue := supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: "http://localhost:8080",
+		},
+		AppInfo: supertokens.AppInfo{
+			APIDomain:     "api.supertokens.io",
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{
+			Init(&epmodels.TypeInput{
+				ChangeEmailFeature: &epmodels.TypeInputChangeEmailFeature{},
+			}),
+		},
+	}
+
+	BeforeEach()
+	unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+	err := supertokens.Init(configValue)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "CreateAndSendEmailChangedNotification")
+}
+
+func TestChangeEmailIsDisabledWithoutConfig(t *testing.T) {
+	configValue := supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: "http://localhost:8080",
+		},
+		AppInfo: supertokens.AppInfo{
+			APIDomain:     "api.supertokens.io",
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{
+			Init(nil),
+			session.Init(nil),
+		},
+	}
+
+	BeforeEach()
+	unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+	err := supertokens.Init(configValue)
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	signUpResponse, err := SignUp("old@gmail.com", "validpass123")
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	_, err = CreateEmailChangeToken(signUpResponse.OK.User.ID, "new@gmail.com")
+	assert.NotNil(t, err)
+}
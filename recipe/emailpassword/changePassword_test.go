@@ -0,0 +1,226 @@
+/*
+ * Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package emailpassword
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/epmodels"
+	"github.com/supertokens/supertokens-golang/recipe/session"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+	"github.com/supertokens/supertokens-golang/test/unittesting"
+)
+
+func changePasswordRequest(testUrl string, oldPassword string, newPassword string, cookieData map[string]string) (map[string]interface{}, error) {
+	formFields := map[string][]map[string]string{
+		"formFields": {
+			{
+				"id":    "oldPassword",
+				"value": oldPassword,
+			},
+			{
+				"id":    "newPassword",
+				"value": newPassword,
+			},
+		},
+	}
+	postBody, err := json.Marshal(formFields)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(http.MethodPost, testUrl+"/auth/user/password/change", bytes.NewBuffer(postBody))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Cookie", "sAccessToken="+cookieData["sAccessToken"]+"; sIdRefreshToken="+cookieData["sIdRefreshToken"])
+	req.Header.Add("anti-csrf", cookieData["antiCsrf"])
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	dataInBytes, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	res.Body.Close()
+	var result map[string]interface{}
+	err = json.Unmarshal(dataInBytes, &result)
+	return result, err
+}
+
+func TestChangePasswordWithCorrectOldPasswordRevokesOtherSessions(t *testing.T) {
+	customAntiCsrfVal := "VIA_TOKEN"
+	revokeOtherSessions := true
+	configValue := supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: "http://localhost:8080",
+		},
+		AppInfo: supertokens.AppInfo{
+			APIDomain:     "api.supertokens.io",
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{
+			Init(&epmodels.TypeInput{
+				ChangePasswordFeature: &epmodels.TypeInputChangePasswordFeature{
+					RevokeOtherSessions: &revokeOtherSessions,
+				},
+			}),
+			session.Init(&sessmodels.TypeInput{
+				AntiCsrf: &customAntiCsrfVal,
+			}),
+		},
+	}
+
+	BeforeEach()
+	unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+	err := supertokens.Init(configValue)
+	if err != nil {
+		t.Error(err.Error())
+	}
+	mux := http.NewServeMux()
+	testServer := httptest.NewServer(supertokens.Middleware(mux))
+	defer testServer.Close()
+
+	res, err := unittesting.SignupRequest("random@gmail.com", "validpass123", testServer.URL)
+	if err != nil {
+		t.Error(err.Error())
+	}
+	cookieData := unittesting.ExtractInfoFromResponse(res)
+	res.Body.Close()
+
+	res1, err := unittesting.SignInRequest("random@gmail.com", "validpass123", testServer.URL)
+	if err != nil {
+		t.Error(err.Error())
+	}
+	res1.Body.Close()
+
+	user, err := GetUserByEmail("random@gmail.com")
+	if err != nil {
+		t.Error(err.Error())
+	}
+	sessionHandles, err := session.GetAllSessionHandlesForUser(user.ID)
+	if err != nil {
+		t.Error(err.Error())
+	}
+	assert.Equal(t, 2, len(sessionHandles))
+
+	result, err := changePasswordRequest(testServer.URL, "wrongpass123", "newpass123", cookieData)
+	if err != nil {
+		t.Error(err.Error())
+	}
+	assert.Equal(t, "WRONG_CREDENTIALS_ERROR", result["status"])
+
+	result, err = changePasswordRequest(testServer.URL, "validpass123", "short", cookieData)
+	if err != nil {
+		t.Error(err.Error())
+	}
+	assert.Equal(t, "FIELD_ERROR", result["status"])
+
+	result, err = changePasswordRequest(testServer.URL, "validpass123", "newpass123", cookieData)
+	if err != nil {
+		t.Error(err.Error())
+	}
+	assert.Equal(t, "OK", result["status"])
+
+	signInResponse, err := SignIn("random@gmail.com", "validpass123")
+	if err != nil {
+		t.Error(err.Error())
+	}
+	assert.NotNil(t, signInResponse.WrongCredentialsError)
+
+	signInResponse, err = SignIn("random@gmail.com", "newpass123")
+	if err != nil {
+		t.Error(err.Error())
+	}
+	assert.NotNil(t, signInResponse.OK)
+
+	sessionHandles, err = session.GetAllSessionHandlesForUser(user.ID)
+	if err != nil {
+		t.Error(err.Error())
+	}
+	assert.Equal(t, 1, len(sessionHandles))
+}
+
+func TestChangePasswordKeepsOtherSessionsByDefault(t *testing.T) {
+	customAntiCsrfVal := "VIA_TOKEN"
+	configValue := supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: "http://localhost:8080",
+		},
+		AppInfo: supertokens.AppInfo{
+			APIDomain:     "api.supertokens.io",
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{
+			Init(nil),
+			session.Init(&sessmodels.TypeInput{
+				AntiCsrf: &customAntiCsrfVal,
+			}),
+		},
+	}
+
+	BeforeEach()
+	unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+	err := supertokens.Init(configValue)
+	if err != nil {
+		t.Error(err.Error())
+	}
+	mux := http.NewServeMux()
+	testServer := httptest.NewServer(supertokens.Middleware(mux))
+	defer testServer.Close()
+
+	res, err := unittesting.SignupRequest("random@gmail.com", "validpass123", testServer.URL)
+	if err != nil {
+		t.Error(err.Error())
+	}
+	cookieData := unittesting.ExtractInfoFromResponse(res)
+	res.Body.Close()
+
+	res1, err := unittesting.SignInRequest("random@gmail.com", "validpass123", testServer.URL)
+	if err != nil {
+		t.Error(err.Error())
+	}
+	res1.Body.Close()
+
+	result, err := changePasswordRequest(testServer.URL, "validpass123", "newpass123", cookieData)
+	if err != nil {
+		t.Error(err.Error())
+	}
+	assert.Equal(t, "OK", result["status"])
+
+	user, err := GetUserByEmail("random@gmail.com")
+	if err != nil {
+		t.Error(err.Error())
+	}
+	sessionHandles, err := session.GetAllSessionHandlesForUser(user.ID)
+	if err != nil {
+		t.Error(err.Error())
+	}
+	assert.Equal(t, 2, len(sessionHandles))
+}
@@ -21,4 +21,7 @@ const (
 	GeneratePasswordResetTokenAPI = "/user/password/reset/token"
 	PasswordResetAPI              = "/user/password/reset"
 	SignupEmailExistsAPI          = "/signup/email/exists"
+	ChangeEmailAPI                = "/user/email/change"
+	ChangeEmailVerifyAPI          = "/user/email/change/verify"
+	ChangePasswordAPI             = "/user/password/change"
 )
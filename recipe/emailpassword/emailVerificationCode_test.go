@@ -0,0 +1,373 @@
+/*
+ * Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package emailpassword
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/epmodels"
+	"github.com/supertokens/supertokens-golang/recipe/emailverification/evmodels"
+	"github.com/supertokens/supertokens-golang/recipe/session"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+	"github.com/supertokens/supertokens-golang/test/unittesting"
+)
+
+func makeInMemoryEmailVerificationCodeStoreForTest() evmodels.EmailVerificationCodeStore {
+	var lock sync.Mutex
+	codes := map[string]evmodels.EmailVerificationCodeInfo{}
+	return evmodels.EmailVerificationCodeStore{
+		Save: func(codeInfo evmodels.EmailVerificationCodeInfo, userContext supertokens.UserContext) error {
+			lock.Lock()
+			defer lock.Unlock()
+			codes[codeInfo.UserID+":"+codeInfo.Email] = codeInfo
+			return nil
+		},
+		IncrementAttemptCount: func(userID string, email string, userContext supertokens.UserContext) (*evmodels.EmailVerificationCodeInfo, error) {
+			lock.Lock()
+			defer lock.Unlock()
+			codeInfo, ok := codes[userID+":"+email]
+			if !ok {
+				return nil, nil
+			}
+			codeInfo.FailedCodeInputAttemptCount++
+			codes[userID+":"+email] = codeInfo
+			return &codeInfo, nil
+		},
+		Remove: func(userID string, email string, userContext supertokens.UserContext) error {
+			lock.Lock()
+			defer lock.Unlock()
+			delete(codes, userID+":"+email)
+			return nil
+		},
+	}
+}
+
+func emailVerifyCodeRequest(testUrl string, code string, cookieData map[string]string) (map[string]interface{}, error) {
+	postBody, err := json.Marshal(map[string]string{
+		"code": code,
+	})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(http.MethodPost, testUrl+"/auth/user/email/verify/code", bytes.NewBuffer(postBody))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Cookie", "sAccessToken="+cookieData["sAccessToken"]+"; sIdRefreshToken="+cookieData["sIdRefreshToken"])
+	req.Header.Add("anti-csrf", cookieData["antiCsrf"])
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	dataInBytes, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	res.Body.Close()
+	var result map[string]interface{}
+	err = json.Unmarshal(dataInBytes, &result)
+	return result, err
+}
+
+func TestEmailVerificationUsingCode(t *testing.T) {
+	customAntiCsrfVal := "VIA_TOKEN"
+	codeStore := makeInMemoryEmailVerificationCodeStoreForTest()
+	maximumCodeInputAttempts := 2
+	var sentCode string
+	configValue := supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: "http://localhost:8080",
+		},
+		AppInfo: supertokens.AppInfo{
+			APIDomain:     "api.supertokens.io",
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{
+			Init(&epmodels.TypeInput{
+				EmailVerificationFeature: &epmodels.TypeInputEmailVerificationFeature{
+					Mode:                     "CODE",
+					MaximumCodeInputAttempts: &maximumCodeInputAttempts,
+					CodeStore:                &codeStore,
+					CreateAndSendEmailWithCode: func(user epmodels.User, code string, codeLifetime uint64, userContext supertokens.UserContext) {
+						sentCode = code
+					},
+				},
+			}),
+			session.Init(&sessmodels.TypeInput{
+				AntiCsrf: &customAntiCsrfVal,
+			}),
+		},
+	}
+
+	BeforeEach()
+	unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+	err := supertokens.Init(configValue)
+	if err != nil {
+		t.Error(err.Error())
+	}
+	mux := http.NewServeMux()
+	testServer := httptest.NewServer(supertokens.Middleware(mux))
+	defer testServer.Close()
+
+	resp, err := unittesting.SignupRequest("test@gmail.com", "testPass123", testServer.URL)
+	if err != nil {
+		t.Error(err.Error())
+	}
+	data, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	var response map[string]interface{}
+	_ = json.Unmarshal(data, &response)
+	userId := response["user"].(map[string]interface{})["id"]
+	cookieData := unittesting.ExtractInfoFromResponse(resp)
+
+	result, err := emailVerifyCodeRequest(testServer.URL, "123456", cookieData)
+	if err != nil {
+		t.Error(err.Error())
+	}
+	assert.Equal(t, "RESTART_FLOW_ERROR", result["status"])
+
+	resp1, err := unittesting.EmailVerifyTokenRequest(testServer.URL, userId.(string), cookieData["sAccessToken"], cookieData["sIdRefreshToken"], cookieData["antiCsrf"])
+	if err != nil {
+		t.Error(err.Error())
+	}
+	resp1.Body.Close()
+	assert.Equal(t, 6, len(sentCode))
+
+	wrongCode := "000000"
+	if sentCode == wrongCode {
+		wrongCode = "111111"
+	}
+	result, err = emailVerifyCodeRequest(testServer.URL, wrongCode, cookieData)
+	if err != nil {
+		t.Error(err.Error())
+	}
+	assert.Equal(t, "INCORRECT_USER_INPUT_CODE_ERROR", result["status"])
+	assert.Equal(t, float64(1), result["failedCodeInputAttemptCount"])
+	assert.Equal(t, float64(2), result["maximumCodeInputAttempts"])
+
+	result, err = emailVerifyCodeRequest(testServer.URL, sentCode, cookieData)
+	if err != nil {
+		t.Error(err.Error())
+	}
+	assert.Equal(t, "OK", result["status"])
+
+	isVerified, err := IsEmailVerified(userId.(string))
+	if err != nil {
+		t.Error(err.Error())
+	}
+	assert.True(t, isVerified)
+}
+
+func TestParallelWrongEmailVerificationCodesAreLimited(t *testing.T) {
+	customAntiCsrfVal := "VIA_TOKEN"
+	codeStore := makeInMemoryEmailVerificationCodeStoreForTest()
+	maximumCodeInputAttempts := 3
+	var sentCode string
+	configValue := supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: "http://localhost:8080",
+		},
+		AppInfo: supertokens.AppInfo{
+			APIDomain:     "api.supertokens.io",
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{
+			Init(&epmodels.TypeInput{
+				EmailVerificationFeature: &epmodels.TypeInputEmailVerificationFeature{
+					Mode:                     "CODE",
+					MaximumCodeInputAttempts: &maximumCodeInputAttempts,
+					CodeStore:                &codeStore,
+					CreateAndSendEmailWithCode: func(user epmodels.User, code string, codeLifetime uint64, userContext supertokens.UserContext) {
+						sentCode = code
+					},
+				},
+			}),
+			session.Init(&sessmodels.TypeInput{
+				AntiCsrf: &customAntiCsrfVal,
+			}),
+		},
+	}
+
+	BeforeEach()
+	unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+	err := supertokens.Init(configValue)
+	if err != nil {
+		t.Error(err.Error())
+	}
+	mux := http.NewServeMux()
+	testServer := httptest.NewServer(supertokens.Middleware(mux))
+	defer testServer.Close()
+
+	resp, err := unittesting.SignupRequest("test@gmail.com", "testPass123", testServer.URL)
+	if err != nil {
+		t.Error(err.Error())
+	}
+	data, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	var response map[string]interface{}
+	_ = json.Unmarshal(data, &response)
+	userId := response["user"].(map[string]interface{})["id"]
+	cookieData := unittesting.ExtractInfoFromResponse(resp)
+
+	resp1, err := unittesting.EmailVerifyTokenRequest(testServer.URL, userId.(string), cookieData["sAccessToken"], cookieData["sIdRefreshToken"], cookieData["antiCsrf"])
+	if err != nil {
+		t.Error(err.Error())
+	}
+	resp1.Body.Close()
+
+	wrongCode := "000000"
+	if sentCode == wrongCode {
+		wrongCode = "111111"
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = emailVerifyCodeRequest(testServer.URL, wrongCode, cookieData)
+		}()
+	}
+	wg.Wait()
+
+	result, err := emailVerifyCodeRequest(testServer.URL, sentCode, cookieData)
+	if err != nil {
+		t.Error(err.Error())
+	}
+	assert.Equal(t, "RESTART_FLOW_ERROR", result["status"])
+
+	isVerified, err := IsEmailVerified(userId.(string))
+	if err != nil {
+		t.Error(err.Error())
+	}
+	assert.False(t, isVerified)
+}
+
+func TestEmailVerificationCodeUsesTheOverriddenIsEmailVerified(t *testing.T) {
+	customAntiCsrfVal := "VIA_TOKEN"
+	codeStore := makeInMemoryEmailVerificationCodeStoreForTest()
+	codeSent := false
+	configValue := supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: "http://localhost:8080",
+		},
+		AppInfo: supertokens.AppInfo{
+			APIDomain:     "api.supertokens.io",
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{
+			Init(&epmodels.TypeInput{
+				EmailVerificationFeature: &epmodels.TypeInputEmailVerificationFeature{
+					Mode:      "CODE",
+					CodeStore: &codeStore,
+					CreateAndSendEmailWithCode: func(user epmodels.User, code string, codeLifetime uint64, userContext supertokens.UserContext) {
+						codeSent = true
+					},
+				},
+				Override: &epmodels.OverrideStruct{
+					EmailVerificationFeature: &evmodels.OverrideStruct{
+						Functions: func(originalImplementation evmodels.RecipeInterface) evmodels.RecipeInterface {
+							isEmailVerified := func(userID string, email string, userContext supertokens.UserContext) (bool, error) {
+								return true, nil
+							}
+							originalImplementation.IsEmailVerified = &isEmailVerified
+							return originalImplementation
+						},
+					},
+				},
+			}),
+			session.Init(&sessmodels.TypeInput{
+				AntiCsrf: &customAntiCsrfVal,
+			}),
+		},
+	}
+
+	BeforeEach()
+	unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+	err := supertokens.Init(configValue)
+	if err != nil {
+		t.Error(err.Error())
+	}
+	mux := http.NewServeMux()
+	testServer := httptest.NewServer(supertokens.Middleware(mux))
+	defer testServer.Close()
+
+	resp, err := unittesting.SignupRequest("test@gmail.com", "testPass123", testServer.URL)
+	if err != nil {
+		t.Error(err.Error())
+	}
+	data, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	var response map[string]interface{}
+	_ = json.Unmarshal(data, &response)
+	userId := response["user"].(map[string]interface{})["id"]
+	cookieData := unittesting.ExtractInfoFromResponse(resp)
+
+	resp1, err := unittesting.EmailVerifyTokenRequest(testServer.URL, userId.(string), cookieData["sAccessToken"], cookieData["sIdRefreshToken"], cookieData["antiCsrf"])
+	if err != nil {
+		t.Error(err.Error())
+	}
+	data, _ = io.ReadAll(resp1.Body)
+	resp1.Body.Close()
+	var result map[string]interface{}
+	_ = json.Unmarshal(data, &result)
+	assert.Equal(t, "EMAIL_ALREADY_VERIFIED_ERROR", result["status"])
+	assert.False(t, codeSent)
+}
+
+func TestEmailVerificationCodeRequiresACodeStore(t *testing.T) {
+	configValue := supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: "http://localhost:8080",
+		},
+		AppInfo: supertokens.AppInfo{
+			APIDomain:     "api.supertokens.io",
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{
+			Init(&epmodels.TypeInput{
+				EmailVerificationFeature: &epmodels.TypeInputEmailVerificationFeature{
+					Mode: "CODE",
+					CreateAndSendEmailWithCode: func(user epmodels.User, code string, codeLifetime uint64, userContext supertokens.UserContext) {
+					},
+				},
+			}),
+		},
+	}
+
+	BeforeEach()
+	unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+	err := supertokens.Init(configValue)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "CodeStore")
+}
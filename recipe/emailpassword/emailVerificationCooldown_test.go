@@ -0,0 +1,234 @@
+/*
+ * Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package emailpassword
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/epmodels"
+	"github.com/supertokens/supertokens-golang/recipe/emailverification/evmodels"
+	"github.com/supertokens/supertokens-golang/recipe/session"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+	"github.com/supertokens/supertokens-golang/test/unittesting"
+)
+
+func makeInMemoryResendCooldownStoreForTest() evmodels.ResendCooldownStore {
+	var lock sync.Mutex
+	lastSentTimes := map[string]uint64{}
+	return evmodels.ResendCooldownStore{
+		GetLastSentTime: func(userID string, email string, userContext supertokens.UserContext) (*uint64, error) {
+			lock.Lock()
+			defer lock.Unlock()
+			timeSent, ok := lastSentTimes[userID+":"+email]
+			if !ok {
+				return nil, nil
+			}
+			return &timeSent, nil
+		},
+		SetLastSentTime: func(userID string, email string, timeSent uint64, userContext supertokens.UserContext) error {
+			lock.Lock()
+			defer lock.Unlock()
+			lastSentTimes[userID+":"+email] = timeSent
+			return nil
+		},
+	}
+}
+
+func TestGenerateTokenAPIIsRateLimitedByResendCooldown(t *testing.T) {
+	customAntiCsrfVal := "VIA_TOKEN"
+	resendCooldown := uint64(60000)
+	resendCooldownStore := makeInMemoryResendCooldownStoreForTest()
+	configValue := supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: "http://localhost:8080",
+		},
+		AppInfo: supertokens.AppInfo{
+			APIDomain:     "api.supertokens.io",
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{
+			Init(&epmodels.TypeInput{
+				EmailVerificationFeature: &epmodels.TypeInputEmailVerificationFeature{
+					ResendCooldown:      &resendCooldown,
+					ResendCooldownStore: &resendCooldownStore,
+				},
+			}),
+			session.Init(&sessmodels.TypeInput{
+				AntiCsrf: &customAntiCsrfVal,
+			}),
+		},
+	}
+
+	BeforeEach()
+	unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+	err := supertokens.Init(configValue)
+	if err != nil {
+		t.Error(err.Error())
+	}
+	mux := http.NewServeMux()
+	testServer := httptest.NewServer(supertokens.Middleware(mux))
+	defer testServer.Close()
+
+	resp, err := unittesting.SignupRequest("test@gmail.com", "testPass123", testServer.URL)
+	if err != nil {
+		t.Error(err.Error())
+	}
+	data, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	var response map[string]interface{}
+	_ = json.Unmarshal(data, &response)
+	userId := response["user"].(map[string]interface{})["id"]
+	cookieData := unittesting.ExtractInfoFromResponse(resp)
+
+	resp1, err := unittesting.EmailVerifyTokenRequest(testServer.URL, userId.(string), cookieData["sAccessToken"], cookieData["sIdRefreshToken"], cookieData["antiCsrf"])
+	if err != nil {
+		t.Error(err.Error())
+	}
+	data1, _ := io.ReadAll(resp1.Body)
+	resp1.Body.Close()
+	var response1 map[string]interface{}
+	_ = json.Unmarshal(data1, &response1)
+	assert.Equal(t, "OK", response1["status"])
+
+	resp2, err := unittesting.EmailVerifyTokenRequest(testServer.URL, userId.(string), cookieData["sAccessToken"], cookieData["sIdRefreshToken"], cookieData["antiCsrf"])
+	if err != nil {
+		t.Error(err.Error())
+	}
+	data2, _ := io.ReadAll(resp2.Body)
+	resp2.Body.Close()
+	var response2 map[string]interface{}
+	_ = json.Unmarshal(data2, &response2)
+	assert.Equal(t, "TOO_MANY_REQUESTS_ERROR", response2["status"])
+	assert.Greater(t, response2["retryAfter"].(float64), float64(0))
+	assert.LessOrEqual(t, response2["retryAfter"].(float64), float64(60))
+	assert.NotEmpty(t, resp2.Header.Get("Retry-After"))
+}
+
+func TestResendCooldownRequiresAResendCooldownStore(t *testing.T) {
+	resendCooldown := uint64(60000)
+	configValue := supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: "http://localhost:8080",
+		},
+		AppInfo: supertokens.AppInfo{
+			APIDomain:     "api.supertokens.io",
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{
+			Init(&epmodels.TypeInput{
+				EmailVerificationFeature: &epmodels.TypeInputEmailVerificationFeature{
+					ResendCooldown: &resendCooldown,
+				},
+			}),
+		},
+	}
+
+	BeforeEach()
+	unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+	err := supertokens.Init(configValue)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "ResendCooldownStore")
+}
+
+func TestVerifySessionRejectsUnverifiedUsersAfterGracePeriod(t *testing.T) {
+	customAntiCsrfVal := "VIA_TOKEN"
+	gracePeriod := uint64(0)
+	configValue := supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: "http://localhost:8080",
+		},
+		AppInfo: supertokens.AppInfo{
+			APIDomain:     "api.supertokens.io",
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{
+			Init(&epmodels.TypeInput{
+				EmailVerificationFeature: &epmodels.TypeInputEmailVerificationFeature{
+					GracePeriodAfterSignUp: &gracePeriod,
+				},
+			}),
+			session.Init(&sessmodels.TypeInput{
+				AntiCsrf: &customAntiCsrfVal,
+			}),
+		},
+	}
+
+	BeforeEach()
+	unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+	err := supertokens.Init(configValue)
+	if err != nil {
+		t.Error(err.Error())
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/protected", session.VerifySession(nil, func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(200)
+	}))
+	testServer := httptest.NewServer(supertokens.Middleware(mux))
+	defer testServer.Close()
+
+	resp, err := unittesting.SignupRequest("test@gmail.com", "testPass123", testServer.URL)
+	if err != nil {
+		t.Error(err.Error())
+	}
+	data, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	var response map[string]interface{}
+	_ = json.Unmarshal(data, &response)
+	userId := response["user"].(map[string]interface{})["id"]
+	cookieData := unittesting.ExtractInfoFromResponse(resp)
+
+	protectedRequest := func() int {
+		req, err := http.NewRequest(http.MethodGet, testServer.URL+"/protected", nil)
+		if err != nil {
+			t.Error(err.Error())
+		}
+		req.Header.Add("Cookie", "sAccessToken="+cookieData["sAccessToken"]+";"+"sIdRefreshToken="+cookieData["sIdRefreshToken"])
+		req.Header.Add("anti-csrf", cookieData["antiCsrf"])
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Error(err.Error())
+		}
+		res.Body.Close()
+		return res.StatusCode
+	}
+
+	assert.Equal(t, 403, protectedRequest())
+
+	verifyToken, err := CreateEmailVerificationToken(userId.(string))
+	if err != nil {
+		t.Error(err.Error())
+	}
+	_, err = VerifyEmailUsingToken(verifyToken.OK.Token)
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	assert.Equal(t, 200, protectedRequest())
+}
@@ -39,6 +39,9 @@ type APIInterface struct {
 	PasswordResetPOST              *func(formFields []TypeFormField, token string, options APIOptions, userContext supertokens.UserContext) (ResetPasswordUsingTokenResponse, error)
 	SignInPOST                     *func(formFields []TypeFormField, options APIOptions, userContext supertokens.UserContext) (SignInPOSTResponse, error)
 	SignUpPOST                     *func(formFields []TypeFormField, options APIOptions, userContext supertokens.UserContext) (SignUpPOSTResponse, error)
+	ChangeEmailPOST                *func(formFields []TypeFormField, options APIOptions, userContext supertokens.UserContext) (ChangeEmailPOSTResponse, error)
+	VerifyEmailChangePOST          *func(token string, options APIOptions, userContext supertokens.UserContext) (ChangeEmailUsingTokenResponse, error)
+	ChangePasswordPOST             *func(formFields []TypeFormField, options APIOptions, userContext supertokens.UserContext) (ChangePasswordPOSTResponse, error)
 }
 
 type SignUpPOSTResponse struct {
@@ -64,3 +67,13 @@ type EmailExistsGETResponse struct {
 type GeneratePasswordResetTokenPOSTResponse struct {
 	OK *struct{}
 }
+
+type ChangeEmailPOSTResponse struct {
+	OK                      *struct{}
+	EmailAlreadyExistsError *struct{}
+}
+
+type ChangePasswordPOSTResponse struct {
+	OK                    *struct{}
+	WrongCredentialsError *struct{}
+}
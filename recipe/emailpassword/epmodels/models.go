@@ -24,6 +24,9 @@ type TypeNormalisedInput struct {
 	SignUpFeature                  TypeNormalisedInputSignUp
 	SignInFeature                  TypeNormalisedInputSignIn
 	ResetPasswordUsingTokenFeature TypeNormalisedInputResetPasswordUsingTokenFeature
+	ChangeEmailFeature             TypeNormalisedInputChangeEmailFeature
+	ChangePasswordFeature          TypeNormalisedInputChangePasswordFeature
+	UserImportFeature              TypeNormalisedInputUserImportFeature
 	EmailVerificationFeature       evmodels.TypeInput
 	Override                       OverrideStruct
 }
@@ -37,26 +40,69 @@ type OverrideStruct struct {
 type TypeInputEmailVerificationFeature struct {
 	GetEmailVerificationURL  func(user User, userContext supertokens.UserContext) (string, error)
 	CreateAndSendCustomEmail func(user User, emailVerificationURLWithToken string, userContext supertokens.UserContext)
+	// ResendCooldown is the minimum time in milliseconds between two verification emails sent to a user.
+	// It requires a ResendCooldownStore.
+	ResendCooldown      *uint64
+	ResendCooldownStore *evmodels.ResendCooldownStore
+	// GracePeriodAfterSignUp is the time in milliseconds after sign up after which VerifySession
+	// rejects sessions of users that have not verified their email. GetSession does not check it.
+	GracePeriodAfterSignUp *uint64
+	// Mode is either "LINK" (the default) or "CODE". In the "CODE" mode, a numeric code is sent using
+	// CreateAndSendEmailWithCode instead of a link.
+	Mode                       string
+	CreateAndSendEmailWithCode func(user User, code string, codeLifetime uint64, userContext supertokens.UserContext)
+	CodeLifetime               *uint64
+	MaximumCodeInputAttempts   *int
+	// CodeStore is required in the "CODE" mode
+	CodeStore *evmodels.EmailVerificationCodeStore
 }
 
+type FormFieldType string
+
+const (
+	FormFieldTypeString FormFieldType = "string"
+	FormFieldTypeNumber FormFieldType = "number"
+	FormFieldTypeBool   FormFieldType = "bool"
+	FormFieldTypeEnum   FormFieldType = "enum"
+)
+
 type TypeInputFormField struct {
 	ID       string
 	Validate func(value interface{}) *string
 	Optional *bool
+	// Type defaults to FormFieldTypeString. For other types, Validate is called with the parsed
+	// value (float64 for numbers, bool for booleans).
+	Type         FormFieldType
+	EnumValues   []string
+	DefaultValue *string
 }
 
 type TypeInputSignUp struct {
-	FormFields []TypeInputFormField
+	FormFields                      []TypeInputFormField
+	PersistFormFieldsInUserMetadata *bool
+	// UserMetadataStore is where the sign up form fields are saved. It is required if
+	// PersistFormFieldsInUserMetadata is set.
+	UserMetadataStore *UserMetadataStore
 }
 
 type NormalisedFormField struct {
-	ID       string
-	Validate func(value interface{}) *string
-	Optional bool
+	ID           string
+	Validate     func(value interface{}) *string
+	Optional     bool
+	Type         FormFieldType
+	EnumValues   []string
+	DefaultValue *string
 }
 
 type TypeNormalisedInputSignUp struct {
-	FormFields []NormalisedFormField
+	FormFields                      []NormalisedFormField
+	PersistFormFieldsInUserMetadata bool
+	UserMetadataStore               UserMetadataStore
+}
+
+type UserMetadataStore struct {
+	Get    func(userID string, userContext supertokens.UserContext) (map[string]interface{}, error)
+	Update func(userID string, metadata map[string]interface{}, userContext supertokens.UserContext) error
 }
 
 type TypeNormalisedInputSignIn struct {
@@ -66,24 +112,148 @@ type TypeNormalisedInputSignIn struct {
 type TypeInputResetPasswordUsingTokenFeature struct {
 	GetResetPasswordURL      func(user User, userContext supertokens.UserContext) (string, error)
 	CreateAndSendCustomEmail func(user User, passwordResetURLWithToken string, userContext supertokens.UserContext)
+	// CreateAndSendPasswordChangedNotification is called after a successful password reset. No
+	// notification is sent if it is not set.
+	CreateAndSendPasswordChangedNotification func(user User, userContext supertokens.UserContext)
+	// RevokeAllSessionsOnReset revokes all sessions of the user after a successful password reset. Defaults to false.
+	RevokeAllSessionsOnReset *bool
+	// SignInAfterReset makes PasswordResetPOST create a new session for the user. Defaults to false.
+	SignInAfterReset *bool
+	// TokenLifetime is the lifetime of reset tokens in milliseconds, and should match
+	// password_reset_token_lifetime in the core. Defaults to one hour.
+	TokenLifetime *uint64
+	// TokenStore keeps track of issued reset tokens so that they can be listed and invalidated. It must be
+	// shared by all your API instances. Once it is set, ResetPasswordUsingToken only accepts tokens that it
+	// knows about, since the core only forgets the reset tokens of a user when their password changes.
+	TokenStore *ResetPasswordTokenStore
 }
 
 type TypeNormalisedInputResetPasswordUsingTokenFeature struct {
-	GetResetPasswordURL            func(user User, userContext supertokens.UserContext) (string, error)
-	CreateAndSendCustomEmail       func(user User, passwordResetURLWithToken string, userContext supertokens.UserContext)
-	FormFieldsForGenerateTokenForm []NormalisedFormField
-	FormFieldsForPasswordResetForm []NormalisedFormField
+	GetResetPasswordURL                      func(user User, userContext supertokens.UserContext) (string, error)
+	CreateAndSendCustomEmail                 func(user User, passwordResetURLWithToken string, userContext supertokens.UserContext)
+	CreateAndSendPasswordChangedNotification func(user User, userContext supertokens.UserContext)
+	RevokeAllSessionsOnReset                 bool
+	SignInAfterReset                         bool
+	TokenLifetime                            uint64
+	TokenStore                               *ResetPasswordTokenStore
+	FormFieldsForGenerateTokenForm           []NormalisedFormField
+	FormFieldsForPasswordResetForm           []NormalisedFormField
+}
+
+type ResetPasswordTokenStore struct {
+	Save          func(tokenInfo ResetPasswordTokenInfo, userContext supertokens.UserContext) error
+	Get           func(token string, userContext supertokens.UserContext) (*ResetPasswordTokenInfo, error)
+	GetAllForUser func(userID string, userContext supertokens.UserContext) ([]ResetPasswordTokenInfo, error)
+	Remove        func(token string, userContext supertokens.UserContext) error
+}
+
+type ResetPasswordTokenInfo struct {
+	Token       string
+	UserID      string
+	TimeCreated uint64
+	Expiry      uint64
+	Invalidated bool
+}
+
+// TypeInputChangeEmailFeature enables the email change APIs and functions, which are disabled if it is not set.
+type TypeInputChangeEmailFeature struct {
+	GetEmailChangeURL        func(user User, userContext supertokens.UserContext) (string, error)
+	CreateAndSendCustomEmail func(user User, newEmail string, emailChangeURLWithToken string, userContext supertokens.UserContext)
+	// CreateAndSendEmailChangedNotification is required and is called with the user's old email
+	// once the change has been applied, so that the owner of the old address can react to it.
+	CreateAndSendEmailChangedNotification func(user User, newEmail string, userContext supertokens.UserContext)
+}
+
+type TypeNormalisedInputChangeEmailFeature struct {
+	GetEmailChangeURL                     func(user User, userContext supertokens.UserContext) (string, error)
+	CreateAndSendCustomEmail              func(user User, newEmail string, emailChangeURLWithToken string, userContext supertokens.UserContext)
+	CreateAndSendEmailChangedNotification func(user User, newEmail string, userContext supertokens.UserContext)
+	FormFieldsForChangeEmailForm          []NormalisedFormField
+}
+
+type TypeInputChangePasswordFeature struct {
+	// RevokeOtherSessions revokes all the other sessions of the user once the password is changed. It is false by default.
+	RevokeOtherSessions *bool
+}
+
+type TypeNormalisedInputChangePasswordFeature struct {
+	RevokeOtherSessions             bool
+	FormFieldsForChangePasswordForm []NormalisedFormField
+}
+
+type TypeInputUserImportFeature struct {
+	// ProgressInterval is the number of users after which the progress callback of ImportUsers is called
+	ProgressInterval *int
+	// VerifyPasswordHash is used to check a password against an imported hash that the core
+	// cannot store, the first time the user signs in. It is required for importing users.
+	VerifyPasswordHash func(password string, passwordHash ImportedPasswordHash, userContext supertokens.UserContext) (bool, error)
+	// PasswordHashStore keeps the imported hashes that the core cannot store until users sign in
+	// for the first time. It is required and must be persistent and shared by all your API instances,
+	// since these users cannot sign in without it.
+	PasswordHashStore *PasswordHashStore
+}
+
+type TypeNormalisedInputUserImportFeature struct {
+	ProgressInterval   int
+	VerifyPasswordHash func(password string, passwordHash ImportedPasswordHash, userContext supertokens.UserContext) (bool, error)
+	PasswordHashStore  PasswordHashStore
+}
+
+type PasswordHashStore struct {
+	Save   func(userID string, passwordHash ImportedPasswordHash, userContext supertokens.UserContext) error
+	Get    func(userID string, userContext supertokens.UserContext) (*ImportedPasswordHash, error)
+	Remove func(userID string, userContext supertokens.UserContext) error
+}
+
+type ImportedPasswordHash struct {
+	Hash             string                 `json:"hash"`
+	HashingAlgorithm string                 `json:"hashingAlgorithm"`
+	Metadata         map[string]interface{} `json:"metadata,omitempty"`
+}
+
+type ImportUserInput struct {
+	Email        string
+	PasswordHash ImportedPasswordHash
+}
+
+type ImportUsersProgress struct {
+	Total     int
+	Processed int
+	Imported  int
+	Failed    int
+}
+
+type ImportUserResult struct {
+	Index int
+	Email string
+	OK    *struct {
+		User User
+	}
+	EmailAlreadyExistsError *struct{}
+	InvalidInputError       *struct {
+		Msg string
+	}
+}
+
+type ImportUsersResponse struct {
+	Results  []ImportUserResult
+	Imported int
+	Failed   int
 }
 
 type User struct {
-	ID         string `json:"id"`
-	Email      string `json:"email"`
-	TimeJoined uint64 `json:"timejoined"`
+	ID         string                 `json:"id"`
+	Email      string                 `json:"email"`
+	TimeJoined uint64                 `json:"timejoined"`
+	Metadata   map[string]interface{} `json:"metadata,omitempty"`
 }
 
 type TypeInput struct {
 	SignUpFeature                  *TypeInputSignUp
 	ResetPasswordUsingTokenFeature *TypeInputResetPasswordUsingTokenFeature
+	ChangeEmailFeature             *TypeInputChangeEmailFeature
+	ChangePasswordFeature          *TypeInputChangePasswordFeature
+	UserImportFeature              *TypeInputUserImportFeature
 	EmailVerificationFeature       *TypeInputEmailVerificationFeature
 	Override                       *OverrideStruct
 }
@@ -25,6 +25,8 @@ type RecipeInterface struct {
 	CreateResetPasswordToken *func(userID string, userContext supertokens.UserContext) (CreateResetPasswordTokenResponse, error)
 	ResetPasswordUsingToken  *func(token string, newPassword string, userContext supertokens.UserContext) (ResetPasswordUsingTokenResponse, error)
 	UpdateEmailOrPassword    *func(userId string, email *string, password *string, userContext supertokens.UserContext) (UpdateEmailOrPasswordResponse, error)
+	CreateEmailChangeToken   *func(userID string, newEmail string, userContext supertokens.UserContext) (CreateEmailChangeTokenResponse, error)
+	ChangeEmailUsingToken    *func(token string, userContext supertokens.UserContext) (ChangeEmailUsingTokenResponse, error)
 }
 
 type SignUpResponse struct {
@@ -60,3 +62,19 @@ type UpdateEmailOrPasswordResponse struct {
 	UnknownUserIdError      *struct{}
 	EmailAlreadyExistsError *struct{}
 }
+
+type CreateEmailChangeTokenResponse struct {
+	OK *struct {
+		Token string
+	}
+	UnknownUserIdError      *struct{}
+	EmailAlreadyExistsError *struct{}
+}
+
+type ChangeEmailUsingTokenResponse struct {
+	OK *struct {
+		User User
+	}
+	EmailChangeInvalidTokenError *struct{}
+	EmailAlreadyExistsError      *struct{}
+}
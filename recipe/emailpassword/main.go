@@ -83,6 +83,61 @@ func UpdateEmailOrPasswordWithContext(userId string, email *string, password *st
 	return (*instance.RecipeImpl.UpdateEmailOrPassword)(userId, email, password, userContext)
 }
 
+func CreateEmailChangeTokenWithContext(userID string, newEmail string, userContext supertokens.UserContext) (epmodels.CreateEmailChangeTokenResponse, error) {
+	instance, err := getRecipeInstanceOrThrowError()
+	if err != nil {
+		return epmodels.CreateEmailChangeTokenResponse{}, err
+	}
+	return (*instance.RecipeImpl.CreateEmailChangeToken)(userID, newEmail, userContext)
+}
+
+func ChangeEmailUsingTokenWithContext(token string, userContext supertokens.UserContext) (epmodels.ChangeEmailUsingTokenResponse, error) {
+	instance, err := getRecipeInstanceOrThrowError()
+	if err != nil {
+		return epmodels.ChangeEmailUsingTokenResponse{}, err
+	}
+	return (*instance.RecipeImpl.ChangeEmailUsingToken)(token, userContext)
+}
+
+// ImportUsersWithContext creates users with password hashes from another system. Hashes that the core
+// cannot store are kept in UserImportFeature.PasswordHashStore and replaced by one created by the core
+// the first time the user signs in.
+func ImportUsersWithContext(users []epmodels.ImportUserInput, onProgress func(progress epmodels.ImportUsersProgress), userContext supertokens.UserContext) (epmodels.ImportUsersResponse, error) {
+	instance, err := getRecipeInstanceOrThrowError()
+	if err != nil {
+		return epmodels.ImportUsersResponse{}, err
+	}
+	return instance.importUsers(users, onProgress, userContext)
+}
+
+// GetResetPasswordTokensForUserWithContext returns the reset tokens of a user that have not been
+// used, invalidated or expired yet. It needs ResetPasswordUsingTokenFeature.TokenStore.
+func GetResetPasswordTokensForUserWithContext(userID string, userContext supertokens.UserContext) ([]epmodels.ResetPasswordTokenInfo, error) {
+	instance, err := getRecipeInstanceOrThrowError()
+	if err != nil {
+		return nil, err
+	}
+	return instance.getResetPasswordTokensForUser(userID, userContext)
+}
+
+// InvalidateResetPasswordTokenWithContext makes ResetPasswordUsingToken reject a token of the token
+// store. It returns false if the token is unknown or already invalidated.
+func InvalidateResetPasswordTokenWithContext(token string, userContext supertokens.UserContext) (bool, error) {
+	instance, err := getRecipeInstanceOrThrowError()
+	if err != nil {
+		return false, err
+	}
+	return instance.invalidateResetPasswordToken(token, userContext)
+}
+
+func InvalidateAllResetPasswordTokensForUserWithContext(userID string, userContext supertokens.UserContext) error {
+	instance, err := getRecipeInstanceOrThrowError()
+	if err != nil {
+		return err
+	}
+	return instance.invalidateAllResetPasswordTokensForUser(userID, userContext)
+}
+
 func CreateEmailVerificationTokenWithContext(userID string, userContext supertokens.UserContext) (evmodels.CreateEmailVerificationTokenResponse, error) {
 	instance, err := getRecipeInstanceOrThrowError()
 	if err != nil {
@@ -174,6 +229,30 @@ func UpdateEmailOrPassword(userId string, email *string, password *string) (epmo
 	return UpdateEmailOrPasswordWithContext(userId, email, password, &map[string]interface{}{})
 }
 
+func CreateEmailChangeToken(userID string, newEmail string) (epmodels.CreateEmailChangeTokenResponse, error) {
+	return CreateEmailChangeTokenWithContext(userID, newEmail, &map[string]interface{}{})
+}
+
+func ChangeEmailUsingToken(token string) (epmodels.ChangeEmailUsingTokenResponse, error) {
+	return ChangeEmailUsingTokenWithContext(token, &map[string]interface{}{})
+}
+
+func ImportUsers(users []epmodels.ImportUserInput, onProgress func(progress epmodels.ImportUsersProgress)) (epmodels.ImportUsersResponse, error) {
+	return ImportUsersWithContext(users, onProgress, &map[string]interface{}{})
+}
+
+func GetResetPasswordTokensForUser(userID string) ([]epmodels.ResetPasswordTokenInfo, error) {
+	return GetResetPasswordTokensForUserWithContext(userID, &map[string]interface{}{})
+}
+
+func InvalidateResetPasswordToken(token string) (bool, error) {
+	return InvalidateResetPasswordTokenWithContext(token, &map[string]interface{}{})
+}
+
+func InvalidateAllResetPasswordTokensForUser(userID string) error {
+	return InvalidateAllResetPasswordTokensForUserWithContext(userID, &map[string]interface{}{})
+}
+
 func CreateEmailVerificationToken(userID string) (evmodels.CreateEmailVerificationTokenResponse, error) {
 	return CreateEmailVerificationTokenWithContext(userID, &map[string]interface{}{})
 }
@@ -47,10 +47,18 @@ func MakeRecipe(recipeId string, appInfo supertokens.NormalisedAppinfo, config *
 	if err != nil {
 		return Recipe{}, err
 	}
-	verifiedConfig := validateAndNormaliseUserInput(r, appInfo, config)
+	verifiedConfig, err := validateAndNormaliseUserInput(r, appInfo, config)
+	if err != nil {
+		return Recipe{}, err
+	}
 	r.Config = verifiedConfig
 	r.APIImpl = verifiedConfig.Override.APIs(api.MakeAPIImplementation())
-	r.RecipeImpl = verifiedConfig.Override.Functions(MakeRecipeImplementation(*querierInstance))
+	recipeImplementation := MakeRecipeImplementation(*querierInstance)
+	recipeImplementation = makeResetPasswordTokenRecipeImplementation(recipeImplementation, verifiedConfig.ResetPasswordUsingTokenFeature)
+	recipeImplementation = makeUserImportRecipeImplementation(recipeImplementation, verifiedConfig.UserImportFeature)
+	recipeImplementation = makeUserMetadataRecipeImplementation(recipeImplementation, verifiedConfig.SignUpFeature)
+	recipeImplementation = makeChangeEmailRecipeImplementation(recipeImplementation, r)
+	r.RecipeImpl = verifiedConfig.Override.Functions(recipeImplementation)
 
 	if emailVerificationInstance == nil {
 		emailVerificationRecipe, err := emailverification.MakeRecipe(recipeId, appInfo, verifiedConfig.EmailVerificationFeature, onGeneralError)
@@ -110,6 +118,18 @@ func (r *Recipe) getAPIsHandled() ([]supertokens.APIHandled, error) {
 	if err != nil {
 		return nil, err
 	}
+	changeEmailAPI, err := supertokens.NewNormalisedURLPath(constants.ChangeEmailAPI)
+	if err != nil {
+		return nil, err
+	}
+	changeEmailVerifyAPI, err := supertokens.NewNormalisedURLPath(constants.ChangeEmailVerifyAPI)
+	if err != nil {
+		return nil, err
+	}
+	changePasswordAPI, err := supertokens.NewNormalisedURLPath(constants.ChangePasswordAPI)
+	if err != nil {
+		return nil, err
+	}
 	emailverificationAPIhandled, err := r.EmailVerificationRecipe.RecipeModule.GetAPIsHandled()
 	if err != nil {
 		return nil, err
@@ -139,6 +159,21 @@ func (r *Recipe) getAPIsHandled() ([]supertokens.APIHandled, error) {
 		PathWithoutAPIBasePath: signupEmailExistsAPI,
 		ID:                     constants.SignupEmailExistsAPI,
 		Disabled:               r.APIImpl.EmailExistsGET == nil,
+	}, {
+		Method:                 http.MethodPost,
+		PathWithoutAPIBasePath: changeEmailAPI,
+		ID:                     constants.ChangeEmailAPI,
+		Disabled:               r.APIImpl.ChangeEmailPOST == nil || r.Config.ChangeEmailFeature.CreateAndSendEmailChangedNotification == nil,
+	}, {
+		Method:                 http.MethodPost,
+		PathWithoutAPIBasePath: changeEmailVerifyAPI,
+		ID:                     constants.ChangeEmailVerifyAPI,
+		Disabled:               r.APIImpl.VerifyEmailChangePOST == nil || r.Config.ChangeEmailFeature.CreateAndSendEmailChangedNotification == nil,
+	}, {
+		Method:                 http.MethodPost,
+		PathWithoutAPIBasePath: changePasswordAPI,
+		ID:                     constants.ChangePasswordAPI,
+		Disabled:               r.APIImpl.ChangePasswordPOST == nil,
 	}}, emailverificationAPIhandled...), nil
 }
 
@@ -162,6 +197,12 @@ func (r *Recipe) handleAPIRequest(id string, req *http.Request, res http.Respons
 		return api.PasswordReset(r.APIImpl, options)
 	} else if id == constants.SignupEmailExistsAPI {
 		return api.EmailExists(r.APIImpl, options)
+	} else if id == constants.ChangeEmailAPI {
+		return api.ChangeEmail(r.APIImpl, options)
+	} else if id == constants.ChangeEmailVerifyAPI {
+		return api.VerifyEmailChange(r.APIImpl, options)
+	} else if id == constants.ChangePasswordAPI {
+		return api.ChangePassword(r.APIImpl, options)
 	}
 	return r.EmailVerificationRecipe.RecipeModule.HandleAPIRequest(id, req, res, theirHandler, path, method)
 }
@@ -192,6 +233,17 @@ func (r *Recipe) getEmailForUserId(userID string, userContext supertokens.UserCo
 	return userInfo.Email, nil
 }
 
+func (r *Recipe) getTimeJoinedForUserId(userID string, userContext supertokens.UserContext) (*uint64, error) {
+	userInfo, err := (*r.RecipeImpl.GetUserByID)(userID, userContext)
+	if err != nil {
+		return nil, err
+	}
+	if userInfo == nil {
+		return nil, nil
+	}
+	return &userInfo.TimeJoined, nil
+}
+
 func ResetForTest() {
 	singletonInstance = nil
 }
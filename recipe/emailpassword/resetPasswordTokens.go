@@ -0,0 +1,257 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package emailpassword
+
+import (
+	"errors"
+	"time"
+
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/epmodels"
+	"github.com/supertokens/supertokens-golang/recipe/session"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// same as the default password_reset_token_lifetime of the core
+const defaultResetPasswordTokenLifetime uint64 = 3600000
+
+func getCurrTimeInMS() uint64 {
+	return uint64(time.Now().UnixNano() / 1000000)
+}
+
+// makeResetPasswordTokenRecipeImplementation makes password changes invalidate the reset tokens
+// of the user in the core, keeps track of the reset tokens in the token store (if there is one)
+// and applies the post reset policy once a password has been reset.
+func makeResetPasswordTokenRecipeImplementation(originalImplementation epmodels.RecipeInterface, config epmodels.TypeNormalisedInputResetPasswordUsingTokenFeature) epmodels.RecipeInterface {
+	originalCreateResetPasswordToken := *originalImplementation.CreateResetPasswordToken
+	originalResetPasswordUsingToken := *originalImplementation.ResetPasswordUsingToken
+	originalUpdateEmailOrPassword := *originalImplementation.UpdateEmailOrPassword
+
+	createResetPasswordToken := func(userID string, userContext supertokens.UserContext) (epmodels.CreateResetPasswordTokenResponse, error) {
+		response, err := originalCreateResetPasswordToken(userID, userContext)
+		if err != nil {
+			return epmodels.CreateResetPasswordTokenResponse{}, err
+		}
+		if response.OK != nil && config.TokenStore != nil {
+			now := getCurrTimeInMS()
+			err = config.TokenStore.Save(epmodels.ResetPasswordTokenInfo{
+				Token:       response.OK.Token,
+				UserID:      userID,
+				TimeCreated: now,
+				Expiry:      now + config.TokenLifetime,
+				Invalidated: false,
+			}, userContext)
+			if err != nil {
+				return epmodels.CreateResetPasswordTokenResponse{}, err
+			}
+		}
+		return response, nil
+	}
+
+	resetPasswordUsingToken := func(token, newPassword string, userContext supertokens.UserContext) (epmodels.ResetPasswordUsingTokenResponse, error) {
+		var tokenInfo *epmodels.ResetPasswordTokenInfo
+		if config.TokenStore != nil {
+			var err error
+			tokenInfo, err = config.TokenStore.Get(token, userContext)
+			if err != nil {
+				return epmodels.ResetPasswordUsingTokenResponse{}, err
+			}
+			// the core only forgets tokens once one of them is used, so it would
+			// still accept the ones that were invalidated using the token store
+			if tokenInfo == nil || tokenInfo.Invalidated || tokenInfo.Expiry <= getCurrTimeInMS() {
+				return epmodels.ResetPasswordUsingTokenResponse{
+					ResetPasswordInvalidTokenError: &struct{}{},
+				}, nil
+			}
+		}
+
+		response, err := originalResetPasswordUsingToken(token, newPassword, userContext)
+		if err != nil {
+			return epmodels.ResetPasswordUsingTokenResponse{}, err
+		}
+		if response.OK == nil {
+			return response, nil
+		}
+
+		userID := response.OK.UserId
+		if userID == nil && tokenInfo != nil {
+			// the core only returns the user ID for CDI >= 2.12
+			userID = &tokenInfo.UserID
+			response.OK.UserId = userID
+		}
+		if userID == nil {
+			if config.TokenStore != nil {
+				err = config.TokenStore.Remove(token, userContext)
+				if err != nil {
+					return epmodels.ResetPasswordUsingTokenResponse{}, err
+				}
+			}
+			return response, nil
+		}
+
+		// the core deletes all reset tokens of the user once one of them is used
+		if config.TokenStore != nil {
+			err = removeResetPasswordTokensForUser(*config.TokenStore, *userID, userContext)
+			if err != nil {
+				return epmodels.ResetPasswordUsingTokenResponse{}, err
+			}
+		}
+
+		if config.RevokeAllSessionsOnReset {
+			_, err = session.RevokeAllSessionsForUserWithContext(*userID, userContext)
+			if err != nil {
+				return epmodels.ResetPasswordUsingTokenResponse{}, err
+			}
+		}
+
+		if config.CreateAndSendPasswordChangedNotification != nil {
+			user, err := (*originalImplementation.GetUserByID)(*userID, userContext)
+			if err != nil {
+				return epmodels.ResetPasswordUsingTokenResponse{}, err
+			}
+			if user != nil {
+				config.CreateAndSendPasswordChangedNotification(*user, userContext)
+			}
+		}
+
+		return response, nil
+	}
+
+	updateEmailOrPassword := func(userId string, email, password *string, userContext supertokens.UserContext) (epmodels.UpdateEmailOrPasswordResponse, error) {
+		if password == nil {
+			return originalUpdateEmailOrPassword(userId, email, password, userContext)
+		}
+		if email != nil {
+			response, err := originalUpdateEmailOrPassword(userId, email, nil, userContext)
+			if err != nil || response.OK == nil {
+				return response, err
+			}
+		}
+
+		// reset tokens created for the old password must not be usable anymore. The core
+		// deletes all reset tokens of a user once one of them is used, so we set the new
+		// password by using a new token instead of updating it.
+		tokenResponse, err := originalCreateResetPasswordToken(userId, userContext)
+		if err != nil {
+			return epmodels.UpdateEmailOrPasswordResponse{}, err
+		}
+		if tokenResponse.UnknownUserIdError != nil {
+			return epmodels.UpdateEmailOrPasswordResponse{
+				UnknownUserIdError: &struct{}{},
+			}, nil
+		}
+		resetResponse, err := originalResetPasswordUsingToken(tokenResponse.OK.Token, *password, userContext)
+		if err != nil {
+			return epmodels.UpdateEmailOrPasswordResponse{}, err
+		}
+		if resetResponse.ResetPasswordInvalidTokenError != nil {
+			return epmodels.UpdateEmailOrPasswordResponse{}, errors.New("should never come here")
+		}
+		if config.TokenStore != nil {
+			err = removeResetPasswordTokensForUser(*config.TokenStore, userId, userContext)
+			if err != nil {
+				return epmodels.UpdateEmailOrPasswordResponse{}, err
+			}
+		}
+		return epmodels.UpdateEmailOrPasswordResponse{
+			OK: &struct{}{},
+		}, nil
+	}
+
+	originalImplementation.CreateResetPasswordToken = &createResetPasswordToken
+	originalImplementation.ResetPasswordUsingToken = &resetPasswordUsingToken
+	originalImplementation.UpdateEmailOrPassword = &updateEmailOrPassword
+	return originalImplementation
+}
+
+func removeResetPasswordTokensForUser(store epmodels.ResetPasswordTokenStore, userID string, userContext supertokens.UserContext) error {
+	tokens, err := store.GetAllForUser(userID, userContext)
+	if err != nil {
+		return err
+	}
+	for _, tokenInfo := range tokens {
+		err = store.Remove(tokenInfo.Token, userContext)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Recipe) getResetPasswordTokenStoreOrThrowError() (epmodels.ResetPasswordTokenStore, error) {
+	store := r.Config.ResetPasswordUsingTokenFeature.TokenStore
+	if store == nil {
+		return epmodels.ResetPasswordTokenStore{}, errors.New("please provide ResetPasswordUsingTokenFeature.TokenStore in the emailpassword config to manage reset password tokens")
+	}
+	return *store, nil
+}
+
+func (r *Recipe) getResetPasswordTokensForUser(userID string, userContext supertokens.UserContext) ([]epmodels.ResetPasswordTokenInfo, error) {
+	store, err := r.getResetPasswordTokenStoreOrThrowError()
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := store.GetAllForUser(userID, userContext)
+	if err != nil {
+		return nil, err
+	}
+	now := getCurrTimeInMS()
+	result := []epmodels.ResetPasswordTokenInfo{}
+	for _, tokenInfo := range tokens {
+		if !tokenInfo.Invalidated && tokenInfo.Expiry > now {
+			result = append(result, tokenInfo)
+		}
+	}
+	return result, nil
+}
+
+func (r *Recipe) invalidateResetPasswordToken(token string, userContext supertokens.UserContext) (bool, error) {
+	store, err := r.getResetPasswordTokenStoreOrThrowError()
+	if err != nil {
+		return false, err
+	}
+	tokenInfo, err := store.Get(token, userContext)
+	if err != nil {
+		return false, err
+	}
+	if tokenInfo == nil || tokenInfo.Invalidated {
+		return false, nil
+	}
+	// invalidated tokens are kept until they expire, since the core still knows about them
+	tokenInfo.Invalidated = true
+	return true, store.Save(*tokenInfo, userContext)
+}
+
+func (r *Recipe) invalidateAllResetPasswordTokensForUser(userID string, userContext supertokens.UserContext) error {
+	store, err := r.getResetPasswordTokenStoreOrThrowError()
+	if err != nil {
+		return err
+	}
+	tokens, err := store.GetAllForUser(userID, userContext)
+	if err != nil {
+		return err
+	}
+	for _, tokenInfo := range tokens {
+		if tokenInfo.Invalidated {
+			continue
+		}
+		tokenInfo.Invalidated = true
+		err = store.Save(tokenInfo, userContext)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
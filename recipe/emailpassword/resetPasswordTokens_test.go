@@ -0,0 +1,283 @@
+/*
+ * Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package emailpassword
+
+import (
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/epmodels"
+	"github.com/supertokens/supertokens-golang/recipe/session"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+	"github.com/supertokens/supertokens-golang/test/unittesting"
+)
+
+func makeInMemoryResetPasswordTokenStoreForTest() epmodels.ResetPasswordTokenStore {
+	var lock sync.Mutex
+	tokens := map[string]epmodels.ResetPasswordTokenInfo{}
+	return epmodels.ResetPasswordTokenStore{
+		Save: func(tokenInfo epmodels.ResetPasswordTokenInfo, userContext supertokens.UserContext) error {
+			lock.Lock()
+			defer lock.Unlock()
+			tokens[tokenInfo.Token] = tokenInfo
+			return nil
+		},
+		Get: func(token string, userContext supertokens.UserContext) (*epmodels.ResetPasswordTokenInfo, error) {
+			lock.Lock()
+			defer lock.Unlock()
+			tokenInfo, ok := tokens[token]
+			if !ok {
+				return nil, nil
+			}
+			return &tokenInfo, nil
+		},
+		GetAllForUser: func(userID string, userContext supertokens.UserContext) ([]epmodels.ResetPasswordTokenInfo, error) {
+			lock.Lock()
+			defer lock.Unlock()
+			result := []epmodels.ResetPasswordTokenInfo{}
+			for _, tokenInfo := range tokens {
+				if tokenInfo.UserID == userID {
+					result = append(result, tokenInfo)
+				}
+			}
+			return result, nil
+		},
+		Remove: func(token string, userContext supertokens.UserContext) error {
+			lock.Lock()
+			defer lock.Unlock()
+			delete(tokens, token)
+			return nil
+		},
+	}
+}
+
+func TestInvalidatedAndOutdatedResetPasswordTokensAreRejected(t *testing.T) {
+	tokenStore := makeInMemoryResetPasswordTokenStoreForTest()
+	configValue := supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: "http://localhost:8080",
+		},
+		AppInfo: supertokens.AppInfo{
+			APIDomain:     "api.supertokens.io",
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{
+			Init(&epmodels.TypeInput{
+				ResetPasswordUsingTokenFeature: &epmodels.TypeInputResetPasswordUsingTokenFeature{
+					TokenStore: &tokenStore,
+				},
+			}),
+		},
+	}
+
+	BeforeEach()
+	unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+	err := supertokens.Init(configValue)
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	signUpResponse, err := SignUp("random@gmail.com", "validpass123")
+	if err != nil {
+		t.Error(err.Error())
+	}
+	userID := signUpResponse.OK.User.ID
+
+	token1, err := CreateResetPasswordToken(userID)
+	if err != nil {
+		t.Error(err.Error())
+	}
+	token2, err := CreateResetPasswordToken(userID)
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	tokens, err := GetResetPasswordTokensForUser(userID)
+	if err != nil {
+		t.Error(err.Error())
+	}
+	assert.Equal(t, 2, len(tokens))
+
+	invalidated, err := InvalidateResetPasswordToken(token1.OK.Token)
+	if err != nil {
+		t.Error(err.Error())
+	}
+	assert.True(t, invalidated)
+
+	tokens, err = GetResetPasswordTokensForUser(userID)
+	if err != nil {
+		t.Error(err.Error())
+	}
+	assert.Equal(t, 1, len(tokens))
+	assert.Equal(t, token2.OK.Token, tokens[0].Token)
+
+	resetResponse, err := ResetPasswordUsingToken(token1.OK.Token, "newvalidpass123")
+	if err != nil {
+		t.Error(err.Error())
+	}
+	assert.NotNil(t, resetResponse.ResetPasswordInvalidTokenError)
+
+	newPassword := "newvalidpass123"
+	updateResponse, err := UpdateEmailOrPassword(userID, nil, &newPassword)
+	if err != nil {
+		t.Error(err.Error())
+	}
+	assert.NotNil(t, updateResponse.OK)
+
+	tokens, err = GetResetPasswordTokensForUser(userID)
+	if err != nil {
+		t.Error(err.Error())
+	}
+	assert.Equal(t, 0, len(tokens))
+
+	resetResponse, err = ResetPasswordUsingToken(token2.OK.Token, "anothervalidpass123")
+	if err != nil {
+		t.Error(err.Error())
+	}
+	assert.NotNil(t, resetResponse.ResetPasswordInvalidTokenError)
+}
+
+func TestPostResetPolicyRevokesSessionsAndNotifiesUser(t *testing.T) {
+	True := true
+	var notifiedUser *epmodels.User
+	configValue := supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: "http://localhost:8080",
+		},
+		AppInfo: supertokens.AppInfo{
+			APIDomain:     "api.supertokens.io",
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{
+			Init(&epmodels.TypeInput{
+				ResetPasswordUsingTokenFeature: &epmodels.TypeInputResetPasswordUsingTokenFeature{
+					RevokeAllSessionsOnReset: &True,
+					CreateAndSendPasswordChangedNotification: func(user epmodels.User, userContext supertokens.UserContext) {
+						notifiedUser = &user
+					},
+				},
+			}),
+			session.Init(&sessmodels.TypeInput{}),
+		},
+	}
+
+	BeforeEach()
+	unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+	err := supertokens.Init(configValue)
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	signUpResponse, err := SignUp("random@gmail.com", "validpass123")
+	if err != nil {
+		t.Error(err.Error())
+	}
+	userID := signUpResponse.OK.User.ID
+
+	_, err = session.CreateNewSession(httptest.NewRecorder(), userID, map[string]interface{}{}, map[string]interface{}{})
+	if err != nil {
+		t.Error(err.Error())
+	}
+	sessionHandles, err := session.GetAllSessionHandlesForUser(userID)
+	if err != nil {
+		t.Error(err.Error())
+	}
+	assert.Equal(t, 1, len(sessionHandles))
+
+	token, err := CreateResetPasswordToken(userID)
+	if err != nil {
+		t.Error(err.Error())
+	}
+	resetResponse, err := ResetPasswordUsingToken(token.OK.Token, "newvalidpass123")
+	if err != nil {
+		t.Error(err.Error())
+	}
+	assert.NotNil(t, resetResponse.OK)
+	assert.Equal(t, userID, *resetResponse.OK.UserId)
+
+	sessionHandles, err = session.GetAllSessionHandlesForUser(userID)
+	if err != nil {
+		t.Error(err.Error())
+	}
+	assert.Equal(t, 0, len(sessionHandles))
+	assert.NotNil(t, notifiedUser)
+	assert.Equal(t, "random@gmail.com", notifiedUser.Email)
+}
+
+func TestUpdatingThePasswordInvalidatesResetPasswordTokensInTheCore(t *testing.T) {
+	configValue := supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: "http://localhost:8080",
+		},
+		AppInfo: supertokens.AppInfo{
+			APIDomain:     "api.supertokens.io",
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{
+			Init(nil),
+		},
+	}
+
+	BeforeEach()
+	unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+	err := supertokens.Init(configValue)
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	signUpResponse, err := SignUp("random@gmail.com", "validpass123")
+	if err != nil {
+		t.Error(err.Error())
+	}
+	userID := signUpResponse.OK.User.ID
+
+	token, err := CreateResetPasswordToken(userID)
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	newPassword := "newvalidpass123"
+	updateResponse, err := UpdateEmailOrPassword(userID, nil, &newPassword)
+	if err != nil {
+		t.Error(err.Error())
+	}
+	assert.NotNil(t, updateResponse.OK)
+
+	signInResponse, err := SignIn("random@gmail.com", newPassword)
+	if err != nil {
+		t.Error(err.Error())
+	}
+	assert.NotNil(t, signInResponse.OK)
+
+	resetResponse, err := ResetPasswordUsingToken(token.OK.Token, "anothervalidpass123")
+	if err != nil {
+		t.Error(err.Error())
+	}
+	assert.NotNil(t, resetResponse.ResetPasswordInvalidTokenError)
+
+	_, err = GetResetPasswordTokensForUser(userID)
+	assert.NotNil(t, err)
+}
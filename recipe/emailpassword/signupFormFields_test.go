@@ -0,0 +1,305 @@
+/*
+ * Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package emailpassword
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/epmodels"
+	"github.com/supertokens/supertokens-golang/recipe/session"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+	"github.com/supertokens/supertokens-golang/test/unittesting"
+)
+
+func signUpRequestWithFormFields(testUrl string, formFields []map[string]interface{}) (map[string]interface{}, error) {
+	postBody, err := json.Marshal(map[string]interface{}{
+		"formFields": formFields,
+	})
+	if err != nil {
+		return nil, err
+	}
+	res, err := http.Post(testUrl+"/auth/signup", "application/json", bytes.NewBuffer(postBody))
+	if err != nil {
+		return nil, err
+	}
+	dataInBytes, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	res.Body.Close()
+	var result map[string]interface{}
+	err = json.Unmarshal(dataInBytes, &result)
+	return result, err
+}
+
+func makeInMemoryUserMetadataStoreForTest() epmodels.UserMetadataStore {
+	var lock sync.Mutex
+	userMetadata := map[string]map[string]interface{}{}
+	return epmodels.UserMetadataStore{
+		Get: func(userID string, userContext supertokens.UserContext) (map[string]interface{}, error) {
+			lock.Lock()
+			defer lock.Unlock()
+			result := map[string]interface{}{}
+			for key, value := range userMetadata[userID] {
+				result[key] = value
+			}
+			return result, nil
+		},
+		Update: func(userID string, metadata map[string]interface{}, userContext supertokens.UserContext) error {
+			lock.Lock()
+			defer lock.Unlock()
+			if userMetadata[userID] == nil {
+				userMetadata[userID] = map[string]interface{}{}
+			}
+			for key, value := range metadata {
+				userMetadata[userID][key] = value
+			}
+			return nil
+		},
+	}
+}
+
+func typedFormFieldsConfig() *epmodels.TypeInput {
+	True := true
+	defaultPlan := "free"
+	userMetadataStore := makeInMemoryUserMetadataStoreForTest()
+	return &epmodels.TypeInput{
+		SignUpFeature: &epmodels.TypeInputSignUp{
+			PersistFormFieldsInUserMetadata: &True,
+			UserMetadataStore:               &userMetadataStore,
+			FormFields: []epmodels.TypeInputFormField{
+				{
+					ID:   "age",
+					Type: epmodels.FormFieldTypeNumber,
+					Validate: func(value interface{}) *string {
+						if value.(float64) < 18 {
+							msg := "You must be over 18 to register"
+							return &msg
+						}
+						return nil
+					},
+				},
+				{
+					ID:       "newsletter",
+					Type:     epmodels.FormFieldTypeBool,
+					Optional: &True,
+				},
+				{
+					ID:           "plan",
+					Type:         epmodels.FormFieldTypeEnum,
+					EnumValues:   []string{"free", "pro"},
+					DefaultValue: &defaultPlan,
+				},
+			},
+		},
+	}
+}
+
+func TestSignUpWithTypedFormFieldsPersistsUserMetadata(t *testing.T) {
+	configValue := supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: "http://localhost:8080",
+		},
+		AppInfo: supertokens.AppInfo{
+			APIDomain:     "api.supertokens.io",
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{
+			Init(typedFormFieldsConfig()),
+			session.Init(&sessmodels.TypeInput{}),
+		},
+	}
+
+	BeforeEach()
+	unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+	err := supertokens.Init(configValue)
+	if err != nil {
+		t.Error(err.Error())
+	}
+	mux := http.NewServeMux()
+	testServer := httptest.NewServer(supertokens.Middleware(mux))
+	defer testServer.Close()
+
+	result, err := signUpRequestWithFormFields(testServer.URL, []map[string]interface{}{
+		{"id": "email", "value": "random@gmail.com"},
+		{"id": "password", "value": "validpass123"},
+		{"id": "age", "value": 21},
+		{"id": "newsletter", "value": true},
+	})
+	if err != nil {
+		t.Error(err.Error())
+	}
+	assert.Equal(t, "OK", result["status"])
+	metadata := result["user"].(map[string]interface{})["metadata"].(map[string]interface{})
+	assert.Equal(t, float64(21), metadata["age"])
+	assert.Equal(t, true, metadata["newsletter"])
+	assert.Equal(t, "free", metadata["plan"])
+
+	user, err := GetUserByEmail("random@gmail.com")
+	if err != nil {
+		t.Error(err.Error())
+	}
+	assert.Equal(t, float64(21), user.Metadata["age"])
+	assert.Equal(t, true, user.Metadata["newsletter"])
+	assert.Equal(t, "free", user.Metadata["plan"])
+}
+
+func TestSignUpWithInvalidTypedFormFieldsReturnsFieldError(t *testing.T) {
+	configValue := supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: "http://localhost:8080",
+		},
+		AppInfo: supertokens.AppInfo{
+			APIDomain:     "api.supertokens.io",
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{
+			Init(typedFormFieldsConfig()),
+			session.Init(&sessmodels.TypeInput{}),
+		},
+	}
+
+	BeforeEach()
+	unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+	err := supertokens.Init(configValue)
+	if err != nil {
+		t.Error(err.Error())
+	}
+	mux := http.NewServeMux()
+	testServer := httptest.NewServer(supertokens.Middleware(mux))
+	defer testServer.Close()
+
+	result, err := signUpRequestWithFormFields(testServer.URL, []map[string]interface{}{
+		{"id": "email", "value": "random@gmail.com"},
+		{"id": "password", "value": "validpass123"},
+		{"id": "age", "value": "twenty"},
+		{"id": "newsletter", "value": "maybe"},
+		{"id": "plan", "value": "enterprise"},
+	})
+	if err != nil {
+		t.Error(err.Error())
+	}
+	assert.Equal(t, "FIELD_ERROR", result["status"])
+	formFields := result["formFields"].([]interface{})
+	assert.Equal(t, 3, len(formFields))
+	errorMessages := map[string]string{}
+	for _, formField := range formFields {
+		field := formField.(map[string]interface{})
+		errorMessages[field["id"].(string)] = field["error"].(string)
+	}
+	assert.Equal(t, "Field must be a number", errorMessages["age"])
+	assert.Equal(t, "Field must be true or false", errorMessages["newsletter"])
+	assert.Equal(t, "Field must be one of: free, pro", errorMessages["plan"])
+}
+
+func TestSignUpPOSTSavesUserMetadataWhenSignUpIsOverridden(t *testing.T) {
+	config := typedFormFieldsConfig()
+	config.Override = &epmodels.OverrideStruct{
+		Functions: func(originalImplementation epmodels.RecipeInterface) epmodels.RecipeInterface {
+			originalSignUp := *originalImplementation.SignUp
+			signUp := func(email, password string, userContext supertokens.UserContext) (epmodels.SignUpResponse, error) {
+				// the user context of the API is not passed on
+				return originalSignUp(email, password, &map[string]interface{}{})
+			}
+			originalImplementation.SignUp = &signUp
+			return originalImplementation
+		},
+	}
+	configValue := supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: "http://localhost:8080",
+		},
+		AppInfo: supertokens.AppInfo{
+			APIDomain:     "api.supertokens.io",
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{
+			Init(config),
+			session.Init(&sessmodels.TypeInput{}),
+		},
+	}
+
+	BeforeEach()
+	unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+	err := supertokens.Init(configValue)
+	if err != nil {
+		t.Error(err.Error())
+	}
+	mux := http.NewServeMux()
+	testServer := httptest.NewServer(supertokens.Middleware(mux))
+	defer testServer.Close()
+
+	result, err := signUpRequestWithFormFields(testServer.URL, []map[string]interface{}{
+		{"id": "email", "value": "random@gmail.com"},
+		{"id": "password", "value": "validpass123"},
+		{"id": "age", "value": 21},
+		{"id": "plan", "value": "pro"},
+	})
+	if err != nil {
+		t.Error(err.Error())
+	}
+	assert.Equal(t, "OK", result["status"])
+
+	user, err := GetUserByEmail("random@gmail.com")
+	if err != nil {
+		t.Error(err.Error())
+	}
+	assert.Equal(t, float64(21), user.Metadata["age"])
+	assert.Equal(t, "pro", user.Metadata["plan"])
+}
+
+func TestPersistingFormFieldsRequiresAUserMetadataStore(t *testing.T) {
+	True := true
+	configValue := supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: "http://localhost:8080",
+		},
+		AppInfo: supertokens.AppInfo{
+			APIDomain:     "api.supertokens.io",
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{
+			Init(&epmodels.TypeInput{
+				SignUpFeature: &epmodels.TypeInputSignUp{
+					PersistFormFieldsInUserMetadata: &True,
+				},
+			}),
+		},
+	}
+
+	BeforeEach()
+	unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+	err := supertokens.Init(configValue)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "UserMetadataStore")
+}
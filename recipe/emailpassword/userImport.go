@@ -0,0 +1,270 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package emailpassword
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/epmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// makeUserImportRecipeImplementation makes SignIn fall back to the imported password hash
+// of a user, and replaces it with a hash created by the core once it has been verified.
+func makeUserImportRecipeImplementation(originalImplementation epmodels.RecipeInterface, config epmodels.TypeNormalisedInputUserImportFeature) epmodels.RecipeInterface {
+	if config.VerifyPasswordHash == nil {
+		return originalImplementation
+	}
+
+	originalSignIn := *originalImplementation.SignIn
+	originalUpdateEmailOrPassword := *originalImplementation.UpdateEmailOrPassword
+	originalResetPasswordUsingToken := *originalImplementation.ResetPasswordUsingToken
+
+	signIn := func(email, password string, userContext supertokens.UserContext) (epmodels.SignInResponse, error) {
+		response, err := originalSignIn(email, password, userContext)
+		if err != nil {
+			return epmodels.SignInResponse{}, err
+		}
+		if response.WrongCredentialsError == nil {
+			return response, nil
+		}
+
+		user, err := (*originalImplementation.GetUserByEmail)(email, userContext)
+		if err != nil {
+			return epmodels.SignInResponse{}, err
+		}
+		if user == nil {
+			return response, nil
+		}
+		passwordHash, err := config.PasswordHashStore.Get(user.ID, userContext)
+		if err != nil {
+			return epmodels.SignInResponse{}, err
+		}
+		if passwordHash == nil {
+			return response, nil
+		}
+		isValid, err := config.VerifyPasswordHash(password, *passwordHash, userContext)
+		if err != nil {
+			return epmodels.SignInResponse{}, err
+		}
+		if !isValid {
+			return response, nil
+		}
+
+		updateResponse, err := originalUpdateEmailOrPassword(user.ID, nil, &password, userContext)
+		if err != nil {
+			return epmodels.SignInResponse{}, err
+		}
+		if updateResponse.OK == nil {
+			return response, nil
+		}
+		err = config.PasswordHashStore.Remove(user.ID, userContext)
+		if err != nil {
+			return epmodels.SignInResponse{}, err
+		}
+		return epmodels.SignInResponse{
+			OK: &struct{ User epmodels.User }{User: *user},
+		}, nil
+	}
+
+	updateEmailOrPassword := func(userId string, email, password *string, userContext supertokens.UserContext) (epmodels.UpdateEmailOrPasswordResponse, error) {
+		response, err := originalUpdateEmailOrPassword(userId, email, password, userContext)
+		if err != nil {
+			return epmodels.UpdateEmailOrPasswordResponse{}, err
+		}
+		if response.OK != nil && password != nil {
+			// the imported hash must not be usable once the user has a new password
+			err = config.PasswordHashStore.Remove(userId, userContext)
+			if err != nil {
+				return epmodels.UpdateEmailOrPasswordResponse{}, err
+			}
+		}
+		return response, nil
+	}
+
+	resetPasswordUsingToken := func(token, newPassword string, userContext supertokens.UserContext) (epmodels.ResetPasswordUsingTokenResponse, error) {
+		response, err := originalResetPasswordUsingToken(token, newPassword, userContext)
+		if err != nil {
+			return epmodels.ResetPasswordUsingTokenResponse{}, err
+		}
+		// the user ID is only returned by the core for CDI >= 2.12
+		if response.OK != nil && response.OK.UserId != nil {
+			err = config.PasswordHashStore.Remove(*response.OK.UserId, userContext)
+			if err != nil {
+				return epmodels.ResetPasswordUsingTokenResponse{}, err
+			}
+		}
+		return response, nil
+	}
+
+	originalImplementation.SignIn = &signIn
+	originalImplementation.UpdateEmailOrPassword = &updateEmailOrPassword
+	originalImplementation.ResetPasswordUsingToken = &resetPasswordUsingToken
+	return originalImplementation
+}
+
+func (r *Recipe) importUsers(users []epmodels.ImportUserInput, onProgress func(progress epmodels.ImportUsersProgress), userContext supertokens.UserContext) (epmodels.ImportUsersResponse, error) {
+	config := r.Config.UserImportFeature
+	if config.VerifyPasswordHash == nil {
+		return epmodels.ImportUsersResponse{}, errors.New("please provide UserImportFeature.VerifyPasswordHash in the emailpassword config to import users")
+	}
+
+	var emailValidator func(value interface{}) *string
+	for _, formField := range r.Config.SignUpFeature.FormFields {
+		if formField.ID == "email" {
+			emailValidator = formField.Validate
+		}
+	}
+
+	response := epmodels.ImportUsersResponse{
+		Results: []epmodels.ImportUserResult{},
+	}
+	for i, userInput := range users {
+		result, err := r.importUser(i, userInput, emailValidator, userContext)
+		if err != nil {
+			// we return the results so far so that the import can be resumed after the failed row
+			return response, err
+		}
+		response.Results = append(response.Results, result)
+		if result.OK != nil {
+			response.Imported++
+		} else {
+			response.Failed++
+		}
+
+		if onProgress != nil && ((i+1)%config.ProgressInterval == 0 || i+1 == len(users)) {
+			onProgress(epmodels.ImportUsersProgress{
+				Total:     len(users),
+				Processed: i + 1,
+				Imported:  response.Imported,
+				Failed:    response.Failed,
+			})
+		}
+	}
+	return response, nil
+}
+
+func (r *Recipe) importUser(index int, userInput epmodels.ImportUserInput, emailValidator func(value interface{}) *string, userContext supertokens.UserContext) (epmodels.ImportUserResult, error) {
+	email := strings.TrimSpace(userInput.Email)
+	result := epmodels.ImportUserResult{
+		Index: index,
+		Email: email,
+	}
+
+	if emailValidator != nil {
+		if errMsg := emailValidator(email); errMsg != nil {
+			result.InvalidInputError = &struct{ Msg string }{Msg: *errMsg}
+			return result, nil
+		}
+	}
+	if userInput.PasswordHash.Hash == "" {
+		result.InvalidInputError = &struct{ Msg string }{Msg: "Password hash is empty"}
+		return result, nil
+	}
+	if userInput.PasswordHash.HashingAlgorithm == "" {
+		result.InvalidInputError = &struct{ Msg string }{Msg: "Hashing algorithm is empty"}
+		return result, nil
+	}
+
+	existingUser, err := (*r.RecipeImpl.GetUserByEmail)(email, userContext)
+	if err != nil {
+		return epmodels.ImportUserResult{}, err
+	}
+	if existingUser != nil {
+		// the core would replace the password hash of an existing user
+		result.EmailAlreadyExistsError = &struct{}{}
+		return result, nil
+	}
+
+	user, err := r.importUserIntoCore(email, userInput.PasswordHash)
+	if err != nil {
+		return epmodels.ImportUserResult{}, err
+	}
+	if user != nil {
+		result.OK = &struct{ User epmodels.User }{User: *user}
+		return result, nil
+	}
+
+	// the core cannot store this hash, so the user is created with a password that
+	// nobody knows. It is replaced on the first successful sign in with the imported hash.
+	password, err := generateRandomPassword()
+	if err != nil {
+		return epmodels.ImportUserResult{}, err
+	}
+	signUpResponse, err := (*r.RecipeImpl.SignUp)(email, password, userContext)
+	if err != nil {
+		return epmodels.ImportUserResult{}, err
+	}
+	if signUpResponse.EmailAlreadyExistsError != nil {
+		result.EmailAlreadyExistsError = &struct{}{}
+		return result, nil
+	}
+
+	err = r.Config.UserImportFeature.PasswordHashStore.Save(signUpResponse.OK.User.ID, userInput.PasswordHash, userContext)
+	if err != nil {
+		// without its imported hash, nobody could ever sign in as this user
+		deleteErr := supertokens.DeleteUser(signUpResponse.OK.User.ID)
+		if deleteErr != nil {
+			return epmodels.ImportUserResult{}, fmt.Errorf("%s (and the imported user %s could not be deleted: %s)", err.Error(), signUpResponse.OK.User.ID, deleteErr.Error())
+		}
+		return epmodels.ImportUserResult{}, err
+	}
+	result.OK = &struct{ User epmodels.User }{User: signUpResponse.OK.User}
+	return result, nil
+}
+
+// importUserIntoCore returns nil if the core cannot store the password hash, either because
+// of its hashing algorithm or because the core does not support importing password hashes.
+func (r *Recipe) importUserIntoCore(email string, passwordHash epmodels.ImportedPasswordHash) (*epmodels.User, error) {
+	hashingAlgorithm := strings.ToLower(passwordHash.HashingAlgorithm)
+	if hashingAlgorithm != "bcrypt" && hashingAlgorithm != "argon2" {
+		return nil, nil
+	}
+	querier, err := supertokens.GetNewQuerierInstanceOrThrowError(r.RecipeModule.GetRecipeID())
+	if err != nil {
+		return nil, err
+	}
+	response, err := querier.SendPostRequest("/recipe/user/passwordhash/import", map[string]interface{}{
+		"email":            email,
+		"passwordHash":     passwordHash.Hash,
+		"hashingAlgorithm": strings.ToUpper(hashingAlgorithm),
+	})
+	if err != nil {
+		// older cores do not know this API, and reject hashes they cannot parse
+		var coreError supertokens.CoreError
+		if errors.As(err, &coreError) && (coreError.StatusCode == 404 || coreError.StatusCode == 400) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if status, ok := response["status"].(string); !ok || status != "OK" {
+		return nil, nil
+	}
+	return parseUser(response["user"])
+}
+
+func generateRandomPassword() (string, error) {
+	randomBytes := make([]byte, 32)
+	_, err := rand.Read(randomBytes)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(randomBytes), nil
+}
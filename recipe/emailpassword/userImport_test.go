@@ -0,0 +1,233 @@
+/*
+ * Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package emailpassword
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/epmodels"
+	"github.com/supertokens/supertokens-golang/recipe/session"
+	"github.com/supertokens/supertokens-golang/supertokens"
+	"github.com/supertokens/supertokens-golang/test/unittesting"
+)
+
+func makeInMemoryPasswordHashStoreForTest() epmodels.PasswordHashStore {
+	var lock sync.Mutex
+	passwordHashes := map[string]epmodels.ImportedPasswordHash{}
+	return epmodels.PasswordHashStore{
+		Save: func(userID string, passwordHash epmodels.ImportedPasswordHash, userContext supertokens.UserContext) error {
+			lock.Lock()
+			defer lock.Unlock()
+			passwordHashes[userID] = passwordHash
+			return nil
+		},
+		Get: func(userID string, userContext supertokens.UserContext) (*epmodels.ImportedPasswordHash, error) {
+			lock.Lock()
+			defer lock.Unlock()
+			passwordHash, ok := passwordHashes[userID]
+			if !ok {
+				return nil, nil
+			}
+			return &passwordHash, nil
+		},
+		Remove: func(userID string, userContext supertokens.UserContext) error {
+			lock.Lock()
+			defer lock.Unlock()
+			delete(passwordHashes, userID)
+			return nil
+		},
+	}
+}
+
+func sha256Hex(value string) string {
+	hash := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(hash[:])
+}
+
+func TestImportUsersAndRehashOnFirstSignIn(t *testing.T) {
+	progressInterval := 2
+	passwordHashStore := makeInMemoryPasswordHashStoreForTest()
+	configValue := supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: "http://localhost:8080",
+		},
+		AppInfo: supertokens.AppInfo{
+			APIDomain:     "api.supertokens.io",
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{
+			Init(&epmodels.TypeInput{
+				UserImportFeature: &epmodels.TypeInputUserImportFeature{
+					ProgressInterval:  &progressInterval,
+					PasswordHashStore: &passwordHashStore,
+					VerifyPasswordHash: func(password string, passwordHash epmodels.ImportedPasswordHash, userContext supertokens.UserContext) (bool, error) {
+						return passwordHash.HashingAlgorithm == "sha256" && sha256Hex(password) == passwordHash.Hash, nil
+					},
+				},
+			}),
+			session.Init(nil),
+		},
+	}
+
+	BeforeEach()
+	unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+	err := supertokens.Init(configValue)
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	progressUpdates := []epmodels.ImportUsersProgress{}
+	response, err := ImportUsers([]epmodels.ImportUserInput{{
+		Email:        "user1@gmail.com",
+		PasswordHash: epmodels.ImportedPasswordHash{Hash: sha256Hex("legacypass1"), HashingAlgorithm: "sha256"},
+	}, {
+		Email:        "invalidemail",
+		PasswordHash: epmodels.ImportedPasswordHash{Hash: sha256Hex("legacypass2"), HashingAlgorithm: "sha256"},
+	}, {
+		Email:        "user1@gmail.com",
+		PasswordHash: epmodels.ImportedPasswordHash{Hash: sha256Hex("legacypass3"), HashingAlgorithm: "sha256"},
+	}}, func(progress epmodels.ImportUsersProgress) {
+		progressUpdates = append(progressUpdates, progress)
+	})
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	assert.Equal(t, 1, response.Imported)
+	assert.Equal(t, 2, response.Failed)
+	assert.NotNil(t, response.Results[0].OK)
+	assert.NotNil(t, response.Results[1].InvalidInputError)
+	assert.NotNil(t, response.Results[2].EmailAlreadyExistsError)
+	assert.Equal(t, []epmodels.ImportUsersProgress{
+		{Total: 3, Processed: 2, Imported: 1, Failed: 1},
+		{Total: 3, Processed: 3, Imported: 1, Failed: 2},
+	}, progressUpdates)
+
+	signInResponse, err := SignIn("user1@gmail.com", "wrongpass123")
+	if err != nil {
+		t.Error(err.Error())
+	}
+	assert.NotNil(t, signInResponse.WrongCredentialsError)
+
+	signInResponse, err = SignIn("user1@gmail.com", "legacypass1")
+	if err != nil {
+		t.Error(err.Error())
+	}
+	assert.NotNil(t, signInResponse.OK)
+	assert.Equal(t, response.Results[0].OK.User.ID, signInResponse.OK.User.ID)
+
+	instance, err := getRecipeInstanceOrThrowError()
+	if err != nil {
+		t.Error(err.Error())
+	}
+	passwordHash, err := instance.Config.UserImportFeature.PasswordHashStore.Get(signInResponse.OK.User.ID, &map[string]interface{}{})
+	if err != nil {
+		t.Error(err.Error())
+	}
+	assert.Nil(t, passwordHash)
+
+	signInResponse, err = SignIn("user1@gmail.com", "legacypass1")
+	if err != nil {
+		t.Error(err.Error())
+	}
+	assert.NotNil(t, signInResponse.OK)
+}
+
+func TestImportedUserIsDeletedIfItsPasswordHashCannotBeSaved(t *testing.T) {
+	passwordHashStore := makeInMemoryPasswordHashStoreForTest()
+	passwordHashStore.Save = func(userID string, passwordHash epmodels.ImportedPasswordHash, userContext supertokens.UserContext) error {
+		return errors.New("store unavailable")
+	}
+	configValue := supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: "http://localhost:8080",
+		},
+		AppInfo: supertokens.AppInfo{
+			APIDomain:     "api.supertokens.io",
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{
+			Init(&epmodels.TypeInput{
+				UserImportFeature: &epmodels.TypeInputUserImportFeature{
+					PasswordHashStore: &passwordHashStore,
+					VerifyPasswordHash: func(password string, passwordHash epmodels.ImportedPasswordHash, userContext supertokens.UserContext) (bool, error) {
+						return sha256Hex(password) == passwordHash.Hash, nil
+					},
+				},
+			}),
+			session.Init(nil),
+		},
+	}
+
+	BeforeEach()
+	unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+	err := supertokens.Init(configValue)
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	_, err = ImportUsers([]epmodels.ImportUserInput{{
+		Email:        "user1@gmail.com",
+		PasswordHash: epmodels.ImportedPasswordHash{Hash: sha256Hex("legacypass1"), HashingAlgorithm: "sha256"},
+	}}, nil)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "store unavailable")
+
+	user, err := GetUserByEmail("user1@gmail.com")
+	if err != nil {
+		t.Error(err.Error())
+	}
+	assert.Nil(t, user)
+}
+
+func TestImportUsersRequiresAPasswordHashStore(t *testing.T) {
+	configValue := supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: "http://localhost:8080",
+		},
+		AppInfo: supertokens.AppInfo{
+			APIDomain:     "api.supertokens.io",
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{
+			Init(&epmodels.TypeInput{
+				UserImportFeature: &epmodels.TypeInputUserImportFeature{
+					VerifyPasswordHash: func(password string, passwordHash epmodels.ImportedPasswordHash, userContext supertokens.UserContext) (bool, error) {
+						return false, nil
+					},
+				},
+			}),
+		},
+	}
+
+	BeforeEach()
+	unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+	err := supertokens.Init(configValue)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "PasswordHashStore")
+}
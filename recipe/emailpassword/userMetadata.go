@@ -0,0 +1,64 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package emailpassword
+
+import (
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/epmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// makeUserMetadataRecipeImplementation adds the metadata saved by SignUpPOST to the users returned
+// by GetUserByID and GetUserByEmail.
+func makeUserMetadataRecipeImplementation(originalImplementation epmodels.RecipeInterface, config epmodels.TypeNormalisedInputSignUp) epmodels.RecipeInterface {
+	if !config.PersistFormFieldsInUserMetadata {
+		return originalImplementation
+	}
+
+	originalGetUserByID := *originalImplementation.GetUserByID
+	originalGetUserByEmail := *originalImplementation.GetUserByEmail
+
+	addMetadata := func(user *epmodels.User, userContext supertokens.UserContext) (*epmodels.User, error) {
+		if user == nil {
+			return nil, nil
+		}
+		metadata, err := config.UserMetadataStore.Get(user.ID, userContext)
+		if err != nil {
+			return nil, err
+		}
+		user.Metadata = metadata
+		return user, nil
+	}
+
+	getUserByID := func(userID string, userContext supertokens.UserContext) (*epmodels.User, error) {
+		user, err := originalGetUserByID(userID, userContext)
+		if err != nil {
+			return nil, err
+		}
+		return addMetadata(user, userContext)
+	}
+
+	getUserByEmail := func(email string, userContext supertokens.UserContext) (*epmodels.User, error) {
+		user, err := originalGetUserByEmail(email, userContext)
+		if err != nil {
+			return nil, err
+		}
+		return addMetadata(user, userContext)
+	}
+
+	originalImplementation.GetUserByID = &getUserByID
+	originalImplementation.GetUserByEmail = &getUserByEmail
+	return originalImplementation
+}
@@ -26,12 +26,16 @@ import (
 	"github.com/supertokens/supertokens-golang/supertokens"
 )
 
-func validateAndNormaliseUserInput(recipeInstance *Recipe, appInfo supertokens.NormalisedAppinfo, config *epmodels.TypeInput) epmodels.TypeNormalisedInput {
+func validateAndNormaliseUserInput(recipeInstance *Recipe, appInfo supertokens.NormalisedAppinfo, config *epmodels.TypeInput) (epmodels.TypeNormalisedInput, error) {
 
 	typeNormalisedInput := makeTypeNormalisedInput(recipeInstance)
 
 	if config != nil && config.SignUpFeature != nil {
-		typeNormalisedInput.SignUpFeature = validateAndNormaliseSignupConfig(config.SignUpFeature)
+		signUpFeature, err := validateAndNormaliseSignupConfig(config.SignUpFeature)
+		if err != nil {
+			return epmodels.TypeNormalisedInput{}, err
+		}
+		typeNormalisedInput.SignUpFeature = signUpFeature
 		typeNormalisedInput.ResetPasswordUsingTokenFeature = validateAndNormaliseResetPasswordUsingTokenConfig(appInfo, typeNormalisedInput.SignUpFeature, nil)
 	}
 
@@ -42,6 +46,20 @@ func validateAndNormaliseUserInput(recipeInstance *Recipe, appInfo supertokens.N
 		typeNormalisedInput.ResetPasswordUsingTokenFeature = validateAndNormaliseResetPasswordUsingTokenConfig(appInfo, typeNormalisedInput.SignUpFeature, config.ResetPasswordUsingTokenFeature)
 	}
 
+	if config != nil {
+		changeEmailFeature, err := validateAndNormaliseChangeEmailConfig(appInfo, typeNormalisedInput.SignUpFeature, config.ChangeEmailFeature)
+		if err != nil {
+			return epmodels.TypeNormalisedInput{}, err
+		}
+		typeNormalisedInput.ChangeEmailFeature = changeEmailFeature
+		typeNormalisedInput.ChangePasswordFeature = validateAndNormaliseChangePasswordConfig(typeNormalisedInput.SignUpFeature, config.ChangePasswordFeature)
+		userImportFeature, err := validateAndNormaliseUserImportConfig(config.UserImportFeature)
+		if err != nil {
+			return epmodels.TypeNormalisedInput{}, err
+		}
+		typeNormalisedInput.UserImportFeature = userImportFeature
+	}
+
 	typeNormalisedInput.EmailVerificationFeature = validateAndNormaliseEmailVerificationConfig(recipeInstance, config)
 
 	if config != nil && config.Override != nil {
@@ -56,15 +74,21 @@ func validateAndNormaliseUserInput(recipeInstance *Recipe, appInfo supertokens.N
 		}
 	}
 
-	return typeNormalisedInput
+	return typeNormalisedInput, nil
 }
 
 func makeTypeNormalisedInput(recipeInstance *Recipe) epmodels.TypeNormalisedInput {
-	signUpConfig := validateAndNormaliseSignupConfig(nil)
+	signUpConfig, _ := validateAndNormaliseSignupConfig(nil)
+	// the change email and user import features are disabled unless they are configured
+	changeEmailConfig, _ := validateAndNormaliseChangeEmailConfig(recipeInstance.RecipeModule.GetAppInfo(), signUpConfig, nil)
+	userImportConfig, _ := validateAndNormaliseUserImportConfig(nil)
 	return epmodels.TypeNormalisedInput{
 		SignUpFeature:                  signUpConfig,
 		SignInFeature:                  validateAndNormaliseSignInConfig(signUpConfig),
 		ResetPasswordUsingTokenFeature: validateAndNormaliseResetPasswordUsingTokenConfig(recipeInstance.RecipeModule.GetAppInfo(), signUpConfig, nil),
+		ChangeEmailFeature:             changeEmailConfig,
+		ChangePasswordFeature:          validateAndNormaliseChangePasswordConfig(signUpConfig, nil),
+		UserImportFeature:              userImportConfig,
 		EmailVerificationFeature:       validateAndNormaliseEmailVerificationConfig(recipeInstance, nil),
 		Override: epmodels.OverrideStruct{
 			Functions: func(originalImplementation epmodels.RecipeInterface) epmodels.RecipeInterface {
@@ -80,8 +104,9 @@ func makeTypeNormalisedInput(recipeInstance *Recipe) epmodels.TypeNormalisedInpu
 
 func validateAndNormaliseEmailVerificationConfig(recipeInstance *Recipe, config *epmodels.TypeInput) evmodels.TypeInput {
 	emailverificationTypeInput := evmodels.TypeInput{
-		GetEmailForUserID: recipeInstance.getEmailForUserId,
-		Override:          nil,
+		GetEmailForUserID:      recipeInstance.getEmailForUserId,
+		GetTimeJoinedForUserID: recipeInstance.getTimeJoinedForUserId,
+		Override:               nil,
 	}
 
 	if config != nil {
@@ -89,6 +114,27 @@ func validateAndNormaliseEmailVerificationConfig(recipeInstance *Recipe, config
 			emailverificationTypeInput.Override = config.Override.EmailVerificationFeature
 		}
 		if config.EmailVerificationFeature != nil {
+			emailverificationTypeInput.ResendCooldown = config.EmailVerificationFeature.ResendCooldown
+			emailverificationTypeInput.ResendCooldownStore = config.EmailVerificationFeature.ResendCooldownStore
+			emailverificationTypeInput.GracePeriodAfterSignUp = config.EmailVerificationFeature.GracePeriodAfterSignUp
+			emailverificationTypeInput.Mode = config.EmailVerificationFeature.Mode
+			emailverificationTypeInput.CodeLifetime = config.EmailVerificationFeature.CodeLifetime
+			emailverificationTypeInput.MaximumCodeInputAttempts = config.EmailVerificationFeature.MaximumCodeInputAttempts
+			emailverificationTypeInput.CodeStore = config.EmailVerificationFeature.CodeStore
+
+			if config.EmailVerificationFeature.CreateAndSendEmailWithCode != nil {
+				emailverificationTypeInput.CreateAndSendEmailWithCode = func(user evmodels.User, code string, codeLifetime uint64, userContext supertokens.UserContext) {
+					userInfo, err := (*recipeInstance.RecipeImpl.GetUserByID)(user.ID, userContext)
+					if err != nil {
+						return
+					}
+					if userInfo == nil {
+						return
+					}
+					config.EmailVerificationFeature.CreateAndSendEmailWithCode(*userInfo, code, codeLifetime, userContext)
+				}
+			}
+
 			if config.EmailVerificationFeature.CreateAndSendCustomEmail != nil {
 				emailverificationTypeInput.CreateAndSendCustomEmail = func(user evmodels.User, link string, userContext supertokens.UserContext) {
 					userInfo, err := (*recipeInstance.RecipeImpl.GetUserByID)(user.ID, userContext)
@@ -122,10 +168,15 @@ func validateAndNormaliseEmailVerificationConfig(recipeInstance *Recipe, config
 
 func validateAndNormaliseResetPasswordUsingTokenConfig(appInfo supertokens.NormalisedAppinfo, signUpConfig epmodels.TypeNormalisedInputSignUp, config *epmodels.TypeInputResetPasswordUsingTokenFeature) epmodels.TypeNormalisedInputResetPasswordUsingTokenFeature {
 	normalisedInputResetPasswordUsingTokenFeature := epmodels.TypeNormalisedInputResetPasswordUsingTokenFeature{
-		FormFieldsForGenerateTokenForm: nil,
-		FormFieldsForPasswordResetForm: nil,
-		GetResetPasswordURL:            defaultGetResetPasswordURL(appInfo),
-		CreateAndSendCustomEmail:       defaultCreateAndSendCustomPasswordResetEmail(appInfo),
+		FormFieldsForGenerateTokenForm:           nil,
+		FormFieldsForPasswordResetForm:           nil,
+		GetResetPasswordURL:                      defaultGetResetPasswordURL(appInfo),
+		CreateAndSendCustomEmail:                 defaultCreateAndSendCustomPasswordResetEmail(appInfo),
+		CreateAndSendPasswordChangedNotification: nil,
+		RevokeAllSessionsOnReset:                 false,
+		SignInAfterReset:                         false,
+		TokenLifetime:                            defaultResetPasswordTokenLifetime,
+		TokenStore:                               nil,
 	}
 
 	if len(signUpConfig.FormFields) > 0 {
@@ -151,9 +202,107 @@ func validateAndNormaliseResetPasswordUsingTokenConfig(appInfo supertokens.Norma
 	if config != nil && config.CreateAndSendCustomEmail != nil {
 		normalisedInputResetPasswordUsingTokenFeature.CreateAndSendCustomEmail = config.CreateAndSendCustomEmail
 	}
+	if config != nil && config.CreateAndSendPasswordChangedNotification != nil {
+		normalisedInputResetPasswordUsingTokenFeature.CreateAndSendPasswordChangedNotification = config.CreateAndSendPasswordChangedNotification
+	}
+	if config != nil && config.RevokeAllSessionsOnReset != nil {
+		normalisedInputResetPasswordUsingTokenFeature.RevokeAllSessionsOnReset = *config.RevokeAllSessionsOnReset
+	}
+	if config != nil && config.SignInAfterReset != nil {
+		normalisedInputResetPasswordUsingTokenFeature.SignInAfterReset = *config.SignInAfterReset
+	}
+	if config != nil && config.TokenLifetime != nil {
+		normalisedInputResetPasswordUsingTokenFeature.TokenLifetime = *config.TokenLifetime
+	}
+	if config != nil && config.TokenStore != nil {
+		normalisedInputResetPasswordUsingTokenFeature.TokenStore = config.TokenStore
+	}
 
 	return normalisedInputResetPasswordUsingTokenFeature
 }
+
+func validateAndNormaliseChangeEmailConfig(appInfo supertokens.NormalisedAppinfo, signUpConfig epmodels.TypeNormalisedInputSignUp, config *epmodels.TypeInputChangeEmailFeature) (epmodels.TypeNormalisedInputChangeEmailFeature, error) {
+	normalisedInputChangeEmailFeature := epmodels.TypeNormalisedInputChangeEmailFeature{
+		FormFieldsForChangeEmailForm:          nil,
+		GetEmailChangeURL:                     defaultGetEmailChangeURL(appInfo),
+		CreateAndSendCustomEmail:              defaultCreateAndSendCustomEmailChangeEmail(appInfo),
+		CreateAndSendEmailChangedNotification: nil,
+	}
+
+	for _, formField := range signUpConfig.FormFields {
+		if formField.ID == "email" {
+			normalisedInputChangeEmailFeature.FormFieldsForChangeEmailForm = append(normalisedInputChangeEmailFeature.FormFieldsForChangeEmailForm, formField)
+		}
+	}
+
+	if config != nil && config.GetEmailChangeURL != nil {
+		normalisedInputChangeEmailFeature.GetEmailChangeURL = config.GetEmailChangeURL
+	}
+	if config != nil && config.CreateAndSendCustomEmail != nil {
+		normalisedInputChangeEmailFeature.CreateAndSendCustomEmail = config.CreateAndSendCustomEmail
+	}
+	if config != nil {
+		if config.CreateAndSendEmailChangedNotification == nil {
+			return epmodels.TypeNormalisedInputChangeEmailFeature{}, errors.New("please provide ChangeEmailFeature.CreateAndSendEmailChangedNotification so that the old email of a user is told about the change")
+		}
+		normalisedInputChangeEmailFeature.CreateAndSendEmailChangedNotification = config.CreateAndSendEmailChangedNotification
+	}
+
+	return normalisedInputChangeEmailFeature, nil
+}
+
+func validateAndNormaliseChangePasswordConfig(signUpConfig epmodels.TypeNormalisedInputSignUp, config *epmodels.TypeInputChangePasswordFeature) epmodels.TypeNormalisedInputChangePasswordFeature {
+	normalisedInputChangePasswordFeature := epmodels.TypeNormalisedInputChangePasswordFeature{
+		RevokeOtherSessions: false,
+		FormFieldsForChangePasswordForm: []epmodels.NormalisedFormField{{
+			ID:       "oldPassword",
+			Validate: defaultValidator,
+			Optional: false,
+		}},
+	}
+
+	for _, formField := range signUpConfig.FormFields {
+		if formField.ID == "password" {
+			normalisedInputChangePasswordFeature.FormFieldsForChangePasswordForm = append(normalisedInputChangePasswordFeature.FormFieldsForChangePasswordForm, epmodels.NormalisedFormField{
+				ID:       "newPassword",
+				Validate: formField.Validate,
+				Optional: false,
+			})
+		}
+	}
+
+	if config != nil && config.RevokeOtherSessions != nil {
+		normalisedInputChangePasswordFeature.RevokeOtherSessions = *config.RevokeOtherSessions
+	}
+
+	return normalisedInputChangePasswordFeature
+}
+
+func validateAndNormaliseUserImportConfig(config *epmodels.TypeInputUserImportFeature) (epmodels.TypeNormalisedInputUserImportFeature, error) {
+	normalisedInputUserImportFeature := epmodels.TypeNormalisedInputUserImportFeature{
+		ProgressInterval:   100,
+		VerifyPasswordHash: nil,
+	}
+	if config == nil {
+		return normalisedInputUserImportFeature, nil
+	}
+
+	if config.VerifyPasswordHash == nil {
+		return epmodels.TypeNormalisedInputUserImportFeature{}, errors.New("please provide UserImportFeature.VerifyPasswordHash in the emailpassword config to import users")
+	}
+	if config.PasswordHashStore == nil {
+		return epmodels.TypeNormalisedInputUserImportFeature{}, errors.New("please provide UserImportFeature.PasswordHashStore in the emailpassword config to import users")
+	}
+	normalisedInputUserImportFeature.VerifyPasswordHash = config.VerifyPasswordHash
+	normalisedInputUserImportFeature.PasswordHashStore = *config.PasswordHashStore
+
+	if config.ProgressInterval != nil && *config.ProgressInterval > 0 {
+		normalisedInputUserImportFeature.ProgressInterval = *config.ProgressInterval
+	}
+
+	return normalisedInputUserImportFeature, nil
+}
+
 func validateAndNormaliseSignInConfig(signUpConfig epmodels.TypeNormalisedInputSignUp) epmodels.TypeNormalisedInputSignIn {
 	return epmodels.TypeNormalisedInputSignIn{
 		FormFields: normaliseSignInFormFields(signUpConfig.FormFields),
@@ -183,15 +332,27 @@ func normaliseSignInFormFields(formFields []epmodels.NormalisedFormField) []epmo
 	return normalisedFormFields
 }
 
-func validateAndNormaliseSignupConfig(config *epmodels.TypeInputSignUp) epmodels.TypeNormalisedInputSignUp {
+func validateAndNormaliseSignupConfig(config *epmodels.TypeInputSignUp) (epmodels.TypeNormalisedInputSignUp, error) {
 	if config == nil {
 		return epmodels.TypeNormalisedInputSignUp{
-			FormFields: NormaliseSignUpFormFields(nil),
-		}
+			FormFields:                      NormaliseSignUpFormFields(nil),
+			PersistFormFieldsInUserMetadata: false,
+		}, nil
+	}
+	normalisedInputSignUp := epmodels.TypeNormalisedInputSignUp{
+		FormFields:                      NormaliseSignUpFormFields(config.FormFields),
+		PersistFormFieldsInUserMetadata: false,
+	}
+	if config.PersistFormFieldsInUserMetadata != nil {
+		normalisedInputSignUp.PersistFormFieldsInUserMetadata = *config.PersistFormFieldsInUserMetadata
 	}
-	return epmodels.TypeNormalisedInputSignUp{
-		FormFields: NormaliseSignUpFormFields(config.FormFields),
+	if normalisedInputSignUp.PersistFormFieldsInUserMetadata {
+		if config.UserMetadataStore == nil {
+			return epmodels.TypeNormalisedInputSignUp{}, errors.New("please provide SignUpFeature.UserMetadataStore in the emailpassword config to persist sign up form fields")
+		}
+		normalisedInputSignUp.UserMetadataStore = *config.UserMetadataStore
 	}
+	return normalisedInputSignUp, nil
 }
 
 func NormaliseSignUpFormFields(formFields []epmodels.TypeInputFormField) []epmodels.NormalisedFormField {
@@ -204,8 +365,11 @@ func NormaliseSignUpFormFields(formFields []epmodels.TypeInputFormField) []epmod
 	if len(formFields) > 0 {
 		for _, formField := range formFields {
 			var (
-				validate func(value interface{}) *string
-				optional bool = false
+				validate     func(value interface{}) *string
+				optional     bool                   = false
+				fieldType    epmodels.FormFieldType = epmodels.FormFieldTypeString
+				enumValues   []string
+				defaultValue *string
 			)
 			if formField.ID == "password" {
 				formFieldPasswordIDCount++
@@ -227,11 +391,19 @@ func NormaliseSignUpFormFields(formFields []epmodels.TypeInputFormField) []epmod
 				if formField.Optional != nil {
 					optional = *formField.Optional
 				}
+				if formField.Type != "" {
+					fieldType = formField.Type
+				}
+				enumValues = formField.EnumValues
+				defaultValue = formField.DefaultValue
 			}
 			normalisedFormFields = append(normalisedFormFields, epmodels.NormalisedFormField{
-				ID:       formField.ID,
-				Validate: validate,
-				Optional: optional,
+				ID:           formField.ID,
+				Validate:     validate,
+				Optional:     optional,
+				Type:         fieldType,
+				EnumValues:   enumValues,
+				DefaultValue: defaultValue,
 			})
 		}
 	}
@@ -240,6 +412,7 @@ func NormaliseSignUpFormFields(formFields []epmodels.TypeInputFormField) []epmod
 			ID:       "password",
 			Validate: defaultPasswordValidator,
 			Optional: false,
+			Type:     epmodels.FormFieldTypeString,
 		})
 	}
 	if formFieldEmailIDCount == 0 {
@@ -247,6 +420,7 @@ func NormaliseSignUpFormFields(formFields []epmodels.TypeInputFormField) []epmod
 			ID:       "email",
 			Validate: defaultEmailValidator,
 			Optional: false,
+			Type:     epmodels.FormFieldTypeString,
 		})
 	}
 	return normalisedFormFields
@@ -0,0 +1,82 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package api
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"reflect"
+	"strings"
+
+	"github.com/supertokens/supertokens-golang/recipe/emailverification/evmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func EmailVerifyCode(apiImplementation evmodels.APIInterface, options evmodels.APIOptions) error {
+	if apiImplementation.VerifyEmailCodePOST == nil ||
+		(*apiImplementation.VerifyEmailCodePOST) == nil {
+		options.OtherHandler(options.Res, options.Req)
+		return nil
+	}
+
+	body, err := ioutil.ReadAll(options.Req.Body)
+	if err != nil {
+		return err
+	}
+	var readBody map[string]interface{}
+	err = json.Unmarshal(body, &readBody)
+	if err != nil {
+		return err
+	}
+	code, ok := readBody["code"]
+	if !ok {
+		return supertokens.BadInputError{Msg: "Please provide the email verification code"}
+	}
+	if reflect.ValueOf(code).Kind() != reflect.String {
+		return supertokens.BadInputError{Msg: "The email verification code must be a string"}
+	}
+
+	response, err := (*apiImplementation.VerifyEmailCodePOST)(strings.TrimSpace(code.(string)), options, &map[string]interface{}{})
+	if err != nil {
+		return err
+	}
+
+	var result map[string]interface{}
+	if response.OK != nil {
+		result = map[string]interface{}{
+			"status": "OK",
+			"user":   response.OK.User,
+		}
+	} else if response.IncorrectUserInputCodeError != nil {
+		result = map[string]interface{}{
+			"status":                      "INCORRECT_USER_INPUT_CODE_ERROR",
+			"failedCodeInputAttemptCount": response.IncorrectUserInputCodeError.FailedCodeInputAttemptCount,
+			"maximumCodeInputAttempts":    response.IncorrectUserInputCodeError.MaximumCodeInputAttempts,
+		}
+	} else if response.ExpiredUserInputCodeError != nil {
+		result = map[string]interface{}{
+			"status":                      "EXPIRED_USER_INPUT_CODE_ERROR",
+			"failedCodeInputAttemptCount": response.ExpiredUserInputCodeError.FailedCodeInputAttemptCount,
+			"maximumCodeInputAttempts":    response.ExpiredUserInputCodeError.MaximumCodeInputAttempts,
+		}
+	} else {
+		result = map[string]interface{}{
+			"status": "RESTART_FLOW_ERROR",
+		}
+	}
+
+	return supertokens.Send200Response(options.Res, result)
+}
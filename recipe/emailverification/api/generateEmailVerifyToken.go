@@ -16,6 +16,8 @@
 package api
 
 import (
+	"strconv"
+
 	"github.com/supertokens/supertokens-golang/recipe/emailverification/evmodels"
 	"github.com/supertokens/supertokens-golang/supertokens"
 )
@@ -35,6 +37,12 @@ func GenerateEmailVerifyToken(apiImplementation evmodels.APIInterface, options e
 		return supertokens.Send200Response(options.Res, map[string]interface{}{
 			"status": "EMAIL_ALREADY_VERIFIED_ERROR",
 		})
+	} else if response.TooManyRequestsError != nil {
+		options.Res.Header().Set("Retry-After", strconv.FormatUint(response.TooManyRequestsError.RetryAfter, 10))
+		return supertokens.Send200Response(options.Res, map[string]interface{}{
+			"status":     "TOO_MANY_REQUESTS_ERROR",
+			"retryAfter": response.TooManyRequestsError.RetryAfter,
+		})
 	} else {
 		return supertokens.Send200Response(options.Res, map[string]interface{}{
 			"status": "OK",
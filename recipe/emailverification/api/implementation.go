@@ -16,6 +16,8 @@
 package api
 
 import (
+	"time"
+
 	"github.com/supertokens/supertokens-golang/recipe/emailverification/evmodels"
 	"github.com/supertokens/supertokens-golang/recipe/session"
 	"github.com/supertokens/supertokens-golang/supertokens"
@@ -66,6 +68,52 @@ func MakeAPIImplementation() evmodels.APIInterface {
 		if err != nil {
 			return evmodels.GenerateEmailVerifyTokenPOSTResponse{}, err
 		}
+		now := uint64(time.Now().UnixNano() / 1000000)
+		if options.Config.ResendCooldownStore != nil {
+			lastSentTime, err := options.Config.ResendCooldownStore.GetLastSentTime(userID, email, userContext)
+			if err != nil {
+				return evmodels.GenerateEmailVerifyTokenPOSTResponse{}, err
+			}
+			if lastSentTime != nil && now < *lastSentTime+options.Config.ResendCooldown {
+				retryAfterMS := *lastSentTime + options.Config.ResendCooldown - now
+				return evmodels.GenerateEmailVerifyTokenPOSTResponse{
+					TooManyRequestsError: &struct{ RetryAfter uint64 }{
+						// rounded up so that retrying after this many seconds always succeeds
+						RetryAfter: (retryAfterMS + 999) / 1000,
+					},
+				}, nil
+			}
+		}
+
+		user := evmodels.User{
+			ID:    userID,
+			Email: email,
+		}
+
+		if options.Config.Mode == "CODE" {
+			codeResponse, err := (*options.RecipeImplementation.CreateEmailVerificationCode)(userID, email, userContext)
+			if err != nil {
+				return evmodels.GenerateEmailVerifyTokenPOSTResponse{}, err
+			}
+			if codeResponse.EmailAlreadyVerifiedError != nil {
+				return evmodels.GenerateEmailVerifyTokenPOSTResponse{
+					EmailAlreadyVerifiedError: &struct{}{},
+				}, nil
+			}
+
+			options.Config.CreateAndSendEmailWithCode(user, codeResponse.OK.Code, options.Config.CodeLifetime, userContext)
+
+			if options.Config.ResendCooldownStore != nil {
+				err = options.Config.ResendCooldownStore.SetLastSentTime(userID, email, now, userContext)
+				if err != nil {
+					return evmodels.GenerateEmailVerifyTokenPOSTResponse{}, err
+				}
+			}
+			return evmodels.GenerateEmailVerifyTokenPOSTResponse{
+				OK: &struct{}{},
+			}, nil
+		}
+
 		response, err := (*options.RecipeImplementation.CreateEmailVerificationToken)(userID, email, userContext)
 		if err != nil {
 			return evmodels.GenerateEmailVerifyTokenPOSTResponse{}, err
@@ -77,10 +125,6 @@ func MakeAPIImplementation() evmodels.APIInterface {
 			}, nil
 		}
 
-		user := evmodels.User{
-			ID:    userID,
-			Email: email,
-		}
 		emailVerificationURL, err := options.Config.GetEmailVerificationURL(user, userContext)
 		if err != nil {
 			return evmodels.GenerateEmailVerifyTokenPOSTResponse{}, err
@@ -89,14 +133,39 @@ func MakeAPIImplementation() evmodels.APIInterface {
 
 		options.Config.CreateAndSendCustomEmail(user, emailVerifyLink, userContext)
 
+		if options.Config.ResendCooldownStore != nil {
+			err = options.Config.ResendCooldownStore.SetLastSentTime(userID, email, now, userContext)
+			if err != nil {
+				return evmodels.GenerateEmailVerifyTokenPOSTResponse{}, err
+			}
+		}
+
 		return evmodels.GenerateEmailVerifyTokenPOSTResponse{
 			OK: &struct{}{},
 		}, nil
 	}
 
+	verifyEmailCodePOST := func(code string, options evmodels.APIOptions, userContext supertokens.UserContext) (evmodels.VerifyEmailUsingCodeResponse, error) {
+		session, err := session.GetSessionWithContext(options.Req, options.Res, nil, userContext)
+		if err != nil {
+			return evmodels.VerifyEmailUsingCodeResponse{}, err
+		}
+		if session == nil {
+			return evmodels.VerifyEmailUsingCodeResponse{}, supertokens.BadInputError{Msg: "Session is undefined. Should not come here."}
+		}
+
+		userID := session.GetUserIDWithContext(userContext)
+		email, err := options.Config.GetEmailForUserID(userID, userContext)
+		if err != nil {
+			return evmodels.VerifyEmailUsingCodeResponse{}, err
+		}
+		return (*options.RecipeImplementation.VerifyEmailUsingCode)(userID, email, code, userContext)
+	}
+
 	return evmodels.APIInterface{
 		VerifyEmailPOST:              &verifyEmailPOST,
 		IsEmailVerifiedGET:           &isEmailVerifiedGET,
 		GenerateEmailVerifyTokenPOST: &generateEmailVerifyTokenPOST,
+		VerifyEmailCodePOST:          &verifyEmailCodePOST,
 	}
 }
@@ -18,4 +18,8 @@ package emailverification
 const (
 	generateEmailVerifyTokenAPI = "/user/email/verify/token"
 	emailVerifyAPI              = "/user/email/verify"
+	emailVerifyCodeAPI          = "/user/email/verify/code"
+
+	verificationModeLink = "LINK"
+	verificationModeCode = "CODE"
 )
@@ -0,0 +1,40 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package emailverification
+
+import (
+	"crypto/rand"
+	"math/big"
+	"strings"
+)
+
+const (
+	defaultCodeLength                      = 6
+	defaultCodeLifetime             uint64 = 900000
+	defaultMaximumCodeInputAttempts        = 5
+)
+
+func generateNumericCode(length int) (string, error) {
+	var code strings.Builder
+	for i := 0; i < length; i++ {
+		digit, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", err
+		}
+		code.WriteString(digit.String())
+	}
+	return code.String(), nil
+}
@@ -34,6 +34,7 @@ type APIInterface struct {
 	VerifyEmailPOST              *func(token string, options APIOptions, userContext supertokens.UserContext) (VerifyEmailUsingTokenResponse, error)
 	IsEmailVerifiedGET           *func(options APIOptions, userContext supertokens.UserContext) (IsEmailVerifiedGETResponse, error)
 	GenerateEmailVerifyTokenPOST *func(options APIOptions, userContext supertokens.UserContext) (GenerateEmailVerifyTokenPOSTResponse, error)
+	VerifyEmailCodePOST          *func(code string, options APIOptions, userContext supertokens.UserContext) (VerifyEmailUsingCodeResponse, error)
 }
 
 type IsEmailVerifiedGETResponse struct {
@@ -45,4 +46,8 @@ type IsEmailVerifiedGETResponse struct {
 type GenerateEmailVerifyTokenPOSTResponse struct {
 	OK                        *struct{}
 	EmailAlreadyVerifiedError *struct{}
+	TooManyRequestsError      *struct {
+		// RetryAfter is the number of seconds after which a new email can be requested
+		RetryAfter uint64
+	}
 }
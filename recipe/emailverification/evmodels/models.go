@@ -21,14 +21,77 @@ type TypeInput struct {
 	GetEmailForUserID        func(userID string, userContext supertokens.UserContext) (string, error)
 	GetEmailVerificationURL  func(user User, userContext supertokens.UserContext) (string, error)
 	CreateAndSendCustomEmail func(user User, emailVerificationURLWithToken string, userContext supertokens.UserContext)
-	Override                 *OverrideStruct
+	// ResendCooldown is the minimum time in milliseconds between two verification emails sent
+	// to a user. There is no cooldown if it is not set.
+	ResendCooldown *uint64
+	// ResendCooldownStore keeps track of when the last verification email was sent to a user. It is
+	// required if ResendCooldown is set, and must be shared by all the instances of your API.
+	ResendCooldownStore *ResendCooldownStore
+	// GracePeriodAfterSignUp is the time in milliseconds after sign up during which VerifySession
+	// accepts sessions of users that have not verified their email. If not set, unverified users are never blocked.
+	// It is only enforced by VerifySession, since the email verification APIs use GetSession and must keep working
+	// for these users. Handlers that call GetSession themselves need to check IsEmailVerified.
+	GracePeriodAfterSignUp *uint64
+	// GetTimeJoinedForUserID is required if GracePeriodAfterSignUp is set. It should return nil if the user is unknown.
+	GetTimeJoinedForUserID func(userID string, userContext supertokens.UserContext) (*uint64, error)
+	// Mode is either "LINK" (the default) or "CODE". In the "CODE" mode, GenerateEmailVerifyTokenPOST
+	// sends a numeric code using CreateAndSendEmailWithCode which is consumed by VerifyEmailCodePOST.
+	Mode                       string
+	CreateAndSendEmailWithCode func(user User, code string, codeLifetime uint64, userContext supertokens.UserContext)
+	// CodeLength defaults to 6 digits
+	CodeLength *int
+	// CodeLifetime is in milliseconds and defaults to 15 minutes
+	CodeLifetime *uint64
+	// MaximumCodeInputAttempts defaults to 5
+	MaximumCodeInputAttempts *int
+	// CodeStore is required in the "CODE" mode, and must be shared by all the instances of your API
+	CodeStore *EmailVerificationCodeStore
+	Override  *OverrideStruct
 }
 
 type TypeNormalisedInput struct {
-	GetEmailForUserID        func(userID string, userContext supertokens.UserContext) (string, error)
-	GetEmailVerificationURL  func(user User, userContext supertokens.UserContext) (string, error)
-	CreateAndSendCustomEmail func(user User, emailVerificationURLWithToken string, userContext supertokens.UserContext)
-	Override                 OverrideStruct
+	GetEmailForUserID          func(userID string, userContext supertokens.UserContext) (string, error)
+	GetEmailVerificationURL    func(user User, userContext supertokens.UserContext) (string, error)
+	CreateAndSendCustomEmail   func(user User, emailVerificationURLWithToken string, userContext supertokens.UserContext)
+	ResendCooldown             uint64
+	ResendCooldownStore        *ResendCooldownStore
+	GracePeriodAfterSignUp     *uint64
+	GetTimeJoinedForUserID     func(userID string, userContext supertokens.UserContext) (*uint64, error)
+	Mode                       string
+	CreateAndSendEmailWithCode func(user User, code string, codeLifetime uint64, userContext supertokens.UserContext)
+	CodeLength                 int
+	CodeLifetime               uint64
+	MaximumCodeInputAttempts   int
+	CodeStore                  *EmailVerificationCodeStore
+	Override                   OverrideStruct
+}
+
+type EmailVerificationCodeStore struct {
+	// Save replaces the code of the user and email of codeInfo
+	Save func(codeInfo EmailVerificationCodeInfo, userContext supertokens.UserContext) error
+	// IncrementAttemptCount must atomically increment FailedCodeInputAttemptCount of the code and
+	// return the updated code info, or nil if there is no code for the user and email.
+	IncrementAttemptCount func(userID string, email string, userContext supertokens.UserContext) (*EmailVerificationCodeInfo, error)
+	Remove                func(userID string, email string, userContext supertokens.UserContext) error
+}
+
+type EmailVerificationCodeInfo struct {
+	UserID string
+	Email  string
+	Code   string
+	// Token is the email verification token created in the core along with the code. It is used to
+	// verify the email once the right code is entered.
+	Token                       string
+	TimeCreated                 uint64
+	Expiry                      uint64
+	FailedCodeInputAttemptCount int
+}
+
+// ResendCooldownStore entries are only needed for ResendCooldown milliseconds after they are set,
+// so they can be expired after that.
+type ResendCooldownStore struct {
+	GetLastSentTime func(userID string, email string, userContext supertokens.UserContext) (*uint64, error)
+	SetLastSentTime func(userID string, email string, timeSent uint64, userContext supertokens.UserContext) error
 }
 
 type OverrideStruct struct {
@@ -23,6 +23,8 @@ type RecipeInterface struct {
 	IsEmailVerified               *func(userID, email string, userContext supertokens.UserContext) (bool, error)
 	RevokeEmailVerificationTokens *func(userId, email string, userContext supertokens.UserContext) (RevokeEmailVerificationTokensResponse, error)
 	UnverifyEmail                 *func(userId, email string, userContext supertokens.UserContext) (UnverifyEmailResponse, error)
+	CreateEmailVerificationCode   *func(userID, email string, userContext supertokens.UserContext) (CreateEmailVerificationCodeResponse, error)
+	VerifyEmailUsingCode          *func(userID, email, code string, userContext supertokens.UserContext) (VerifyEmailUsingCodeResponse, error)
 }
 
 type CreateEmailVerificationTokenResponse struct {
@@ -46,3 +48,25 @@ type RevokeEmailVerificationTokensResponse struct {
 type UnverifyEmailResponse struct {
 	OK *struct{}
 }
+
+type CreateEmailVerificationCodeResponse struct {
+	OK *struct {
+		Code string
+	}
+	EmailAlreadyVerifiedError *struct{}
+}
+
+type VerifyEmailUsingCodeResponse struct {
+	OK *struct {
+		User User
+	}
+	IncorrectUserInputCodeError *struct {
+		FailedCodeInputAttemptCount int
+		MaximumCodeInputAttempts    int
+	}
+	ExpiredUserInputCodeError *struct {
+		FailedCodeInputAttemptCount int
+		MaximumCodeInputAttempts    int
+	}
+	RestartFlowError *struct{}
+}
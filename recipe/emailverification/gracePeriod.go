@@ -0,0 +1,58 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package emailverification
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/supertokens/supertokens-golang/recipe/emailverification/errors"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func getCurrTimeInMS() uint64 {
+	return uint64(time.Now().UnixNano() / 1000000)
+}
+
+// checkGracePeriodAfterSignUp is registered with the session recipe so that VerifySession
+// rejects sessions of unverified users once their grace period is over.
+func (r *Recipe) checkGracePeriodAfterSignUp(sessionContainer sessmodels.SessionContainer, req *http.Request, userContext supertokens.UserContext) error {
+	userID := sessionContainer.GetUserIDWithContext(userContext)
+	timeJoined, err := r.Config.GetTimeJoinedForUserID(userID, userContext)
+	if err != nil {
+		return err
+	}
+	if timeJoined == nil {
+		// the user belongs to another recipe
+		return nil
+	}
+	if getCurrTimeInMS() < *timeJoined+*r.Config.GracePeriodAfterSignUp {
+		return nil
+	}
+	email, err := r.Config.GetEmailForUserID(userID, userContext)
+	if err != nil {
+		return err
+	}
+	isVerified, err := (*r.RecipeImpl.IsEmailVerified)(userID, email, userContext)
+	if err != nil {
+		return err
+	}
+	if !isVerified {
+		return errors.EmailVerificationRequiredError{Msg: "email verification required"}
+	}
+	return nil
+}
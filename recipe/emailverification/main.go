@@ -64,6 +64,22 @@ func UnverifyEmailWithContext(userID, email string, userContext supertokens.User
 	return (*instance.RecipeImpl.UnverifyEmail)(userID, email, userContext)
 }
 
+func CreateEmailVerificationCodeWithContext(userID, email string, userContext supertokens.UserContext) (evmodels.CreateEmailVerificationCodeResponse, error) {
+	instance, err := getRecipeInstanceOrThrowError()
+	if err != nil {
+		return evmodels.CreateEmailVerificationCodeResponse{}, err
+	}
+	return (*instance.RecipeImpl.CreateEmailVerificationCode)(userID, email, userContext)
+}
+
+func VerifyEmailUsingCodeWithContext(userID, email, code string, userContext supertokens.UserContext) (evmodels.VerifyEmailUsingCodeResponse, error) {
+	instance, err := getRecipeInstanceOrThrowError()
+	if err != nil {
+		return evmodels.VerifyEmailUsingCodeResponse{}, err
+	}
+	return (*instance.RecipeImpl.VerifyEmailUsingCode)(userID, email, code, userContext)
+}
+
 func CreateEmailVerificationToken(userID, email string) (evmodels.CreateEmailVerificationTokenResponse, error) {
 	return CreateEmailVerificationTokenWithContext(userID, email, &map[string]interface{}{})
 }
@@ -83,3 +99,11 @@ func RevokeEmailVerificationTokens(userID, email string) (evmodels.RevokeEmailVe
 func UnverifyEmail(userID, email string) (evmodels.UnverifyEmailResponse, error) {
 	return UnverifyEmailWithContext(userID, email, &map[string]interface{}{})
 }
+
+func CreateEmailVerificationCode(userID, email string) (evmodels.CreateEmailVerificationCodeResponse, error) {
+	return CreateEmailVerificationCodeWithContext(userID, email, &map[string]interface{}{})
+}
+
+func VerifyEmailUsingCode(userID, email, code string) (evmodels.VerifyEmailUsingCodeResponse, error) {
+	return VerifyEmailUsingCodeWithContext(userID, email, code, &map[string]interface{}{})
+}
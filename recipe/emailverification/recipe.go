@@ -20,7 +20,9 @@ import (
 	"net/http"
 
 	"github.com/supertokens/supertokens-golang/recipe/emailverification/api"
+	evErrors "github.com/supertokens/supertokens-golang/recipe/emailverification/errors"
 	"github.com/supertokens/supertokens-golang/recipe/emailverification/evmodels"
+	"github.com/supertokens/supertokens-golang/recipe/session"
 	"github.com/supertokens/supertokens-golang/supertokens"
 )
 
@@ -45,9 +47,20 @@ func MakeRecipe(recipeId string, appInfo supertokens.NormalisedAppinfo, config e
 	if err != nil {
 		return Recipe{}, err
 	}
-	recipeImplementation := makeRecipeImplementation(*querierInstance)
+	err = validateCodeConfig(verifiedConfig)
+	if err != nil {
+		return Recipe{}, err
+	}
+	recipeImplementation := makeRecipeImplementation(*querierInstance, verifiedConfig, r)
 	r.RecipeImpl = verifiedConfig.Override.Functions(recipeImplementation)
 
+	if verifiedConfig.GracePeriodAfterSignUp != nil {
+		if verifiedConfig.GetTimeJoinedForUserID == nil {
+			return Recipe{}, errors.New("GetTimeJoinedForUserID must be provided when GracePeriodAfterSignUp is set")
+		}
+		session.AddVerifySessionCheck(recipeId, r.checkGracePeriodAfterSignUp)
+	}
+
 	recipeModuleInstance := supertokens.MakeRecipeModule(recipeId, appInfo, r.handleAPIRequest, r.getAllCORSHeaders, r.getAPIsHandled, r.handleError, onGeneralError)
 	r.RecipeModule = recipeModuleInstance
 
@@ -87,6 +100,11 @@ func (r *Recipe) getAPIsHandled() ([]supertokens.APIHandled, error) {
 		return nil, err
 	}
 
+	emailVerifyCodeAPINormalised, err := supertokens.NewNormalisedURLPath(emailVerifyCodeAPI)
+	if err != nil {
+		return nil, err
+	}
+
 	return []supertokens.APIHandled{{
 		Method:                 http.MethodPost,
 		PathWithoutAPIBasePath: generateEmailVerifyTokenAPINormalised,
@@ -102,6 +120,11 @@ func (r *Recipe) getAPIsHandled() ([]supertokens.APIHandled, error) {
 		PathWithoutAPIBasePath: emailVerifyAPINormalised,
 		ID:                     emailVerifyAPI,
 		Disabled:               r.APIImpl.IsEmailVerifiedGET == nil,
+	}, {
+		Method:                 http.MethodPost,
+		PathWithoutAPIBasePath: emailVerifyCodeAPINormalised,
+		ID:                     emailVerifyCodeAPI,
+		Disabled:               r.APIImpl.VerifyEmailCodePOST == nil,
 	}}, nil
 }
 
@@ -116,6 +139,8 @@ func (r *Recipe) handleAPIRequest(id string, req *http.Request, res http.Respons
 	}
 	if id == generateEmailVerifyTokenAPI {
 		return api.GenerateEmailVerifyToken(r.APIImpl, options)
+	} else if id == emailVerifyCodeAPI {
+		return api.EmailVerifyCode(r.APIImpl, options)
 	} else {
 		return api.EmailVerify(r.APIImpl, options)
 	}
@@ -126,6 +151,10 @@ func (r *Recipe) getAllCORSHeaders() []string {
 }
 
 func (r *Recipe) handleError(err error, req *http.Request, res http.ResponseWriter) (bool, error) {
+	if errors.As(err, &evErrors.EmailVerificationRequiredError{}) {
+		supertokens.LogDebugMessage("errorHandler: returning EMAIL_VERIFICATION_REQUIRED")
+		return true, supertokens.SendNon200Response(res, err.Error(), 403)
+	}
 	return false, nil
 }
 
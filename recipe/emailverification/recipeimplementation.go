@@ -16,11 +16,15 @@
 package emailverification
 
 import (
+	"crypto/subtle"
+
 	"github.com/supertokens/supertokens-golang/recipe/emailverification/evmodels"
 	"github.com/supertokens/supertokens-golang/supertokens"
 )
 
-func makeRecipeImplementation(querier supertokens.Querier) evmodels.RecipeInterface {
+// makeRecipeImplementation uses recipeInstance to call the (possibly overridden) token functions
+// and IsEmailVerified from the code functions. Its RecipeImpl is set once MakeRecipe is done.
+func makeRecipeImplementation(querier supertokens.Querier, config evmodels.TypeNormalisedInput, recipeInstance *Recipe) evmodels.RecipeInterface {
 	createEmailVerificationToken := func(userID, email string, userContext supertokens.UserContext) (evmodels.CreateEmailVerificationTokenResponse, error) {
 		response, err := querier.SendPostRequest("/recipe/user/email/verify/token", map[string]interface{}{
 			"userId": userID,
@@ -99,11 +103,126 @@ func makeRecipeImplementation(querier supertokens.Querier) evmodels.RecipeInterf
 			OK: &struct{}{},
 		}, nil
 	}
+
+	createEmailVerificationCode := func(userID, email string, userContext supertokens.UserContext) (evmodels.CreateEmailVerificationCodeResponse, error) {
+		isVerified, err := (*recipeInstance.RecipeImpl.IsEmailVerified)(userID, email, userContext)
+		if err != nil {
+			return evmodels.CreateEmailVerificationCodeResponse{}, err
+		}
+		if isVerified {
+			return evmodels.CreateEmailVerificationCodeResponse{
+				EmailAlreadyVerifiedError: &struct{}{},
+			}, nil
+		}
+
+		// the core is the source of truth for the verification status, so the code stands for an
+		// email verification token which is consumed once the right code is entered.
+		tokenResponse, err := (*recipeInstance.RecipeImpl.CreateEmailVerificationToken)(userID, email, userContext)
+		if err != nil {
+			return evmodels.CreateEmailVerificationCodeResponse{}, err
+		}
+		if tokenResponse.EmailAlreadyVerifiedError != nil {
+			return evmodels.CreateEmailVerificationCodeResponse{
+				EmailAlreadyVerifiedError: &struct{}{},
+			}, nil
+		}
+
+		code, err := generateNumericCode(config.CodeLength)
+		if err != nil {
+			return evmodels.CreateEmailVerificationCodeResponse{}, err
+		}
+		now := getCurrTimeInMS()
+		// a new code replaces the previous one and resets the number of attempts
+		err = config.CodeStore.Save(evmodels.EmailVerificationCodeInfo{
+			UserID:                      userID,
+			Email:                       email,
+			Code:                        code,
+			Token:                       tokenResponse.OK.Token,
+			TimeCreated:                 now,
+			Expiry:                      now + config.CodeLifetime,
+			FailedCodeInputAttemptCount: 0,
+		}, userContext)
+		if err != nil {
+			return evmodels.CreateEmailVerificationCodeResponse{}, err
+		}
+		return evmodels.CreateEmailVerificationCodeResponse{
+			OK: &struct{ Code string }{Code: code},
+		}, nil
+	}
+
+	verifyEmailUsingCode := func(userID, email, code string, userContext supertokens.UserContext) (evmodels.VerifyEmailUsingCodeResponse, error) {
+		// every attempt is counted before the code is checked, so that parallel
+		// requests cannot make more than MaximumCodeInputAttempts guesses.
+		codeInfo, err := config.CodeStore.IncrementAttemptCount(userID, email, userContext)
+		if err != nil {
+			return evmodels.VerifyEmailUsingCodeResponse{}, err
+		}
+		if codeInfo == nil || codeInfo.FailedCodeInputAttemptCount > config.MaximumCodeInputAttempts {
+			return evmodels.VerifyEmailUsingCodeResponse{
+				RestartFlowError: &struct{}{},
+			}, nil
+		}
+
+		if subtle.ConstantTimeCompare([]byte(codeInfo.Code), []byte(code)) != 1 {
+			if codeInfo.FailedCodeInputAttemptCount >= config.MaximumCodeInputAttempts {
+				err = config.CodeStore.Remove(userID, email, userContext)
+				if err != nil {
+					return evmodels.VerifyEmailUsingCodeResponse{}, err
+				}
+				return evmodels.VerifyEmailUsingCodeResponse{
+					RestartFlowError: &struct{}{},
+				}, nil
+			}
+			return evmodels.VerifyEmailUsingCodeResponse{
+				IncorrectUserInputCodeError: &struct {
+					FailedCodeInputAttemptCount int
+					MaximumCodeInputAttempts    int
+				}{
+					FailedCodeInputAttemptCount: codeInfo.FailedCodeInputAttemptCount,
+					MaximumCodeInputAttempts:    config.MaximumCodeInputAttempts,
+				},
+			}, nil
+		}
+
+		if codeInfo.Expiry <= getCurrTimeInMS() {
+			return evmodels.VerifyEmailUsingCodeResponse{
+				ExpiredUserInputCodeError: &struct {
+					FailedCodeInputAttemptCount int
+					MaximumCodeInputAttempts    int
+				}{
+					// the current attempt used the right code
+					FailedCodeInputAttemptCount: codeInfo.FailedCodeInputAttemptCount - 1,
+					MaximumCodeInputAttempts:    config.MaximumCodeInputAttempts,
+				},
+			}, nil
+		}
+
+		err = config.CodeStore.Remove(userID, email, userContext)
+		if err != nil {
+			return evmodels.VerifyEmailUsingCodeResponse{}, err
+		}
+
+		verifyResponse, err := (*recipeInstance.RecipeImpl.VerifyEmailUsingToken)(codeInfo.Token, userContext)
+		if err != nil {
+			return evmodels.VerifyEmailUsingCodeResponse{}, err
+		}
+		if verifyResponse.OK == nil {
+			return evmodels.VerifyEmailUsingCodeResponse{
+				RestartFlowError: &struct{}{},
+			}, nil
+		}
+		return evmodels.VerifyEmailUsingCodeResponse{
+			OK: &struct{ User evmodels.User }{User: verifyResponse.OK.User},
+		}, nil
+	}
+
 	return evmodels.RecipeInterface{
 		CreateEmailVerificationToken:  &createEmailVerificationToken,
 		VerifyEmailUsingToken:         &verifyEmailUsingToken,
 		IsEmailVerified:               &isEmailVerified,
 		RevokeEmailVerificationTokens: &revokeEmailVerificationTokens,
 		UnverifyEmail:                 &unverifyEmail,
+		CreateEmailVerificationCode:   &createEmailVerificationCode,
+		VerifyEmailUsingCode:          &verifyEmailUsingCode,
 	}
 }
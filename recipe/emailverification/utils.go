@@ -45,16 +45,80 @@ func validateAndNormaliseUserInput(appInfo supertokens.NormalisedAppinfo, config
 	if config.GetEmailForUserID != nil {
 		typeNormalisedInput.GetEmailForUserID = config.GetEmailForUserID
 	}
+
+	if config.ResendCooldown != nil {
+		typeNormalisedInput.ResendCooldown = *config.ResendCooldown
+	}
+
+	typeNormalisedInput.ResendCooldownStore = config.ResendCooldownStore
+
+	typeNormalisedInput.GracePeriodAfterSignUp = config.GracePeriodAfterSignUp
+
+	if config.GetTimeJoinedForUserID != nil {
+		typeNormalisedInput.GetTimeJoinedForUserID = config.GetTimeJoinedForUserID
+	}
+
+	if config.Mode != "" {
+		typeNormalisedInput.Mode = config.Mode
+	}
+
+	typeNormalisedInput.CreateAndSendEmailWithCode = config.CreateAndSendEmailWithCode
+
+	if config.CodeLength != nil {
+		typeNormalisedInput.CodeLength = *config.CodeLength
+	}
+
+	if config.CodeLifetime != nil {
+		typeNormalisedInput.CodeLifetime = *config.CodeLifetime
+	}
+
+	if config.MaximumCodeInputAttempts != nil {
+		typeNormalisedInput.MaximumCodeInputAttempts = *config.MaximumCodeInputAttempts
+	}
+
+	typeNormalisedInput.CodeStore = config.CodeStore
 	return typeNormalisedInput
 }
 
+func validateCodeConfig(config evmodels.TypeNormalisedInput) error {
+	if config.Mode != verificationModeLink && config.Mode != verificationModeCode {
+		return errors.New("Mode must be either \"LINK\" or \"CODE\"")
+	}
+	if config.Mode == verificationModeCode && config.CreateAndSendEmailWithCode == nil {
+		return errors.New("CreateAndSendEmailWithCode must be provided when Mode is \"CODE\"")
+	}
+	if config.Mode == verificationModeCode && config.CodeStore == nil {
+		return errors.New("please provide a CodeStore in the email verification config when Mode is \"CODE\"")
+	}
+	if config.ResendCooldown > 0 && config.ResendCooldownStore == nil {
+		return errors.New("please provide a ResendCooldownStore in the email verification config when ResendCooldown is set")
+	}
+	if config.CodeLength <= 0 {
+		return errors.New("CodeLength must be greater than 0")
+	}
+	if config.MaximumCodeInputAttempts <= 0 {
+		return errors.New("MaximumCodeInputAttempts must be greater than 0")
+	}
+	return nil
+}
+
 func makeTypeNormalisedInput(appInfo supertokens.NormalisedAppinfo) evmodels.TypeNormalisedInput {
 	return evmodels.TypeNormalisedInput{
 		GetEmailForUserID: func(userID string, userContext supertokens.UserContext) (string, error) {
 			return "", errors.New("not defined by user")
 		},
-		GetEmailVerificationURL:  DefaultGetEmailVerificationURL(appInfo),
-		CreateAndSendCustomEmail: DefaultCreateAndSendCustomEmail(appInfo),
+		GetEmailVerificationURL:    DefaultGetEmailVerificationURL(appInfo),
+		CreateAndSendCustomEmail:   DefaultCreateAndSendCustomEmail(appInfo),
+		ResendCooldown:             0,
+		ResendCooldownStore:        nil,
+		GracePeriodAfterSignUp:     nil,
+		GetTimeJoinedForUserID:     nil,
+		Mode:                       verificationModeLink,
+		CreateAndSendEmailWithCode: nil,
+		CodeLength:                 defaultCodeLength,
+		CodeLifetime:               defaultCodeLifetime,
+		MaximumCodeInputAttempts:   defaultMaximumCodeInputAttempts,
+		CodeStore:                  nil,
 		Override: evmodels.OverrideStruct{
 			Functions: func(originalImplementation evmodels.RecipeInterface) evmodels.RecipeInterface {
 				return originalImplementation
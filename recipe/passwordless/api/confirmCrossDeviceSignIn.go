@@ -0,0 +1,70 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package api
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"reflect"
+
+	"github.com/supertokens/supertokens-golang/recipe/passwordless/plessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func ConfirmCrossDeviceSignIn(apiImplementation plessmodels.APIInterface, options plessmodels.APIOptions) error {
+	if apiImplementation.ConfirmCrossDeviceSignInPOST == nil || (*apiImplementation.ConfirmCrossDeviceSignInPOST) == nil {
+		options.OtherHandler(options.Res, options.Req)
+		return nil
+	}
+
+	body, err := ioutil.ReadAll(options.Req.Body)
+	if err != nil {
+		return err
+	}
+	var readBody map[string]interface{}
+	err = json.Unmarshal(body, &readBody)
+	if err != nil {
+		return err
+	}
+
+	preAuthSessionID, okPreAuthSessionID := readBody["preAuthSessionId"]
+	if !okPreAuthSessionID || reflect.ValueOf(preAuthSessionID).Kind() != reflect.String {
+		return supertokens.BadInputError{Msg: "Please provide preAuthSessionId"}
+	}
+
+	response, err := (*apiImplementation.ConfirmCrossDeviceSignInPOST)(preAuthSessionID.(string), options, &map[string]interface{}{})
+	if err != nil {
+		return err
+	}
+
+	var result map[string]interface{}
+
+	if response.OK != nil {
+		result = map[string]interface{}{
+			"status": "OK",
+		}
+	} else if response.NoPendingSignInError != nil {
+		result = map[string]interface{}{
+			"status": "NO_PENDING_SIGN_IN_ERROR",
+		}
+	} else {
+		result = map[string]interface{}{
+			"status": "RESTART_FLOW_ERROR",
+		}
+	}
+
+	return supertokens.Send200Response(options.Res, result)
+}
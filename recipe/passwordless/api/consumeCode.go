@@ -119,6 +119,11 @@ func ConsumeCode(apiImplementation plessmodels.APIInterface, options plessmodels
 		result = map[string]interface{}{
 			"status": "RESTART_FLOW_ERROR",
 		}
+	} else if response.CrossDeviceLinkError != nil {
+		result = map[string]interface{}{
+			"status":               "CROSS_DEVICE_LINK_ERROR",
+			"confirmationRequired": response.CrossDeviceLinkError.ConfirmationRequired,
+		}
 	} else {
 		result = map[string]interface{}{
 			"status":  "GENERAL_ERROR",
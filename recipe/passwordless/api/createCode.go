@@ -19,9 +19,9 @@ import (
 	"encoding/json"
 	"io/ioutil"
 	"reflect"
+	"strconv"
 	"strings"
 
-	"github.com/nyaruka/phonenumbers"
 	"github.com/supertokens/supertokens-golang/recipe/passwordless/plessmodels"
 	"github.com/supertokens/supertokens-golang/supertokens"
 )
@@ -96,13 +96,13 @@ func CreateCode(apiImplementation plessmodels.APIInterface, options plessmodels.
 			})
 		}
 
-		parsedPhoneNumber, err := phonenumbers.Parse(phoneNumber.(string), "")
-		if err != nil {
-			// this can come here if the user has provided their own impl of ValidatePhoneNumber and
-			// the phone number is valid according to their impl, but not according to the phonenumbers lib.
-			phoneNumber = strings.TrimSpace(phoneNumber.(string))
-		} else {
-			phoneNumber = phonenumbers.Format(parsedPhoneNumber, phonenumbers.E164)
+		phoneNumber = NormalisePhoneNumber(phoneNumber.(string), options.Config.PhoneNumberPolicy.DefaultRegion)
+		policyErr := checkPhoneNumberPolicy(phoneNumber.(string), options.Config.PhoneNumberPolicy)
+		if policyErr != nil {
+			return supertokens.Send200Response(options.Res, map[string]interface{}{
+				"status":  "GENERAL_ERROR",
+				"message": *policyErr,
+			})
 		}
 	}
 
@@ -131,6 +131,16 @@ func CreateCode(apiImplementation plessmodels.APIInterface, options plessmodels.
 			"preAuthSessionId": response.OK.PreAuthSessionID,
 			"flowType":         response.OK.FlowType,
 		}
+	} else if response.SignUpNotAllowedError != nil {
+		result = map[string]interface{}{
+			"status": "SIGN_UP_NOT_ALLOWED_ERROR",
+		}
+	} else if response.TooManyRequestsError != nil {
+		options.Res.Header().Set("Retry-After", strconv.FormatUint(response.TooManyRequestsError.RetryAfter, 10))
+		result = map[string]interface{}{
+			"status":     "TOO_MANY_REQUESTS_ERROR",
+			"retryAfter": response.TooManyRequestsError.RetryAfter,
+		}
 	} else {
 		result = map[string]interface{}{
 			"status":  "GENERAL_ERROR",
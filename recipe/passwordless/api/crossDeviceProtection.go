@@ -0,0 +1,130 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package api
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/supertokens/supertokens-golang/recipe/passwordless/plessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// magic links are bound to the browser that requested them using this cookie, which holds the device ID
+const deviceIDCookieName = "sPasswordlessDeviceId"
+
+func setDeviceIDCookie(options plessmodels.APIOptions, deviceID string, expiry uint64) {
+	if options.Config.CrossDeviceProtection == nil {
+		return
+	}
+	// the API can be on another site than the website, in which case browsers only send the
+	// cookie if it uses SameSite=None, which requires https
+	secure := strings.HasPrefix(options.AppInfo.APIDomain.GetAsStringDangerous(), "https")
+	sameSite := http.SameSiteLaxMode
+	if secure {
+		sameSite = http.SameSiteNoneMode
+	}
+	path := options.AppInfo.APIBasePath.GetAsStringDangerous()
+	if path == "" {
+		path = "/"
+	}
+	http.SetCookie(options.Res, &http.Cookie{
+		Name:     deviceIDCookieName,
+		Value:    deviceID,
+		Path:     path,
+		Expires:  time.Unix(int64(expiry/1000), 0),
+		Secure:   secure,
+		HttpOnly: true,
+		SameSite: sameSite,
+	})
+}
+
+// isOriginalDevice checks if the request comes from the browser that requested the codes of preAuthSessionID
+func isOriginalDevice(preAuthSessionID string, options plessmodels.APIOptions, userContext supertokens.UserContext) (bool, error) {
+	cookie, err := options.Req.Cookie(deviceIDCookieName)
+	if err != nil || cookie.Value == "" {
+		return false, nil
+	}
+	device, err := (*options.RecipeImplementation.ListCodesByDeviceID)(cookie.Value, userContext)
+	if err != nil {
+		return false, err
+	}
+	return device != nil && device.PreAuthSessionID == preAuthSessionID, nil
+}
+
+// checkCrossDeviceLink returns the error to respond with if the magic link of preAuthSessionID cannot
+// be used on this device yet. In the "REQUIRE_CONFIRMATION" mode, the sign in is saved so that it can
+// be confirmed on the original device.
+func checkCrossDeviceLink(preAuthSessionID string, options plessmodels.APIOptions, userContext supertokens.UserContext) (*struct{ ConfirmationRequired bool }, error) {
+	protection := options.Config.CrossDeviceProtection
+	if protection == nil {
+		return nil, nil
+	}
+	isOriginal, err := isOriginalDevice(preAuthSessionID, options, userContext)
+	if err != nil {
+		return nil, err
+	}
+	if isOriginal {
+		return nil, nil
+	}
+	if protection.OnOtherDevice == "REQUIRE_USER_INPUT_CODE" {
+		return &struct{ ConfirmationRequired bool }{
+			ConfirmationRequired: false,
+		}, nil
+	}
+
+	now := getCurrTimeInMS()
+	info, err := protection.Store.Get(preAuthSessionID, userContext)
+	if err != nil {
+		return nil, err
+	}
+	if info != nil && info.Expiry > now {
+		if info.Confirmed {
+			err = protection.Store.Remove(preAuthSessionID, userContext)
+			if err != nil {
+				return nil, err
+			}
+			return nil, nil
+		}
+	} else {
+		device, err := (*options.RecipeImplementation.ListCodesByPreAuthSessionID)(preAuthSessionID, userContext)
+		if err != nil {
+			return nil, err
+		}
+		if device == nil {
+			// the codes have expired or been used, which consuming the link reports
+			return nil, nil
+		}
+		var expiry uint64
+		for _, code := range device.Codes {
+			if code.TimeCreated+code.CodeLifetime > expiry {
+				expiry = code.TimeCreated + code.CodeLifetime
+			}
+		}
+		err = protection.Store.Save(plessmodels.CrossDeviceSignInInfo{
+			PreAuthSessionID: preAuthSessionID,
+			Confirmed:        false,
+			Expiry:           expiry,
+		}, userContext)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &struct{ ConfirmationRequired bool }{
+		ConfirmationRequired: true,
+	}, nil
+}
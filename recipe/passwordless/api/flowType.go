@@ -0,0 +1,69 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package api
+
+import (
+	"github.com/supertokens/supertokens-golang/recipe/passwordless/plessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// getFlowType returns the flow type to use for sending a code to the email or phone number of this request
+func getFlowType(email *string, options plessmodels.APIOptions, userContext supertokens.UserContext) (plessmodels.FlowType, error) {
+	if options.Config.GetFlowType == nil {
+		return options.Config.FlowType, nil
+	}
+
+	contactMethod := "PHONE"
+	if email != nil {
+		contactMethod = "EMAIL"
+	}
+	flowType, err := options.Config.GetFlowType(options.Req, contactMethod, userContext)
+	if err != nil {
+		return "", err
+	}
+	if !options.Config.AllowedFlowTypes[flowType] {
+		return "", supertokens.BadInputError{Msg: "The flow type \"" + string(flowType) + "\" is not allowed"}
+	}
+	return flowType, nil
+}
+
+// saveFlowTypeOfDevice keeps the flow type chosen for a device until its latest code expires
+func saveFlowTypeOfDevice(deviceID string, flowType plessmodels.FlowType, expiry uint64, options plessmodels.APIOptions, userContext supertokens.UserContext) error {
+	if options.Config.GetFlowType == nil {
+		return nil
+	}
+	return options.Config.FlowTypeStore.Save(plessmodels.DeviceFlowTypeInfo{
+		DeviceID: deviceID,
+		FlowType: flowType,
+		Expiry:   expiry,
+	}, userContext)
+}
+
+// getFlowTypeOfDevice returns the flow type that was chosen when the codes of the device were created,
+// or nil if it is not known anymore
+func getFlowTypeOfDevice(deviceID string, options plessmodels.APIOptions, userContext supertokens.UserContext) (*plessmodels.FlowType, error) {
+	if options.Config.GetFlowType == nil {
+		return &options.Config.FlowType, nil
+	}
+	info, err := options.Config.FlowTypeStore.Get(deviceID, userContext)
+	if err != nil {
+		return nil, err
+	}
+	if info == nil || info.Expiry <= getCurrTimeInMS() {
+		return nil, nil
+	}
+	return &info.FlowType, nil
+}
@@ -16,6 +16,8 @@
 package api
 
 import (
+	"errors"
+
 	"github.com/supertokens/supertokens-golang/recipe/passwordless/plessmodels"
 	"github.com/supertokens/supertokens-golang/recipe/session"
 	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
@@ -25,6 +27,37 @@ import (
 func MakeAPIImplementation() plessmodels.APIInterface {
 
 	consumeCodePOST := func(userInput *plessmodels.UserInputCodeWithDeviceID, linkCode *string, preAuthSessionID string, options plessmodels.APIOptions, userContext supertokens.UserContext) (plessmodels.ConsumeCodePOSTResponse, error) {
+		if options.Config.ShouldAllowSignUp != nil {
+			// codes of refused sign ups are never sent, but they must not create a user if guessed
+			deviceInfo, err := (*options.RecipeImplementation.ListCodesByPreAuthSessionID)(preAuthSessionID, userContext)
+			if err != nil {
+				return plessmodels.ConsumeCodePOSTResponse{}, err
+			}
+			if deviceInfo != nil {
+				isSignUpAllowed, err := checkSignUpAllowed(deviceInfo.Email, deviceInfo.PhoneNumber, options, userContext)
+				if err != nil {
+					return plessmodels.ConsumeCodePOSTResponse{}, err
+				}
+				if !isSignUpAllowed {
+					return plessmodels.ConsumeCodePOSTResponse{
+						RestartFlowError: &struct{}{},
+					}, nil
+				}
+			}
+		}
+
+		if linkCode != nil {
+			crossDeviceLinkError, err := checkCrossDeviceLink(preAuthSessionID, options, userContext)
+			if err != nil {
+				return plessmodels.ConsumeCodePOSTResponse{}, err
+			}
+			if crossDeviceLinkError != nil {
+				return plessmodels.ConsumeCodePOSTResponse{
+					CrossDeviceLinkError: crossDeviceLinkError,
+				}, nil
+			}
+		}
+
 		response, err := (*options.RecipeImplementation.ConsumeCode)(userInput, linkCode, preAuthSessionID, userContext)
 		if err != nil {
 			return plessmodels.ConsumeCodePOSTResponse{}, err
@@ -59,6 +92,32 @@ func MakeAPIImplementation() plessmodels.APIInterface {
 	}
 
 	createCodePOST := func(email *string, phoneNumber *string, options plessmodels.APIOptions, userContext supertokens.UserContext) (plessmodels.CreateCodePOSTResponse, error) {
+		retryAfter, err := checkRateLimit(email, phoneNumber, options, userContext)
+		if err != nil {
+			return plessmodels.CreateCodePOSTResponse{}, err
+		}
+		if retryAfter != nil {
+			return plessmodels.CreateCodePOSTResponse{
+				TooManyRequestsError: &struct{ RetryAfter uint64 }{
+					RetryAfter: *retryAfter,
+				},
+			}, nil
+		}
+
+		flowType, err := getFlowType(email, options, userContext)
+		if err != nil {
+			return plessmodels.CreateCodePOSTResponse{}, err
+		}
+
+		isSignUpAllowed, err := checkSignUpAllowed(email, phoneNumber, options, userContext)
+		if err != nil {
+			return plessmodels.CreateCodePOSTResponse{}, err
+		}
+		if !isSignUpAllowed && options.Config.RevealSignUpNotAllowed {
+			return plessmodels.CreateCodePOSTResponse{
+				SignUpNotAllowedError: &struct{}{},
+			}, nil
+		}
 
 		var userInputCodeInput *string
 		if options.Config.GetCustomUserInputCode != nil {
@@ -73,12 +132,33 @@ func MakeAPIImplementation() plessmodels.APIInterface {
 		if err != nil {
 			return plessmodels.CreateCodePOSTResponse{}, err
 		}
+		expiry := getCurrTimeInMS() + response.OK.CodeLifetime
+		setDeviceIDCookie(options, response.OK.DeviceID, expiry)
+		err = saveFlowTypeOfDevice(response.OK.DeviceID, flowType, expiry, options, userContext)
+		if err != nil {
+			return plessmodels.CreateCodePOSTResponse{}, err
+		}
+
+		if !isSignUpAllowed {
+			// the device is created like for any other request, so that neither this response nor
+			// later resends reveal that the sign up was refused, but the code is never sent
+			return plessmodels.CreateCodePOSTResponse{
+				OK: &struct {
+					DeviceID         string
+					PreAuthSessionID string
+					FlowType         plessmodels.FlowType
+				}{
+					DeviceID:         response.OK.DeviceID,
+					PreAuthSessionID: response.OK.PreAuthSessionID,
+					FlowType:         flowType,
+				},
+			}, nil
+		}
 
 		// now we will send an email / text message
 		var magicLink *string
 		var userInputCode *string
-		flowType := options.Config.FlowType
-		if flowType == "MAGIC_LINK" || flowType == "USER_INPUT_CODE_AND_MAGIC_LINK" {
+		if flowType.UsesMagicLink() {
 			link, err := options.Config.GetLinkDomainAndPath(email, phoneNumber, userContext)
 			if err != nil {
 				return plessmodels.CreateCodePOSTResponse{}, err
@@ -88,7 +168,7 @@ func MakeAPIImplementation() plessmodels.APIInterface {
 			magicLink = &link
 		}
 
-		if flowType == "USER_INPUT_CODE" || flowType == "USER_INPUT_CODE_AND_MAGIC_LINK" {
+		if flowType.UsesUserInputCode() {
 			userInputCode = &response.OK.UserInputCode
 		}
 
@@ -138,11 +218,11 @@ func MakeAPIImplementation() plessmodels.APIInterface {
 			OK: &struct {
 				DeviceID         string
 				PreAuthSessionID string
-				FlowType         string
+				FlowType         plessmodels.FlowType
 			}{
 				DeviceID:         response.OK.DeviceID,
 				PreAuthSessionID: response.OK.PreAuthSessionID,
-				FlowType:         options.Config.FlowType,
+				FlowType:         flowType,
 			},
 		}, nil
 	}
@@ -191,6 +271,37 @@ func MakeAPIImplementation() plessmodels.APIInterface {
 			}, nil
 		}
 
+		retryAfter := checkResendCooldown(*deviceInfo, options)
+		if retryAfter == nil {
+			retryAfter, err = checkRateLimit(deviceInfo.Email, deviceInfo.PhoneNumber, options, userContext)
+			if err != nil {
+				return plessmodels.ResendCodePOSTResponse{}, err
+			}
+		}
+		if retryAfter != nil {
+			return plessmodels.ResendCodePOSTResponse{
+				TooManyRequestsError: &struct{ RetryAfter uint64 }{
+					RetryAfter: *retryAfter,
+				},
+			}, nil
+		}
+
+		// the codes are resent the way they were sent when the device was created
+		flowType, err := getFlowTypeOfDevice(deviceID, options, userContext)
+		if err != nil {
+			return plessmodels.ResendCodePOSTResponse{}, err
+		}
+		if flowType == nil {
+			return plessmodels.ResendCodePOSTResponse{
+				ResetFlowError: &struct{}{},
+			}, nil
+		}
+
+		isSignUpAllowed, err := checkSignUpAllowed(deviceInfo.Email, deviceInfo.PhoneNumber, options, userContext)
+		if err != nil {
+			return plessmodels.ResendCodePOSTResponse{}, err
+		}
+
 		for numberOfTriesToCreateNewCode := 0; numberOfTriesToCreateNewCode < 3; numberOfTriesToCreateNewCode++ {
 			var userInputCodeInput *string
 			if options.Config.GetCustomUserInputCode != nil {
@@ -215,10 +326,23 @@ func MakeAPIImplementation() plessmodels.APIInterface {
 				}, nil
 			}
 
+			expiry := getCurrTimeInMS() + response.OK.CodeLifetime
+			err = saveFlowTypeOfDevice(deviceID, *flowType, expiry, options, userContext)
+			if err != nil {
+				return plessmodels.ResendCodePOSTResponse{}, err
+			}
+
+			if !isSignUpAllowed {
+				// like in createCodePOST, codes of refused sign ups are never sent
+				setDeviceIDCookie(options, deviceID, expiry)
+				return plessmodels.ResendCodePOSTResponse{
+					OK: &struct{}{},
+				}, nil
+			}
+
 			var magicLink *string
 			var userInputCode *string
-			flowType := options.Config.FlowType
-			if flowType == "MAGIC_LINK" || flowType == "USER_INPUT_CODE_AND_MAGIC_LINK" {
+			if (*flowType).UsesMagicLink() {
 				link, err := options.Config.GetLinkDomainAndPath(deviceInfo.Email, deviceInfo.PhoneNumber, userContext)
 				if err != nil {
 					return plessmodels.ResendCodePOSTResponse{}, err
@@ -228,7 +352,7 @@ func MakeAPIImplementation() plessmodels.APIInterface {
 				magicLink = &link
 			}
 
-			if flowType == "USER_INPUT_CODE" || flowType == "USER_INPUT_CODE_AND_MAGIC_LINK" {
+			if (*flowType).UsesUserInputCode() {
 				userInputCode = &response.OK.UserInputCode
 			}
 
@@ -274,6 +398,7 @@ func MakeAPIImplementation() plessmodels.APIInterface {
 				}
 			}
 
+			setDeviceIDCookie(options, deviceID, expiry)
 			return plessmodels.ResendCodePOSTResponse{
 				OK: &struct{}{},
 			}, nil
@@ -287,11 +412,161 @@ func MakeAPIImplementation() plessmodels.APIInterface {
 		}, nil
 	}
 
+	confirmCrossDeviceSignInPOST := func(preAuthSessionID string, options plessmodels.APIOptions, userContext supertokens.UserContext) (plessmodels.ConfirmCrossDeviceSignInPOSTResponse, error) {
+		isOriginal, err := isOriginalDevice(preAuthSessionID, options, userContext)
+		if err != nil {
+			return plessmodels.ConfirmCrossDeviceSignInPOSTResponse{}, err
+		}
+		if !isOriginal || options.Config.CrossDeviceProtection == nil {
+			return plessmodels.ConfirmCrossDeviceSignInPOSTResponse{
+				RestartFlowError: &struct{}{},
+			}, nil
+		}
+
+		store := options.Config.CrossDeviceProtection.Store
+		if store == nil {
+			// sign ins only wait for confirmation in the "REQUIRE_CONFIRMATION" mode, which has a store
+			return plessmodels.ConfirmCrossDeviceSignInPOSTResponse{
+				NoPendingSignInError: &struct{}{},
+			}, nil
+		}
+		info, err := store.Get(preAuthSessionID, userContext)
+		if err != nil {
+			return plessmodels.ConfirmCrossDeviceSignInPOSTResponse{}, err
+		}
+		if info == nil || info.Expiry <= getCurrTimeInMS() {
+			return plessmodels.ConfirmCrossDeviceSignInPOSTResponse{
+				NoPendingSignInError: &struct{}{},
+			}, nil
+		}
+		info.Confirmed = true
+		err = store.Save(*info, userContext)
+		if err != nil {
+			return plessmodels.ConfirmCrossDeviceSignInPOSTResponse{}, err
+		}
+		return plessmodels.ConfirmCrossDeviceSignInPOSTResponse{
+			OK: &struct{}{},
+		}, nil
+	}
+
+	createSecondFactorCodePOST := func(options plessmodels.APIOptions, userContext supertokens.UserContext) (plessmodels.CreateSecondFactorCodePOSTResponse, error) {
+		if options.Config.SecondFactor == nil {
+			return plessmodels.CreateSecondFactorCodePOSTResponse{}, errors.New("please set the SecondFactor config to send second factor codes")
+		}
+
+		sessionContainer, err := session.GetSessionWithContext(options.Req, options.Res, nil, userContext)
+		if err != nil {
+			return plessmodels.CreateSecondFactorCodePOSTResponse{}, err
+		}
+		if sessionContainer == nil {
+			return plessmodels.CreateSecondFactorCodePOSTResponse{}, supertokens.BadInputError{Msg: "Session is undefined. Should not come here."}
+		}
+
+		email, phoneNumber, err := getSecondFactorDestination(sessionContainer.GetUserIDWithContext(userContext), options, userContext)
+		if err != nil {
+			return plessmodels.CreateSecondFactorCodePOSTResponse{}, err
+		}
+		if email == nil && phoneNumber == nil {
+			return plessmodels.CreateSecondFactorCodePOSTResponse{
+				NoContactInfoError: &struct{}{},
+			}, nil
+		}
+
+		retryAfter, err := checkRateLimit(email, phoneNumber, options, userContext)
+		if err != nil {
+			return plessmodels.CreateSecondFactorCodePOSTResponse{}, err
+		}
+		if retryAfter != nil {
+			return plessmodels.CreateSecondFactorCodePOSTResponse{
+				TooManyRequestsError: &struct{ RetryAfter uint64 }{
+					RetryAfter: *retryAfter,
+				},
+			}, nil
+		}
+
+		var userInputCodeInput *string
+		if options.Config.GetCustomUserInputCode != nil {
+			c, err := options.Config.GetCustomUserInputCode(userContext)
+			if err != nil {
+				return plessmodels.CreateSecondFactorCodePOSTResponse{}, err
+			}
+			userInputCodeInput = &c
+		}
+
+		response, err := (*options.RecipeImplementation.CreateCode)(email, phoneNumber, userInputCodeInput, userContext)
+		if err != nil {
+			return plessmodels.CreateSecondFactorCodePOSTResponse{}, err
+		}
+		// a new code replaces the previous one of the session and resets the number of attempts
+		err = options.Config.SecondFactor.CodeStore.Save(plessmodels.SecondFactorCodeInfo{
+			SessionHandle:               sessionContainer.GetHandleWithContext(userContext),
+			DeviceID:                    response.OK.DeviceID,
+			CodeID:                      response.OK.CodeID,
+			UserInputCode:               response.OK.UserInputCode,
+			Expiry:                      response.OK.TimeCreated + response.OK.CodeLifetime,
+			FailedCodeInputAttemptCount: 0,
+		}, userContext)
+		if err != nil {
+			return plessmodels.CreateSecondFactorCodePOSTResponse{}, err
+		}
+
+		err = sendSecondFactorCode(email, phoneNumber, *response.OK, options, userContext)
+		if err != nil {
+			return plessmodels.CreateSecondFactorCodePOSTResponse{
+				GeneralError: &struct{ Message string }{
+					Message: err.Error(),
+				},
+			}, nil
+		}
+
+		return plessmodels.CreateSecondFactorCodePOSTResponse{
+			OK: &struct{ CodeLifetime uint64 }{
+				CodeLifetime: response.OK.CodeLifetime,
+			},
+		}, nil
+	}
+
+	consumeSecondFactorCodePOST := func(userInputCode string, options plessmodels.APIOptions, userContext supertokens.UserContext) (plessmodels.ConsumeSecondFactorCodePOSTResponse, error) {
+		if options.Config.SecondFactor == nil {
+			return plessmodels.ConsumeSecondFactorCodePOSTResponse{}, errors.New("please set the SecondFactor config to consume second factor codes")
+		}
+
+		sessionContainer, err := session.GetSessionWithContext(options.Req, options.Res, nil, userContext)
+		if err != nil {
+			return plessmodels.ConsumeSecondFactorCodePOSTResponse{}, err
+		}
+		if sessionContainer == nil {
+			return plessmodels.ConsumeSecondFactorCodePOSTResponse{}, supertokens.BadInputError{Msg: "Session is undefined. Should not come here."}
+		}
+
+		response, err := consumeSecondFactorCode(sessionContainer.GetHandleWithContext(userContext), userInputCode, options, userContext)
+		if err != nil || response.OK == nil {
+			return response, err
+		}
+
+		accessTokenPayload := map[string]interface{}{}
+		for key, value := range sessionContainer.GetAccessTokenPayloadWithContext(userContext) {
+			accessTokenPayload[key] = value
+		}
+		accessTokenPayload[options.Config.SecondFactor.AccessTokenPayloadKey] = getCurrTimeInMS()
+		err = sessionContainer.UpdateAccessTokenPayloadWithContext(accessTokenPayload, userContext)
+		if err != nil {
+			return plessmodels.ConsumeSecondFactorCodePOSTResponse{}, err
+		}
+
+		return plessmodels.ConsumeSecondFactorCodePOSTResponse{
+			OK: &struct{}{},
+		}, nil
+	}
+
 	return plessmodels.APIInterface{
-		ConsumeCodePOST:      &consumeCodePOST,
-		CreateCodePOST:       &createCodePOST,
-		EmailExistsGET:       &emailExistsGET,
-		PhoneNumberExistsGET: &phoneNumberExistsGET,
-		ResendCodePOST:       &resendCodePOST,
+		ConsumeCodePOST:              &consumeCodePOST,
+		CreateCodePOST:               &createCodePOST,
+		EmailExistsGET:               &emailExistsGET,
+		PhoneNumberExistsGET:         &phoneNumberExistsGET,
+		ResendCodePOST:               &resendCodePOST,
+		ConfirmCrossDeviceSignInPOST: &confirmCrossDeviceSignInPOST,
+		CreateSecondFactorCodePOST:   &createSecondFactorCodePOST,
+		ConsumeSecondFactorCodePOST:  &consumeSecondFactorCodePOST,
 	}
 }
@@ -0,0 +1,62 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package api
+
+import (
+	"strings"
+
+	"github.com/nyaruka/phonenumbers"
+	"github.com/supertokens/supertokens-golang/recipe/passwordless/plessmodels"
+)
+
+// NormalisePhoneNumber formats the phone number in the E.164 format, using the default region for
+// numbers given without a country code.
+func NormalisePhoneNumber(phoneNumber string, defaultRegion string) string {
+	phoneNumber = strings.TrimSpace(phoneNumber)
+	parsedPhoneNumber, err := phonenumbers.Parse(phoneNumber, defaultRegion)
+	if err != nil {
+		// this can come here if the user has provided their own impl of ValidatePhoneNumber and
+		// the phone number is valid according to their impl, but not according to the phonenumbers lib.
+		return phoneNumber
+	}
+	return phonenumbers.Format(parsedPhoneNumber, phonenumbers.E164)
+}
+
+// checkPhoneNumberPolicy returns an error message if the (normalised) phone number cannot be used
+// because of its country or type.
+func checkPhoneNumberPolicy(phoneNumber string, policy plessmodels.TypeNormalisedInputPhoneNumberPolicy) *string {
+	if len(policy.AllowedRegions) == 0 && len(policy.BlockedRegions) == 0 && len(policy.AllowedNumberTypes) == 0 && len(policy.BlockedNumberTypes) == 0 {
+		return nil
+	}
+	parsedPhoneNumber, err := phonenumbers.Parse(phoneNumber, policy.DefaultRegion)
+	if err != nil {
+		msg := "Phone number is invalid"
+		return &msg
+	}
+
+	region := phonenumbers.GetRegionCodeForNumber(parsedPhoneNumber)
+	if (len(policy.AllowedRegions) > 0 && !policy.AllowedRegions[region]) || policy.BlockedRegions[region] {
+		msg := "Phone numbers from this country are not allowed"
+		return &msg
+	}
+
+	numberType := phonenumbers.GetNumberType(parsedPhoneNumber)
+	if (len(policy.AllowedNumberTypes) > 0 && !policy.AllowedNumberTypes[numberType]) || policy.BlockedNumberTypes[numberType] {
+		msg := "This type of phone number is not allowed"
+		return &msg
+	}
+	return nil
+}
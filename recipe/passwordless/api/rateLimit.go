@@ -0,0 +1,97 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package api
+
+import (
+	"strings"
+	"time"
+
+	"github.com/supertokens/supertokens-golang/recipe/passwordless/plessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func getCurrTimeInMS() uint64 {
+	return uint64(time.Now().UnixNano() / 1000000)
+}
+
+// checkRateLimit counts a request to send a code to the destination against the configured limits.
+// It returns the number of seconds after which the request can be retried if one of them is exceeded.
+func checkRateLimit(email *string, phoneNumber *string, options plessmodels.APIOptions, userContext supertokens.UserContext) (*uint64, error) {
+	rateLimit := options.Config.RateLimit
+	if rateLimit == nil {
+		return nil, nil
+	}
+
+	ipAddress, err := rateLimit.GetIPAddress(options.Req, userContext)
+	if err != nil {
+		return nil, err
+	}
+	retryAfter, err := incrementRateLimitCounter("ip:"+ipAddress, rateLimit.PerIPAddress, rateLimit.Store, userContext)
+	if err != nil || retryAfter != nil {
+		return retryAfter, err
+	}
+
+	var destinationKey string
+	if email != nil {
+		// the same email in a different case reaches the same inbox
+		destinationKey = "email:" + strings.ToLower(*email)
+	} else if phoneNumber != nil {
+		destinationKey = "phone:" + *phoneNumber
+	} else {
+		return nil, nil
+	}
+	return incrementRateLimitCounter(destinationKey, rateLimit.PerDestination, rateLimit.Store, userContext)
+}
+
+func incrementRateLimitCounter(key string, window plessmodels.RateLimitWindow, store plessmodels.RateLimitStore, userContext supertokens.UserContext) (*uint64, error) {
+	counter, err := store.Increment(key, window.Duration, userContext)
+	if err != nil {
+		return nil, err
+	}
+	if counter.Count <= window.MaxRequests {
+		return nil, nil
+	}
+	return getRetryAfter(counter.WindowStart + window.Duration), nil
+}
+
+// checkResendCooldown returns the number of seconds after which a new code can be sent for the device,
+// or nil if the latest code of the device was created before the cooldown.
+func checkResendCooldown(device plessmodels.DeviceType, options plessmodels.APIOptions) *uint64 {
+	rateLimit := options.Config.RateLimit
+	if rateLimit == nil || rateLimit.ResendCooldown == 0 {
+		return nil
+	}
+	var lastCodeTimeCreated uint64
+	for _, code := range device.Codes {
+		if code.TimeCreated > lastCodeTimeCreated {
+			lastCodeTimeCreated = code.TimeCreated
+		}
+	}
+	if getCurrTimeInMS() >= lastCodeTimeCreated+rateLimit.ResendCooldown {
+		return nil
+	}
+	return getRetryAfter(lastCodeTimeCreated + rateLimit.ResendCooldown)
+}
+
+func getRetryAfter(allowedAfter uint64) *uint64 {
+	var retryAfterMS uint64
+	if now := getCurrTimeInMS(); allowedAfter > now {
+		retryAfterMS = allowedAfter - now
+	}
+	// rounded up so that retrying after this many seconds always succeeds
+	retryAfter := (retryAfterMS + 999) / 1000
+	return &retryAfter
+}
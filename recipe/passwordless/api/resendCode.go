@@ -19,6 +19,7 @@ import (
 	"encoding/json"
 	"io/ioutil"
 	"reflect"
+	"strconv"
 
 	"github.com/supertokens/supertokens-golang/recipe/passwordless/plessmodels"
 	"github.com/supertokens/supertokens-golang/supertokens"
@@ -74,6 +75,12 @@ func ResendCode(apiImplementation plessmodels.APIInterface, options plessmodels.
 		result = map[string]interface{}{
 			"status": "RESTART_FLOW_ERROR",
 		}
+	} else if response.TooManyRequestsError != nil {
+		options.Res.Header().Set("Retry-After", strconv.FormatUint(response.TooManyRequestsError.RetryAfter, 10))
+		result = map[string]interface{}{
+			"status":     "TOO_MANY_REQUESTS_ERROR",
+			"retryAfter": response.TooManyRequestsError.RetryAfter,
+		}
 	} else {
 		result = map[string]interface{}{
 			"status":  "GENERAL_ERROR",
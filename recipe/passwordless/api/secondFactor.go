@@ -0,0 +1,143 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package api
+
+import (
+	"crypto/subtle"
+
+	"github.com/supertokens/supertokens-golang/recipe/passwordless/plessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// getSecondFactorDestination returns either the email or the phone number of the user that the code
+// is sent to, depending on the enabled contact method. Both are nil if the code cannot be sent.
+func getSecondFactorDestination(userID string, options plessmodels.APIOptions, userContext supertokens.UserContext) (*string, *string, error) {
+	email, phoneNumber, err := options.Config.SecondFactor.GetContactInfo(userID, userContext)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if email != nil && (options.Config.ContactMethodEmail.Enabled || options.Config.ContactMethodEmailOrPhone.Enabled) {
+		return email, nil, nil
+	}
+	if phoneNumber != nil && (options.Config.ContactMethodPhone.Enabled || options.Config.ContactMethodEmailOrPhone.Enabled) {
+		normalisedPhoneNumber := NormalisePhoneNumber(*phoneNumber, options.Config.PhoneNumberPolicy.DefaultRegion)
+		return nil, &normalisedPhoneNumber, nil
+	}
+	return nil, nil, nil
+}
+
+// sendSecondFactorCode uses the functions that send sign in codes, without a magic link
+func sendSecondFactorCode(email *string, phoneNumber *string, code plessmodels.NewCode, options plessmodels.APIOptions, userContext supertokens.UserContext) error {
+	if email != nil {
+		if options.Config.ContactMethodEmail.Enabled {
+			return options.Config.ContactMethodEmail.CreateAndSendCustomEmail(*email, &code.UserInputCode, nil, code.CodeLifetime, code.PreAuthSessionID, userContext)
+		}
+		return options.Config.ContactMethodEmailOrPhone.CreateAndSendCustomEmail(*email, &code.UserInputCode, nil, code.CodeLifetime, code.PreAuthSessionID, userContext)
+	}
+	if options.Config.ContactMethodPhone.Enabled {
+		return options.Config.ContactMethodPhone.CreateAndSendCustomTextMessage(*phoneNumber, &code.UserInputCode, nil, code.CodeLifetime, code.PreAuthSessionID, userContext)
+	}
+	return options.Config.ContactMethodEmailOrPhone.CreateAndSendCustomTextMessage(*phoneNumber, &code.UserInputCode, nil, code.CodeLifetime, code.PreAuthSessionID, userContext)
+}
+
+// consumeSecondFactorCode checks the code of the session without ConsumeCode, which would sign up the
+// owner of the email or phone number. The code is revoked in the core once the right code is entered.
+func consumeSecondFactorCode(sessionHandle string, userInputCode string, options plessmodels.APIOptions, userContext supertokens.UserContext) (plessmodels.ConsumeSecondFactorCodePOSTResponse, error) {
+	secondFactor := options.Config.SecondFactor
+	// every attempt is counted before the code is checked, so that parallel
+	// requests cannot make more than MaximumCodeInputAttempts guesses.
+	codeInfo, err := secondFactor.CodeStore.IncrementAttemptCount(sessionHandle, userContext)
+	if err != nil {
+		return plessmodels.ConsumeSecondFactorCodePOSTResponse{}, err
+	}
+	if codeInfo == nil || codeInfo.FailedCodeInputAttemptCount > secondFactor.MaximumCodeInputAttempts {
+		return plessmodels.ConsumeSecondFactorCodePOSTResponse{
+			RestartFlowError: &struct{}{},
+		}, nil
+	}
+
+	if subtle.ConstantTimeCompare([]byte(codeInfo.UserInputCode), []byte(userInputCode)) != 1 {
+		if codeInfo.FailedCodeInputAttemptCount >= secondFactor.MaximumCodeInputAttempts {
+			err = removeSecondFactorCode(*codeInfo, options, userContext)
+			if err != nil {
+				return plessmodels.ConsumeSecondFactorCodePOSTResponse{}, err
+			}
+			return plessmodels.ConsumeSecondFactorCodePOSTResponse{
+				RestartFlowError: &struct{}{},
+			}, nil
+		}
+		return plessmodels.ConsumeSecondFactorCodePOSTResponse{
+			IncorrectUserInputCodeError: &struct {
+				FailedCodeInputAttemptCount int
+				MaximumCodeInputAttempts    int
+			}{
+				FailedCodeInputAttemptCount: codeInfo.FailedCodeInputAttemptCount,
+				MaximumCodeInputAttempts:    secondFactor.MaximumCodeInputAttempts,
+			},
+		}, nil
+	}
+
+	if codeInfo.Expiry <= getCurrTimeInMS() {
+		return plessmodels.ConsumeSecondFactorCodePOSTResponse{
+			ExpiredUserInputCodeError: &struct {
+				FailedCodeInputAttemptCount int
+				MaximumCodeInputAttempts    int
+			}{
+				// the current attempt used the right code
+				FailedCodeInputAttemptCount: codeInfo.FailedCodeInputAttemptCount - 1,
+				MaximumCodeInputAttempts:    secondFactor.MaximumCodeInputAttempts,
+			},
+		}, nil
+	}
+
+	// the code can also have been revoked in the core, for example with RevokeAllCodes
+	device, err := (*options.RecipeImplementation.ListCodesByDeviceID)(codeInfo.DeviceID, userContext)
+	if err != nil {
+		return plessmodels.ConsumeSecondFactorCodePOSTResponse{}, err
+	}
+	codeExists := false
+	if device != nil {
+		for _, code := range device.Codes {
+			if code.CodeID == codeInfo.CodeID {
+				codeExists = true
+				break
+			}
+		}
+	}
+
+	err = removeSecondFactorCode(*codeInfo, options, userContext)
+	if err != nil {
+		return plessmodels.ConsumeSecondFactorCodePOSTResponse{}, err
+	}
+	if !codeExists {
+		return plessmodels.ConsumeSecondFactorCodePOSTResponse{
+			RestartFlowError: &struct{}{},
+		}, nil
+	}
+	return plessmodels.ConsumeSecondFactorCodePOSTResponse{
+		OK: &struct{}{},
+	}, nil
+}
+
+// removeSecondFactorCode removes the code from the store and revokes it in the core
+func removeSecondFactorCode(codeInfo plessmodels.SecondFactorCodeInfo, options plessmodels.APIOptions, userContext supertokens.UserContext) error {
+	err := options.Config.SecondFactor.CodeStore.Remove(codeInfo.SessionHandle, userContext)
+	if err != nil {
+		return err
+	}
+	return (*options.RecipeImplementation.RevokeCode)(codeInfo.CodeID, userContext)
+}
@@ -0,0 +1,43 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package api
+
+import (
+	"github.com/supertokens/supertokens-golang/recipe/passwordless/plessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// checkSignUpAllowed returns false if the email or phone number does not belong to a user and
+// ShouldAllowSignUp refuses the sign up
+func checkSignUpAllowed(email *string, phoneNumber *string, options plessmodels.APIOptions, userContext supertokens.UserContext) (bool, error) {
+	if options.Config.ShouldAllowSignUp == nil {
+		return true, nil
+	}
+	var user *plessmodels.User
+	var err error
+	if email != nil {
+		user, err = (*options.RecipeImplementation.GetUserByEmail)(*email, userContext)
+	} else if phoneNumber != nil {
+		user, err = (*options.RecipeImplementation.GetUserByPhoneNumber)(*phoneNumber, userContext)
+	}
+	if err != nil {
+		return false, err
+	}
+	if user != nil {
+		return true, nil
+	}
+	return options.Config.ShouldAllowSignUp(email, phoneNumber, userContext)
+}
@@ -0,0 +1,282 @@
+/*
+ * Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package passwordless
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/passwordless/plessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+var validFlowTypes = []plessmodels.FlowType{
+	plessmodels.FlowTypeUserInputCode,
+	plessmodels.FlowTypeMagicLink,
+	plessmodels.FlowTypeUserInputCodeAndMagicLink,
+}
+
+func sendTestEmail(email string, userInputCode *string, urlWithLinkCode *string, codeLifetime uint64, preAuthSessionId string, userContext supertokens.UserContext) error {
+	return nil
+}
+
+func sendTestTextMessage(phoneNumber string, userInputCode *string, urlWithLinkCode *string, codeLifetime uint64, preAuthSessionId string, userContext supertokens.UserContext) error {
+	return nil
+}
+
+func getTestAppInfo(t *testing.T) supertokens.NormalisedAppinfo {
+	appInfo, err := supertokens.NormaliseInputAppInfoOrThrowError(supertokens.AppInfo{
+		AppName:       "SuperTokens",
+		APIDomain:     "api.supertokens.io",
+		WebsiteDomain: "supertokens.io",
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	return appInfo
+}
+
+func TestEveryCombinationOfContactMethodsAndFlowTypes(t *testing.T) {
+	appInfo := getTestAppInfo(t)
+	flowTypes := append([]plessmodels.FlowType{"", "SOMETHING_ELSE", "magic_link"}, validFlowTypes...)
+
+	for _, flowType := range flowTypes {
+		for combination := 0; combination < 8; combination++ {
+			emailEnabled := combination&1 != 0
+			phoneEnabled := combination&2 != 0
+			emailOrPhoneEnabled := combination&4 != 0
+
+			config := plessmodels.TypeInput{
+				FlowType: flowType,
+			}
+			if emailEnabled {
+				config.ContactMethodEmail = plessmodels.NewContactMethodEmailConfig(sendTestEmail)
+			}
+			if phoneEnabled {
+				config.ContactMethodPhone = plessmodels.NewContactMethodPhoneConfig(sendTestTextMessage)
+			}
+			if emailOrPhoneEnabled {
+				config.ContactMethodEmailOrPhone = plessmodels.NewContactMethodEmailOrPhoneConfig(sendTestEmail, sendTestTextMessage)
+			}
+
+			normalisedConfig, err := validateAndNormaliseUserInput(appInfo, config)
+
+			enabledCount := 0
+			for _, enabled := range []bool{emailEnabled, phoneEnabled, emailOrPhoneEnabled} {
+				if enabled {
+					enabledCount++
+				}
+			}
+			if !flowType.IsValid() {
+				assert.EqualError(t, err, "FlowType config must be provided and must be one of \"USER_INPUT_CODE\", \"MAGIC_LINK\" or \"USER_INPUT_CODE_AND_MAGIC_LINK\"", "flowType %q, combination %d", flowType, combination)
+			} else if enabledCount != 1 {
+				assert.EqualError(t, err, "Please enable only one of ContactMethodEmail, ContactMethodPhone or ContactMethodEmailOrPhone", "flowType %q, combination %d", flowType, combination)
+			} else {
+				assert.NoError(t, err, "flowType %q, combination %d", flowType, combination)
+				assert.Equal(t, flowType, normalisedConfig.FlowType)
+				assert.Equal(t, map[plessmodels.FlowType]bool{flowType: true}, normalisedConfig.AllowedFlowTypes)
+				assert.Equal(t, emailEnabled, normalisedConfig.ContactMethodEmail.Enabled)
+				assert.Equal(t, phoneEnabled, normalisedConfig.ContactMethodPhone.Enabled)
+				assert.Equal(t, emailOrPhoneEnabled, normalisedConfig.ContactMethodEmailOrPhone.Enabled)
+			}
+		}
+	}
+}
+
+func TestContactMethodsWithoutAFunctionToSendCodes(t *testing.T) {
+	appInfo := getTestAppInfo(t)
+
+	testCases := []struct {
+		config        plessmodels.TypeInput
+		expectedError string
+	}{
+		{
+			config: plessmodels.TypeInput{
+				ContactMethodEmail: plessmodels.NewContactMethodEmailConfig(nil),
+			},
+			expectedError: "Please pass a function (ContactMethodEmail.CreateAndSendCustomEmail) to send emails.",
+		},
+		{
+			config: plessmodels.TypeInput{
+				ContactMethodPhone: plessmodels.NewContactMethodPhoneConfig(nil),
+			},
+			expectedError: "Please pass a function (ContactMethodPhone.CreateAndSendCustomTextMessage) to send text messages.",
+		},
+		{
+			config: plessmodels.TypeInput{
+				ContactMethodEmailOrPhone: plessmodels.NewContactMethodEmailOrPhoneConfig(sendTestEmail, nil),
+			},
+			expectedError: "Please pass a function (ContactMethodEmailOrPhone.CreateAndSendCustomTextMessage) to send text messages.",
+		},
+		{
+			config: plessmodels.TypeInput{
+				ContactMethodEmailOrPhone: plessmodels.NewContactMethodEmailOrPhoneConfig(nil, sendTestTextMessage),
+			},
+			expectedError: "Please pass a function (ContactMethodEmailOrPhone.CreateAndSendCustomEmail) to send emails.",
+		},
+	}
+
+	for _, flowType := range validFlowTypes {
+		for _, testCase := range testCases {
+			testCase.config.FlowType = flowType
+			_, err := validateAndNormaliseUserInput(appInfo, testCase.config)
+			assert.EqualError(t, err, testCase.expectedError)
+		}
+	}
+}
+
+func TestAllowedFlowTypesConfig(t *testing.T) {
+	appInfo := getTestAppInfo(t)
+	getFlowType := func(req *http.Request, contactMethod string, userContext supertokens.UserContext) (plessmodels.FlowType, error) {
+		return plessmodels.FlowTypeMagicLink, nil
+	}
+
+	normalisedConfig, err := validateAndNormaliseUserInput(appInfo, plessmodels.TypeInput{
+		ContactMethodEmail: plessmodels.NewContactMethodEmailConfig(sendTestEmail),
+		FlowType:           plessmodels.FlowTypeUserInputCode,
+		GetFlowType:        getFlowType,
+		FlowTypeStore:      makeInMemoryFlowTypeStoreForTest(),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[plessmodels.FlowType]bool{
+		plessmodels.FlowTypeUserInputCode:             true,
+		plessmodels.FlowTypeMagicLink:                 true,
+		plessmodels.FlowTypeUserInputCodeAndMagicLink: true,
+	}, normalisedConfig.AllowedFlowTypes)
+
+	normalisedConfig, err = validateAndNormaliseUserInput(appInfo, plessmodels.TypeInput{
+		ContactMethodEmail: plessmodels.NewContactMethodEmailConfig(sendTestEmail),
+		FlowType:           plessmodels.FlowTypeUserInputCode,
+		GetFlowType:        getFlowType,
+		FlowTypeStore:      makeInMemoryFlowTypeStoreForTest(),
+		AllowedFlowTypes:   []plessmodels.FlowType{plessmodels.FlowTypeMagicLink},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[plessmodels.FlowType]bool{plessmodels.FlowTypeMagicLink: true}, normalisedConfig.AllowedFlowTypes)
+
+	_, err = validateAndNormaliseUserInput(appInfo, plessmodels.TypeInput{
+		ContactMethodEmail: plessmodels.NewContactMethodEmailConfig(sendTestEmail),
+		FlowType:           plessmodels.FlowTypeUserInputCode,
+		GetFlowType:        getFlowType,
+		FlowTypeStore:      makeInMemoryFlowTypeStoreForTest(),
+		AllowedFlowTypes:   []plessmodels.FlowType{plessmodels.FlowTypeMagicLink, "LINK"},
+	})
+	assert.EqualError(t, err, "AllowedFlowTypes must only contain \"USER_INPUT_CODE\", \"MAGIC_LINK\" or \"USER_INPUT_CODE_AND_MAGIC_LINK\"")
+}
+
+func TestInvalidOptionalConfigIsReturnedAsAnError(t *testing.T) {
+	appInfo := getTestAppInfo(t)
+
+	_, err := validateAndNormaliseUserInput(appInfo, plessmodels.TypeInput{
+		ContactMethodEmail:    plessmodels.NewContactMethodEmailConfig(sendTestEmail),
+		FlowType:              plessmodels.FlowTypeMagicLink,
+		CrossDeviceProtection: &plessmodels.TypeInputCrossDeviceProtection{},
+	})
+	assert.EqualError(t, err, "CrossDeviceProtection.OnOtherDevice cannot be \"REQUIRE_USER_INPUT_CODE\" if the FlowType is \"MAGIC_LINK\" since no code is sent to the user")
+
+	_, err = validateAndNormaliseUserInput(appInfo, plessmodels.TypeInput{
+		ContactMethodEmail: plessmodels.NewContactMethodEmailConfig(sendTestEmail),
+		FlowType:           plessmodels.FlowTypeMagicLink,
+		CrossDeviceProtection: &plessmodels.TypeInputCrossDeviceProtection{
+			OnOtherDevice: "REQUIRE_CONFIRMATION",
+		},
+	})
+	assert.EqualError(t, err, "please provide a Store in the CrossDeviceProtection config when OnOtherDevice is \"REQUIRE_CONFIRMATION\"")
+
+	_, err = validateAndNormaliseUserInput(appInfo, plessmodels.TypeInput{
+		ContactMethodPhone: plessmodels.NewContactMethodPhoneConfig(sendTestTextMessage),
+		FlowType:           plessmodels.FlowTypeUserInputCode,
+		PhoneNumberPolicy: &plessmodels.TypeInputPhoneNumberPolicy{
+			DefaultRegion: "XX",
+		},
+	})
+	assert.EqualError(t, err, "PhoneNumberPolicy.DefaultRegion contains an unknown region: XX")
+
+	_, err = validateAndNormaliseUserInput(appInfo, plessmodels.TypeInput{
+		ContactMethodPhone: plessmodels.NewContactMethodPhoneConfig(sendTestTextMessage),
+		FlowType:           plessmodels.FlowTypeUserInputCode,
+		RateLimit:          &plessmodels.TypeInputRateLimit{},
+	})
+	assert.EqualError(t, err, "please provide a Store in the RateLimit config")
+
+	rateLimitStore := makeInMemoryRateLimitStoreForTest()
+	_, err = validateAndNormaliseUserInput(appInfo, plessmodels.TypeInput{
+		ContactMethodPhone: plessmodels.NewContactMethodPhoneConfig(sendTestTextMessage),
+		FlowType:           plessmodels.FlowTypeUserInputCode,
+		RateLimit: &plessmodels.TypeInputRateLimit{
+			PerIPAddress: &plessmodels.RateLimitWindow{},
+			Store:        &rateLimitStore,
+		},
+	})
+	assert.EqualError(t, err, "RateLimit.PerIPAddress must allow at least one request in a window with a non zero Duration")
+}
+
+func TestFlowTypeSendsCodesAndLinks(t *testing.T) {
+	assert.True(t, plessmodels.FlowTypeUserInputCode.UsesUserInputCode())
+	assert.False(t, plessmodels.FlowTypeUserInputCode.UsesMagicLink())
+	assert.False(t, plessmodels.FlowTypeMagicLink.UsesUserInputCode())
+	assert.True(t, plessmodels.FlowTypeMagicLink.UsesMagicLink())
+	assert.True(t, plessmodels.FlowTypeUserInputCodeAndMagicLink.UsesUserInputCode())
+	assert.True(t, plessmodels.FlowTypeUserInputCodeAndMagicLink.UsesMagicLink())
+}
+
+func TestSecondFactorConfig(t *testing.T) {
+	normalisedConfig, err := validateAndNormaliseSecondFactorConfig(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, normalisedConfig)
+
+	_, err = validateAndNormaliseSecondFactorConfig(&plessmodels.TypeInputSecondFactor{})
+	assert.EqualError(t, err, "please provide GetContactInfo in the SecondFactor config")
+
+	getContactInfo := func(userID string, userContext supertokens.UserContext) (*string, *string, error) {
+		return nil, nil, nil
+	}
+	_, err = validateAndNormaliseSecondFactorConfig(&plessmodels.TypeInputSecondFactor{
+		GetContactInfo: getContactInfo,
+	})
+	assert.EqualError(t, err, "please provide a CodeStore in the SecondFactor config")
+
+	codeStore := makeInMemorySecondFactorCodeStoreForTest()
+	normalisedConfig, err = validateAndNormaliseSecondFactorConfig(&plessmodels.TypeInputSecondFactor{
+		GetContactInfo: getContactInfo,
+		CodeStore:      &codeStore,
+	})
+	assert.NoError(t, err)
+	assert.NotNil(t, normalisedConfig.GetContactInfo)
+	assert.Equal(t, "passwordlessSecondFactorCompletedAt", normalisedConfig.AccessTokenPayloadKey)
+	assert.Equal(t, 5, normalisedConfig.MaximumCodeInputAttempts)
+
+	maximumCodeInputAttempts := 3
+	normalisedConfig, err = validateAndNormaliseSecondFactorConfig(&plessmodels.TypeInputSecondFactor{
+		GetContactInfo:           getContactInfo,
+		AccessTokenPayloadKey:    "otpCompletedAt",
+		MaximumCodeInputAttempts: &maximumCodeInputAttempts,
+		CodeStore:                &codeStore,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "otpCompletedAt", normalisedConfig.AccessTokenPayloadKey)
+	assert.Equal(t, 3, normalisedConfig.MaximumCodeInputAttempts)
+
+	maximumCodeInputAttempts = 0
+	_, err = validateAndNormaliseSecondFactorConfig(&plessmodels.TypeInputSecondFactor{
+		GetContactInfo:           getContactInfo,
+		MaximumCodeInputAttempts: &maximumCodeInputAttempts,
+		CodeStore:                &codeStore,
+	})
+	assert.EqualError(t, err, "SecondFactor.MaximumCodeInputAttempts must be greater than 0")
+}
@@ -16,9 +16,12 @@
 package passwordless
 
 const (
-	createCodeAPI           = "/signinup/code"
-	resendCodeAPI           = "/signinup/code/resend"
-	consumeCodeAPI          = "/signinup/code/consume"
-	doesEmailExistAPI       = "/signup/email/exists"
-	doesPhoneNumberExistAPI = "/signup/phonenumber/exists"
+	createCodeAPI              = "/signinup/code"
+	resendCodeAPI              = "/signinup/code/resend"
+	consumeCodeAPI             = "/signinup/code/consume"
+	confirmCrossDeviceAPI      = "/signinup/code/confirm"
+	doesEmailExistAPI          = "/signup/email/exists"
+	doesPhoneNumberExistAPI    = "/signup/phonenumber/exists"
+	createSecondFactorCodeAPI  = "/secondfactor/code"
+	consumeSecondFactorCodeAPI = "/secondfactor/code/consume"
 )
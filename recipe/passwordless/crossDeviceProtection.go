@@ -0,0 +1,47 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package passwordless
+
+import (
+	"errors"
+
+	"github.com/supertokens/supertokens-golang/recipe/passwordless/plessmodels"
+)
+
+func validateAndNormaliseCrossDeviceProtectionConfig(config *plessmodels.TypeInputCrossDeviceProtection, allowedFlowTypes map[plessmodels.FlowType]bool) (*plessmodels.TypeNormalisedInputCrossDeviceProtection, error) {
+	if config == nil {
+		return nil, nil
+	}
+	normalisedConfig := plessmodels.TypeNormalisedInputCrossDeviceProtection{
+		OnOtherDevice: "REQUIRE_USER_INPUT_CODE",
+	}
+	if config.OnOtherDevice != "" {
+		if config.OnOtherDevice != "REQUIRE_USER_INPUT_CODE" && config.OnOtherDevice != "REQUIRE_CONFIRMATION" {
+			return nil, errors.New("CrossDeviceProtection.OnOtherDevice must be one of \"REQUIRE_USER_INPUT_CODE\" or \"REQUIRE_CONFIRMATION\"")
+		}
+		normalisedConfig.OnOtherDevice = config.OnOtherDevice
+	}
+	if normalisedConfig.OnOtherDevice == "REQUIRE_USER_INPUT_CODE" && len(allowedFlowTypes) == 1 && allowedFlowTypes[plessmodels.FlowTypeMagicLink] {
+		return nil, errors.New("CrossDeviceProtection.OnOtherDevice cannot be \"REQUIRE_USER_INPUT_CODE\" if the FlowType is \"MAGIC_LINK\" since no code is sent to the user")
+	}
+	if normalisedConfig.OnOtherDevice == "REQUIRE_CONFIRMATION" {
+		if config.Store == nil {
+			return nil, errors.New("please provide a Store in the CrossDeviceProtection config when OnOtherDevice is \"REQUIRE_CONFIRMATION\"")
+		}
+		normalisedConfig.Store = config.Store
+	}
+	return &normalisedConfig, nil
+}
@@ -0,0 +1,318 @@
+/*
+ * Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package passwordless
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/passwordless/api"
+	"github.com/supertokens/supertokens-golang/recipe/passwordless/plessmodels"
+	"github.com/supertokens/supertokens-golang/recipe/session"
+	"github.com/supertokens/supertokens-golang/supertokens"
+	"github.com/supertokens/supertokens-golang/test/unittesting"
+)
+
+func makeInMemoryCrossDeviceSignInStoreForTest() plessmodels.CrossDeviceSignInStore {
+	var lock sync.Mutex
+	signIns := map[string]plessmodels.CrossDeviceSignInInfo{}
+	return plessmodels.CrossDeviceSignInStore{
+		Save: func(info plessmodels.CrossDeviceSignInInfo, userContext supertokens.UserContext) error {
+			lock.Lock()
+			defer lock.Unlock()
+			signIns[info.PreAuthSessionID] = info
+			return nil
+		},
+		Get: func(preAuthSessionID string, userContext supertokens.UserContext) (*plessmodels.CrossDeviceSignInInfo, error) {
+			lock.Lock()
+			defer lock.Unlock()
+			info, ok := signIns[preAuthSessionID]
+			if !ok {
+				return nil, nil
+			}
+			return &info, nil
+		},
+		Remove: func(preAuthSessionID string, userContext supertokens.UserContext) error {
+			lock.Lock()
+			defer lock.Unlock()
+			delete(signIns, preAuthSessionID)
+			return nil
+		},
+	}
+}
+
+type sentCodeForTest struct {
+	email         string
+	userInputCode *string
+	linkCode      string
+}
+
+// startServerForTest initialises the recipe with the config and returns a test server for its APIs,
+// and a function that returns the latest code that was sent by email. GET /sessioninfo responds with
+// the access token payload of the session.
+func startServerForTest(t *testing.T, config plessmodels.TypeInput) (*httptest.Server, func() sentCodeForTest) {
+	var sentCode sentCodeForTest
+	config.ContactMethodEmail = plessmodels.NewContactMethodEmailConfig(func(email string, userInputCode *string, urlWithLinkCode *string, codeLifetime uint64, preAuthSessionId string, userContext supertokens.UserContext) error {
+		sentCode = sentCodeForTest{
+			email:         email,
+			userInputCode: userInputCode,
+		}
+		if urlWithLinkCode != nil {
+			link, err := url.Parse(*urlWithLinkCode)
+			if err != nil {
+				return err
+			}
+			sentCode.linkCode = link.Fragment
+		}
+		return nil
+	})
+
+	configValue := supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: "http://localhost:8080",
+		},
+		AppInfo: supertokens.AppInfo{
+			APIDomain:     "http://api.supertokens.io",
+			AppName:       "SuperTokens",
+			WebsiteDomain: "http://supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{
+			session.Init(nil),
+			Init(config),
+		},
+	}
+	err := supertokens.Init(configValue)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/sessioninfo", session.VerifySession(nil, func(rw http.ResponseWriter, r *http.Request) {
+		sessionContainer := session.GetSessionFromRequestContext(r.Context())
+		err := supertokens.Send200Response(rw, sessionContainer.GetAccessTokenPayload())
+		if err != nil {
+			t.Error(err.Error())
+		}
+	}))
+	testServer := httptest.NewServer(supertokens.Middleware(mux))
+	return testServer, func() sentCodeForTest {
+		return sentCode
+	}
+}
+
+// makeDeviceForTest returns a client that keeps its cookies, like a browser
+func makeDeviceForTest(t *testing.T) *http.Client {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	return &http.Client{Jar: jar}
+}
+
+func postForTest(t *testing.T, client *http.Client, url string, body map[string]interface{}) map[string]interface{} {
+	bodyBytes, err := json.Marshal(body)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(bodyBytes))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer resp.Body.Close()
+	dataInBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	var data map[string]interface{}
+	err = json.Unmarshal(dataInBytes, &data)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	return data
+}
+
+func TestMagicLinkCanBeConsumedOnTheSameDevice(t *testing.T) {
+	BeforeEach()
+	unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+	testServer, getSentCode := startServerForTest(t, plessmodels.TypeInput{
+		FlowType:              plessmodels.FlowTypeUserInputCodeAndMagicLink,
+		CrossDeviceProtection: &plessmodels.TypeInputCrossDeviceProtection{},
+	})
+	defer testServer.Close()
+
+	device := makeDeviceForTest(t)
+	data := postForTest(t, device, testServer.URL+"/auth/signinup/code", map[string]interface{}{
+		"email": "johndoe@gmail.com",
+	})
+	assert.Equal(t, "OK", data["status"])
+
+	data = postForTest(t, device, testServer.URL+"/auth/signinup/code/consume", map[string]interface{}{
+		"preAuthSessionId": data["preAuthSessionId"],
+		"linkCode":         getSentCode().linkCode,
+	})
+	assert.Equal(t, "OK", data["status"])
+	assert.Equal(t, true, data["createdNewUser"])
+}
+
+func TestMagicLinkOnAnotherDeviceWorksOnceConfirmed(t *testing.T) {
+	BeforeEach()
+	unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+	crossDeviceSignInStore := makeInMemoryCrossDeviceSignInStoreForTest()
+	testServer, getSentCode := startServerForTest(t, plessmodels.TypeInput{
+		FlowType: plessmodels.FlowTypeMagicLink,
+		CrossDeviceProtection: &plessmodels.TypeInputCrossDeviceProtection{
+			OnOtherDevice: "REQUIRE_CONFIRMATION",
+			Store:         &crossDeviceSignInStore,
+		},
+	})
+	defer testServer.Close()
+
+	originalDevice := makeDeviceForTest(t)
+	otherDevice := makeDeviceForTest(t)
+	data := postForTest(t, originalDevice, testServer.URL+"/auth/signinup/code", map[string]interface{}{
+		"email": "johndoe@gmail.com",
+	})
+	assert.Equal(t, "OK", data["status"])
+	preAuthSessionID := data["preAuthSessionId"]
+	consumeBody := map[string]interface{}{
+		"preAuthSessionId": preAuthSessionID,
+		"linkCode":         getSentCode().linkCode,
+	}
+
+	data = postForTest(t, otherDevice, testServer.URL+"/auth/signinup/code/consume", consumeBody)
+	assert.Equal(t, "CROSS_DEVICE_LINK_ERROR", data["status"])
+	assert.Equal(t, true, data["confirmationRequired"])
+
+	// only the original device can confirm the sign in
+	data = postForTest(t, otherDevice, testServer.URL+"/auth/signinup/code/confirm", map[string]interface{}{
+		"preAuthSessionId": preAuthSessionID,
+	})
+	assert.Equal(t, "RESTART_FLOW_ERROR", data["status"])
+
+	data = postForTest(t, otherDevice, testServer.URL+"/auth/signinup/code/consume", consumeBody)
+	assert.Equal(t, "CROSS_DEVICE_LINK_ERROR", data["status"])
+
+	data = postForTest(t, originalDevice, testServer.URL+"/auth/signinup/code/confirm", map[string]interface{}{
+		"preAuthSessionId": preAuthSessionID,
+	})
+	assert.Equal(t, "OK", data["status"])
+
+	data = postForTest(t, otherDevice, testServer.URL+"/auth/signinup/code/consume", consumeBody)
+	assert.Equal(t, "OK", data["status"])
+}
+
+func TestMagicLinkOnAnotherDeviceRequiresTheUserInputCode(t *testing.T) {
+	BeforeEach()
+	unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+	testServer, getSentCode := startServerForTest(t, plessmodels.TypeInput{
+		FlowType: plessmodels.FlowTypeUserInputCodeAndMagicLink,
+		CrossDeviceProtection: &plessmodels.TypeInputCrossDeviceProtection{
+			OnOtherDevice: "REQUIRE_USER_INPUT_CODE",
+		},
+	})
+	defer testServer.Close()
+
+	originalDevice := makeDeviceForTest(t)
+	otherDevice := makeDeviceForTest(t)
+	data := postForTest(t, originalDevice, testServer.URL+"/auth/signinup/code", map[string]interface{}{
+		"email": "johndoe@gmail.com",
+	})
+	assert.Equal(t, "OK", data["status"])
+	preAuthSessionID := data["preAuthSessionId"]
+	deviceID := data["deviceId"]
+
+	data = postForTest(t, otherDevice, testServer.URL+"/auth/signinup/code/consume", map[string]interface{}{
+		"preAuthSessionId": preAuthSessionID,
+		"linkCode":         getSentCode().linkCode,
+	})
+	assert.Equal(t, "CROSS_DEVICE_LINK_ERROR", data["status"])
+	assert.Equal(t, false, data["confirmationRequired"])
+
+	// confirming does not help in this mode
+	data = postForTest(t, originalDevice, testServer.URL+"/auth/signinup/code/confirm", map[string]interface{}{
+		"preAuthSessionId": preAuthSessionID,
+	})
+	assert.Equal(t, "NO_PENDING_SIGN_IN_ERROR", data["status"])
+
+	data = postForTest(t, originalDevice, testServer.URL+"/auth/signinup/code/consume", map[string]interface{}{
+		"preAuthSessionId": preAuthSessionID,
+		"deviceId":         deviceID,
+		"userInputCode":    *getSentCode().userInputCode,
+	})
+	assert.Equal(t, "OK", data["status"])
+}
+
+func TestDeviceIDCookieAttributes(t *testing.T) {
+	for _, apiDomain := range []string{"http://api.supertokens.io", "https://api.supertokens.io"} {
+		appInfo, err := supertokens.NormaliseInputAppInfoOrThrowError(supertokens.AppInfo{
+			AppName:       "SuperTokens",
+			APIDomain:     apiDomain,
+			WebsiteDomain: "https://supertokens.io",
+		})
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		config, err := validateAndNormaliseUserInput(appInfo, plessmodels.TypeInput{
+			ContactMethodEmail:    plessmodels.NewContactMethodEmailConfig(sendTestEmail),
+			FlowType:              plessmodels.FlowTypeUserInputCodeAndMagicLink,
+			CrossDeviceProtection: &plessmodels.TypeInputCrossDeviceProtection{},
+		})
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		res, data, err := callAPIForTest(t, api.CreateCode, api.MakeAPIImplementation(), config, makeTestCore().makeRecipeImplementation(), testAPIRequest{
+			body:    map[string]interface{}{"email": "johndoe@gmail.com"},
+			appInfo: &appInfo,
+		})
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		assert.Equal(t, "OK", data["status"])
+
+		cookies := res.Cookies()
+		if !assert.Len(t, cookies, 1) {
+			continue
+		}
+		cookie := cookies[0]
+		assert.Equal(t, "sPasswordlessDeviceId", cookie.Name)
+		assert.Equal(t, data["deviceId"], cookie.Value)
+		assert.Equal(t, "/auth", cookie.Path)
+		assert.True(t, cookie.HttpOnly)
+		// the cookie expires with the code, which lives for 15 minutes in the test core
+		assert.WithinDuration(t, time.Now().Add(15*time.Minute), cookie.Expires, 5*time.Second)
+		if apiDomain == "https://api.supertokens.io" {
+			assert.True(t, cookie.Secure)
+			assert.Equal(t, http.SameSiteNoneMode, cookie.SameSite)
+		} else {
+			assert.False(t, cookie.Secure)
+			assert.Equal(t, http.SameSiteLaxMode, cookie.SameSite)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+/*
+ * Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package passwordless
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/passwordless/api"
+	"github.com/supertokens/supertokens-golang/recipe/passwordless/plessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func makeInMemoryFlowTypeStoreForTest() *plessmodels.FlowTypeStore {
+	var lock sync.Mutex
+	infos := map[string]plessmodels.DeviceFlowTypeInfo{}
+	return &plessmodels.FlowTypeStore{
+		Save: func(info plessmodels.DeviceFlowTypeInfo, userContext supertokens.UserContext) error {
+			lock.Lock()
+			defer lock.Unlock()
+			infos[info.DeviceID] = info
+			return nil
+		},
+		Get: func(deviceID string, userContext supertokens.UserContext) (*plessmodels.DeviceFlowTypeInfo, error) {
+			lock.Lock()
+			defer lock.Unlock()
+			info, ok := infos[deviceID]
+			if !ok {
+				return nil, nil
+			}
+			return &info, nil
+		},
+	}
+}
+
+func TestGetFlowTypeRequiresAFlowTypeStore(t *testing.T) {
+	_, err := validateAndNormaliseUserInput(getTestAppInfo(t), plessmodels.TypeInput{
+		ContactMethodEmail: plessmodels.NewContactMethodEmailConfig(sendTestEmail),
+		FlowType:           plessmodels.FlowTypeUserInputCode,
+		GetFlowType: func(req *http.Request, contactMethod string, userContext supertokens.UserContext) (plessmodels.FlowType, error) {
+			return plessmodels.FlowTypeMagicLink, nil
+		},
+	})
+	assert.EqualError(t, err, "please provide a FlowTypeStore in the config when GetFlowType is set")
+}
+
+func TestResentCodesUseTheFlowTypeOfTheDevice(t *testing.T) {
+	var sentUserInputCode, sentLink *string
+	flowType := plessmodels.FlowTypeMagicLink
+	flowTypeStore := makeInMemoryFlowTypeStoreForTest()
+	config, err := validateAndNormaliseUserInput(getTestAppInfo(t), plessmodels.TypeInput{
+		ContactMethodEmail: plessmodels.NewContactMethodEmailConfig(func(email string, userInputCode *string, urlWithLinkCode *string, codeLifetime uint64, preAuthSessionId string, userContext supertokens.UserContext) error {
+			sentUserInputCode = userInputCode
+			sentLink = urlWithLinkCode
+			return nil
+		}),
+		FlowType: plessmodels.FlowTypeUserInputCode,
+		GetFlowType: func(req *http.Request, contactMethod string, userContext supertokens.UserContext) (plessmodels.FlowType, error) {
+			return flowType, nil
+		},
+		FlowTypeStore: flowTypeStore,
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	recipeImplementation := makeTestCore().makeRecipeImplementation()
+	apiImplementation := api.MakeAPIImplementation()
+
+	_, data, err := callAPIForTest(t, api.CreateCode, apiImplementation, config, recipeImplementation, testAPIRequest{
+		body: map[string]interface{}{"email": "johndoe@gmail.com"},
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	assert.Equal(t, "OK", data["status"])
+	assert.Equal(t, "MAGIC_LINK", data["flowType"])
+	assert.NotNil(t, sentLink)
+	assert.Nil(t, sentUserInputCode)
+	deviceID := data["deviceId"].(string)
+	resendBody := map[string]interface{}{
+		"deviceId":         deviceID,
+		"preAuthSessionId": data["preAuthSessionId"],
+	}
+
+	// a request resolving to another flow type does not change how the codes of the device are sent
+	flowType = plessmodels.FlowTypeUserInputCode
+	sentUserInputCode, sentLink = nil, nil
+	_, data, err = callAPIForTest(t, api.ResendCode, apiImplementation, config, recipeImplementation, testAPIRequest{
+		body: resendBody,
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	assert.Equal(t, "OK", data["status"])
+	assert.NotNil(t, sentLink)
+	assert.Nil(t, sentUserInputCode)
+
+	info, err := flowTypeStore.Get(deviceID, &map[string]interface{}{})
+	assert.NoError(t, err)
+	info.Expiry = 0
+	assert.NoError(t, flowTypeStore.Save(*info, &map[string]interface{}{}))
+	_, data, err = callAPIForTest(t, api.ResendCode, apiImplementation, config, recipeImplementation, testAPIRequest{
+		body: resendBody,
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	assert.Equal(t, "RESTART_FLOW_ERROR", data["status"])
+}
+
+func TestFlowTypesThatAreNotAllowedAreABadInput(t *testing.T) {
+	sentEmails := 0
+	config, err := validateAndNormaliseUserInput(getTestAppInfo(t), plessmodels.TypeInput{
+		ContactMethodEmail: plessmodels.NewContactMethodEmailConfig(func(email string, userInputCode *string, urlWithLinkCode *string, codeLifetime uint64, preAuthSessionId string, userContext supertokens.UserContext) error {
+			sentEmails++
+			return nil
+		}),
+		FlowType: plessmodels.FlowTypeUserInputCode,
+		GetFlowType: func(req *http.Request, contactMethod string, userContext supertokens.UserContext) (plessmodels.FlowType, error) {
+			return plessmodels.FlowType(req.URL.Query().Get("flowType")), nil
+		},
+		AllowedFlowTypes: []plessmodels.FlowType{plessmodels.FlowTypeMagicLink},
+		FlowTypeStore:    makeInMemoryFlowTypeStoreForTest(),
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	_, _, err = callAPIForTest(t, api.CreateCode, api.MakeAPIImplementation(), config, makeTestCore().makeRecipeImplementation(), testAPIRequest{
+		body:  map[string]interface{}{"email": "johndoe@gmail.com"},
+		query: "flowType=USER_INPUT_CODE",
+	})
+	assert.Equal(t, supertokens.BadInputError{Msg: "The flow type \"USER_INPUT_CODE\" is not allowed"}, err)
+	assert.Equal(t, 0, sentEmails)
+
+	_, data, err := callAPIForTest(t, api.CreateCode, api.MakeAPIImplementation(), config, makeTestCore().makeRecipeImplementation(), testAPIRequest{
+		body:  map[string]interface{}{"email": "johndoe@gmail.com"},
+		query: "flowType=MAGIC_LINK",
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	assert.Equal(t, "MAGIC_LINK", data["flowType"])
+	assert.Equal(t, 1, sentEmails)
+}
@@ -0,0 +1,143 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package passwordless
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/nyaruka/phonenumbers"
+	"github.com/supertokens/supertokens-golang/recipe/passwordless/api"
+	"github.com/supertokens/supertokens-golang/recipe/passwordless/plessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+var phoneNumberTypes = map[string]phonenumbers.PhoneNumberType{
+	"FIXED_LINE":           phonenumbers.FIXED_LINE,
+	"MOBILE":               phonenumbers.MOBILE,
+	"FIXED_LINE_OR_MOBILE": phonenumbers.FIXED_LINE_OR_MOBILE,
+	"TOLL_FREE":            phonenumbers.TOLL_FREE,
+	"PREMIUM_RATE":         phonenumbers.PREMIUM_RATE,
+	"SHARED_COST":          phonenumbers.SHARED_COST,
+	"VOIP":                 phonenumbers.VOIP,
+	"PERSONAL_NUMBER":      phonenumbers.PERSONAL_NUMBER,
+	"PAGER":                phonenumbers.PAGER,
+	"UAN":                  phonenumbers.UAN,
+	"VOICEMAIL":            phonenumbers.VOICEMAIL,
+	"UNKNOWN":              phonenumbers.UNKNOWN,
+}
+
+func normaliseRegion(region string, configName string) (string, error) {
+	normalisedRegion := strings.ToUpper(strings.TrimSpace(region))
+	if !phonenumbers.GetSupportedRegions()[normalisedRegion] {
+		return "", errors.New("PhoneNumberPolicy." + configName + " contains an unknown region: " + region)
+	}
+	return normalisedRegion, nil
+}
+
+func normaliseRegions(regions []string, configName string) (map[string]bool, error) {
+	normalisedRegions := map[string]bool{}
+	for _, region := range regions {
+		normalisedRegion, err := normaliseRegion(region, configName)
+		if err != nil {
+			return nil, err
+		}
+		normalisedRegions[normalisedRegion] = true
+	}
+	return normalisedRegions, nil
+}
+
+func normaliseNumberTypes(numberTypes []string, configName string) (map[phonenumbers.PhoneNumberType]bool, error) {
+	normalisedNumberTypes := map[phonenumbers.PhoneNumberType]bool{}
+	for _, name := range numberTypes {
+		numberType, ok := phoneNumberTypes[strings.ToUpper(strings.TrimSpace(name))]
+		if !ok {
+			return nil, errors.New("PhoneNumberPolicy." + configName + " contains an unknown phone number type: " + name)
+		}
+		normalisedNumberTypes[numberType] = true
+	}
+	return normalisedNumberTypes, nil
+}
+
+func validateAndNormalisePhoneNumberPolicyConfig(config *plessmodels.TypeInputPhoneNumberPolicy) (plessmodels.TypeNormalisedInputPhoneNumberPolicy, error) {
+	normalisedConfig := plessmodels.TypeNormalisedInputPhoneNumberPolicy{}
+	if config == nil {
+		return normalisedConfig, nil
+	}
+	var err error
+	normalisedConfig.AllowedRegions, err = normaliseRegions(config.AllowedRegions, "AllowedRegions")
+	if err != nil {
+		return plessmodels.TypeNormalisedInputPhoneNumberPolicy{}, err
+	}
+	normalisedConfig.BlockedRegions, err = normaliseRegions(config.BlockedRegions, "BlockedRegions")
+	if err != nil {
+		return plessmodels.TypeNormalisedInputPhoneNumberPolicy{}, err
+	}
+	normalisedConfig.AllowedNumberTypes, err = normaliseNumberTypes(config.AllowedNumberTypes, "AllowedNumberTypes")
+	if err != nil {
+		return plessmodels.TypeNormalisedInputPhoneNumberPolicy{}, err
+	}
+	normalisedConfig.BlockedNumberTypes, err = normaliseNumberTypes(config.BlockedNumberTypes, "BlockedNumberTypes")
+	if err != nil {
+		return plessmodels.TypeNormalisedInputPhoneNumberPolicy{}, err
+	}
+	if config.DefaultRegion != "" {
+		normalisedConfig.DefaultRegion, err = normaliseRegion(config.DefaultRegion, "DefaultRegion")
+		if err != nil {
+			return plessmodels.TypeNormalisedInputPhoneNumberPolicy{}, err
+		}
+	}
+	return normalisedConfig, nil
+}
+
+// makePhoneNumberNormalisingRecipeImplementation formats the phone numbers passed to the recipe functions
+// in the E.164 format, which is how they are saved, so that lookups work regardless of the given format.
+func makePhoneNumberNormalisingRecipeImplementation(originalImplementation plessmodels.RecipeInterface, defaultRegion string) plessmodels.RecipeInterface {
+	normalise := func(phoneNumber *string) *string {
+		if phoneNumber == nil {
+			return nil
+		}
+		normalisedPhoneNumber := api.NormalisePhoneNumber(*phoneNumber, defaultRegion)
+		return &normalisedPhoneNumber
+	}
+
+	ogCreateCode := *originalImplementation.CreateCode
+	(*originalImplementation.CreateCode) = func(email *string, phoneNumber *string, userInputCode *string, userContext supertokens.UserContext) (plessmodels.CreateCodeResponse, error) {
+		return ogCreateCode(email, normalise(phoneNumber), userInputCode, userContext)
+	}
+
+	ogGetUserByPhoneNumber := *originalImplementation.GetUserByPhoneNumber
+	(*originalImplementation.GetUserByPhoneNumber) = func(phoneNumber string, userContext supertokens.UserContext) (*plessmodels.User, error) {
+		return ogGetUserByPhoneNumber(*normalise(&phoneNumber), userContext)
+	}
+
+	ogUpdateUser := *originalImplementation.UpdateUser
+	(*originalImplementation.UpdateUser) = func(userID string, email *string, phoneNumber *string, userContext supertokens.UserContext) (plessmodels.UpdateUserResponse, error) {
+		return ogUpdateUser(userID, email, normalise(phoneNumber), userContext)
+	}
+
+	ogRevokeAllCodes := *originalImplementation.RevokeAllCodes
+	(*originalImplementation.RevokeAllCodes) = func(email *string, phoneNumber *string, userContext supertokens.UserContext) error {
+		return ogRevokeAllCodes(email, normalise(phoneNumber), userContext)
+	}
+
+	ogListCodesByPhoneNumber := *originalImplementation.ListCodesByPhoneNumber
+	(*originalImplementation.ListCodesByPhoneNumber) = func(phoneNumber string, userContext supertokens.UserContext) ([]plessmodels.DeviceType, error) {
+		return ogListCodesByPhoneNumber(*normalise(&phoneNumber), userContext)
+	}
+
+	return originalImplementation
+}
@@ -0,0 +1,159 @@
+/*
+ * Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package passwordless
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/passwordless/api"
+	"github.com/supertokens/supertokens-golang/recipe/passwordless/plessmodels"
+)
+
+func TestPhoneNumbersAreParsedWithTheDefaultRegion(t *testing.T) {
+	config, err := validateAndNormaliseUserInput(getTestAppInfo(t), plessmodels.TypeInput{
+		ContactMethodPhone: plessmodels.NewContactMethodPhoneConfig(sendTestTextMessage),
+		FlowType:           plessmodels.FlowTypeUserInputCode,
+		PhoneNumberPolicy: &plessmodels.TypeInputPhoneNumberPolicy{
+			DefaultRegion: " gb ",
+		},
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	assert.Equal(t, "GB", config.PhoneNumberPolicy.DefaultRegion)
+
+	assert.Equal(t, "+442079460958", api.NormalisePhoneNumber(" 020 7946 0958 ", "GB"))
+	// numbers with a country code ignore the default region
+	assert.Equal(t, "+16502530000", api.NormalisePhoneNumber("+1 650-253-0000", "GB"))
+	assert.Equal(t, "+16502530000", api.NormalisePhoneNumber("+1 650-253-0000", ""))
+	// numbers that cannot be parsed are only trimmed
+	assert.Equal(t, "020 7946 0958", api.NormalisePhoneNumber(" 020 7946 0958 ", ""))
+
+	recipeImplementation := makeTestCore().makeRecipeImplementation()
+	_, data, err := callAPIForTest(t, api.CreateCode, api.MakeAPIImplementation(), config, recipeImplementation, testAPIRequest{
+		body: map[string]interface{}{"phoneNumber": "020 7946 0958"},
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	assert.Equal(t, "OK", data["status"])
+	device, err := (*recipeImplementation.ListCodesByDeviceID)(data["deviceId"].(string), &map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.Equal(t, "+442079460958", *device.PhoneNumber)
+}
+
+func TestPhoneNumbersAreLookedUpInTheE164Format(t *testing.T) {
+	core := makeTestCore()
+	recipeImplementation := makePhoneNumberNormalisingRecipeImplementation(core.makeRecipeImplementation(), "GB")
+	userContext := &map[string]interface{}{}
+	phoneNumber := "+442079460958"
+	user := core.addUser(nil, &phoneNumber)
+
+	for _, format := range []string{"+442079460958", "+44 20 7946 0958", "020 7946 0958", " (020) 7946-0958 "} {
+		foundUser, err := (*recipeImplementation.GetUserByPhoneNumber)(format, userContext)
+		assert.NoError(t, err)
+		if assert.NotNil(t, foundUser, format) {
+			assert.Equal(t, user.ID, foundUser.ID)
+		}
+	}
+
+	newPhoneNumber := "07911 123456"
+	response, err := (*recipeImplementation.UpdateUser)(user.ID, nil, &newPhoneNumber, userContext)
+	assert.NoError(t, err)
+	assert.NotNil(t, response.OK)
+
+	updatedUser, err := (*recipeImplementation.GetUserByID)(user.ID, userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, "+447911123456", *updatedUser.PhoneNumber)
+
+	foundUser, err := (*recipeImplementation.GetUserByPhoneNumber)("+44 7911 123456", userContext)
+	assert.NoError(t, err)
+	if assert.NotNil(t, foundUser) {
+		assert.Equal(t, user.ID, foundUser.ID)
+	}
+	foundUser, err = (*recipeImplementation.GetUserByPhoneNumber)("020 7946 0958", userContext)
+	assert.NoError(t, err)
+	assert.Nil(t, foundUser)
+}
+
+func TestPhoneNumberRegionsAndTypes(t *testing.T) {
+	testCases := []struct {
+		policy   plessmodels.TypeInputPhoneNumberPolicy
+		expected map[string]string
+	}{
+		{
+			policy: plessmodels.TypeInputPhoneNumberPolicy{
+				AllowedRegions:     []string{"GB", "us"},
+				BlockedNumberTypes: []string{"toll_free"},
+			},
+			expected: map[string]string{
+				"+442079460958":     "",
+				"+1 650-253-0000":   "",
+				"+33 1 23 45 67 89": "Phone numbers from this country are not allowed",
+				"+1 800-253-0000":   "This type of phone number is not allowed",
+			},
+		},
+		{
+			policy: plessmodels.TypeInputPhoneNumberPolicy{
+				BlockedRegions:     []string{"FR"},
+				AllowedNumberTypes: []string{"MOBILE"},
+			},
+			expected: map[string]string{
+				"+447911123456":     "",
+				"+33 6 12 34 56 78": "Phone numbers from this country are not allowed",
+				"+442079460958":     "This type of phone number is not allowed",
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		policy := testCase.policy
+		config, err := validateAndNormaliseUserInput(getTestAppInfo(t), plessmodels.TypeInput{
+			ContactMethodPhone: plessmodels.NewContactMethodPhoneConfig(sendTestTextMessage),
+			FlowType:           plessmodels.FlowTypeUserInputCode,
+			PhoneNumberPolicy:  &policy,
+		})
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		for phoneNumber, expectedMessage := range testCase.expected {
+			_, data, err := callAPIForTest(t, api.CreateCode, api.MakeAPIImplementation(), config, makeTestCore().makeRecipeImplementation(), testAPIRequest{
+				body: map[string]interface{}{"phoneNumber": phoneNumber},
+			})
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+			if expectedMessage == "" {
+				assert.Equal(t, "OK", data["status"], phoneNumber)
+			} else {
+				assert.Equal(t, "GENERAL_ERROR", data["status"], phoneNumber)
+				assert.Equal(t, expectedMessage, data["message"], phoneNumber)
+			}
+		}
+	}
+
+	_, err := validateAndNormaliseUserInput(getTestAppInfo(t), plessmodels.TypeInput{
+		ContactMethodPhone: plessmodels.NewContactMethodPhoneConfig(sendTestTextMessage),
+		FlowType:           plessmodels.FlowTypeUserInputCode,
+		PhoneNumberPolicy: &plessmodels.TypeInputPhoneNumberPolicy{
+			BlockedNumberTypes: []string{"SATELLITE"},
+		},
+	})
+	assert.EqualError(t, err, "PhoneNumberPolicy.BlockedNumberTypes contains an unknown phone number type: SATELLITE")
+}
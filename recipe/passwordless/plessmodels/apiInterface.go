@@ -29,6 +29,7 @@ type APIOptions struct {
 	Req                  *http.Request
 	Res                  http.ResponseWriter
 	OtherHandler         http.HandlerFunc
+	AppInfo              supertokens.NormalisedAppinfo
 }
 
 type APIInterface struct {
@@ -37,6 +38,13 @@ type APIInterface struct {
 	ConsumeCodePOST      *func(userInput *UserInputCodeWithDeviceID, linkCode *string, preAuthSessionID string, options APIOptions, userContext supertokens.UserContext) (ConsumeCodePOSTResponse, error)
 	EmailExistsGET       *func(email string, options APIOptions, userContext supertokens.UserContext) (EmailExistsGETResponse, error)
 	PhoneNumberExistsGET *func(phoneNumber string, options APIOptions, userContext supertokens.UserContext) (PhoneNumberExistsGETResponse, error)
+	// ConfirmCrossDeviceSignInPOST is called from the device that requested a magic link to allow the
+	// link to be used on another device. It is only used if CrossDeviceProtection requires confirmation.
+	ConfirmCrossDeviceSignInPOST *func(preAuthSessionID string, options APIOptions, userContext supertokens.UserContext) (ConfirmCrossDeviceSignInPOSTResponse, error)
+	// CreateSecondFactorCodePOST sends a code to the user of the session of the request, which is
+	// consumed by ConsumeSecondFactorCodePOST. Both are only enabled if SecondFactor is set.
+	CreateSecondFactorCodePOST  *func(options APIOptions, userContext supertokens.UserContext) (CreateSecondFactorCodePOSTResponse, error)
+	ConsumeSecondFactorCodePOST *func(userInputCode string, options APIOptions, userContext supertokens.UserContext) (ConsumeSecondFactorCodePOSTResponse, error)
 }
 
 type ConsumeCodePOSTResponse struct {
@@ -54,15 +62,59 @@ type ConsumeCodePOSTResponse struct {
 		MaximumCodeInputAttempts    int
 	}
 	RestartFlowError *struct{}
-	GeneralError     *struct {
+	// CrossDeviceLinkError is returned if a magic link is opened on a device other than the one that
+	// requested it. If ConfirmationRequired is false, the user has to enter the code on the original
+	// device instead, otherwise the link can be used once the sign in is confirmed there.
+	CrossDeviceLinkError *struct {
+		ConfirmationRequired bool
+	}
+	GeneralError *struct {
+		Message string
+	}
+}
+
+type ConfirmCrossDeviceSignInPOSTResponse struct {
+	OK *struct{}
+	// NoPendingSignInError is returned if the magic link has not been opened on another device
+	NoPendingSignInError *struct{}
+	RestartFlowError     *struct{}
+}
+
+type CreateSecondFactorCodePOSTResponse struct {
+	OK *struct {
+		CodeLifetime uint64
+	}
+	// NoContactInfoError is returned if the user has no email or phone number that a code can be sent to
+	NoContactInfoError   *struct{}
+	TooManyRequestsError *struct {
+		RetryAfter uint64
+	}
+	GeneralError *struct {
 		Message string
 	}
 }
 
+type ConsumeSecondFactorCodePOSTResponse struct {
+	OK                          *struct{}
+	IncorrectUserInputCodeError *struct {
+		FailedCodeInputAttemptCount int
+		MaximumCodeInputAttempts    int
+	}
+	ExpiredUserInputCodeError *struct {
+		FailedCodeInputAttemptCount int
+		MaximumCodeInputAttempts    int
+	}
+	RestartFlowError *struct{}
+}
+
 type ResendCodePOSTResponse struct {
-	OK             *struct{}
-	ResetFlowError *struct{}
-	GeneralError   *struct {
+	OK                   *struct{}
+	ResetFlowError       *struct{}
+	TooManyRequestsError *struct {
+		// RetryAfter is the number of seconds after which a new code can be requested
+		RetryAfter uint64
+	}
+	GeneralError *struct {
 		Message string
 	}
 }
@@ -71,9 +123,13 @@ type CreateCodePOSTResponse struct {
 	OK *struct {
 		DeviceID         string
 		PreAuthSessionID string
-		FlowType         string
+		FlowType         FlowType
 	}
-	GeneralError *struct {
+	TooManyRequestsError *struct {
+		RetryAfter uint64
+	}
+	SignUpNotAllowedError *struct{}
+	GeneralError          *struct {
 		Message string
 	}
 }
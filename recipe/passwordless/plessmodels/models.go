@@ -15,7 +15,12 @@
 
 package plessmodels
 
-import "github.com/supertokens/supertokens-golang/supertokens"
+import (
+	"net/http"
+
+	"github.com/nyaruka/phonenumbers"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
 
 type User struct {
 	ID          string  `json:"id"`
@@ -28,22 +33,232 @@ type TypeInput struct {
 	ContactMethodPhone        ContactMethodPhoneConfig
 	ContactMethodEmail        ContactMethodEmailConfig
 	ContactMethodEmailOrPhone ContactMethodEmailOrPhoneConfig
-	FlowType                  string
-	GetLinkDomainAndPath      func(email *string, phoneNumber *string, userContext supertokens.UserContext) (string, error)
-	GetCustomUserInputCode    func(userContext supertokens.UserContext) (string, error)
-	Override                  *OverrideStruct
+	FlowType                  FlowType
+	// GetFlowType returns the flow type to use for a request, for example to send codes to mobile apps and
+	// magic links to browsers. contactMethod is either "EMAIL" or "PHONE". If it is not set, FlowType is used.
+	GetFlowType func(req *http.Request, contactMethod string, userContext supertokens.UserContext) (FlowType, error)
+	// AllowedFlowTypes are the flow types that GetFlowType can return. Defaults to all of them
+	AllowedFlowTypes []FlowType
+	// FlowTypeStore keeps the flow type chosen by GetFlowType for each device, so that resent codes
+	// use it too. It is required if GetFlowType is set, and has to be shared by all instances of the
+	// backend.
+	FlowTypeStore          *FlowTypeStore
+	GetLinkDomainAndPath   func(email *string, phoneNumber *string, userContext supertokens.UserContext) (string, error)
+	GetCustomUserInputCode func(userContext supertokens.UserContext) (string, error)
+	// RateLimit limits how many codes are sent by CreateCodePOST and ResendCodePOST. If not set,
+	// a code is sent for every request.
+	RateLimit *TypeInputRateLimit
+	// CrossDeviceProtection binds magic links to the browser that requested them. If not set, a magic
+	// link signs in whoever opens it.
+	CrossDeviceProtection *TypeInputCrossDeviceProtection
+	// PhoneNumberPolicy sets the region of phone numbers given without a country code and which phone
+	// numbers can be used to sign in
+	PhoneNumberPolicy *TypeInputPhoneNumberPolicy
+	// ShouldAllowSignUp is called before a code is sent to an email or phone number that does not belong
+	// to a user yet. If it returns false, no code is sent. If it is not set, anyone can sign up.
+	ShouldAllowSignUp func(email *string, phoneNumber *string, userContext supertokens.UserContext) (bool, error)
+	// RevealSignUpNotAllowed makes CreateCodePOST return a SignUpNotAllowedError if a sign up is refused.
+	// By default, it responds as if a code was sent, so that it does not reveal who has an account, and
+	// the email and phone number exists APIs are disabled.
+	RevealSignUpNotAllowed bool
+	// SecondFactor lets signed in users confirm a code sent to their email or phone number as a second
+	// factor. If not set, the second factor APIs are disabled.
+	SecondFactor *TypeInputSecondFactor
+	Override     *OverrideStruct
+}
+
+// FlowType decides whether users sign in by entering a code, by opening a magic link or with either of them
+type FlowType string
+
+const (
+	FlowTypeUserInputCode             FlowType = "USER_INPUT_CODE"
+	FlowTypeMagicLink                 FlowType = "MAGIC_LINK"
+	FlowTypeUserInputCodeAndMagicLink FlowType = "USER_INPUT_CODE_AND_MAGIC_LINK"
+)
+
+// IsValid is false for values other than the ones above
+func (f FlowType) IsValid() bool {
+	return f == FlowTypeUserInputCode || f == FlowTypeMagicLink || f == FlowTypeUserInputCodeAndMagicLink
+}
+
+// UsesUserInputCode is true if a code that the user has to enter is sent
+func (f FlowType) UsesUserInputCode() bool {
+	return f == FlowTypeUserInputCode || f == FlowTypeUserInputCodeAndMagicLink
+}
+
+// UsesMagicLink is true if a magic link is sent
+func (f FlowType) UsesMagicLink() bool {
+	return f == FlowTypeMagicLink || f == FlowTypeUserInputCodeAndMagicLink
 }
 
 type TypeNormalisedInput struct {
 	ContactMethodPhone        ContactMethodPhoneConfig
 	ContactMethodEmail        ContactMethodEmailConfig
 	ContactMethodEmailOrPhone ContactMethodEmailOrPhoneConfig
-	FlowType                  string
+	FlowType                  FlowType
+	GetFlowType               func(req *http.Request, contactMethod string, userContext supertokens.UserContext) (FlowType, error)
+	AllowedFlowTypes          map[FlowType]bool
+	FlowTypeStore             *FlowTypeStore
 	GetLinkDomainAndPath      func(email *string, phoneNumber *string, userContext supertokens.UserContext) (string, error)
 	GetCustomUserInputCode    func(userContext supertokens.UserContext) (string, error)
+	RateLimit                 *TypeNormalisedInputRateLimit
+	CrossDeviceProtection     *TypeNormalisedInputCrossDeviceProtection
+	PhoneNumberPolicy         TypeNormalisedInputPhoneNumberPolicy
+	ShouldAllowSignUp         func(email *string, phoneNumber *string, userContext supertokens.UserContext) (bool, error)
+	RevealSignUpNotAllowed    bool
+	SecondFactor              *TypeNormalisedInputSecondFactor
 	Override                  OverrideStruct
 }
 
+type TypeInputPhoneNumberPolicy struct {
+	// DefaultRegion is the ISO 3166-1 alpha-2 code, like "US", of the country of phone numbers given
+	// without a + prefix. If it is not set, such numbers are invalid.
+	DefaultRegion string
+	// AllowedRegions are the only countries whose phone numbers can be used, if it is not empty
+	AllowedRegions []string
+	BlockedRegions []string
+	// AllowedNumberTypes are the only types of phone numbers that can be used, if it is not empty. The types
+	// are "FIXED_LINE", "MOBILE", "FIXED_LINE_OR_MOBILE", "TOLL_FREE", "PREMIUM_RATE", "SHARED_COST", "VOIP",
+	// "PERSONAL_NUMBER", "PAGER", "UAN", "VOICEMAIL" and "UNKNOWN"
+	AllowedNumberTypes []string
+	// BlockedNumberTypes can be used to block numbers like []string{"PREMIUM_RATE", "VOIP"}
+	BlockedNumberTypes []string
+}
+
+type TypeNormalisedInputPhoneNumberPolicy struct {
+	DefaultRegion      string
+	AllowedRegions     map[string]bool
+	BlockedRegions     map[string]bool
+	AllowedNumberTypes map[phonenumbers.PhoneNumberType]bool
+	BlockedNumberTypes map[phonenumbers.PhoneNumberType]bool
+}
+
+type TypeInputCrossDeviceProtection struct {
+	// OnOtherDevice decides what happens when a magic link is opened on another device. It is either
+	// "REQUIRE_USER_INPUT_CODE" (the default), where the link is refused and the user has to enter the
+	// code on the original device instead, or "REQUIRE_CONFIRMATION", where the link only works once the
+	// sign in is confirmed on the original device using ConfirmCrossDeviceSignInPOST.
+	OnOtherDevice string
+	// Store keeps track of the sign ins waiting for confirmation. It is required if OnOtherDevice is
+	// "REQUIRE_CONFIRMATION", and must be shared by all the instances of your API.
+	Store *CrossDeviceSignInStore
+}
+
+type TypeNormalisedInputCrossDeviceProtection struct {
+	OnOtherDevice string
+	Store         *CrossDeviceSignInStore
+}
+
+type CrossDeviceSignInInfo struct {
+	PreAuthSessionID string
+	Confirmed        bool
+	// Expiry is the time in milliseconds after which the info can be discarded
+	Expiry uint64
+}
+
+type CrossDeviceSignInStore struct {
+	Save   func(info CrossDeviceSignInInfo, userContext supertokens.UserContext) error
+	Get    func(preAuthSessionID string, userContext supertokens.UserContext) (*CrossDeviceSignInInfo, error)
+	Remove func(preAuthSessionID string, userContext supertokens.UserContext) error
+}
+
+type DeviceFlowTypeInfo struct {
+	DeviceID string
+	FlowType FlowType
+	// Expiry is the time in milliseconds after which the info can be discarded
+	Expiry uint64
+}
+
+type FlowTypeStore struct {
+	Save func(info DeviceFlowTypeInfo, userContext supertokens.UserContext) error
+	Get  func(deviceID string, userContext supertokens.UserContext) (*DeviceFlowTypeInfo, error)
+}
+
+type TypeInputSecondFactor struct {
+	// GetContactInfo returns the verified email and phone number of a user that a code can be sent to.
+	// It is required, since the users of other recipes are not known to this recipe.
+	GetContactInfo func(userID string, userContext supertokens.UserContext) (*string, *string, error)
+	// AccessTokenPayloadKey is set to the time in milliseconds at which the second factor was completed.
+	// Defaults to "passwordlessSecondFactorCompletedAt"
+	AccessTokenPayloadKey string
+	// MaximumCodeInputAttempts defaults to 5
+	MaximumCodeInputAttempts *int
+	// CodeStore keeps the latest code of each session. It is required, and must be shared by all the
+	// instances of your API. The codes are checked using this store instead of ConsumeCode, which would
+	// sign up the owner of the email or phone number.
+	CodeStore *SecondFactorCodeStore
+}
+
+type TypeNormalisedInputSecondFactor struct {
+	GetContactInfo           func(userID string, userContext supertokens.UserContext) (*string, *string, error)
+	AccessTokenPayloadKey    string
+	MaximumCodeInputAttempts int
+	CodeStore                SecondFactorCodeStore
+}
+
+type SecondFactorCodeStore struct {
+	// Save replaces the code of the session of codeInfo
+	Save func(codeInfo SecondFactorCodeInfo, userContext supertokens.UserContext) error
+	// IncrementAttemptCount must atomically increment FailedCodeInputAttemptCount of the code of the
+	// session and return the updated code info, or nil if the session has no code.
+	IncrementAttemptCount func(sessionHandle string, userContext supertokens.UserContext) (*SecondFactorCodeInfo, error)
+	Remove                func(sessionHandle string, userContext supertokens.UserContext) error
+}
+
+// SecondFactorCodeInfo entries can be expired after Expiry
+type SecondFactorCodeInfo struct {
+	SessionHandle string
+	// DeviceID and CodeID are those of the code created with CreateCode, which is revoked once the
+	// right code is entered
+	DeviceID                    string
+	CodeID                      string
+	UserInputCode               string
+	Expiry                      uint64
+	FailedCodeInputAttemptCount int
+}
+
+type TypeInputRateLimit struct {
+	// PerDestination limits the codes sent to a single email or phone number. Defaults to 5 per hour
+	PerDestination *RateLimitWindow
+	// PerIPAddress limits the codes requested from a single IP address. Defaults to 20 per hour
+	PerIPAddress *RateLimitWindow
+	// ResendCooldown is the minimum time in milliseconds between two codes sent for the same device,
+	// based on the TimeCreated of its latest code. Defaults to 30 seconds
+	ResendCooldown *uint64
+	// GetIPAddress defaults to the remote address of the request, without the port. Override it if
+	// the API runs behind a proxy.
+	GetIPAddress func(req *http.Request, userContext supertokens.UserContext) (string, error)
+	// Store keeps count of the requests made in each window. It is required, and must be shared by
+	// all the instances of your API so that the limits apply to all of them
+	Store *RateLimitStore
+}
+
+type TypeNormalisedInputRateLimit struct {
+	PerDestination RateLimitWindow
+	PerIPAddress   RateLimitWindow
+	ResendCooldown uint64
+	GetIPAddress   func(req *http.Request, userContext supertokens.UserContext) (string, error)
+	Store          RateLimitStore
+}
+
+type RateLimitWindow struct {
+	MaxRequests int
+	// Duration is in milliseconds
+	Duration uint64
+}
+
+type RateLimitCounter struct {
+	Count int
+	// WindowStart is the time in milliseconds at which the current window started
+	WindowStart uint64
+}
+
+type RateLimitStore struct {
+	// Increment must atomically count a request for the key and return the counter of the current window. A new
+	// window is started if the previous one started more than windowDuration milliseconds ago.
+	Increment func(key string, windowDuration uint64, userContext supertokens.UserContext) (RateLimitCounter, error)
+}
+
 type OverrideStruct struct {
 	Functions func(originalImplementation RecipeInterface) RecipeInterface
 	APIs      func(originalImplementation APIInterface) APIInterface
@@ -68,3 +283,30 @@ type ContactMethodPhoneConfig struct {
 	ValidatePhoneNumber            func(phoneNumber interface{}) *string
 	CreateAndSendCustomTextMessage func(phoneNumber string, userInputCode *string, urlWithLinkCode *string, codeLifetime uint64, preAuthSessionId string, userContext supertokens.UserContext) error
 }
+
+// NewContactMethodEmailConfig enables signing in with an email address, using createAndSendCustomEmail
+// to send the codes and magic links
+func NewContactMethodEmailConfig(createAndSendCustomEmail func(email string, userInputCode *string, urlWithLinkCode *string, codeLifetime uint64, preAuthSessionId string, userContext supertokens.UserContext) error) ContactMethodEmailConfig {
+	return ContactMethodEmailConfig{
+		Enabled:                  true,
+		CreateAndSendCustomEmail: createAndSendCustomEmail,
+	}
+}
+
+// NewContactMethodPhoneConfig enables signing in with a phone number, using createAndSendCustomTextMessage
+// to send the codes and magic links
+func NewContactMethodPhoneConfig(createAndSendCustomTextMessage func(phoneNumber string, userInputCode *string, urlWithLinkCode *string, codeLifetime uint64, preAuthSessionId string, userContext supertokens.UserContext) error) ContactMethodPhoneConfig {
+	return ContactMethodPhoneConfig{
+		Enabled:                        true,
+		CreateAndSendCustomTextMessage: createAndSendCustomTextMessage,
+	}
+}
+
+// NewContactMethodEmailOrPhoneConfig lets users choose between an email address and a phone number
+func NewContactMethodEmailOrPhoneConfig(createAndSendCustomEmail func(email string, userInputCode *string, urlWithLinkCode *string, codeLifetime uint64, preAuthSessionId string, userContext supertokens.UserContext) error, createAndSendCustomTextMessage func(phoneNumber string, userInputCode *string, urlWithLinkCode *string, codeLifetime uint64, preAuthSessionId string, userContext supertokens.UserContext) error) ContactMethodEmailOrPhoneConfig {
+	return ContactMethodEmailOrPhoneConfig{
+		Enabled:                        true,
+		CreateAndSendCustomEmail:       createAndSendCustomEmail,
+		CreateAndSendCustomTextMessage: createAndSendCustomTextMessage,
+	}
+}
@@ -0,0 +1,76 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package passwordless
+
+import (
+	"errors"
+	"net"
+	"net/http"
+
+	"github.com/supertokens/supertokens-golang/recipe/passwordless/plessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+const hourInMS uint64 = 60 * 60 * 1000
+
+func defaultGetIPAddress(req *http.Request, userContext supertokens.UserContext) (string, error) {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr, nil
+	}
+	return host, nil
+}
+
+func validateAndNormaliseRateLimitConfig(config *plessmodels.TypeInputRateLimit) (*plessmodels.TypeNormalisedInputRateLimit, error) {
+	if config == nil {
+		return nil, nil
+	}
+	if config.Store == nil {
+		return nil, errors.New("please provide a Store in the RateLimit config")
+	}
+	normalisedConfig := plessmodels.TypeNormalisedInputRateLimit{
+		PerDestination: plessmodels.RateLimitWindow{
+			MaxRequests: 5,
+			Duration:    hourInMS,
+		},
+		PerIPAddress: plessmodels.RateLimitWindow{
+			MaxRequests: 20,
+			Duration:    hourInMS,
+		},
+		ResendCooldown: 30 * 1000,
+		GetIPAddress:   defaultGetIPAddress,
+		Store:          *config.Store,
+	}
+	if config.PerDestination != nil {
+		if config.PerDestination.MaxRequests <= 0 || config.PerDestination.Duration == 0 {
+			return nil, errors.New("RateLimit.PerDestination must allow at least one request in a window with a non zero Duration")
+		}
+		normalisedConfig.PerDestination = *config.PerDestination
+	}
+	if config.PerIPAddress != nil {
+		if config.PerIPAddress.MaxRequests <= 0 || config.PerIPAddress.Duration == 0 {
+			return nil, errors.New("RateLimit.PerIPAddress must allow at least one request in a window with a non zero Duration")
+		}
+		normalisedConfig.PerIPAddress = *config.PerIPAddress
+	}
+	if config.ResendCooldown != nil {
+		normalisedConfig.ResendCooldown = *config.ResendCooldown
+	}
+	if config.GetIPAddress != nil {
+		normalisedConfig.GetIPAddress = config.GetIPAddress
+	}
+	return &normalisedConfig, nil
+}
@@ -0,0 +1,200 @@
+/*
+ * Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package passwordless
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/passwordless/plessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+	"github.com/supertokens/supertokens-golang/test/unittesting"
+)
+
+func makeInMemoryRateLimitStoreForTest() plessmodels.RateLimitStore {
+	var lock sync.Mutex
+	counters := map[string]plessmodels.RateLimitCounter{}
+	return plessmodels.RateLimitStore{
+		Increment: func(key string, windowDuration uint64, userContext supertokens.UserContext) (plessmodels.RateLimitCounter, error) {
+			lock.Lock()
+			defer lock.Unlock()
+			now := uint64(time.Now().UnixNano() / 1000000)
+			counter, ok := counters[key]
+			if !ok || counter.WindowStart+windowDuration <= now {
+				counter = plessmodels.RateLimitCounter{
+					Count:       0,
+					WindowStart: now,
+				}
+			}
+			counter.Count++
+			counters[key] = counter
+			return counter, nil
+		},
+	}
+}
+
+// getIPAddressForTest reads the IP address from a header, since all the requests made to the test
+// server come from the same address
+func getIPAddressForTest(req *http.Request, userContext supertokens.UserContext) (string, error) {
+	return req.Header.Get("X-Forwarded-For"), nil
+}
+
+func postFromIPAddressForTest(t *testing.T, url string, ipAddress string, body map[string]interface{}) (*http.Response, map[string]interface{}) {
+	bodyBytes, err := json.Marshal(body)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(bodyBytes))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Forwarded-For", ipAddress)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer resp.Body.Close()
+	dataInBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	var data map[string]interface{}
+	err = json.Unmarshal(dataInBytes, &data)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	return resp, data
+}
+
+func TestCodesAreLimitedPerDestinationAndIPAddress(t *testing.T) {
+	BeforeEach()
+	unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+	rateLimitStore := makeInMemoryRateLimitStoreForTest()
+	testServer, _ := startServerForTest(t, plessmodels.TypeInput{
+		FlowType: plessmodels.FlowTypeUserInputCode,
+		RateLimit: &plessmodels.TypeInputRateLimit{
+			PerDestination: &plessmodels.RateLimitWindow{
+				MaxRequests: 2,
+				Duration:    hourInMS,
+			},
+			PerIPAddress: &plessmodels.RateLimitWindow{
+				MaxRequests: 4,
+				Duration:    hourInMS,
+			},
+			GetIPAddress: getIPAddressForTest,
+			Store:        &rateLimitStore,
+		},
+	})
+	defer testServer.Close()
+
+	createCode := func(email string, ipAddress string) (*http.Response, map[string]interface{}) {
+		return postFromIPAddressForTest(t, testServer.URL+"/auth/signinup/code", ipAddress, map[string]interface{}{
+			"email": email,
+		})
+	}
+
+	_, data := createCode("johndoe@gmail.com", "1.1.1.1")
+	assert.Equal(t, "OK", data["status"])
+	// the same email in a different case counts against the same limit
+	_, data = createCode("JohnDoe@gmail.com", "1.1.1.1")
+	assert.Equal(t, "OK", data["status"])
+
+	res, data := createCode("johndoe@gmail.com", "1.1.1.1")
+	assert.Equal(t, "TOO_MANY_REQUESTS_ERROR", data["status"])
+	assert.InDelta(t, 3600, data["retryAfter"], 1)
+	assert.Equal(t, strconv.Itoa(int(data["retryAfter"].(float64))), res.Header.Get("Retry-After"))
+
+	// the IP address has made 3 requests
+	_, data = createCode("janedoe@gmail.com", "1.1.1.1")
+	assert.Equal(t, "OK", data["status"])
+	_, data = createCode("jackdoe@gmail.com", "1.1.1.1")
+	assert.Equal(t, "TOO_MANY_REQUESTS_ERROR", data["status"])
+
+	_, data = createCode("jackdoe@gmail.com", "2.2.2.2")
+	assert.Equal(t, "OK", data["status"])
+}
+
+func TestCodesCannotBeResentBeforeTheCooldown(t *testing.T) {
+	BeforeEach()
+	unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+	resendCooldown := uint64(1000)
+	rateLimitStore := makeInMemoryRateLimitStoreForTest()
+	testServer, _ := startServerForTest(t, plessmodels.TypeInput{
+		FlowType: plessmodels.FlowTypeUserInputCode,
+		RateLimit: &plessmodels.TypeInputRateLimit{
+			ResendCooldown: &resendCooldown,
+			GetIPAddress:   getIPAddressForTest,
+			Store:          &rateLimitStore,
+		},
+	})
+	defer testServer.Close()
+
+	_, data := postFromIPAddressForTest(t, testServer.URL+"/auth/signinup/code", "1.1.1.1", map[string]interface{}{
+		"email": "johndoe@gmail.com",
+	})
+	assert.Equal(t, "OK", data["status"])
+	resendBody := map[string]interface{}{
+		"deviceId":         data["deviceId"],
+		"preAuthSessionId": data["preAuthSessionId"],
+	}
+
+	res, data := postFromIPAddressForTest(t, testServer.URL+"/auth/signinup/code/resend", "1.1.1.1", resendBody)
+	assert.Equal(t, "TOO_MANY_REQUESTS_ERROR", data["status"])
+	assert.InDelta(t, 1, data["retryAfter"], 1)
+	assert.Equal(t, strconv.Itoa(int(data["retryAfter"].(float64))), res.Header.Get("Retry-After"))
+
+	time.Sleep(time.Duration(resendCooldown+100) * time.Millisecond)
+	_, data = postFromIPAddressForTest(t, testServer.URL+"/auth/signinup/code/resend", "1.1.1.1", resendBody)
+	assert.Equal(t, "OK", data["status"])
+}
+
+func TestRateLimitRequiresAStore(t *testing.T) {
+	configValue := supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: "http://localhost:8080",
+		},
+		AppInfo: supertokens.AppInfo{
+			APIDomain:     "api.supertokens.io",
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{
+			Init(plessmodels.TypeInput{
+				ContactMethodEmail: plessmodels.NewContactMethodEmailConfig(sendTestEmail),
+				FlowType:           plessmodels.FlowTypeUserInputCode,
+				RateLimit:          &plessmodels.TypeInputRateLimit{},
+			}),
+		},
+	}
+
+	BeforeEach()
+	unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+	err := supertokens.Init(configValue)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "Store")
+}
@@ -37,7 +37,10 @@ var singletonInstance *Recipe
 
 func MakeRecipe(recipeId string, appInfo supertokens.NormalisedAppinfo, config plessmodels.TypeInput, onGeneralError func(err error, req *http.Request, res http.ResponseWriter)) (Recipe, error) {
 	r := &Recipe{}
-	verifiedConfig := validateAndNormaliseUserInput(appInfo, config)
+	verifiedConfig, err := validateAndNormaliseUserInput(appInfo, config)
+	if err != nil {
+		return Recipe{}, err
+	}
 	r.Config = verifiedConfig
 
 	r.APIImpl = verifiedConfig.Override.APIs(api.MakeAPIImplementation())
@@ -47,7 +50,7 @@ func MakeRecipe(recipeId string, appInfo supertokens.NormalisedAppinfo, config p
 		return Recipe{}, err
 	}
 	recipeImplementation := MakeRecipeImplementation(*querierInstance)
-	r.RecipeImpl = verifiedConfig.Override.Functions(recipeImplementation)
+	r.RecipeImpl = makePhoneNumberNormalisingRecipeImplementation(verifiedConfig.Override.Functions(recipeImplementation), verifiedConfig.PhoneNumberPolicy.DefaultRegion)
 
 	recipeModuleInstance := supertokens.MakeRecipeModule(recipeId, appInfo, r.handleAPIRequest, r.getAllCORSHeaders, r.getAPIsHandled, r.handleError, onGeneralError)
 	r.RecipeModule = recipeModuleInstance
@@ -99,6 +102,21 @@ func (r *Recipe) getAPIsHandled() ([]supertokens.APIHandled, error) {
 	if err != nil {
 		return nil, err
 	}
+	confirmCrossDeviceAPINormalised, err := supertokens.NewNormalisedURLPath(confirmCrossDeviceAPI)
+	if err != nil {
+		return nil, err
+	}
+	createSecondFactorCodeAPINormalised, err := supertokens.NewNormalisedURLPath(createSecondFactorCodeAPI)
+	if err != nil {
+		return nil, err
+	}
+	consumeSecondFactorCodeAPINormalised, err := supertokens.NewNormalisedURLPath(consumeSecondFactorCodeAPI)
+	if err != nil {
+		return nil, err
+	}
+
+	// the exists APIs would reveal who has an account when refused sign ups are hidden
+	isUserExistenceHidden := r.Config.ShouldAllowSignUp != nil && !r.Config.RevealSignUpNotAllowed
 
 	return []supertokens.APIHandled{{
 		Method:                 http.MethodPost,
@@ -114,17 +132,32 @@ func (r *Recipe) getAPIsHandled() ([]supertokens.APIHandled, error) {
 		Method:                 http.MethodGet,
 		PathWithoutAPIBasePath: doesEmailExistsAPINormalised,
 		ID:                     doesEmailExistAPI,
-		Disabled:               r.APIImpl.EmailExistsGET == nil,
+		Disabled:               r.APIImpl.EmailExistsGET == nil || isUserExistenceHidden,
 	}, {
 		Method:                 http.MethodGet,
 		PathWithoutAPIBasePath: doesPhoneNumberExistsAPINormalised,
 		ID:                     doesPhoneNumberExistAPI,
-		Disabled:               r.APIImpl.PhoneNumberExistsGET == nil,
+		Disabled:               r.APIImpl.PhoneNumberExistsGET == nil || isUserExistenceHidden,
 	}, {
 		Method:                 http.MethodPost,
 		PathWithoutAPIBasePath: resendCodeAPINormalised,
 		ID:                     resendCodeAPI,
 		Disabled:               r.APIImpl.ResendCodePOST == nil,
+	}, {
+		Method:                 http.MethodPost,
+		PathWithoutAPIBasePath: confirmCrossDeviceAPINormalised,
+		ID:                     confirmCrossDeviceAPI,
+		Disabled:               r.APIImpl.ConfirmCrossDeviceSignInPOST == nil || r.Config.CrossDeviceProtection == nil || r.Config.CrossDeviceProtection.OnOtherDevice != "REQUIRE_CONFIRMATION",
+	}, {
+		Method:                 http.MethodPost,
+		PathWithoutAPIBasePath: createSecondFactorCodeAPINormalised,
+		ID:                     createSecondFactorCodeAPI,
+		Disabled:               r.APIImpl.CreateSecondFactorCodePOST == nil || r.Config.SecondFactor == nil,
+	}, {
+		Method:                 http.MethodPost,
+		PathWithoutAPIBasePath: consumeSecondFactorCodeAPINormalised,
+		ID:                     consumeSecondFactorCodeAPI,
+		Disabled:               r.APIImpl.ConsumeSecondFactorCodePOST == nil || r.Config.SecondFactor == nil,
 	}}, nil
 }
 
@@ -136,11 +169,18 @@ func (r *Recipe) handleAPIRequest(id string, req *http.Request, res http.Respons
 		Req:                  req,
 		Res:                  res,
 		OtherHandler:         theirHandler,
+		AppInfo:              r.RecipeModule.GetAppInfo(),
 	}
 	if id == consumeCodeAPI {
 		return api.ConsumeCode(r.APIImpl, options)
+	} else if id == confirmCrossDeviceAPI {
+		return api.ConfirmCrossDeviceSignIn(r.APIImpl, options)
 	} else if id == createCodeAPI {
 		return api.CreateCode(r.APIImpl, options)
+	} else if id == createSecondFactorCodeAPI {
+		return api.CreateSecondFactorCode(r.APIImpl, options)
+	} else if id == consumeSecondFactorCodeAPI {
+		return api.ConsumeSecondFactorCode(r.APIImpl, options)
 	} else if id == doesEmailExistAPI {
 		return api.DoesEmailExist(r.APIImpl, options)
 	} else if id == doesPhoneNumberExistAPI {
@@ -197,7 +237,7 @@ func (r *Recipe) SignInUp(email *string, phoneNumber *string, userContext supert
 
 	var userInputCode *plessmodels.UserInputCodeWithDeviceID
 	var linkCode *string
-	if r.Config.FlowType == "MAGIC_LINK" {
+	if r.Config.FlowType == plessmodels.FlowTypeMagicLink {
 		linkCode = &codeInfo.OK.LinkCode
 	} else {
 		userInputCode = &plessmodels.UserInputCodeWithDeviceID{
@@ -0,0 +1,50 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package passwordless
+
+import (
+	"errors"
+
+	"github.com/supertokens/supertokens-golang/recipe/passwordless/plessmodels"
+)
+
+func validateAndNormaliseSecondFactorConfig(config *plessmodels.TypeInputSecondFactor) (*plessmodels.TypeNormalisedInputSecondFactor, error) {
+	if config == nil {
+		return nil, nil
+	}
+	if config.GetContactInfo == nil {
+		return nil, errors.New("please provide GetContactInfo in the SecondFactor config")
+	}
+	if config.CodeStore == nil {
+		return nil, errors.New("please provide a CodeStore in the SecondFactor config")
+	}
+	normalisedConfig := plessmodels.TypeNormalisedInputSecondFactor{
+		GetContactInfo:           config.GetContactInfo,
+		AccessTokenPayloadKey:    "passwordlessSecondFactorCompletedAt",
+		MaximumCodeInputAttempts: 5,
+		CodeStore:                *config.CodeStore,
+	}
+	if config.AccessTokenPayloadKey != "" {
+		normalisedConfig.AccessTokenPayloadKey = config.AccessTokenPayloadKey
+	}
+	if config.MaximumCodeInputAttempts != nil {
+		if *config.MaximumCodeInputAttempts <= 0 {
+			return nil, errors.New("SecondFactor.MaximumCodeInputAttempts must be greater than 0")
+		}
+		normalisedConfig.MaximumCodeInputAttempts = *config.MaximumCodeInputAttempts
+	}
+	return &normalisedConfig, nil
+}
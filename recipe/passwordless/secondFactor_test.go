@@ -0,0 +1,220 @@
+/*
+ * Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package passwordless
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/passwordless/plessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+	"github.com/supertokens/supertokens-golang/test/unittesting"
+)
+
+// the second factor codes are sent to another email than the one used to sign in, which does not
+// belong to a passwordless user
+const secondFactorEmailForTest = "johndoe+otp@gmail.com"
+
+func makeInMemorySecondFactorCodeStoreForTest() plessmodels.SecondFactorCodeStore {
+	var lock sync.Mutex
+	codes := map[string]plessmodels.SecondFactorCodeInfo{}
+	return plessmodels.SecondFactorCodeStore{
+		Save: func(codeInfo plessmodels.SecondFactorCodeInfo, userContext supertokens.UserContext) error {
+			lock.Lock()
+			defer lock.Unlock()
+			codes[codeInfo.SessionHandle] = codeInfo
+			return nil
+		},
+		IncrementAttemptCount: func(sessionHandle string, userContext supertokens.UserContext) (*plessmodels.SecondFactorCodeInfo, error) {
+			lock.Lock()
+			defer lock.Unlock()
+			codeInfo, ok := codes[sessionHandle]
+			if !ok {
+				return nil, nil
+			}
+			codeInfo.FailedCodeInputAttemptCount++
+			codes[sessionHandle] = codeInfo
+			return &codeInfo, nil
+		},
+		Remove: func(sessionHandle string, userContext supertokens.UserContext) error {
+			lock.Lock()
+			defer lock.Unlock()
+			delete(codes, sessionHandle)
+			return nil
+		},
+	}
+}
+
+func makeSecondFactorConfigForTest() *plessmodels.TypeInputSecondFactor {
+	codeStore := makeInMemorySecondFactorCodeStoreForTest()
+	return &plessmodels.TypeInputSecondFactor{
+		GetContactInfo: func(userID string, userContext supertokens.UserContext) (*string, *string, error) {
+			email := secondFactorEmailForTest
+			return &email, nil, nil
+		},
+		CodeStore: &codeStore,
+	}
+}
+
+func startSecondFactorServerForTest(t *testing.T) (*httptest.Server, func() sentCodeForTest) {
+	return startServerForTest(t, plessmodels.TypeInput{
+		FlowType:     plessmodels.FlowTypeUserInputCode,
+		SecondFactor: makeSecondFactorConfigForTest(),
+	})
+}
+
+// signInForTest signs in with a passwordless code, so that the device gets a session
+func signInForTest(t *testing.T, testServer *httptest.Server, device *http.Client, getSentCode func() sentCodeForTest) {
+	data := postForTest(t, device, testServer.URL+"/auth/signinup/code", map[string]interface{}{
+		"email": "johndoe@gmail.com",
+	})
+	assert.Equal(t, "OK", data["status"])
+	data = postForTest(t, device, testServer.URL+"/auth/signinup/code/consume", map[string]interface{}{
+		"preAuthSessionId": data["preAuthSessionId"],
+		"deviceId":         data["deviceId"],
+		"userInputCode":    *getSentCode().userInputCode,
+	})
+	if data["status"] != "OK" {
+		t.Fatalf("could not sign in: %v", data)
+	}
+}
+
+func getAccessTokenPayloadForTest(t *testing.T, testServer *httptest.Server, device *http.Client) map[string]interface{} {
+	resp, err := device.Get(testServer.URL + "/sessioninfo")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer resp.Body.Close()
+	dataInBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	var data map[string]interface{}
+	err = json.Unmarshal(dataInBytes, &data)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	return data
+}
+
+func TestSecondFactorCodeCanBeConsumedOnce(t *testing.T) {
+	BeforeEach()
+	unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+	testServer, getSentCode := startSecondFactorServerForTest(t)
+	defer testServer.Close()
+
+	device := makeDeviceForTest(t)
+	signInForTest(t, testServer, device, getSentCode)
+	assert.NotContains(t, getAccessTokenPayloadForTest(t, testServer, device), "passwordlessSecondFactorCompletedAt")
+
+	data := postForTest(t, device, testServer.URL+"/auth/secondfactor/code", map[string]interface{}{})
+	assert.Equal(t, "OK", data["status"])
+	assert.Greater(t, data["codeLifetime"], float64(0))
+	sentCode := getSentCode()
+	assert.Equal(t, secondFactorEmailForTest, sentCode.email)
+	assert.Empty(t, sentCode.linkCode)
+
+	data = postForTest(t, device, testServer.URL+"/auth/secondfactor/code/consume", map[string]interface{}{
+		"userInputCode": "wrong",
+	})
+	assert.Equal(t, "INCORRECT_USER_INPUT_CODE_ERROR", data["status"])
+	assert.Equal(t, float64(1), data["failedCodeInputAttemptCount"])
+
+	data = postForTest(t, device, testServer.URL+"/auth/secondfactor/code/consume", map[string]interface{}{
+		"userInputCode": *sentCode.userInputCode,
+	})
+	assert.Equal(t, "OK", data["status"])
+	assert.Contains(t, getAccessTokenPayloadForTest(t, testServer, device), "passwordlessSecondFactorCompletedAt")
+
+	// consuming the code does not sign up the owner of the email
+	user, err := GetUserByEmail(secondFactorEmailForTest)
+	assert.NoError(t, err)
+	assert.Nil(t, user)
+
+	data = postForTest(t, device, testServer.URL+"/auth/secondfactor/code/consume", map[string]interface{}{
+		"userInputCode": *sentCode.userInputCode,
+	})
+	assert.Equal(t, "RESTART_FLOW_ERROR", data["status"])
+}
+
+func TestSecondFactorCodeCanOnlyBeConsumedByItsSession(t *testing.T) {
+	BeforeEach()
+	unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+	testServer, getSentCode := startSecondFactorServerForTest(t)
+	defer testServer.Close()
+
+	device := makeDeviceForTest(t)
+	otherDevice := makeDeviceForTest(t)
+	signInForTest(t, testServer, device, getSentCode)
+	signInForTest(t, testServer, otherDevice, getSentCode)
+
+	data := postForTest(t, device, testServer.URL+"/auth/secondfactor/code", map[string]interface{}{})
+	assert.Equal(t, "OK", data["status"])
+	userInputCode := *getSentCode().userInputCode
+
+	data = postForTest(t, otherDevice, testServer.URL+"/auth/secondfactor/code/consume", map[string]interface{}{
+		"userInputCode": userInputCode,
+	})
+	assert.Equal(t, "RESTART_FLOW_ERROR", data["status"])
+	assert.NotContains(t, getAccessTokenPayloadForTest(t, testServer, otherDevice), "passwordlessSecondFactorCompletedAt")
+
+	data = postForTest(t, device, testServer.URL+"/auth/secondfactor/code/consume", map[string]interface{}{
+		"userInputCode": userInputCode,
+	})
+	assert.Equal(t, "OK", data["status"])
+}
+
+func TestSecondFactorCodeCanOnlyBeTriedAFewTimes(t *testing.T) {
+	BeforeEach()
+	unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+	testServer, getSentCode := startSecondFactorServerForTest(t)
+	defer testServer.Close()
+
+	device := makeDeviceForTest(t)
+	signInForTest(t, testServer, device, getSentCode)
+
+	data := postForTest(t, device, testServer.URL+"/auth/secondfactor/code", map[string]interface{}{})
+	assert.Equal(t, "OK", data["status"])
+	userInputCode := *getSentCode().userInputCode
+
+	// 5 attempts are allowed by default
+	for i := 1; i < 5; i++ {
+		data = postForTest(t, device, testServer.URL+"/auth/secondfactor/code/consume", map[string]interface{}{
+			"userInputCode": "wrong",
+		})
+		assert.Equal(t, "INCORRECT_USER_INPUT_CODE_ERROR", data["status"])
+		assert.Equal(t, float64(i), data["failedCodeInputAttemptCount"])
+		assert.Equal(t, float64(5), data["maximumCodeInputAttempts"])
+	}
+	data = postForTest(t, device, testServer.URL+"/auth/secondfactor/code/consume", map[string]interface{}{
+		"userInputCode": "wrong",
+	})
+	assert.Equal(t, "RESTART_FLOW_ERROR", data["status"])
+
+	data = postForTest(t, device, testServer.URL+"/auth/secondfactor/code/consume", map[string]interface{}{
+		"userInputCode": userInputCode,
+	})
+	assert.Equal(t, "RESTART_FLOW_ERROR", data["status"])
+}
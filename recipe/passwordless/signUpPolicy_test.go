@@ -0,0 +1,149 @@
+/*
+ * Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package passwordless
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/passwordless/api"
+	"github.com/supertokens/supertokens-golang/recipe/passwordless/plessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func makeSignUpPolicyConfigForTest(t *testing.T, revealSignUpNotAllowed bool, sentEmails *[]string) plessmodels.TypeNormalisedInput {
+	config, err := validateAndNormaliseUserInput(getTestAppInfo(t), plessmodels.TypeInput{
+		ContactMethodEmail: plessmodels.NewContactMethodEmailConfig(func(email string, userInputCode *string, urlWithLinkCode *string, codeLifetime uint64, preAuthSessionId string, userContext supertokens.UserContext) error {
+			*sentEmails = append(*sentEmails, email)
+			return nil
+		}),
+		FlowType: plessmodels.FlowTypeUserInputCode,
+		ShouldAllowSignUp: func(email *string, phoneNumber *string, userContext supertokens.UserContext) (bool, error) {
+			return false, nil
+		},
+		RevealSignUpNotAllowed: revealSignUpNotAllowed,
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	return config
+}
+
+func TestRefusedSignUpsLookLikeSignIns(t *testing.T) {
+	sentEmails := []string{}
+	config := makeSignUpPolicyConfigForTest(t, false, &sentEmails)
+	core := makeTestCore()
+	existingEmail := "johndoe@gmail.com"
+	core.addUser(&existingEmail, nil)
+	recipeImplementation := core.makeRecipeImplementation()
+	apiImplementation := api.MakeAPIImplementation()
+
+	callAPI := func(handler func(plessmodels.APIInterface, plessmodels.APIOptions) error, body map[string]interface{}) map[string]interface{} {
+		_, data, err := callAPIForTest(t, handler, apiImplementation, config, recipeImplementation, testAPIRequest{
+			body: body,
+		})
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		return data
+	}
+
+	for _, email := range []string{existingEmail, "janedoe@gmail.com"} {
+		data := callAPI(api.CreateCode, map[string]interface{}{"email": email})
+		assert.Equal(t, "OK", data["status"], email)
+		assert.Equal(t, "USER_INPUT_CODE", data["flowType"], email)
+		deviceID := data["deviceId"].(string)
+		preAuthSessionID := data["preAuthSessionId"].(string)
+
+		data = callAPI(api.ResendCode, map[string]interface{}{
+			"deviceId":         deviceID,
+			"preAuthSessionId": preAuthSessionID,
+		})
+		assert.Equal(t, "OK", data["status"], email)
+
+		if email != existingEmail {
+			// the code can be guessed, but it must not create the user
+			device := core.devices[deviceID]
+			data = callAPI(api.ConsumeCode, map[string]interface{}{
+				"deviceId":         deviceID,
+				"preAuthSessionId": preAuthSessionID,
+				"userInputCode":    device.codes[len(device.codes)-1].userInputCode,
+			})
+			assert.Equal(t, "RESTART_FLOW_ERROR", data["status"])
+			user, err := (*recipeImplementation.GetUserByEmail)(email, &map[string]interface{}{})
+			assert.NoError(t, err)
+			assert.Nil(t, user)
+		}
+	}
+
+	// the code is only sent to the existing user, on create and on resend
+	assert.Equal(t, []string{existingEmail, existingEmail}, sentEmails)
+}
+
+func TestRefusedSignUpsCanBeRevealed(t *testing.T) {
+	sentEmails := []string{}
+	config := makeSignUpPolicyConfigForTest(t, true, &sentEmails)
+
+	_, data, err := callAPIForTest(t, api.CreateCode, api.MakeAPIImplementation(), config, makeTestCore().makeRecipeImplementation(), testAPIRequest{
+		body: map[string]interface{}{"email": "janedoe@gmail.com"},
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	assert.Equal(t, "SIGN_UP_NOT_ALLOWED_ERROR", data["status"])
+	assert.Empty(t, sentEmails)
+}
+
+func TestExistsAPIsAreDisabledWhenRefusedSignUpsAreHidden(t *testing.T) {
+	shouldAllowSignUp := func(email *string, phoneNumber *string, userContext supertokens.UserContext) (bool, error) {
+		return false, nil
+	}
+	testCases := []struct {
+		shouldAllowSignUp      func(email *string, phoneNumber *string, userContext supertokens.UserContext) (bool, error)
+		revealSignUpNotAllowed bool
+		disabled               bool
+	}{
+		{shouldAllowSignUp: nil, revealSignUpNotAllowed: false, disabled: false},
+		{shouldAllowSignUp: shouldAllowSignUp, revealSignUpNotAllowed: true, disabled: false},
+		{shouldAllowSignUp: shouldAllowSignUp, revealSignUpNotAllowed: false, disabled: true},
+	}
+
+	for _, testCase := range testCases {
+		config, err := validateAndNormaliseUserInput(getTestAppInfo(t), plessmodels.TypeInput{
+			ContactMethodEmailOrPhone: plessmodels.NewContactMethodEmailOrPhoneConfig(sendTestEmail, sendTestTextMessage),
+			FlowType:                  plessmodels.FlowTypeUserInputCode,
+			ShouldAllowSignUp:         testCase.shouldAllowSignUp,
+			RevealSignUpNotAllowed:    testCase.revealSignUpNotAllowed,
+		})
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		recipe := Recipe{
+			Config:  config,
+			APIImpl: api.MakeAPIImplementation(),
+		}
+		apisHandled, err := recipe.getAPIsHandled()
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		for _, apiHandled := range apisHandled {
+			if apiHandled.ID == doesEmailExistAPI || apiHandled.ID == doesPhoneNumberExistAPI {
+				assert.Equal(t, testCase.disabled, apiHandled.Disabled, apiHandled.ID)
+			}
+		}
+	}
+}
@@ -0,0 +1,405 @@
+/*
+ * Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package passwordless
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/supertokens/supertokens-golang/recipe/passwordless/plessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+type testDevice struct {
+	deviceID         string
+	preAuthSessionID string
+	email            *string
+	phoneNumber      *string
+	failedAttempts   int
+	codes            []testCode
+}
+
+type testCode struct {
+	codeID        string
+	userInputCode string
+	linkCode      string
+	timeCreated   uint64
+	codeLifetime  uint64
+}
+
+// testCore keeps the codes and users of the recipe functions that call the core, so that the
+// APIs can be tested without it
+type testCore struct {
+	lock    sync.Mutex
+	counter int
+	devices map[string]*testDevice
+	users   []plessmodels.User
+}
+
+func makeTestCore() *testCore {
+	return &testCore{
+		devices: map[string]*testDevice{},
+	}
+}
+
+func (c *testCore) nextID(prefix string) string {
+	c.counter++
+	return prefix + "-" + strconv.Itoa(c.counter)
+}
+
+func (c *testCore) newCode(device *testDevice, userInputCode *string) plessmodels.NewCode {
+	code := testCode{
+		codeID:        c.nextID("code"),
+		userInputCode: strconv.Itoa(100000 + c.counter),
+		linkCode:      c.nextID("link-code"),
+		timeCreated:   uint64(time.Now().UnixNano() / 1000000),
+		codeLifetime:  15 * 60 * 1000,
+	}
+	if userInputCode != nil {
+		code.userInputCode = *userInputCode
+	}
+	device.codes = append(device.codes, code)
+	return plessmodels.NewCode{
+		PreAuthSessionID: device.preAuthSessionID,
+		CodeID:           code.codeID,
+		DeviceID:         device.deviceID,
+		UserInputCode:    code.userInputCode,
+		LinkCode:         code.linkCode,
+		CodeLifetime:     code.codeLifetime,
+		TimeCreated:      code.timeCreated,
+	}
+}
+
+func (c *testCore) toDeviceType(device *testDevice) *plessmodels.DeviceType {
+	codes := []plessmodels.Code{}
+	for _, code := range device.codes {
+		codes = append(codes, plessmodels.Code{
+			CodeID:       code.codeID,
+			TimeCreated:  code.timeCreated,
+			CodeLifetime: code.codeLifetime,
+		})
+	}
+	return &plessmodels.DeviceType{
+		PreAuthSessionID:            device.preAuthSessionID,
+		FailedCodeInputAttemptCount: device.failedAttempts,
+		Email:                       device.email,
+		PhoneNumber:                 device.phoneNumber,
+		Codes:                       codes,
+	}
+}
+
+// ageCodes moves the creation time of the codes of the device back by age milliseconds
+func (c *testCore) ageCodes(deviceID string, age uint64) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for i := range c.devices[deviceID].codes {
+		c.devices[deviceID].codes[i].timeCreated -= age
+	}
+}
+
+func (c *testCore) getUser(email *string, phoneNumber *string) *plessmodels.User {
+	for _, user := range c.users {
+		if (email != nil && user.Email != nil && *user.Email == *email) || (phoneNumber != nil && user.PhoneNumber != nil && *user.PhoneNumber == *phoneNumber) {
+			userCopy := user
+			return &userCopy
+		}
+	}
+	return nil
+}
+
+func (c *testCore) addUser(email *string, phoneNumber *string) plessmodels.User {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	user := plessmodels.User{
+		ID:          c.nextID("user"),
+		Email:       email,
+		PhoneNumber: phoneNumber,
+		TimeJoined:  uint64(time.Now().UnixNano() / 1000000),
+	}
+	c.users = append(c.users, user)
+	return user
+}
+
+func (c *testCore) makeRecipeImplementation() plessmodels.RecipeInterface {
+	createCode := func(email *string, phoneNumber *string, userInputCode *string, userContext supertokens.UserContext) (plessmodels.CreateCodeResponse, error) {
+		c.lock.Lock()
+		defer c.lock.Unlock()
+		device := &testDevice{
+			deviceID:         c.nextID("device"),
+			preAuthSessionID: c.nextID("pre-auth-session"),
+			email:            email,
+			phoneNumber:      phoneNumber,
+		}
+		c.devices[device.deviceID] = device
+		newCode := c.newCode(device, userInputCode)
+		return plessmodels.CreateCodeResponse{
+			OK: &newCode,
+		}, nil
+	}
+
+	createNewCodeForDevice := func(deviceID string, userInputCode *string, userContext supertokens.UserContext) (plessmodels.ResendCodeResponse, error) {
+		c.lock.Lock()
+		defer c.lock.Unlock()
+		device, ok := c.devices[deviceID]
+		if !ok {
+			return plessmodels.ResendCodeResponse{
+				RestartFlowError: &struct{}{},
+			}, nil
+		}
+		newCode := c.newCode(device, userInputCode)
+		return plessmodels.ResendCodeResponse{
+			OK: &newCode,
+		}, nil
+	}
+
+	consumeCode := func(userInput *plessmodels.UserInputCodeWithDeviceID, linkCode *string, preAuthSessionID string, userContext supertokens.UserContext) (plessmodels.ConsumeCodeResponse, error) {
+		c.lock.Lock()
+		var consumedDevice *testDevice
+		for _, device := range c.devices {
+			if device.preAuthSessionID != preAuthSessionID || (userInput != nil && device.deviceID != userInput.DeviceID) {
+				continue
+			}
+			for _, code := range device.codes {
+				if (linkCode != nil && code.linkCode == *linkCode) || (userInput != nil && code.userInputCode == userInput.Code) {
+					consumedDevice = device
+				}
+			}
+			if consumedDevice == nil && userInput != nil {
+				device.failedAttempts++
+				failedAttempts := device.failedAttempts
+				if failedAttempts >= 5 {
+					delete(c.devices, device.deviceID)
+					c.lock.Unlock()
+					return plessmodels.ConsumeCodeResponse{
+						RestartFlowError: &struct{}{},
+					}, nil
+				}
+				c.lock.Unlock()
+				return plessmodels.ConsumeCodeResponse{
+					IncorrectUserInputCodeError: &struct {
+						FailedCodeInputAttemptCount int
+						MaximumCodeInputAttempts    int
+					}{
+						FailedCodeInputAttemptCount: failedAttempts,
+						MaximumCodeInputAttempts:    5,
+					},
+				}, nil
+			}
+		}
+		if consumedDevice == nil {
+			c.lock.Unlock()
+			return plessmodels.ConsumeCodeResponse{
+				RestartFlowError: &struct{}{},
+			}, nil
+		}
+		delete(c.devices, consumedDevice.deviceID)
+		user := c.getUser(consumedDevice.email, consumedDevice.phoneNumber)
+		c.lock.Unlock()
+
+		createdNewUser := user == nil
+		if createdNewUser {
+			newUser := c.addUser(consumedDevice.email, consumedDevice.phoneNumber)
+			user = &newUser
+		}
+		return plessmodels.ConsumeCodeResponse{
+			OK: &struct {
+				CreatedNewUser bool
+				User           plessmodels.User
+			}{
+				CreatedNewUser: createdNewUser,
+				User:           *user,
+			},
+		}, nil
+	}
+
+	getUserByID := func(userID string, userContext supertokens.UserContext) (*plessmodels.User, error) {
+		c.lock.Lock()
+		defer c.lock.Unlock()
+		for _, user := range c.users {
+			if user.ID == userID {
+				userCopy := user
+				return &userCopy, nil
+			}
+		}
+		return nil, nil
+	}
+
+	getUserByEmail := func(email string, userContext supertokens.UserContext) (*plessmodels.User, error) {
+		c.lock.Lock()
+		defer c.lock.Unlock()
+		return c.getUser(&email, nil), nil
+	}
+
+	getUserByPhoneNumber := func(phoneNumber string, userContext supertokens.UserContext) (*plessmodels.User, error) {
+		c.lock.Lock()
+		defer c.lock.Unlock()
+		return c.getUser(nil, &phoneNumber), nil
+	}
+
+	updateUser := func(userID string, email *string, phoneNumber *string, userContext supertokens.UserContext) (plessmodels.UpdateUserResponse, error) {
+		c.lock.Lock()
+		defer c.lock.Unlock()
+		for i, user := range c.users {
+			if user.ID != userID {
+				continue
+			}
+			if email != nil {
+				if existingUser := c.getUser(email, nil); existingUser != nil && existingUser.ID != userID {
+					return plessmodels.UpdateUserResponse{
+						EmailAlreadyExistsError: &struct{}{},
+					}, nil
+				}
+				c.users[i].Email = email
+			}
+			if phoneNumber != nil {
+				if existingUser := c.getUser(nil, phoneNumber); existingUser != nil && existingUser.ID != userID {
+					return plessmodels.UpdateUserResponse{
+						PhoneNumberAlreadyExistsError: &struct{}{},
+					}, nil
+				}
+				c.users[i].PhoneNumber = phoneNumber
+			}
+			return plessmodels.UpdateUserResponse{
+				OK: &struct{}{},
+			}, nil
+		}
+		return plessmodels.UpdateUserResponse{
+			UnknownUserIdError: &struct{}{},
+		}, nil
+	}
+
+	revokeAllCodes := func(email *string, phoneNumber *string, userContext supertokens.UserContext) error {
+		c.lock.Lock()
+		defer c.lock.Unlock()
+		for deviceID, device := range c.devices {
+			if (email != nil && device.email != nil && *device.email == *email) || (phoneNumber != nil && device.phoneNumber != nil && *device.phoneNumber == *phoneNumber) {
+				delete(c.devices, deviceID)
+			}
+		}
+		return nil
+	}
+
+	listCodesByPhoneNumber := func(phoneNumber string, userContext supertokens.UserContext) ([]plessmodels.DeviceType, error) {
+		c.lock.Lock()
+		defer c.lock.Unlock()
+		devices := []plessmodels.DeviceType{}
+		for _, device := range c.devices {
+			if device.phoneNumber != nil && *device.phoneNumber == phoneNumber {
+				devices = append(devices, *c.toDeviceType(device))
+			}
+		}
+		return devices, nil
+	}
+
+	listCodesByDeviceID := func(deviceID string, userContext supertokens.UserContext) (*plessmodels.DeviceType, error) {
+		c.lock.Lock()
+		defer c.lock.Unlock()
+		device, ok := c.devices[deviceID]
+		if !ok {
+			return nil, nil
+		}
+		return c.toDeviceType(device), nil
+	}
+
+	listCodesByPreAuthSessionID := func(preAuthSessionID string, userContext supertokens.UserContext) (*plessmodels.DeviceType, error) {
+		c.lock.Lock()
+		defer c.lock.Unlock()
+		for _, device := range c.devices {
+			if device.preAuthSessionID == preAuthSessionID {
+				return c.toDeviceType(device), nil
+			}
+		}
+		return nil, nil
+	}
+
+	return plessmodels.RecipeInterface{
+		CreateCode:                  &createCode,
+		CreateNewCodeForDevice:      &createNewCodeForDevice,
+		ConsumeCode:                 &consumeCode,
+		GetUserByID:                 &getUserByID,
+		GetUserByEmail:              &getUserByEmail,
+		GetUserByPhoneNumber:        &getUserByPhoneNumber,
+		UpdateUser:                  &updateUser,
+		RevokeAllCodes:              &revokeAllCodes,
+		ListCodesByPhoneNumber:      &listCodesByPhoneNumber,
+		ListCodesByDeviceID:         &listCodesByDeviceID,
+		ListCodesByPreAuthSessionID: &listCodesByPreAuthSessionID,
+	}
+}
+
+type testAPIRequest struct {
+	method  string
+	body    map[string]interface{}
+	query   string
+	cookies []*http.Cookie
+	// appInfo defaults to getTestAppInfo
+	appInfo *supertokens.NormalisedAppinfo
+}
+
+// callAPIForTest calls an API handler of the recipe with the request and returns the response and its body
+func callAPIForTest(t *testing.T, handler func(apiImplementation plessmodels.APIInterface, options plessmodels.APIOptions) error, apiImplementation plessmodels.APIInterface, config plessmodels.TypeNormalisedInput, recipeImplementation plessmodels.RecipeInterface, request testAPIRequest) (*http.Response, map[string]interface{}, error) {
+	method := request.method
+	if method == "" {
+		method = http.MethodPost
+	}
+	var bodyBytes []byte
+	if request.body != nil {
+		var err error
+		bodyBytes, err = json.Marshal(request.body)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+	req := httptest.NewRequest(method, "/auth/test?"+request.query, bytes.NewReader(bodyBytes))
+	for _, cookie := range request.cookies {
+		req.AddCookie(cookie)
+	}
+	res := httptest.NewRecorder()
+	appInfo := getTestAppInfo(t)
+	if request.appInfo != nil {
+		appInfo = *request.appInfo
+	}
+
+	err := handler(apiImplementation, plessmodels.APIOptions{
+		RecipeImplementation: recipeImplementation,
+		Config:               config,
+		RecipeID:             RECIPE_ID,
+		Req:                  req,
+		Res:                  res,
+		OtherHandler:         func(w http.ResponseWriter, r *http.Request) {},
+		AppInfo:              appInfo,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	response := res.Result()
+	var data map[string]interface{}
+	if res.Body.Len() != 0 {
+		err = json.Unmarshal(res.Body.Bytes(), &data)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+	return response, data, nil
+}
@@ -16,6 +16,7 @@
 package passwordless
 
 import (
+	"errors"
 	"reflect"
 	"regexp"
 
@@ -24,36 +25,25 @@ import (
 	"github.com/supertokens/supertokens-golang/supertokens"
 )
 
-func validateAndNormaliseUserInput(appInfo supertokens.NormalisedAppinfo, config plessmodels.TypeInput) plessmodels.TypeNormalisedInput {
-
-	if config.FlowType != "USER_INPUT_CODE" && config.FlowType != "MAGIC_LINK" && config.FlowType != "USER_INPUT_CODE_AND_MAGIC_LINK" {
-		panic("FlowType config must be provided and must be one of \"USER_INPUT_CODE\", \"MAGIC_LINK\" or \"USER_INPUT_CODE_AND_MAGIC_LINK\"")
-	}
-
-	contactMethodEnabledCounter := 0
-
-	if config.ContactMethodEmail.Enabled {
-		contactMethodEnabledCounter++
-	}
-
-	if config.ContactMethodPhone.Enabled {
-		contactMethodEnabledCounter++
+func validateAndNormaliseUserInput(appInfo supertokens.NormalisedAppinfo, config plessmodels.TypeInput) (plessmodels.TypeNormalisedInput, error) {
+	err := validateFlowTypeConfig(config)
+	if err != nil {
+		return plessmodels.TypeNormalisedInput{}, err
 	}
 
-	if config.ContactMethodEmailOrPhone.Enabled {
-		contactMethodEnabledCounter++
+	err = validateContactMethodConfig(config)
+	if err != nil {
+		return plessmodels.TypeNormalisedInput{}, err
 	}
 
-	if contactMethodEnabledCounter != 1 {
-		panic("Please enable only one of ContactMethodEmail, ContactMethodPhone or ContactMethodEmailOrPhone")
+	phoneNumberPolicy, err := validateAndNormalisePhoneNumberPolicyConfig(config.PhoneNumberPolicy)
+	if err != nil {
+		return plessmodels.TypeNormalisedInput{}, err
 	}
 
-	typeNormalisedInput := makeTypeNormalisedInput(appInfo, config)
+	typeNormalisedInput := makeTypeNormalisedInput(appInfo, config, phoneNumberPolicy)
 
 	if config.ContactMethodPhone.Enabled {
-		if config.ContactMethodPhone.CreateAndSendCustomTextMessage == nil {
-			panic("Please pass a function (ContactMethodPhone.CreateAndSendCustomTextMessage) to send text messages.")
-		}
 		typeNormalisedInput.ContactMethodPhone.Enabled = true
 		if config.ContactMethodPhone.CreateAndSendCustomTextMessage != nil {
 			typeNormalisedInput.ContactMethodPhone.CreateAndSendCustomTextMessage = config.ContactMethodPhone.CreateAndSendCustomTextMessage
@@ -64,9 +54,6 @@ func validateAndNormaliseUserInput(appInfo supertokens.NormalisedAppinfo, config
 	}
 
 	if config.ContactMethodEmail.Enabled {
-		if config.ContactMethodEmail.CreateAndSendCustomEmail == nil {
-			panic("Please pass a function (ContactMethodEmail.CreateAndSendCustomEmail) to send emails.")
-		}
 		typeNormalisedInput.ContactMethodEmail.Enabled = true
 		if config.ContactMethodEmail.CreateAndSendCustomEmail != nil {
 			typeNormalisedInput.ContactMethodEmail.CreateAndSendCustomEmail = config.ContactMethodEmail.CreateAndSendCustomEmail
@@ -77,12 +64,6 @@ func validateAndNormaliseUserInput(appInfo supertokens.NormalisedAppinfo, config
 	}
 
 	if config.ContactMethodEmailOrPhone.Enabled {
-		if config.ContactMethodEmailOrPhone.CreateAndSendCustomTextMessage == nil {
-			panic("Please pass a function (ContactMethodEmailOrPhone.CreateAndSendCustomTextMessage) to send text messages.")
-		}
-		if config.ContactMethodEmailOrPhone.CreateAndSendCustomEmail == nil {
-			panic("Please pass a function (ContactMethodEmailOrPhone.CreateAndSendCustomEmail) to send emails.")
-		}
 		typeNormalisedInput.ContactMethodEmailOrPhone.Enabled = true
 		if config.ContactMethodEmailOrPhone.CreateAndSendCustomEmail != nil {
 			typeNormalisedInput.ContactMethodEmailOrPhone.CreateAndSendCustomEmail = config.ContactMethodEmailOrPhone.CreateAndSendCustomEmail
@@ -100,12 +81,48 @@ func validateAndNormaliseUserInput(appInfo supertokens.NormalisedAppinfo, config
 
 	// FlowType is initialized correctly in makeTypeNormalisedInput
 
+	typeNormalisedInput.GetFlowType = config.GetFlowType
+	typeNormalisedInput.AllowedFlowTypes = map[plessmodels.FlowType]bool{
+		config.FlowType: true,
+	}
+	if config.GetFlowType != nil {
+		allowedFlowTypes := config.AllowedFlowTypes
+		if len(allowedFlowTypes) == 0 {
+			allowedFlowTypes = []plessmodels.FlowType{plessmodels.FlowTypeUserInputCode, plessmodels.FlowTypeMagicLink, plessmodels.FlowTypeUserInputCodeAndMagicLink}
+		}
+		typeNormalisedInput.AllowedFlowTypes = map[plessmodels.FlowType]bool{}
+		for _, flowType := range allowedFlowTypes {
+			typeNormalisedInput.AllowedFlowTypes[flowType] = true
+		}
+		if config.FlowTypeStore == nil {
+			return plessmodels.TypeNormalisedInput{}, errors.New("please provide a FlowTypeStore in the config when GetFlowType is set")
+		}
+		typeNormalisedInput.FlowTypeStore = config.FlowTypeStore
+	}
+
 	if config.GetLinkDomainAndPath != nil {
 		typeNormalisedInput.GetLinkDomainAndPath = config.GetLinkDomainAndPath
 	}
 
 	// GetCustomUserInputCode is initialized correctly in makeTypeNormalisedInput
 
+	typeNormalisedInput.ShouldAllowSignUp = config.ShouldAllowSignUp
+	typeNormalisedInput.RevealSignUpNotAllowed = config.RevealSignUpNotAllowed
+
+	typeNormalisedInput.RateLimit, err = validateAndNormaliseRateLimitConfig(config.RateLimit)
+	if err != nil {
+		return plessmodels.TypeNormalisedInput{}, err
+	}
+	typeNormalisedInput.CrossDeviceProtection, err = validateAndNormaliseCrossDeviceProtectionConfig(config.CrossDeviceProtection, typeNormalisedInput.AllowedFlowTypes)
+	if err != nil {
+		return plessmodels.TypeNormalisedInput{}, err
+	}
+	typeNormalisedInput.SecondFactor, err = validateAndNormaliseSecondFactorConfig(config.SecondFactor)
+	if err != nil {
+		return plessmodels.TypeNormalisedInput{}, err
+	}
+	// PhoneNumberPolicy is initialized in makeTypeNormalisedInput since the default phone number validation uses it
+
 	if config.Override != nil {
 		if config.Override.Functions != nil {
 			typeNormalisedInput.Override.Functions = config.Override.Functions
@@ -114,12 +131,58 @@ func validateAndNormaliseUserInput(appInfo supertokens.NormalisedAppinfo, config
 			typeNormalisedInput.Override.APIs = config.Override.APIs
 		}
 	}
-	return typeNormalisedInput
+	return typeNormalisedInput, nil
 }
 
-func makeTypeNormalisedInput(appInfo supertokens.NormalisedAppinfo, inputConfig plessmodels.TypeInput) plessmodels.TypeNormalisedInput {
+func validateFlowTypeConfig(config plessmodels.TypeInput) error {
+	if !config.FlowType.IsValid() {
+		return errors.New("FlowType config must be provided and must be one of \"USER_INPUT_CODE\", \"MAGIC_LINK\" or \"USER_INPUT_CODE_AND_MAGIC_LINK\"")
+	}
+	for _, flowType := range config.AllowedFlowTypes {
+		if !flowType.IsValid() {
+			return errors.New("AllowedFlowTypes must only contain \"USER_INPUT_CODE\", \"MAGIC_LINK\" or \"USER_INPUT_CODE_AND_MAGIC_LINK\"")
+		}
+	}
+	return nil
+}
+
+func validateContactMethodConfig(config plessmodels.TypeInput) error {
+	contactMethodEnabledCounter := 0
+	if config.ContactMethodEmail.Enabled {
+		contactMethodEnabledCounter++
+	}
+	if config.ContactMethodPhone.Enabled {
+		contactMethodEnabledCounter++
+	}
+	if config.ContactMethodEmailOrPhone.Enabled {
+		contactMethodEnabledCounter++
+	}
+	if contactMethodEnabledCounter != 1 {
+		return errors.New("Please enable only one of ContactMethodEmail, ContactMethodPhone or ContactMethodEmailOrPhone")
+	}
+
+	if config.ContactMethodPhone.Enabled && config.ContactMethodPhone.CreateAndSendCustomTextMessage == nil {
+		return errors.New("Please pass a function (ContactMethodPhone.CreateAndSendCustomTextMessage) to send text messages.")
+	}
+	if config.ContactMethodEmail.Enabled && config.ContactMethodEmail.CreateAndSendCustomEmail == nil {
+		return errors.New("Please pass a function (ContactMethodEmail.CreateAndSendCustomEmail) to send emails.")
+	}
+	if config.ContactMethodEmailOrPhone.Enabled {
+		if config.ContactMethodEmailOrPhone.CreateAndSendCustomTextMessage == nil {
+			return errors.New("Please pass a function (ContactMethodEmailOrPhone.CreateAndSendCustomTextMessage) to send text messages.")
+		}
+		if config.ContactMethodEmailOrPhone.CreateAndSendCustomEmail == nil {
+			return errors.New("Please pass a function (ContactMethodEmailOrPhone.CreateAndSendCustomEmail) to send emails.")
+		}
+	}
+	return nil
+}
+
+func makeTypeNormalisedInput(appInfo supertokens.NormalisedAppinfo, inputConfig plessmodels.TypeInput, phoneNumberPolicy plessmodels.TypeNormalisedInputPhoneNumberPolicy) plessmodels.TypeNormalisedInput {
+	defaultValidatePhoneNumber := getDefaultValidatePhoneNumber(phoneNumberPolicy.DefaultRegion)
 	return plessmodels.TypeNormalisedInput{
-		FlowType: inputConfig.FlowType,
+		FlowType:          inputConfig.FlowType,
+		PhoneNumberPolicy: phoneNumberPolicy,
 		ContactMethodEmailOrPhone: plessmodels.ContactMethodEmailOrPhoneConfig{
 			Enabled:                        false,
 			ValidateEmailAddress:           defaultValidateEmailAddress,
@@ -169,22 +232,24 @@ func defaultValidateEmailAddress(value interface{}) *string {
 	return nil
 }
 
-func defaultValidatePhoneNumber(value interface{}) *string {
-	if reflect.TypeOf(value).Kind() != reflect.String {
-		msg := "Development bug: Please make sure the email field yields a string"
-		return &msg
-	}
+func getDefaultValidatePhoneNumber(defaultRegion string) func(value interface{}) *string {
+	return func(value interface{}) *string {
+		if reflect.TypeOf(value).Kind() != reflect.String {
+			msg := "Development bug: Please make sure the email field yields a string"
+			return &msg
+		}
 
-	parsedPhoneNumber, err := phonenumbers.Parse(value.(string), "")
-	if err != nil {
-		msg := "Phone number is invalid"
-		return &msg
-	}
-	if !phonenumbers.IsValidNumber(parsedPhoneNumber) {
-		msg := "Phone number is invalid"
-		return &msg
+		parsedPhoneNumber, err := phonenumbers.Parse(value.(string), defaultRegion)
+		if err != nil {
+			msg := "Phone number is invalid"
+			return &msg
+		}
+		if !phonenumbers.IsValidNumber(parsedPhoneNumber) {
+			msg := "Phone number is invalid"
+			return &msg
+		}
+		return nil
 	}
-	return nil
 }
 
 // func defaultCreateAndSendCustomEmail(email string, userInputCode *string, urlWithLinkCode *string, codeLifetime uint64, preAuthSessionId string, userContext supertokens.UserContext) {
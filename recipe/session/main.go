@@ -118,6 +118,15 @@ func VerifySession(options *sessmodels.VerifySessionOptions, otherHandler http.H
 	return VerifySessionHelper(*instance, options, otherHandler)
 }
 
+// AddVerifySessionCheck registers a function that is called by VerifySession for each valid
+// session. If it returns an error, the request is rejected using that error. The checks are not
+// run by GetSession, which recipes use in APIs that must work regardless of these checks (like
+// the email verification APIs). A recipe has at most one check, so adding a check again for the
+// same recipeID replaces the previous one.
+func AddVerifySessionCheck(recipeID string, check func(sessionContainer sessmodels.SessionContainer, req *http.Request, userContext supertokens.UserContext) error) {
+	verifySessionChecks[recipeID] = check
+}
+
 func GetSessionFromRequestContext(ctx context.Context) *sessmodels.SessionContainer {
 	value := ctx.Value(sessmodels.SessionContext)
 	if value == nil {
@@ -26,6 +26,7 @@ import (
 func VerifySessionHelper(recipeInstance Recipe, options *sessmodels.VerifySessionOptions, otherHandler http.HandlerFunc) http.HandlerFunc {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		dw := supertokens.MakeDoneWriter(w)
+		userContext := &map[string]interface{}{}
 		session, err := (*recipeInstance.APIImpl.VerifySession)(options, sessmodels.APIOptions{
 			Config:               recipeInstance.Config,
 			OtherHandler:         otherHandler,
@@ -33,7 +34,15 @@ func VerifySessionHelper(recipeInstance Recipe, options *sessmodels.VerifySessio
 			Res:                  dw,
 			RecipeID:             recipeInstance.RecipeModule.GetRecipeID(),
 			RecipeImplementation: recipeInstance.RecipeImpl,
-		}, &map[string]interface{}{})
+		}, userContext)
+		if err == nil && session != nil {
+			for _, check := range verifySessionChecks {
+				err = check(*session, r, userContext)
+				if err != nil {
+					break
+				}
+			}
+		}
 		if err != nil {
 			err = supertokens.ErrorHandler(err, r, dw)
 			if err != nil {
@@ -41,6 +41,11 @@ const RECIPE_ID = "session"
 
 var singletonInstance *Recipe
 
+// checks registered by other recipes using AddVerifySessionCheck, by the ID of the recipe that added
+// them. This is not stored in the recipe instance since other recipes may be initialised before the
+// session recipe.
+var verifySessionChecks = map[string]func(sessionContainer sessmodels.SessionContainer, req *http.Request, userContext supertokens.UserContext) error{}
+
 func MakeRecipe(recipeId string, appInfo supertokens.NormalisedAppinfo, config *sessmodels.TypeInput, onGeneralError func(err error, req *http.Request, res http.ResponseWriter)) (Recipe, error) {
 	r := &Recipe{}
 
@@ -189,4 +194,5 @@ func (r *Recipe) handleError(err error, req *http.Request, res http.ResponseWrit
 
 func ResetForTest() {
 	singletonInstance = nil
+	verifySessionChecks = map[string]func(sessionContainer sessmodels.SessionContainer, req *http.Request, userContext supertokens.UserContext) error{}
 }
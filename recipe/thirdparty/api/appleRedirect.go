@@ -17,17 +17,22 @@ package api
 
 import (
 	"github.com/supertokens/supertokens-golang/recipe/thirdparty/tpmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
 )
 
-func AppleRedirectHandler(apiImplementation tpmodels.APIInterface, options tpmodels.APIOptions) error {
+func AppleRedirectHandler(apiImplementation tpmodels.APIInterface, options tpmodels.APIOptions, userContext supertokens.UserContext) error {
 	if apiImplementation.AppleRedirectHandlerPOST == nil || (*apiImplementation.AppleRedirectHandlerPOST) == nil {
 		options.OtherHandler(options.Res, options.Req)
 		return nil
 	}
 
+	if findRightProvider(options.Providers, "apple", nil) == nil {
+		return supertokens.BadInputError{Msg: "The third party provider apple seems to be missing from the backend configs"}
+	}
+
 	options.Req.ParseMultipartForm(0)
 	state := options.Req.FormValue("state")
 	code := options.Req.FormValue("code")
 
-	return (*apiImplementation.AppleRedirectHandlerPOST)(code, state, options, &map[string]interface{}{})
+	return (*apiImplementation.AppleRedirectHandlerPOST)(code, state, options, userContext)
 }
@@ -0,0 +1,53 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package api
+
+import (
+	"strings"
+
+	"github.com/supertokens/supertokens-golang/recipe/thirdparty/tpmodels"
+)
+
+// checkEmailPolicy returns the reason for which the email cannot be used to sign in with the
+// provider, or nil if it is allowed
+func checkEmailPolicy(thirdPartyID string, email tpmodels.EmailStruct, emailPolicy tpmodels.TypeNormalisedInputEmailPolicy) *string {
+	if emailPolicy.RequireVerifiedEmail && !email.IsVerified {
+		reason := "The email of your " + thirdPartyID + " account is not verified. Please verify it and try again"
+		return &reason
+	}
+
+	domain := ""
+	if at := strings.LastIndex(email.ID, "@"); at != -1 {
+		domain = strings.ToLower(email.ID[at+1:])
+	}
+
+	if emailPolicy.BlockedDomains[domain] || (len(emailPolicy.AllowedDomains) != 0 && !emailPolicy.AllowedDomains[domain]) {
+		reason := "Signing in with emails of the domain " + domain + " is not allowed"
+		return &reason
+	}
+
+	if allowedProviderIDs, ok := emailPolicy.AllowedProvidersForDomain[domain]; ok {
+		for _, providerID := range allowedProviderIDs {
+			if providerID == thirdPartyID {
+				return nil
+			}
+		}
+		reason := "Emails of the domain " + domain + " can only be used to sign in with: " + strings.Join(allowedProviderIDs, ", ")
+		return &reason
+	}
+
+	return nil
+}
@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"reflect"
 	"strings"
 
@@ -34,7 +35,19 @@ import (
 
 func MakeAPIImplementation() tpmodels.APIInterface {
 	authorisationUrlGET := func(provider tpmodels.TypeProvider, options tpmodels.APIOptions, userContext supertokens.UserContext) (tpmodels.AuthorisationUrlGETResponse, error) {
+		if provider.SAML != nil {
+			return getSAMLAuthorisationUrl(provider, options, userContext)
+		}
+
+		// providers returned by GetProviders are not checked when the recipe is initialised
+		if provider.RequiresPKCE && (options.Config.SignInAndUpFeature.StateAndPKCE == nil || options.Config.SignInAndUpFeature.StateAndPKCE.DisablePKCE) {
+			return tpmodels.AuthorisationUrlGETResponse{}, errors.New("The third party provider " + provider.ID + " requires PKCE. Please set StateAndPKCE in the SignInAndUpFeature config without DisablePKCE")
+		}
+
 		providerInfo := provider.Get(nil, nil, userContext)
+		if providerInfo.Error != nil {
+			return tpmodels.AuthorisationUrlGETResponse{}, providerInfo.Error
+		}
 		params := map[string]string{}
 		for key, value := range providerInfo.AuthorisationRedirect.Params {
 			if reflect.ValueOf(value).Kind() == reflect.String {
@@ -64,6 +77,18 @@ func MakeAPIImplementation() tpmodels.APIInterface {
 			params["redirect_uri"] = rU
 		}
 
+		if options.Config.SignInAndUpFeature.StateAndPKCE != nil {
+			stateParams, err := createOAuthState(provider, providerInfo.RequiresNonce, *options.Config.SignInAndUpFeature.StateAndPKCE, options, userContext)
+			if err != nil {
+				return tpmodels.AuthorisationUrlGETResponse{}, err
+			}
+			for key, value := range stateParams {
+				params[key] = value
+			}
+		} else if providerInfo.RequiresNonce {
+			return tpmodels.AuthorisationUrlGETResponse{}, errors.New("The third party provider " + provider.ID + " requires a nonce. Please enable StateAndPKCE in the SignInAndUpFeature config")
+		}
+
 		if isUsingDevelopmentClientId(providerInfo.GetClientId(userContext)) {
 			params["actual_redirect_uri"] = providerInfo.AuthorisationRedirect.URL
 
@@ -95,6 +120,9 @@ func MakeAPIImplementation() tpmodels.APIInterface {
 	signInUpPOST := func(provider tpmodels.TypeProvider, code string, authCodeResponse interface{}, redirectURI string, options tpmodels.APIOptions, userContext supertokens.UserContext) (tpmodels.SignInUpPOSTResponse, error) {
 		{
 			providerInfo := provider.Get(nil, nil, userContext)
+			if providerInfo.Error != nil {
+				return tpmodels.SignInUpPOSTResponse{}, providerInfo.Error
+			}
 			if isUsingDevelopmentClientId(providerInfo.GetClientId(userContext)) {
 				redirectURI = DevOauthRedirectUrl
 			} else if providerInfo.GetRedirectURI != nil {
@@ -113,8 +141,33 @@ func MakeAPIImplementation() tpmodels.APIInterface {
 		var accessTokenAPIResponse map[string]interface{} = nil
 
 		if authCodeResponse != nil && len(authCodeResponse.(map[string]interface{})) != 0 {
+			// the state and code verifier can only be checked if the code is exchanged by the backend
+			if options.Config.SignInAndUpFeature.StateAndPKCE != nil {
+				return tpmodels.SignInUpPOSTResponse{}, supertokens.BadInputError{Msg: "authCodeResponse cannot be used when StateAndPKCE is enabled. Please send the code instead"}
+			}
 			accessTokenAPIResponse = authCodeResponse.(map[string]interface{})
 		} else {
+			if options.Config.SignInAndUpFeature.StateAndPKCE != nil {
+				stateInfo, err := consumeOAuthState(provider.ID, options.State, *options.Config.SignInAndUpFeature.StateAndPKCE, options, userContext)
+				if err != nil {
+					return tpmodels.SignInUpPOSTResponse{}, err
+				}
+				if stateInfo == nil {
+					return tpmodels.SignInUpPOSTResponse{
+						InvalidStateError: &struct{}{},
+					}, nil
+				}
+				if stateInfo.Nonce != "" {
+					(*userContext)[OIDCNonceUserContextKey] = stateInfo.Nonce
+				}
+				if stateInfo.CodeVerifier != "" {
+					if providerInfo.AccessTokenAPI.Params == nil {
+						providerInfo.AccessTokenAPI.Params = map[string]string{}
+					}
+					providerInfo.AccessTokenAPI.Params["code_verifier"] = stateInfo.CodeVerifier
+				}
+			}
+
 			if isUsingDevelopmentClientId(providerInfo.GetClientId(userContext)) {
 
 				for key, value := range providerInfo.AccessTokenAPI.Params {
@@ -124,11 +177,16 @@ func MakeAPIImplementation() tpmodels.APIInterface {
 				}
 			}
 
-			accessTokenAPIResponseTemp, err := postRequest(providerInfo, userContext)
-			if err != nil {
-				return tpmodels.SignInUpPOSTResponse{}, err
+			if providerInfo.AccessTokenAPI.URL == "" {
+				// providers like SAML have no token endpoint and use the code in GetProfileInfo
+				accessTokenAPIResponse = map[string]interface{}{}
+			} else {
+				accessTokenAPIResponseTemp, err := postRequest(providerInfo, userContext)
+				if err != nil {
+					return tpmodels.SignInUpPOSTResponse{}, err
+				}
+				accessTokenAPIResponse = accessTokenAPIResponseTemp
 			}
-			accessTokenAPIResponse = accessTokenAPIResponseTemp
 		}
 
 		userInfo, err := providerInfo.GetProfileInfo(accessTokenAPIResponse, userContext)
@@ -141,74 +199,211 @@ func MakeAPIImplementation() tpmodels.APIInterface {
 			}, nil
 		}
 
-		emailInfo := userInfo.Email
-		if emailInfo == nil {
-			return tpmodels.SignInUpPOSTResponse{
-				NoEmailGivenByProviderError: &struct{}{},
-			}, nil
+		return signInUpWithUserInfo(provider, providerInfo.GetClientId(userContext), userInfo, accessTokenAPIResponse, options, userContext)
+	}
+
+	nativeSignInUpPOST := func(provider tpmodels.TypeProvider, tokens tpmodels.NativeTokens, options tpmodels.APIOptions, userContext supertokens.UserContext) (tpmodels.SignInUpPOSTResponse, error) {
+		providerInfo := provider.Get(nil, nil, userContext)
+		if providerInfo.Error != nil {
+			return tpmodels.SignInUpPOSTResponse{}, providerInfo.Error
+		}
+		if providerInfo.GetProfileInfoFromNativeTokens == nil {
+			return tpmodels.SignInUpPOSTResponse{}, supertokens.BadInputError{Msg: "The third party provider " + provider.ID + " does not support signing in with an id token or access token"}
 		}
 
-		response, err := (*options.RecipeImplementation.SignInUp)(provider.ID, userInfo.ID, *emailInfo, userContext)
+		userInfo, err := providerInfo.GetProfileInfoFromNativeTokens(tokens, userContext)
 		if err != nil {
-			return tpmodels.SignInUpPOSTResponse{}, err
-		}
-		if response.FieldError != nil {
+			errMsg := err.Error()
 			return tpmodels.SignInUpPOSTResponse{
 				FieldError: &struct{ ErrorMsg string }{
-					ErrorMsg: response.FieldError.ErrorMsg,
+					ErrorMsg: errMsg,
 				},
 			}, nil
 		}
 
-		if emailInfo.IsVerified {
-			tokenResponse, err := (*options.EmailVerificationRecipeImplementation.CreateEmailVerificationToken)(response.OK.User.ID, response.OK.User.Email, userContext)
+		// the tokens are saved like the response of the token endpoint, so that the token vault can use them
+		accessTokenAPIResponse := map[string]interface{}{}
+		if tokens.IDToken != nil {
+			accessTokenAPIResponse["id_token"] = *tokens.IDToken
+		}
+		if tokens.AccessToken != nil {
+			accessTokenAPIResponse["access_token"] = *tokens.AccessToken
+		}
+		return signInUpWithUserInfo(provider, providerInfo.GetClientId(userContext), userInfo, accessTokenAPIResponse, options, userContext)
+	}
+
+	appleRedirectHandlerPOST := func(code string, state string, options tpmodels.APIOptions, userContext supertokens.UserContext) error {
+		if options.Config.SignInAndUpFeature.StateAndPKCE != nil {
+			// Apple posts to this API from another site, so the state cookie is only checked in SignInUpPOST.
+			// The code is not forwarded to the frontend for states that we did not create.
+			stateInfo, err := options.Config.SignInAndUpFeature.StateAndPKCE.StateStore.Get(state, userContext)
 			if err != nil {
-				return tpmodels.SignInUpPOSTResponse{}, err
+				return err
 			}
-			if tokenResponse.OK != nil {
-				_, err := (*options.EmailVerificationRecipeImplementation.VerifyEmailUsingToken)(tokenResponse.OK.Token, userContext)
-				if err != nil {
-					return tpmodels.SignInUpPOSTResponse{}, err
-				}
+			if stateInfo == nil {
+				code = ""
 			}
 		}
 
-		session, err := session.CreateNewSessionWithContext(options.Res, response.OK.User.ID, nil, nil, userContext)
+		redirectURL := options.AppInfo.WebsiteDomain.GetAsStringDangerous() +
+			options.AppInfo.WebsiteBasePath.GetAsStringDangerous() + "/callback/apple?state=" + url.QueryEscape(state) + "&code=" + url.QueryEscape(code)
+
+		options.Res.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+		fmt.Fprint(options.Res, "<html><head><script>window.location.replace(\""+redirectURL+"\");</script></head></html>")
+		return nil
+	}
+
+	samlLoginGET := func(provider tpmodels.TypeProvider, relayState string, options tpmodels.APIOptions, userContext supertokens.UserContext) (tpmodels.SAMLLoginGETResponse, error) {
+		redirectURL, err := provider.SAML.GetLoginRedirectURL(getSAMLACSURL(provider, options), relayState, userContext)
 		if err != nil {
-			return tpmodels.SignInUpPOSTResponse{}, err
+			return tpmodels.SAMLLoginGETResponse{}, err
 		}
-		return tpmodels.SignInUpPOSTResponse{
-			OK: &struct {
-				CreatedNewUser   bool
-				User             tpmodels.User
-				Session          sessmodels.SessionContainer
-				AuthCodeResponse interface{}
-			}{
-				CreatedNewUser:   response.OK.CreatedNewUser,
-				User:             response.OK.User,
-				Session:          session,
-				AuthCodeResponse: accessTokenAPIResponse,
+		return tpmodels.SAMLLoginGETResponse{
+			OK: &struct{ RedirectURL string }{
+				RedirectURL: redirectURL,
 			},
 		}, nil
 	}
 
-	appleRedirectHandlerPOST := func(code string, state string, options tpmodels.APIOptions, userContext supertokens.UserContext) error {
-		redirectURL := options.AppInfo.WebsiteDomain.GetAsStringDangerous() +
-			options.AppInfo.WebsiteBasePath.GetAsStringDangerous() + "/callback/apple?state=" + state + "&code=" + code
+	samlACSPOST := func(provider tpmodels.TypeProvider, samlResponse string, relayState string, options tpmodels.APIOptions, userContext supertokens.UserContext) error {
+		response, err := provider.SAML.ConsumeSAMLResponse(getSAMLACSURL(provider, options), samlResponse, userContext)
+		if err != nil {
+			return err
+		}
 
-		options.Res.Header().Set("Content-Type", "text/html; charset=utf-8")
+		redirectURL := options.AppInfo.WebsiteDomain.GetAsStringDangerous() +
+			options.AppInfo.WebsiteBasePath.GetAsStringDangerous() + "/callback/" + url.PathEscape(provider.ID) + "?state=" + url.QueryEscape(relayState)
+		if response.OK != nil {
+			redirectURL += "&code=" + url.QueryEscape(response.OK.Code)
+		} else {
+			supertokens.LogDebugMessage("SAMLACSPOST: " + response.InvalidSAMLResponseError.Msg)
+			redirectURL += "&error=INVALID_SAML_RESPONSE"
+		}
 
-		fmt.Fprint(options.Res, "<html><head><script>window.location.replace(\""+redirectURL+"\");</script></head></html>")
+		http.Redirect(options.Res, options.Req, redirectURL, http.StatusSeeOther)
 		return nil
 	}
 
 	return tpmodels.APIInterface{
 		AuthorisationUrlGET:      &authorisationUrlGET,
 		SignInUpPOST:             &signInUpPOST,
+		NativeSignInUpPOST:       &nativeSignInUpPOST,
 		AppleRedirectHandlerPOST: &appleRedirectHandlerPOST,
+		SAMLLoginGET:             &samlLoginGET,
+		SAMLACSPOST:              &samlACSPOST,
 	}
 }
 
+// signInUpWithUserInfo signs in the user that the provider returned and creates their session
+func signInUpWithUserInfo(provider tpmodels.TypeProvider, clientId string, userInfo tpmodels.UserInfo, accessTokenAPIResponse map[string]interface{}, options tpmodels.APIOptions, userContext supertokens.UserContext) (tpmodels.SignInUpPOSTResponse, error) {
+	emailInfo := userInfo.Email
+	if emailInfo == nil {
+		return tpmodels.SignInUpPOSTResponse{
+			NoEmailGivenByProviderError: &struct{}{},
+		}, nil
+	}
+
+	if options.Config.SignInAndUpFeature.EmailPolicy != nil {
+		reason := checkEmailPolicy(provider.ID, *emailInfo, *options.Config.SignInAndUpFeature.EmailPolicy)
+		if reason != nil {
+			return tpmodels.SignInUpPOSTResponse{
+				FieldError: &struct{ ErrorMsg string }{
+					ErrorMsg: *reason,
+				},
+			}, nil
+		}
+	}
+
+	response, err := (*options.RecipeImplementation.SignInUp)(provider.ID, userInfo.ID, *emailInfo, userContext)
+	if err != nil {
+		return tpmodels.SignInUpPOSTResponse{}, err
+	}
+	if response.FieldError != nil {
+		return tpmodels.SignInUpPOSTResponse{
+			FieldError: &struct{ ErrorMsg string }{
+				ErrorMsg: response.FieldError.ErrorMsg,
+			},
+		}, nil
+	}
+
+	if options.Config.SignInAndUpFeature.TokenVault != nil {
+		err := saveProviderTokens(provider, clientId, response.OK.User.ID, accessTokenAPIResponse, *options.Config.SignInAndUpFeature.TokenVault, userContext)
+		if err != nil {
+			return tpmodels.SignInUpPOSTResponse{}, err
+		}
+	}
+
+	// the user may be a linked primary user with a different email than the one given by the provider
+	if emailInfo.IsVerified && response.OK.User.Email == emailInfo.ID {
+		tokenResponse, err := (*options.EmailVerificationRecipeImplementation.CreateEmailVerificationToken)(response.OK.User.ID, response.OK.User.Email, userContext)
+		if err != nil {
+			return tpmodels.SignInUpPOSTResponse{}, err
+		}
+		if tokenResponse.OK != nil {
+			_, err := (*options.EmailVerificationRecipeImplementation.VerifyEmailUsingToken)(tokenResponse.OK.Token, userContext)
+			if err != nil {
+				return tpmodels.SignInUpPOSTResponse{}, err
+			}
+		}
+	}
+
+	profile := getProfile(userInfo.RawUserInfoFromProvider, options.Config.SignInAndUpFeature.ProfileClaimMapping)
+	var accessTokenPayload map[string]interface{} = nil
+	if options.Config.SignInAndUpFeature.GetAccessTokenPayload != nil {
+		accessTokenPayload, err = options.Config.SignInAndUpFeature.GetAccessTokenPayload(response.OK.User, profile, userInfo.RawUserInfoFromProvider, userContext)
+		if err != nil {
+			return tpmodels.SignInUpPOSTResponse{}, err
+		}
+	}
+
+	session, err := session.CreateNewSessionWithContext(options.Res, response.OK.User.ID, accessTokenPayload, nil, userContext)
+	if err != nil {
+		return tpmodels.SignInUpPOSTResponse{}, err
+	}
+	return tpmodels.SignInUpPOSTResponse{
+		OK: &struct {
+			CreatedNewUser          bool
+			User                    tpmodels.User
+			Session                 sessmodels.SessionContainer
+			AuthCodeResponse        interface{}
+			Profile                 tpmodels.Profile
+			RawUserInfoFromProvider tpmodels.RawUserInfoFromProvider
+		}{
+			CreatedNewUser:          response.OK.CreatedNewUser,
+			User:                    response.OK.User,
+			Session:                 session,
+			AuthCodeResponse:        accessTokenAPIResponse,
+			Profile:                 profile,
+			RawUserInfoFromProvider: userInfo.RawUserInfoFromProvider,
+		},
+	}, nil
+}
+
+// getSAMLAuthorisationUrl returns the URL of the SAML login API, which redirects to the IdP
+func getSAMLAuthorisationUrl(provider tpmodels.TypeProvider, options tpmodels.APIOptions, userContext supertokens.UserContext) (tpmodels.AuthorisationUrlGETResponse, error) {
+	params := map[string]string{
+		"thirdPartyId": provider.ID,
+	}
+	if options.Config.SignInAndUpFeature.StateAndPKCE != nil {
+		stateParams, err := createOAuthState(provider, false, *options.Config.SignInAndUpFeature.StateAndPKCE, options, userContext)
+		if err != nil {
+			return tpmodels.AuthorisationUrlGETResponse{}, err
+		}
+		params["state"] = stateParams["state"]
+	}
+
+	paramsString, err := getParamString(params)
+	if err != nil {
+		return tpmodels.AuthorisationUrlGETResponse{}, err
+	}
+	return tpmodels.AuthorisationUrlGETResponse{
+		OK: &struct{ Url string }{
+			Url: options.AppInfo.APIDomain.GetAsStringDangerous() + options.AppInfo.APIBasePath.GetAsStringDangerous() + samlLoginAPIPath + "?" + paramsString,
+		},
+	}, nil
+}
+
 func postRequest(providerInfo tpmodels.TypeProviderGetResponse, userContext supertokens.UserContext) (map[string]interface{}, error) {
 	querystring, err := getParamString(providerInfo.AccessTokenAPI.Params)
 	if err != nil {
@@ -220,6 +415,9 @@ func postRequest(providerInfo tpmodels.TypeProviderGetResponse, userContext supe
 	}
 	req.Header.Set("content-type", "application/x-www-form-urlencoded")
 	req.Header.Set("accept", "application/json") // few providers like github don't send back json response by default
+	for key, value := range providerInfo.AccessTokenAPI.Headers {
+		req.Header.Set(key, value)
+	}
 
 	client := &http.Client{}
 	response, err := client.Do(req)
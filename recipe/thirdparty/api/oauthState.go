@@ -0,0 +1,133 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package api
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/supertokens/supertokens-golang/recipe/thirdparty/tpmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// the state is bound to the browser that started the sign in using this cookie
+const oauthStateCookieName = "sOAuthState"
+
+// OIDCNonceUserContextKey is the key of the nonce saved with the OAuth state in the user context that
+// SignInUpPOST passes to GetProfileInfo
+const OIDCNonceUserContextKey = "thirdpartyOIDCNonce"
+
+func generateRandomURLSafeString(numberOfBytes int) (string, error) {
+	randomBytes := make([]byte, numberOfBytes)
+	_, err := rand.Read(randomBytes)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(randomBytes), nil
+}
+
+// createOAuthState saves a new state (and PKCE code verifier and nonce) for the provider and returns the
+// params that need to be added to the authorisation URL
+func createOAuthState(provider tpmodels.TypeProvider, requiresNonce bool, config tpmodels.TypeNormalisedInputStateAndPKCE, options tpmodels.APIOptions, userContext supertokens.UserContext) (map[string]string, error) {
+	state, err := generateRandomURLSafeString(32)
+	if err != nil {
+		return nil, err
+	}
+	params := map[string]string{
+		"state": state,
+	}
+	stateInfo := tpmodels.OAuthStateInfo{
+		State:        state,
+		ThirdPartyID: provider.ID,
+		Expiry:       uint64(time.Now().UnixNano()/1000000) + config.StateLifetime,
+	}
+	if !config.DisablePKCE {
+		// RFC 7636 requires the verifier to be between 43 and 128 characters long
+		codeVerifier, err := generateRandomURLSafeString(32)
+		if err != nil {
+			return nil, err
+		}
+		codeChallenge := sha256.Sum256([]byte(codeVerifier))
+		stateInfo.CodeVerifier = codeVerifier
+		params["code_challenge"] = base64.RawURLEncoding.EncodeToString(codeChallenge[:])
+		params["code_challenge_method"] = "S256"
+	}
+	if requiresNonce {
+		nonce, err := generateRandomURLSafeString(32)
+		if err != nil {
+			return nil, err
+		}
+		stateInfo.Nonce = nonce
+		params["nonce"] = nonce
+	}
+
+	err = config.StateStore.Save(stateInfo, userContext)
+	if err != nil {
+		return nil, err
+	}
+	setOAuthStateCookie(options, state, stateInfo.Expiry)
+	return params, nil
+}
+
+// consumeOAuthState returns the saved state info if the state was created for this provider, is not
+// expired and belongs to the browser that sent the request, and makes sure that it can not be used
+// again. It returns nil otherwise.
+func consumeOAuthState(providerID string, state string, config tpmodels.TypeNormalisedInputStateAndPKCE, options tpmodels.APIOptions, userContext supertokens.UserContext) (*tpmodels.OAuthStateInfo, error) {
+	if state == "" {
+		return nil, nil
+	}
+	cookie, err := options.Req.Cookie(oauthStateCookieName)
+	if err != nil || subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(state)) != 1 {
+		return nil, nil
+	}
+	stateInfo, err := config.StateStore.Consume(state, userContext)
+	if err != nil {
+		return nil, err
+	}
+	setOAuthStateCookie(options, "", 0)
+	if stateInfo == nil || stateInfo.ThirdPartyID != providerID || stateInfo.Expiry <= uint64(time.Now().UnixNano()/1000000) {
+		return nil, nil
+	}
+	return stateInfo, nil
+}
+
+func setOAuthStateCookie(options tpmodels.APIOptions, value string, expiry uint64) {
+	// the sign in and the apple redirect requests can come from another site, in which case
+	// browsers only send the cookie if it uses SameSite=None, which requires https
+	secure := strings.HasPrefix(options.AppInfo.APIDomain.GetAsStringDangerous(), "https")
+	sameSite := http.SameSiteLaxMode
+	if secure {
+		sameSite = http.SameSiteNoneMode
+	}
+	path := options.AppInfo.APIBasePath.GetAsStringDangerous()
+	if path == "" {
+		path = "/"
+	}
+	http.SetCookie(options.Res, &http.Cookie{
+		Name:     oauthStateCookieName,
+		Value:    value,
+		Path:     path,
+		Expires:  time.Unix(int64(expiry/1000), 0),
+		Secure:   secure,
+		HttpOnly: true,
+		SameSite: sameSite,
+	})
+}
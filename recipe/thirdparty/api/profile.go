@@ -0,0 +1,74 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package api
+
+import (
+	"fmt"
+
+	"github.com/supertokens/supertokens-golang/recipe/thirdparty/tpmodels"
+)
+
+func getProfile(rawUserInfo tpmodels.RawUserInfoFromProvider, claimMapping tpmodels.ProfileClaimMapping) tpmodels.Profile {
+	return tpmodels.Profile{
+		Name:       getProfileClaim(rawUserInfo, claimMapping.Name),
+		GivenName:  getProfileClaim(rawUserInfo, claimMapping.GivenName),
+		FamilyName: getProfileClaim(rawUserInfo, claimMapping.FamilyName),
+		Picture:    getProfileClaim(rawUserInfo, claimMapping.Picture),
+		Locale:     getProfileClaim(rawUserInfo, claimMapping.Locale),
+		Groups:     getProfileGroups(rawUserInfo, claimMapping.Groups),
+	}
+}
+
+// findProfileClaim returns the value of the first claim that is set, looking in the id token payload first
+func findProfileClaim(rawUserInfo tpmodels.RawUserInfoFromProvider, claims []string) interface{} {
+	for _, claim := range claims {
+		if value, ok := rawUserInfo.FromIdTokenPayload[claim]; ok && value != nil && value != "" {
+			return value
+		}
+		if value, ok := rawUserInfo.FromUserInfoAPI[claim]; ok && value != nil && value != "" {
+			return value
+		}
+	}
+	return nil
+}
+
+func getProfileClaim(rawUserInfo tpmodels.RawUserInfoFromProvider, claims []string) string {
+	value := findProfileClaim(rawUserInfo, claims)
+	if value == nil {
+		return ""
+	}
+	if str, ok := value.(string); ok {
+		return str
+	}
+	return fmt.Sprint(value)
+}
+
+func getProfileGroups(rawUserInfo tpmodels.RawUserInfoFromProvider, claims []string) []string {
+	groups := []string{}
+	switch value := findProfileClaim(rawUserInfo, claims).(type) {
+	case string:
+		groups = append(groups, value)
+	case []string:
+		groups = append(groups, value...)
+	case []interface{}:
+		for _, group := range value {
+			if str, ok := group.(string); ok {
+				groups = append(groups, str)
+			}
+		}
+	}
+	return groups
+}
@@ -0,0 +1,86 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package api
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/supertokens/supertokens-golang/recipe/thirdparty/tpmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+const (
+	samlLoginAPIPath = "/saml/login"
+	samlACSAPIPath   = "/saml/acs"
+)
+
+func SAMLLoginAPI(apiImplementation tpmodels.APIInterface, options tpmodels.APIOptions, userContext supertokens.UserContext) error {
+	if apiImplementation.SAMLLoginGET == nil || (*apiImplementation.SAMLLoginGET) == nil {
+		options.OtherHandler(options.Res, options.Req)
+		return nil
+	}
+
+	queryParams := options.Req.URL.Query()
+	provider, err := getSAMLProvider(queryParams.Get("thirdPartyId"), options)
+	if err != nil {
+		return err
+	}
+
+	result, err := (*apiImplementation.SAMLLoginGET)(*provider, queryParams.Get("state"), options, userContext)
+	if err != nil {
+		return err
+	}
+	http.Redirect(options.Res, options.Req, result.OK.RedirectURL, http.StatusFound)
+	return nil
+}
+
+func SAMLACSAPI(apiImplementation tpmodels.APIInterface, options tpmodels.APIOptions, userContext supertokens.UserContext) error {
+	if apiImplementation.SAMLACSPOST == nil || (*apiImplementation.SAMLACSPOST) == nil {
+		options.OtherHandler(options.Res, options.Req)
+		return nil
+	}
+
+	provider, err := getSAMLProvider(options.Req.URL.Query().Get("thirdPartyId"), options)
+	if err != nil {
+		return err
+	}
+
+	options.Req.ParseForm()
+	samlResponse := options.Req.PostFormValue("SAMLResponse")
+	if samlResponse == "" {
+		return supertokens.BadInputError{Msg: "Please provide the SAMLResponse in the request body"}
+	}
+
+	return (*apiImplementation.SAMLACSPOST)(*provider, samlResponse, options.Req.PostFormValue("RelayState"), options, userContext)
+}
+
+func getSAMLProvider(thirdPartyId string, options tpmodels.APIOptions) (*tpmodels.TypeProvider, error) {
+	if len(thirdPartyId) == 0 {
+		return nil, supertokens.BadInputError{Msg: "Please provide the thirdPartyId as a GET param"}
+	}
+	provider := findRightProvider(options.Providers, thirdPartyId, nil)
+	if provider == nil || provider.SAML == nil {
+		return nil, supertokens.BadInputError{Msg: "The SAML provider " + thirdPartyId + " seems to be missing from the backend configs"}
+	}
+	return provider, nil
+}
+
+// getSAMLACSURL returns the URL that the IdP has to post its response to
+func getSAMLACSURL(provider tpmodels.TypeProvider, options tpmodels.APIOptions) string {
+	return options.AppInfo.APIDomain.GetAsStringDangerous() + options.AppInfo.APIBasePath.GetAsStringDangerous() +
+		samlACSAPIPath + "?thirdPartyId=" + url.QueryEscape(provider.ID)
+}
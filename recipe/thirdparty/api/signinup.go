@@ -26,12 +26,15 @@ import (
 type bodyParams struct {
 	ThirdPartyId     string                 `json:"thirdPartyId"`
 	Code             string                 `json:"code"`
+	State            string                 `json:"state"`
 	RedirectURI      string                 `json:"redirectURI"`
 	AuthCodeResponse map[string]interface{} `json:"authCodeResponse"`
 	ClientId         string                 `json:"clientId"`
+	IdToken          string                 `json:"idToken"`
+	AccessToken      string                 `json:"accessToken"`
 }
 
-func SignInUpAPI(apiImplementation tpmodels.APIInterface, options tpmodels.APIOptions) error {
+func SignInUpAPI(apiImplementation tpmodels.APIInterface, options tpmodels.APIOptions, userContext supertokens.UserContext) error {
 	if apiImplementation.SignInUpPOST == nil || (*apiImplementation.SignInUpPOST) == nil {
 		options.OtherHandler(options.Res, options.Req)
 		return nil
@@ -56,16 +59,24 @@ func SignInUpAPI(apiImplementation tpmodels.APIInterface, options tpmodels.APIOp
 		return supertokens.BadInputError{Msg: "Please provide the thirdPartyId in request body"}
 	}
 
-	if bodyParams.Code == "" && bodyParams.AuthCodeResponse == nil {
-		return supertokens.BadInputError{Msg: "Please provide one of code or authCodeResponse in the request body"}
-	}
+	// native apps send the tokens that they got from the SDK of the provider instead of a code
+	isNativeSignIn := bodyParams.IdToken != "" || bodyParams.AccessToken != ""
 
-	if bodyParams.AuthCodeResponse != nil && bodyParams.AuthCodeResponse["access_token"] == nil {
-		return supertokens.BadInputError{Msg: "Please provide the access_token inside the authCodeResponse request param"}
-	}
+	if !isNativeSignIn {
+		if bodyParams.Code == "" && bodyParams.AuthCodeResponse == nil {
+			return supertokens.BadInputError{Msg: "Please provide one of code or authCodeResponse in the request body"}
+		}
 
-	if bodyParams.RedirectURI == "" {
-		return supertokens.BadInputError{Msg: "Please provide the redirectURI in request body"}
+		if bodyParams.AuthCodeResponse != nil && bodyParams.AuthCodeResponse["access_token"] == nil {
+			return supertokens.BadInputError{Msg: "Please provide the access_token inside the authCodeResponse request param"}
+		}
+
+		if bodyParams.RedirectURI == "" {
+			return supertokens.BadInputError{Msg: "Please provide the redirectURI in request body"}
+		}
+	} else if apiImplementation.NativeSignInUpPOST == nil || (*apiImplementation.NativeSignInUpPOST) == nil {
+		options.OtherHandler(options.Res, options.Req)
+		return nil
 	}
 
 	var provider *tpmodels.TypeProvider = findRightProvider(options.Providers, bodyParams.ThirdPartyId, clientId)
@@ -78,7 +89,20 @@ func SignInUpAPI(apiImplementation tpmodels.APIInterface, options tpmodels.APIOp
 		}
 	}
 
-	result, err := (*apiImplementation.SignInUpPOST)(*provider, bodyParams.Code, bodyParams.AuthCodeResponse, bodyParams.RedirectURI, options, &map[string]interface{}{})
+	var result tpmodels.SignInUpPOSTResponse
+	if isNativeSignIn {
+		tokens := tpmodels.NativeTokens{}
+		if bodyParams.IdToken != "" {
+			tokens.IDToken = &bodyParams.IdToken
+		}
+		if bodyParams.AccessToken != "" {
+			tokens.AccessToken = &bodyParams.AccessToken
+		}
+		result, err = (*apiImplementation.NativeSignInUpPOST)(*provider, tokens, options, userContext)
+	} else {
+		options.State = bodyParams.State
+		result, err = (*apiImplementation.SignInUpPOST)(*provider, bodyParams.Code, bodyParams.AuthCodeResponse, bodyParams.RedirectURI, options, userContext)
+	}
 
 	if err != nil {
 		return err
@@ -94,6 +118,10 @@ func SignInUpAPI(apiImplementation tpmodels.APIInterface, options tpmodels.APIOp
 		return supertokens.Send200Response(options.Res, map[string]interface{}{
 			"status": "NO_EMAIL_GIVEN_BY_PROVIDER",
 		})
+	} else if result.InvalidStateError != nil {
+		return supertokens.Send200Response(options.Res, map[string]interface{}{
+			"status": "INVALID_STATE_ERROR",
+		})
 	} else {
 		return supertokens.Send200Response(options.Res, map[string]interface{}{
 			"status": "FIELD_ERROR",
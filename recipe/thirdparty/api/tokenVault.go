@@ -0,0 +1,203 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package api
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/supertokens/supertokens-golang/recipe/thirdparty/tpmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// access tokens are refreshed this long before they expire, so that they are still valid when they are used
+const providerAccessTokenExpiryBuffer uint64 = 60 * 1000
+
+type providerTokenRefreshLock struct {
+	lock    sync.Mutex
+	waiters int
+}
+
+var providerTokenRefreshLocksMutex sync.Mutex
+var providerTokenRefreshLocks = map[string]*providerTokenRefreshLock{}
+
+// lockProviderTokenRefresh makes sure that the tokens of a user for a provider are only refreshed once
+// at a time in this process, since providers that rotate refresh tokens reject the old one once it has
+// been used. It returns the function that releases the lock.
+func lockProviderTokenRefresh(thirdPartyID string, userID string) func() {
+	key := thirdPartyID + ":" + userID
+	providerTokenRefreshLocksMutex.Lock()
+	refreshLock, ok := providerTokenRefreshLocks[key]
+	if !ok {
+		refreshLock = &providerTokenRefreshLock{}
+		providerTokenRefreshLocks[key] = refreshLock
+	}
+	refreshLock.waiters++
+	providerTokenRefreshLocksMutex.Unlock()
+
+	refreshLock.lock.Lock()
+	return func() {
+		refreshLock.lock.Unlock()
+		providerTokenRefreshLocksMutex.Lock()
+		refreshLock.waiters--
+		if refreshLock.waiters == 0 {
+			delete(providerTokenRefreshLocks, key)
+		}
+		providerTokenRefreshLocksMutex.Unlock()
+	}
+}
+
+func isProviderAccessTokenUsable(tokens tpmodels.ProviderTokens) bool {
+	return tokens.Expiry == 0 || tokens.Expiry > uint64(time.Now().UnixNano()/1000000)+providerAccessTokenExpiryBuffer
+}
+
+// saveProviderTokens saves the tokens in the response of the token endpoint of a provider
+func saveProviderTokens(provider tpmodels.TypeProvider, clientID string, userID string, tokenResponse map[string]interface{}, tokenVault tpmodels.TypeNormalisedInputTokenVault, userContext supertokens.UserContext) error {
+	accessToken, ok := tokenResponse["access_token"].(string)
+	if !ok || accessToken == "" {
+		return nil
+	}
+	tokens := tpmodels.ProviderTokens{
+		AccessToken: accessToken,
+		ClientID:    clientID,
+	}
+
+	if refreshToken, ok := tokenResponse["refresh_token"].(string); ok && refreshToken != "" {
+		tokens.RefreshToken = refreshToken
+	} else {
+		// providers like Google only return a refresh token the first time the user gives consent
+		existingTokens, err := tokenVault.Store.Get(provider.ID, userID, userContext)
+		if err != nil {
+			return err
+		}
+		if existingTokens != nil {
+			tokens.RefreshToken = existingTokens.RefreshToken
+		}
+	}
+
+	var expiresIn float64
+	switch value := tokenResponse["expires_in"].(type) {
+	case float64:
+		expiresIn = value
+	case string:
+		expiresIn, _ = strconv.ParseFloat(value, 64)
+	}
+	if expiresIn > 0 {
+		tokens.Expiry = uint64(time.Now().UnixNano()/1000000) + uint64(expiresIn*1000)
+	}
+
+	return tokenVault.Store.Save(provider.ID, userID, tokens, userContext)
+}
+
+// GetProviderAccessToken returns the saved access token of the user for the provider, refreshing it
+// if it has expired. It returns nil if there is no usable token.
+func GetProviderAccessToken(providers []tpmodels.TypeProvider, tokenVault *tpmodels.TypeNormalisedInputTokenVault, userID string, thirdPartyID string, userContext supertokens.UserContext) (*string, error) {
+	if tokenVault == nil {
+		return nil, errors.New("please enable the TokenVault in the SignInAndUpFeature config to use GetProviderAccessToken")
+	}
+	tokens, err := tokenVault.Store.Get(thirdPartyID, userID, userContext)
+	if err != nil {
+		return nil, err
+	}
+	if tokens == nil {
+		return nil, nil
+	}
+	if isProviderAccessTokenUsable(*tokens) {
+		return &tokens.AccessToken, nil
+	}
+	if tokens.RefreshToken == "" {
+		return nil, nil
+	}
+
+	unlock := lockProviderTokenRefresh(thirdPartyID, userID)
+	defer unlock()
+
+	// the tokens may have been refreshed while waiting for the lock
+	tokens, err = tokenVault.Store.Get(thirdPartyID, userID, userContext)
+	if err != nil {
+		return nil, err
+	}
+	if tokens == nil {
+		return nil, nil
+	}
+	if isProviderAccessTokenUsable(*tokens) {
+		return &tokens.AccessToken, nil
+	}
+	if tokens.RefreshToken == "" {
+		return nil, nil
+	}
+
+	accessToken, err := refreshProviderAccessToken(providers, *tokens, tokenVault, userID, thirdPartyID, userContext)
+	if err != nil {
+		// another API instance may have refreshed the tokens with the same refresh token in the meantime
+		latestTokens, getErr := tokenVault.Store.Get(thirdPartyID, userID, userContext)
+		if getErr == nil && latestTokens != nil && latestTokens.AccessToken != tokens.AccessToken && isProviderAccessTokenUsable(*latestTokens) {
+			return &latestTokens.AccessToken, nil
+		}
+		return nil, err
+	}
+	return accessToken, nil
+}
+
+func refreshProviderAccessToken(providers []tpmodels.TypeProvider, tokens tpmodels.ProviderTokens, tokenVault *tpmodels.TypeNormalisedInputTokenVault, userID string, thirdPartyID string, userContext supertokens.UserContext) (*string, error) {
+
+	var clientID *string = nil
+	if tokens.ClientID != "" {
+		clientID = &tokens.ClientID
+	}
+	provider := findRightProvider(providers, thirdPartyID, clientID)
+	if provider == nil {
+		return nil, errors.New("The third party provider " + thirdPartyID + " seems to be missing from the backend configs")
+	}
+	providerInfo := provider.Get(nil, nil, userContext)
+	if providerInfo.Error != nil {
+		return nil, providerInfo.Error
+	}
+	if providerInfo.AccessTokenAPI.URL == "" {
+		return nil, nil
+	}
+
+	// the refresh request is authenticated like the code exchange, but does not send the code
+	params := map[string]string{}
+	for key, value := range providerInfo.AccessTokenAPI.Params {
+		if key == "code" || key == "redirect_uri" || key == "code_verifier" {
+			continue
+		}
+		if isUsingDevelopmentClientId(providerInfo.GetClientId(userContext)) && value == providerInfo.GetClientId(userContext) {
+			value = GetActualClientIdFromDevelopmentClientId(value)
+		}
+		params[key] = value
+	}
+	params["grant_type"] = "refresh_token"
+	params["refresh_token"] = tokens.RefreshToken
+	providerInfo.AccessTokenAPI.Params = params
+
+	response, err := postRequest(providerInfo, userContext)
+	if err != nil {
+		return nil, err
+	}
+	accessToken, ok := response["access_token"].(string)
+	if !ok || accessToken == "" {
+		return nil, errors.New("the provider " + thirdPartyID + " did not return an access token when refreshing the tokens")
+	}
+	err = saveProviderTokens(*provider, tokens.ClientID, userID, response, *tokenVault, userContext)
+	if err != nil {
+		return nil, err
+	}
+	return &accessToken, nil
+}
@@ -278,3 +278,165 @@ func TestInvalidGetParamsForThirdPartyModule(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 	assert.Equal(t, "Please provide the thirdPartyId as a GET param", data["message"])
 }
+
+func TestAuthorisationUrlUsesTheProvidersOfTheRequest(t *testing.T) {
+	configValue := supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: "http://localhost:8080",
+		},
+		AppInfo: supertokens.AppInfo{
+			APIDomain:     "api.supertokens.io",
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{
+			Init(
+				&tpmodels.TypeInput{
+					SignInAndUpFeature: tpmodels.TypeInputSignInAndUp{
+						GetProviders: func(req *http.Request, userContext supertokens.UserContext) ([]tpmodels.TypeProvider, error) {
+							return []tpmodels.TypeProvider{
+								Google(tpmodels.GoogleConfig{
+									ClientID:     "client-id-of-" + req.Header.Get("tenant"),
+									ClientSecret: "test-secret",
+								}),
+							}, nil
+						},
+					},
+				},
+			),
+		},
+	}
+
+	BeforeEach()
+	unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+	err := supertokens.Init(configValue)
+
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	mux := http.NewServeMux()
+	testServer := httptest.NewServer(supertokens.Middleware(mux))
+	defer testServer.Close()
+
+	for _, tenant := range []string{"tenant-1", "tenant-2"} {
+		req, err := http.NewRequest(http.MethodGet, testServer.URL+"/auth/authorisationurl?thirdPartyId=google", nil)
+		if err != nil {
+			t.Error(err.Error())
+		}
+		req.Header.Set("tenant", tenant)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Error(err.Error())
+		}
+
+		dataInBytes, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Error(err.Error())
+		}
+		resp.Body.Close()
+
+		var data map[string]interface{}
+		err = json.Unmarshal(dataInBytes, &data)
+		if err != nil {
+			t.Error(err.Error())
+		}
+
+		assert.Equal(t, "OK", data["status"])
+
+		fetchedUrl, err := url.Parse(data["url"].(string))
+		if err != nil {
+			t.Error(err.Error())
+		}
+		assert.Equal(t, "client-id-of-"+tenant, fetchedUrl.Query().Get("client_id"))
+	}
+}
+
+func TestGetProvidersReceivesTheUserContextOfTheAPI(t *testing.T) {
+	configValue := supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: "http://localhost:8080",
+		},
+		AppInfo: supertokens.AppInfo{
+			APIDomain:     "api.supertokens.io",
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{
+			Init(
+				&tpmodels.TypeInput{
+					SignInAndUpFeature: tpmodels.TypeInputSignInAndUp{
+						GetProviders: func(req *http.Request, userContext supertokens.UserContext) ([]tpmodels.TypeProvider, error) {
+							(*userContext)["tenant"] = req.Header.Get("tenant")
+							return []tpmodels.TypeProvider{
+								Google(tpmodels.GoogleConfig{
+									ClientID:     "test",
+									ClientSecret: "test-secret",
+								}),
+							}, nil
+						},
+					},
+					Override: &tpmodels.OverrideStruct{
+						APIs: func(originalImplementation tpmodels.APIInterface) tpmodels.APIInterface {
+							originalAuthorisationUrlGET := *originalImplementation.AuthorisationUrlGET
+							nAuthorisationUrlGET := func(provider tpmodels.TypeProvider, options tpmodels.APIOptions, userContext supertokens.UserContext) (tpmodels.AuthorisationUrlGETResponse, error) {
+								response, err := originalAuthorisationUrlGET(provider, options, userContext)
+								if err != nil || response.OK == nil {
+									return response, err
+								}
+								response.OK.Url += "&tenant=" + (*userContext)["tenant"].(string)
+								return response, nil
+							}
+							originalImplementation.AuthorisationUrlGET = &nAuthorisationUrlGET
+							return originalImplementation
+						},
+					},
+				},
+			),
+		},
+	}
+
+	BeforeEach()
+	unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+	err := supertokens.Init(configValue)
+
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	mux := http.NewServeMux()
+	testServer := httptest.NewServer(supertokens.Middleware(mux))
+	defer testServer.Close()
+
+	req, err := http.NewRequest(http.MethodGet, testServer.URL+"/auth/authorisationurl?thirdPartyId=google", nil)
+	if err != nil {
+		t.Error(err.Error())
+	}
+	req.Header.Set("tenant", "tenant-1")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	dataInBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Error(err.Error())
+	}
+	resp.Body.Close()
+
+	var data map[string]interface{}
+	err = json.Unmarshal(dataInBytes, &data)
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	assert.Equal(t, "OK", data["status"])
+
+	fetchedUrl, err := url.Parse(data["url"].(string))
+	if err != nil {
+		t.Error(err.Error())
+	}
+	assert.Equal(t, "tenant-1", fetchedUrl.Query().Get("tenant"))
+}
@@ -17,6 +17,7 @@
 package thirdparty
 
 import (
+	"net/http"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -85,6 +86,44 @@ func TestConfigForInValidInputWithEmptyProviderSliceForThirdPartyModule(t *testi
 	}
 }
 
+func TestTwitterRequiresStateAndPKCE(t *testing.T) {
+	configValue := supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: "http://localhost:8080",
+		},
+		AppInfo: supertokens.AppInfo{
+			APIDomain:     "api.supertokens.io",
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{
+			Init(
+				&tpmodels.TypeInput{
+					SignInAndUpFeature: tpmodels.TypeInputSignInAndUp{
+						Providers: []tpmodels.TypeProvider{
+							Twitter(tpmodels.TwitterConfig{
+								ClientID:     "test",
+								ClientSecret: "test-secret",
+							}),
+						},
+					},
+				},
+			),
+		},
+	}
+
+	BeforeEach()
+	unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+	err := supertokens.Init(configValue)
+
+	if err != nil {
+		assert.Equal(t, "The third party provider twitter requires PKCE. Please set StateAndPKCE in the SignInAndUpFeature config without DisablePKCE", err.Error())
+	} else {
+		t.Fail()
+	}
+}
+
 func TestMinimumConfigForThirdpartyModuleCustomProvider(t *testing.T) {
 	configValue := supertokens.TypeInput{
 		Supertokens: &supertokens.ConnectionInfo{
@@ -138,3 +177,26 @@ func TestMinimumConfigForThirdpartyModuleCustomProvider(t *testing.T) {
 		t.Error(err.Error())
 	}
 }
+
+func TestProvidersReturnedByGetProvidersAreValidated(t *testing.T) {
+	config, err := validateAndNormaliseSignInAndUpConfig(tpmodels.TypeInputSignInAndUp{
+		GetProviders: func(req *http.Request, userContext supertokens.UserContext) ([]tpmodels.TypeProvider, error) {
+			return []tpmodels.TypeProvider{
+				Google(tpmodels.GoogleConfig{ClientID: "client-id-1"}),
+				Google(tpmodels.GoogleConfig{ClientID: "client-id-2"}),
+			}, nil
+		},
+	})
+	if err != nil {
+		t.Error(err.Error())
+	}
+	recipe := Recipe{
+		Config: tpmodels.TypeNormalisedInput{
+			SignInAndUpFeature: config,
+		},
+	}
+
+	_, err = recipe.GetProviders(nil, &map[string]interface{}{})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "Please mark one of them as the default one")
+}
@@ -19,4 +19,6 @@ const (
 	AuthorisationAPI        = "/authorisationurl"
 	SignInUpAPI             = "/signinup"
 	AppleRedirectHandlerAPI = "/callback/apple"
+	SAMLLoginAPI            = "/saml/login"
+	SAMLACSAPI              = "/saml/acs"
 )
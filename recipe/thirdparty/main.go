@@ -19,6 +19,7 @@ import (
 	"errors"
 
 	"github.com/supertokens/supertokens-golang/recipe/emailverification/evmodels"
+	"github.com/supertokens/supertokens-golang/recipe/thirdparty/api"
 	"github.com/supertokens/supertokens-golang/recipe/thirdparty/providers"
 	"github.com/supertokens/supertokens-golang/recipe/thirdparty/tpmodels"
 	"github.com/supertokens/supertokens-golang/supertokens"
@@ -128,6 +129,18 @@ func UnverifyEmailWithContext(userID string, userContext supertokens.UserContext
 	return (*instance.EmailVerificationRecipe.RecipeImpl.UnverifyEmail)(userID, email, userContext)
 }
 
+func GetProviderAccessTokenWithContext(userID string, thirdPartyID string, userContext supertokens.UserContext) (*string, error) {
+	instance, err := getRecipeInstanceOrThrowError()
+	if err != nil {
+		return nil, err
+	}
+	providers, err := instance.GetProviders(nil, userContext)
+	if err != nil {
+		return nil, err
+	}
+	return api.GetProviderAccessToken(providers, instance.Config.SignInAndUpFeature.TokenVault, userID, thirdPartyID, userContext)
+}
+
 func SignInUp(thirdPartyID string, thirdPartyUserID string, email tpmodels.EmailStruct) (tpmodels.SignInUpResponse, error) {
 	return SignInUpWithContext(thirdPartyID, thirdPartyUserID, email, &map[string]interface{}{})
 }
@@ -164,6 +177,12 @@ func UnverifyEmail(userID string) (evmodels.UnverifyEmailResponse, error) {
 	return UnverifyEmailWithContext(userID, &map[string]interface{}{})
 }
 
+// GetProviderAccessToken returns the access token that the provider issued to the user during sign in,
+// refreshing it if it has expired. It requires the TokenVault to be enabled.
+func GetProviderAccessToken(userID string, thirdPartyID string) (*string, error) {
+	return GetProviderAccessTokenWithContext(userID, thirdPartyID, &map[string]interface{}{})
+}
+
 func Apple(config tpmodels.AppleConfig) tpmodels.TypeProvider {
 	return providers.Apple(config)
 }
@@ -187,3 +206,41 @@ func GoogleWorkspaces(config tpmodels.GoogleWorkspacesConfig) tpmodels.TypeProvi
 func Google(config tpmodels.GoogleConfig) tpmodels.TypeProvider {
 	return providers.Google(config)
 }
+
+func OIDC(config tpmodels.OIDCConfig) tpmodels.TypeProvider {
+	return providers.OIDC(config)
+}
+
+func Microsoft(config tpmodels.MicrosoftConfig) tpmodels.TypeProvider {
+	return providers.Microsoft(config)
+}
+
+func Gitlab(config tpmodels.GitlabConfig) tpmodels.TypeProvider {
+	return providers.Gitlab(config)
+}
+
+func Bitbucket(config tpmodels.BitbucketConfig) tpmodels.TypeProvider {
+	return providers.Bitbucket(config)
+}
+
+func Linkedin(config tpmodels.LinkedinConfig) tpmodels.TypeProvider {
+	return providers.Linkedin(config)
+}
+
+func Twitter(config tpmodels.TwitterConfig) tpmodels.TypeProvider {
+	return providers.Twitter(config)
+}
+
+func Okta(config tpmodels.OktaConfig) tpmodels.TypeProvider {
+	return providers.Okta(config)
+}
+
+func SAML(config tpmodels.SAMLConfig) tpmodels.TypeProvider {
+	return providers.SAML(config)
+}
+
+// EndJWKSRefresh stops the background refresh of the keys that are used to verify id tokens
+// returned by providers like Apple, Google Workspaces and OIDC.
+func EndJWKSRefresh() {
+	providers.EndJWKSRefresh()
+}
@@ -0,0 +1,42 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package thirdparty
+
+import (
+	"errors"
+
+	"github.com/supertokens/supertokens-golang/recipe/thirdparty/tpmodels"
+)
+
+const defaultOAuthStateLifetime uint64 = 10 * 60 * 1000
+
+func validateAndNormaliseStateAndPKCEConfig(config *tpmodels.TypeInputStateAndPKCE) (*tpmodels.TypeNormalisedInputStateAndPKCE, error) {
+	if config == nil {
+		return nil, nil
+	}
+	if config.StateStore == nil {
+		return nil, errors.New("please provide a StateStore in the StateAndPKCE config")
+	}
+	normalisedConfig := tpmodels.TypeNormalisedInputStateAndPKCE{
+		StateLifetime: defaultOAuthStateLifetime,
+		DisablePKCE:   config.DisablePKCE,
+	}
+	if config.StateLifetime != nil {
+		normalisedConfig.StateLifetime = *config.StateLifetime
+	}
+	normalisedConfig.StateStore = *config.StateStore
+	return &normalisedConfig, nil
+}
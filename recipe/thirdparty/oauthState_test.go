@@ -0,0 +1,416 @@
+/*
+ * Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package thirdparty
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/session"
+	"github.com/supertokens/supertokens-golang/recipe/thirdparty/tpmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+	"github.com/supertokens/supertokens-golang/test/unittesting"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func makeInMemoryOAuthStateStoreForTest() tpmodels.OAuthStateStore {
+	var lock sync.Mutex
+	states := map[string]tpmodels.OAuthStateInfo{}
+	return tpmodels.OAuthStateStore{
+		Save: func(stateInfo tpmodels.OAuthStateInfo, userContext supertokens.UserContext) error {
+			lock.Lock()
+			defer lock.Unlock()
+			states[stateInfo.State] = stateInfo
+			return nil
+		},
+		Get: func(state string, userContext supertokens.UserContext) (*tpmodels.OAuthStateInfo, error) {
+			lock.Lock()
+			defer lock.Unlock()
+			stateInfo, ok := states[state]
+			if !ok {
+				return nil, nil
+			}
+			return &stateInfo, nil
+		},
+		Consume: func(state string, userContext supertokens.UserContext) (*tpmodels.OAuthStateInfo, error) {
+			lock.Lock()
+			defer lock.Unlock()
+			stateInfo, ok := states[state]
+			if !ok {
+				return nil, nil
+			}
+			delete(states, state)
+			return &stateInfo, nil
+		},
+	}
+}
+
+func TestStateAndPKCERequiresAStateStore(t *testing.T) {
+	configValue := supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: "http://localhost:8080",
+		},
+		AppInfo: supertokens.AppInfo{
+			APIDomain:     "api.supertokens.io",
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{
+			Init(
+				&tpmodels.TypeInput{
+					SignInAndUpFeature: tpmodels.TypeInputSignInAndUp{
+						Providers: []tpmodels.TypeProvider{
+							customProvider1,
+						},
+						StateAndPKCE: &tpmodels.TypeInputStateAndPKCE{},
+					},
+				},
+			),
+		},
+	}
+
+	BeforeEach()
+	unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+	err := supertokens.Init(configValue)
+	assert.NotNil(t, err)
+	assert.Equal(t, "please provide a StateStore in the StateAndPKCE config", err.Error())
+}
+
+func TestSignInUpChecksTheStateAndSendsTheCodeVerifier(t *testing.T) {
+	stateStore := makeInMemoryOAuthStateStoreForTest()
+	configValue := supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: "http://localhost:8080",
+		},
+		AppInfo: supertokens.AppInfo{
+			APIDomain:     "api.supertokens.io",
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{
+			session.Init(nil),
+			Init(
+				&tpmodels.TypeInput{
+					SignInAndUpFeature: tpmodels.TypeInputSignInAndUp{
+						Providers: []tpmodels.TypeProvider{
+							customProvider1,
+						},
+						StateAndPKCE: &tpmodels.TypeInputStateAndPKCE{
+							StateStore: &stateStore,
+						},
+					},
+				},
+			),
+		},
+	}
+
+	BeforeEach()
+	unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+	err := supertokens.Init(configValue)
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	mux := http.NewServeMux()
+	testServer := httptest.NewServer(supertokens.Middleware(mux))
+	defer testServer.Close()
+
+	resp, err := http.Get(testServer.URL + "/auth/authorisationurl?thirdPartyId=custom")
+	if err != nil {
+		t.Error(err.Error())
+	}
+	var stateCookie *http.Cookie
+	for _, cookie := range resp.Cookies() {
+		if cookie.Name == "sOAuthState" {
+			stateCookie = cookie
+		}
+	}
+	dataInBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Error(err.Error())
+	}
+	resp.Body.Close()
+
+	var data map[string]interface{}
+	err = json.Unmarshal(dataInBytes, &data)
+	if err != nil {
+		t.Error(err.Error())
+	}
+	assert.Equal(t, "OK", data["status"])
+
+	fetchedUrl, err := url.Parse(data["url"].(string))
+	if err != nil {
+		t.Error(err.Error())
+	}
+	state := fetchedUrl.Query().Get("state")
+	assert.NotEmpty(t, state)
+	assert.NotEmpty(t, fetchedUrl.Query().Get("code_challenge"))
+	assert.Equal(t, "S256", fetchedUrl.Query().Get("code_challenge_method"))
+	assert.NotNil(t, stateCookie)
+	assert.Equal(t, state, stateCookie.Value)
+
+	defer gock.OffAll()
+	gock.New("https://test.com/").
+		Post("oauth/token").
+		BodyString("code_verifier=").
+		Reply(200).
+		JSON(map[string]string{"access_token": "abcdefghj"})
+	gock.New(testServer.URL).EnableNetworking().Persist()
+	gock.New("http://localhost:8080/").EnableNetworking().Persist()
+
+	signInUp := func(state string, cookie *http.Cookie) map[string]interface{} {
+		postBody, err := json.Marshal(map[string]string{
+			"thirdPartyId": "custom",
+			"code":         "abcdefghj",
+			"state":        state,
+			"redirectURI":  "http://127.0.0.1/callback",
+		})
+		if err != nil {
+			t.Error(err.Error())
+		}
+		req, err := http.NewRequest(http.MethodPost, testServer.URL+"/auth/signinup", bytes.NewBuffer(postBody))
+		if err != nil {
+			t.Error(err.Error())
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if cookie != nil {
+			req.AddCookie(cookie)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Error(err.Error())
+		}
+		dataInBytes, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Error(err.Error())
+		}
+		resp.Body.Close()
+		var result map[string]interface{}
+		err = json.Unmarshal(dataInBytes, &result)
+		if err != nil {
+			t.Error(err.Error())
+		}
+		return result
+	}
+
+	// the state must come from the browser that started the sign in
+	result := signInUp(state, nil)
+	assert.Equal(t, "INVALID_STATE_ERROR", result["status"])
+
+	result = signInUp("some-other-state", &http.Cookie{Name: "sOAuthState", Value: "some-other-state"})
+	assert.Equal(t, "INVALID_STATE_ERROR", result["status"])
+
+	result = signInUp(state, stateCookie)
+	assert.Equal(t, "OK", result["status"])
+
+	// the state can only be used once
+	result = signInUp(state, stateCookie)
+	assert.Equal(t, "INVALID_STATE_ERROR", result["status"])
+
+	// the state cannot be skipped by exchanging the code on the frontend
+	postBody, err := json.Marshal(map[string]interface{}{
+		"thirdPartyId": "custom",
+		"authCodeResponse": map[string]string{
+			"access_token": "abcdefghj",
+		},
+		"redirectURI": "http://127.0.0.1/callback",
+	})
+	if err != nil {
+		t.Error(err.Error())
+	}
+	resp, err = http.Post(testServer.URL+"/auth/signinup", "application/json", bytes.NewBuffer(postBody))
+	if err != nil {
+		t.Error(err.Error())
+	}
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestOIDCNonceIsSavedWithTheOAuthState(t *testing.T) {
+	stateStore := makeInMemoryOAuthStateStoreForTest()
+	defer gock.OffAll()
+	defer EndJWKSRefresh()
+	privateKey := mockOIDCIssuer(t, "https://keycloak.test.com")
+
+	configValue := supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: "http://localhost:8080",
+		},
+		AppInfo: supertokens.AppInfo{
+			APIDomain:     "api.supertokens.io",
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{
+			session.Init(nil),
+			Init(
+				&tpmodels.TypeInput{
+					SignInAndUpFeature: tpmodels.TypeInputSignInAndUp{
+						// the provider is created for every request, so it cannot remember the nonce itself
+						GetProviders: func(req *http.Request, userContext supertokens.UserContext) ([]tpmodels.TypeProvider, error) {
+							return []tpmodels.TypeProvider{
+								OIDC(tpmodels.OIDCConfig{
+									ThirdPartyID: "keycloak",
+									Issuer:       "https://keycloak.test.com",
+									ClientID:     "test",
+									ClientSecret: "test-secret",
+								}),
+							}, nil
+						},
+						StateAndPKCE: &tpmodels.TypeInputStateAndPKCE{
+							StateStore: &stateStore,
+						},
+					},
+				},
+			),
+		},
+	}
+
+	BeforeEach()
+	unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+	err := supertokens.Init(configValue)
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	mux := http.NewServeMux()
+	testServer := httptest.NewServer(supertokens.Middleware(mux))
+	defer testServer.Close()
+	gock.New(testServer.URL).EnableNetworking().Persist()
+	gock.New("http://localhost:8080/").EnableNetworking().Persist()
+
+	resp, err := http.Get(testServer.URL + "/auth/authorisationurl?thirdPartyId=keycloak")
+	if err != nil {
+		t.Error(err.Error())
+	}
+	var stateCookie *http.Cookie
+	for _, cookie := range resp.Cookies() {
+		if cookie.Name == "sOAuthState" {
+			stateCookie = cookie
+		}
+	}
+	dataInBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Error(err.Error())
+	}
+	resp.Body.Close()
+	var data map[string]interface{}
+	err = json.Unmarshal(dataInBytes, &data)
+	if err != nil {
+		t.Error(err.Error())
+	}
+	assert.Equal(t, "OK", data["status"])
+	fetchedUrl, err := url.Parse(data["url"].(string))
+	if err != nil {
+		t.Error(err.Error())
+	}
+	state := fetchedUrl.Query().Get("state")
+	nonce := fetchedUrl.Query().Get("nonce")
+	assert.NotEmpty(t, nonce)
+
+	signInUp := func(nonce string) map[string]interface{} {
+		gock.New("https://keycloak.test.com").
+			Post("/oauth2/v1/token").
+			Reply(200).
+			JSON(map[string]string{
+				"access_token": "abcdefghj",
+				"id_token": signOIDCIdToken(t, privateKey, jwt.MapClaims{
+					"iss":            "https://keycloak.test.com",
+					"aud":            "test",
+					"sub":            "user-1",
+					"exp":            time.Now().Add(time.Hour).Unix(),
+					"nonce":          nonce,
+					"email":          "johndoe@gmail.com",
+					"email_verified": true,
+				}),
+			})
+		postBody, err := json.Marshal(map[string]string{
+			"thirdPartyId": "keycloak",
+			"code":         "abcdefghj",
+			"state":        state,
+			"redirectURI":  "http://127.0.0.1/callback",
+		})
+		if err != nil {
+			t.Error(err.Error())
+		}
+		req, err := http.NewRequest(http.MethodPost, testServer.URL+"/auth/signinup", bytes.NewBuffer(postBody))
+		if err != nil {
+			t.Error(err.Error())
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.AddCookie(stateCookie)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Error(err.Error())
+		}
+		dataInBytes, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Error(err.Error())
+		}
+		resp.Body.Close()
+		var result map[string]interface{}
+		err = json.Unmarshal(dataInBytes, &result)
+		if err != nil {
+			t.Error(err.Error())
+		}
+		return result
+	}
+
+	result := signInUp("some-other-nonce")
+	assert.Equal(t, "FIELD_ERROR", result["status"])
+
+	// the state is consumed by the failed sign in, so a new one is needed
+	resp, err = http.Get(testServer.URL + "/auth/authorisationurl?thirdPartyId=keycloak")
+	if err != nil {
+		t.Error(err.Error())
+	}
+	for _, cookie := range resp.Cookies() {
+		if cookie.Name == "sOAuthState" {
+			stateCookie = cookie
+		}
+	}
+	dataInBytes, err = io.ReadAll(resp.Body)
+	if err != nil {
+		t.Error(err.Error())
+	}
+	resp.Body.Close()
+	err = json.Unmarshal(dataInBytes, &data)
+	if err != nil {
+		t.Error(err.Error())
+	}
+	fetchedUrl, err = url.Parse(data["url"].(string))
+	if err != nil {
+		t.Error(err.Error())
+	}
+	state = fetchedUrl.Query().Get("state")
+	nonce = fetchedUrl.Query().Get("nonce")
+
+	result = signInUp(nonce)
+	assert.Equal(t, "OK", result["status"])
+}
@@ -0,0 +1,288 @@
+/*
+ * Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package thirdparty
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/thirdparty/api"
+	"github.com/supertokens/supertokens-golang/recipe/thirdparty/tpmodels"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func mockOIDCIssuer(t *testing.T, issuer string) *rsa.PrivateKey {
+	return mockOIDCIssuerWithKeys(t, issuer, true)
+}
+
+func mockOIDCIssuerWithKeys(t *testing.T, issuer string, persistKeys bool) *rsa.PrivateKey {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	gock.New(issuer).
+		Get("/.well-known/openid-configuration").
+		Persist().
+		Reply(200).
+		JSON(map[string]interface{}{
+			"issuer":                 issuer,
+			"authorization_endpoint": issuer + "/oauth2/v1/authorize",
+			"token_endpoint":         issuer + "/oauth2/v1/token",
+			"userinfo_endpoint":      issuer + "/oauth2/v1/userinfo",
+			"jwks_uri":               issuer + "/oauth2/v1/keys",
+		})
+	keysMock := gock.New(issuer).
+		Get("/oauth2/v1/keys")
+	if persistKeys {
+		keysMock = keysMock.Persist()
+	}
+	keysMock.
+		Reply(200).
+		JSON(map[string]interface{}{
+			"keys": []map[string]interface{}{jwkFromPrivateKey(privateKey)},
+		})
+	return privateKey
+}
+
+func jwkFromPrivateKey(privateKey *rsa.PrivateKey) map[string]interface{} {
+	return map[string]interface{}{
+		"kty": "RSA",
+		"kid": "test-key",
+		"alg": "RS256",
+		"use": "sig",
+		"n":   base64.RawURLEncoding.EncodeToString(privateKey.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(privateKey.E)).Bytes()),
+	}
+}
+
+func signOIDCIdToken(t *testing.T, privateKey *rsa.PrivateKey, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = "test-key"
+	idToken, err := token.SignedString(privateKey)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	return idToken
+}
+
+func TestOIDCProviderUsesEndpointsFromDiscoveryDocument(t *testing.T) {
+	defer gock.OffAll()
+	mockOIDCIssuer(t, "https://okta.test.com")
+
+	provider := OIDC(tpmodels.OIDCConfig{
+		ThirdPartyID: "okta",
+		Issuer:       "https://okta.test.com",
+		ClientID:     "test",
+		ClientSecret: "test-secret",
+	})
+
+	assert.Equal(t, "okta", provider.ID)
+
+	providerInfoGetResult := provider.Get(nil, nil, nil)
+
+	assert.Equal(t, "https://okta.test.com/oauth2/v1/token", providerInfoGetResult.AccessTokenAPI.URL)
+	assert.Equal(t, "https://okta.test.com/oauth2/v1/authorize", providerInfoGetResult.AuthorisationRedirect.URL)
+	assert.Equal(t, map[string]string{
+		"client_id":     "test",
+		"client_secret": "test-secret",
+		"grant_type":    "authorization_code",
+	}, providerInfoGetResult.AccessTokenAPI.Params)
+
+	authParams := providerInfoGetResult.AuthorisationRedirect.Params
+	assert.Equal(t, "openid email profile", authParams["scope"])
+	assert.Equal(t, "code", authParams["response_type"])
+	assert.Equal(t, "test", authParams["client_id"])
+	// the nonce is created with the OAuth state by AuthorisationUrlGET
+	assert.True(t, providerInfoGetResult.RequiresNonce)
+	assert.Nil(t, authParams["nonce"])
+}
+
+func TestOIDCProviderReturnsAnErrorIfTheDiscoveryDocumentCannotBeFetched(t *testing.T) {
+	defer gock.OffAll()
+	gock.New("https://okta.test.com").
+		Get("/.well-known/openid-configuration").
+		Reply(500)
+
+	provider := OIDC(tpmodels.OIDCConfig{
+		ThirdPartyID: "okta",
+		Issuer:       "https://okta.test.com",
+		ClientID:     "test",
+		ClientSecret: "test-secret",
+	})
+
+	providerInfoGetResult := provider.Get(nil, nil, nil)
+	assert.Error(t, providerInfoGetResult.Error)
+	assert.Equal(t, "", providerInfoGetResult.AuthorisationRedirect.URL)
+}
+
+func TestOIDCProviderVerifiesIdTokenAndMapsClaims(t *testing.T) {
+	defer gock.OffAll()
+	defer EndJWKSRefresh()
+	privateKey := mockOIDCIssuer(t, "https://keycloak.test.com")
+
+	provider := OIDC(tpmodels.OIDCConfig{
+		ThirdPartyID: "keycloak",
+		Issuer:       "https://keycloak.test.com",
+		ClientID:     "test",
+		ClientSecret: "test-secret",
+		ClaimMapping: &tpmodels.OIDCClaimMapping{
+			Email: "preferred_email",
+		},
+	})
+
+	nonce := "nonce-of-the-oauth-state"
+	userContext := &map[string]interface{}{
+		api.OIDCNonceUserContextKey: nonce,
+	}
+	claims := jwt.MapClaims{
+		"iss":             "https://keycloak.test.com",
+		"aud":             "test",
+		"sub":             "user-1",
+		"exp":             time.Now().Add(time.Hour).Unix(),
+		"nonce":           nonce,
+		"preferred_email": "johndoe@gmail.com",
+		"email_verified":  "true",
+	}
+
+	authCode := "code"
+	providerInfoGetResult := provider.Get(nil, &authCode, userContext)
+	userInfo, err := providerInfoGetResult.GetProfileInfo(map[string]interface{}{
+		"id_token": signOIDCIdToken(t, privateKey, claims),
+	}, userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, "user-1", userInfo.ID)
+	assert.Equal(t, "johndoe@gmail.com", userInfo.Email.ID)
+	assert.True(t, userInfo.Email.IsVerified)
+	assert.Equal(t, "johndoe@gmail.com", userInfo.RawUserInfoFromProvider.FromIdTokenPayload["preferred_email"])
+	assert.Nil(t, userInfo.RawUserInfoFromProvider.FromUserInfoAPI)
+
+	// the nonce must be the one saved with the OAuth state of the sign in
+	_, err = providerInfoGetResult.GetProfileInfo(map[string]interface{}{
+		"id_token": signOIDCIdToken(t, privateKey, claims),
+	}, &map[string]interface{}{
+		api.OIDCNonceUserContextKey: "nonce-of-another-oauth-state",
+	})
+	assert.Error(t, err)
+
+	_, err = providerInfoGetResult.GetProfileInfo(map[string]interface{}{
+		"id_token": signOIDCIdToken(t, privateKey, claims),
+	}, &map[string]interface{}{})
+	assert.Error(t, err)
+
+	claims["aud"] = "another-client"
+	_, err = providerInfoGetResult.GetProfileInfo(map[string]interface{}{
+		"id_token": signOIDCIdToken(t, privateKey, claims),
+	}, userContext)
+	assert.Error(t, err)
+
+	claims["aud"] = "test"
+	claims["iss"] = "https://attacker.test.com"
+	_, err = providerInfoGetResult.GetProfileInfo(map[string]interface{}{
+		"id_token": signOIDCIdToken(t, privateKey, claims),
+	}, userContext)
+	assert.Error(t, err)
+
+	claims["iss"] = "https://keycloak.test.com"
+	claims["exp"] = time.Now().Add(-time.Hour).Unix()
+	_, err = providerInfoGetResult.GetProfileInfo(map[string]interface{}{
+		"id_token": signOIDCIdToken(t, privateKey, claims),
+	}, userContext)
+	assert.Error(t, err)
+}
+
+func TestJWKSAreFetchedOnceForAllProvidersWithTheSameIssuer(t *testing.T) {
+	defer gock.OffAll()
+	defer EndJWKSRefresh()
+	// the keys are only mocked for a single request, so the second provider fails if it fetches them again
+	privateKey := mockOIDCIssuerWithKeys(t, "https://auth0.test.com", false)
+
+	for _, clientID := range []string{"client-1", "client-2"} {
+		provider := OIDC(tpmodels.OIDCConfig{
+			ThirdPartyID: "auth0",
+			Issuer:       "https://auth0.test.com",
+			ClientID:     clientID,
+			ClientSecret: "test-secret",
+		})
+
+		nonce := "nonce-of-the-oauth-state"
+		userContext := &map[string]interface{}{
+			api.OIDCNonceUserContextKey: nonce,
+		}
+		authCode := "code"
+		userInfo, err := provider.Get(nil, &authCode, userContext).GetProfileInfo(map[string]interface{}{
+			"id_token": signOIDCIdToken(t, privateKey, jwt.MapClaims{
+				"iss":            "https://auth0.test.com",
+				"aud":            clientID,
+				"sub":            "user-1",
+				"exp":            time.Now().Add(time.Hour).Unix(),
+				"nonce":          nonce,
+				"email":          "johndoe@gmail.com",
+				"email_verified": true,
+			}),
+		}, userContext)
+		assert.NoError(t, err)
+		assert.Equal(t, "user-1", userInfo.ID)
+	}
+}
+
+func TestOIDCProviderAcceptsNativeIdTokensIssuedToAdditionalClientIDs(t *testing.T) {
+	defer gock.OffAll()
+	defer EndJWKSRefresh()
+	privateKey := mockOIDCIssuer(t, "https://keycloak.test.com")
+
+	provider := OIDC(tpmodels.OIDCConfig{
+		ThirdPartyID:        "keycloak",
+		Issuer:              "https://keycloak.test.com",
+		ClientID:            "web",
+		ClientSecret:        "test-secret",
+		AdditionalClientIDs: []string{"ios", "android"},
+	})
+
+	claims := jwt.MapClaims{
+		"iss":            "https://keycloak.test.com",
+		"aud":            "ios",
+		"sub":            "user-1",
+		"exp":            time.Now().Add(time.Hour).Unix(),
+		"email":          "johndoe@gmail.com",
+		"email_verified": true,
+	}
+	idToken := signOIDCIdToken(t, privateKey, claims)
+
+	providerInfoGetResult := provider.Get(nil, nil, nil)
+	userInfo, err := providerInfoGetResult.GetProfileInfoFromNativeTokens(tpmodels.NativeTokens{
+		IDToken: &idToken,
+	}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "user-1", userInfo.ID)
+	assert.Equal(t, "johndoe@gmail.com", userInfo.Email.ID)
+
+	claims["aud"] = "another-client"
+	idToken = signOIDCIdToken(t, privateKey, claims)
+	_, err = providerInfoGetResult.GetProfileInfoFromNativeTokens(tpmodels.NativeTokens{
+		IDToken: &idToken,
+	}, nil)
+	assert.Error(t, err)
+
+	_, err = providerInfoGetResult.GetProfileInfoFromNativeTokens(tpmodels.NativeTokens{}, nil)
+	assert.Error(t, err)
+}
@@ -17,12 +17,17 @@
 package thirdparty
 
 import (
+	"crypto/rand"
+	"crypto/rsa"
 	"testing"
+	"time"
 
+	"github.com/golang-jwt/jwt/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/supertokens/supertokens-golang/recipe/thirdparty/tpmodels"
 	"github.com/supertokens/supertokens-golang/supertokens"
 	"github.com/supertokens/supertokens-golang/test/unittesting"
+	"gopkg.in/h2non/gock.v1"
 )
 
 func TestMinimumConfigForGoogleAsThirdPartyProvider(t *testing.T) {
@@ -519,3 +524,440 @@ func TestPassingScopesInConfigForGithub(t *testing.T) {
 		"scope":     "test-scope-1 test-scope-2",
 	}, providerInfoGetResult.AuthorisationRedirect.Params)
 }
+
+func TestMinimumConfigForMicrosoftWithTenantAsThirdPartyProvider(t *testing.T) {
+	tenantID := "tenant-1"
+	configValue := supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: "http://localhost:8080",
+		},
+		AppInfo: supertokens.AppInfo{
+			APIDomain:     "api.supertokens.io",
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{
+			Init(
+				&tpmodels.TypeInput{
+					SignInAndUpFeature: tpmodels.TypeInputSignInAndUp{
+						Providers: []tpmodels.TypeProvider{
+							Microsoft(tpmodels.MicrosoftConfig{
+								ClientID:     "test",
+								ClientSecret: "test-secret",
+								TenantID:     &tenantID,
+							}),
+						},
+					},
+				},
+			),
+		},
+	}
+
+	BeforeEach()
+	unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+	err := supertokens.Init(configValue)
+
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	singletonInstance, err := getRecipeInstanceOrThrowError()
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	providerInfo := singletonInstance.Providers[0]
+
+	assert.Equal(t, "microsoft", providerInfo.ID)
+
+	providerInfoGetResult := providerInfo.Get(nil, nil, nil)
+
+	assert.Equal(t, "https://login.microsoftonline.com/tenant-1/oauth2/v2.0/token", providerInfoGetResult.AccessTokenAPI.URL)
+	assert.Equal(t, "https://login.microsoftonline.com/tenant-1/oauth2/v2.0/authorize", providerInfoGetResult.AuthorisationRedirect.URL)
+
+	assert.Equal(t, map[string]string{
+		"client_id":     "test",
+		"client_secret": "test-secret",
+		"grant_type":    "authorization_code",
+	}, providerInfoGetResult.AccessTokenAPI.Params)
+
+	assert.Equal(t, map[string]interface{}{
+		"client_id":     "test",
+		"response_type": "code",
+		"response_mode": "query",
+		"scope":         "openid email profile",
+	}, providerInfoGetResult.AuthorisationRedirect.Params)
+}
+
+func TestMinimumConfigForSelfHostedGitlabAsThirdPartyProvider(t *testing.T) {
+	baseURL := "https://gitlab.example.com/"
+	configValue := supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: "http://localhost:8080",
+		},
+		AppInfo: supertokens.AppInfo{
+			APIDomain:     "api.supertokens.io",
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{
+			Init(
+				&tpmodels.TypeInput{
+					SignInAndUpFeature: tpmodels.TypeInputSignInAndUp{
+						Providers: []tpmodels.TypeProvider{
+							Gitlab(tpmodels.GitlabConfig{
+								ClientID:     "test",
+								ClientSecret: "test-secret",
+								BaseURL:      &baseURL,
+							}),
+						},
+					},
+				},
+			),
+		},
+	}
+
+	BeforeEach()
+	unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+	err := supertokens.Init(configValue)
+
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	singletonInstance, err := getRecipeInstanceOrThrowError()
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	providerInfo := singletonInstance.Providers[0]
+
+	assert.Equal(t, "gitlab", providerInfo.ID)
+
+	providerInfoGetResult := providerInfo.Get(nil, nil, nil)
+
+	assert.Equal(t, "https://gitlab.example.com/oauth/token", providerInfoGetResult.AccessTokenAPI.URL)
+	assert.Equal(t, "https://gitlab.example.com/oauth/authorize", providerInfoGetResult.AuthorisationRedirect.URL)
+
+	assert.Equal(t, map[string]interface{}{
+		"client_id":     "test",
+		"response_type": "code",
+		"scope":         "read_user",
+	}, providerInfoGetResult.AuthorisationRedirect.Params)
+}
+
+func TestGetProfileInfoForGitlab(t *testing.T) {
+	defer gock.OffAll()
+	gock.New("https://gitlab.com").
+		Get("/api/v4/user").
+		MatchHeader("Authorization", "Bearer abcdefghj").
+		Reply(200).
+		JSON(map[string]interface{}{
+			"id":           1234,
+			"email":        "johndoe@gmail.com",
+			"confirmed_at": "2021-01-01T00:00:00.000Z",
+		})
+
+	providerInfo := Gitlab(tpmodels.GitlabConfig{
+		ClientID:     "test",
+		ClientSecret: "test-secret",
+	}).Get(nil, nil, nil)
+
+	userInfo, err := providerInfo.GetProfileInfo(map[string]interface{}{"access_token": "abcdefghj"}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "1234", userInfo.ID)
+	assert.Equal(t, "johndoe@gmail.com", userInfo.Email.ID)
+	assert.True(t, userInfo.Email.IsVerified)
+}
+
+func TestGetProfileInfoForBitbucket(t *testing.T) {
+	defer gock.OffAll()
+	gock.New("https://api.bitbucket.org").
+		Get("/2.0/user/emails").
+		Reply(200).
+		JSON(map[string]interface{}{
+			"values": []map[string]interface{}{
+				{"email": "other@gmail.com", "is_primary": false, "is_confirmed": true},
+				{"email": "johndoe@gmail.com", "is_primary": true, "is_confirmed": false},
+			},
+		})
+	gock.New("https://api.bitbucket.org").
+		Get("/2.0/user").
+		Reply(200).
+		JSON(map[string]interface{}{
+			"uuid": "{user-1}",
+		})
+
+	providerInfo := Bitbucket(tpmodels.BitbucketConfig{
+		ClientID:     "test",
+		ClientSecret: "test-secret",
+	}).Get(nil, nil, nil)
+
+	// the client credentials are sent using basic auth
+	assert.Equal(t, "Basic dGVzdDp0ZXN0LXNlY3JldA==", providerInfo.AccessTokenAPI.Headers["Authorization"])
+	assert.NotContains(t, providerInfo.AccessTokenAPI.Params, "client_secret")
+
+	userInfo, err := providerInfo.GetProfileInfo(map[string]interface{}{"access_token": "abcdefghj"}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "{user-1}", userInfo.ID)
+	assert.Equal(t, "johndoe@gmail.com", userInfo.Email.ID)
+	assert.False(t, userInfo.Email.IsVerified)
+}
+
+func TestGetProfileInfoForLinkedin(t *testing.T) {
+	defer gock.OffAll()
+	gock.New("https://api.linkedin.com").
+		Get("/v2/userinfo").
+		MatchHeader("Authorization", "Bearer abcdefghj").
+		Reply(200).
+		JSON(map[string]interface{}{
+			"sub":            "user-1",
+			"email":          "johndoe@gmail.com",
+			"email_verified": true,
+		})
+
+	providerInfo := Linkedin(tpmodels.LinkedinConfig{
+		ClientID:     "test",
+		ClientSecret: "test-secret",
+	}).Get(nil, nil, nil)
+
+	userInfo, err := providerInfo.GetProfileInfo(map[string]interface{}{"access_token": "abcdefghj"}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "user-1", userInfo.ID)
+	assert.Equal(t, "johndoe@gmail.com", userInfo.Email.ID)
+	assert.True(t, userInfo.Email.IsVerified)
+}
+
+func TestGetProfileInfoForTwitter(t *testing.T) {
+	defer gock.OffAll()
+	gock.New("https://api.twitter.com").
+		Get("/2/users/me").
+		MatchParam("user.fields", "confirmed_email").
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"id":       "1234",
+				"username": "johndoe",
+			},
+		})
+
+	providerInfo := Twitter(tpmodels.TwitterConfig{
+		ClientID:     "test",
+		ClientSecret: "test-secret",
+	}).Get(nil, nil, nil)
+
+	assert.Equal(t, "https://twitter.com/i/oauth2/authorize", providerInfo.AuthorisationRedirect.URL)
+	assert.Equal(t, "users.read users.email tweet.read", providerInfo.AuthorisationRedirect.Params["scope"])
+
+	// users without a confirmed email can not sign in
+	userInfo, err := providerInfo.GetProfileInfo(map[string]interface{}{"access_token": "abcdefghj"}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "1234", userInfo.ID)
+	assert.Nil(t, userInfo.Email)
+}
+
+func TestGetProfileInfoReturnsAnErrorForUnexpectedResponses(t *testing.T) {
+	defer gock.OffAll()
+	gock.New("https://api.twitter.com").
+		Get("/2/users/me").
+		Reply(200).
+		JSON(map[string]interface{}{
+			"errors": []map[string]interface{}{{"message": "Unauthorized"}},
+		})
+	gock.New("https://gitlab.com").
+		Get("/api/v4/user").
+		Reply(200).
+		JSON(map[string]interface{}{
+			"message": "401 Unauthorized",
+		})
+	gock.New("https://api.bitbucket.org").
+		Get("/2.0/user").
+		Reply(200).
+		JSON([]interface{}{"unexpected"})
+
+	_, err := Twitter(tpmodels.TwitterConfig{
+		ClientID:     "test",
+		ClientSecret: "test-secret",
+	}).Get(nil, nil, nil).GetProfileInfo(map[string]interface{}{"access_token": "abcdefghj"}, nil)
+	assert.Error(t, err)
+
+	_, err = Gitlab(tpmodels.GitlabConfig{
+		ClientID:     "test",
+		ClientSecret: "test-secret",
+	}).Get(nil, nil, nil).GetProfileInfo(map[string]interface{}{"access_token": "abcdefghj"}, nil)
+	assert.Error(t, err)
+
+	_, err = Bitbucket(tpmodels.BitbucketConfig{
+		ClientID:     "test",
+		ClientSecret: "test-secret",
+	}).Get(nil, nil, nil).GetProfileInfo(map[string]interface{}{"access_token": "abcdefghj"}, nil)
+	assert.Error(t, err)
+
+	_, err = Microsoft(tpmodels.MicrosoftConfig{
+		ClientID:     "test",
+		ClientSecret: "test-secret",
+	}).Get(nil, nil, nil).GetProfileInfo("unexpected", nil)
+	assert.Error(t, err)
+}
+
+func TestGoogleNativeSignInFailsIfTheUserInfoHasNoID(t *testing.T) {
+	defer gock.OffAll()
+	gock.New("https://www.googleapis.com").
+		Get("/oauth2/v3/tokeninfo").
+		Persist().
+		Reply(200).
+		JSON(map[string]interface{}{
+			"aud": "test",
+		})
+	gock.New("https://www.googleapis.com").
+		Get("/oauth2/v1/userinfo").
+		Reply(200).
+		JSON(map[string]interface{}{
+			"email":          "johndoe@gmail.com",
+			"verified_email": "yes",
+		})
+
+	providerInfo := Google(tpmodels.GoogleConfig{
+		ClientID:     "test",
+		ClientSecret: "test-secret",
+	}).Get(nil, nil, nil)
+
+	accessToken := "access-token"
+	_, err := providerInfo.GetProfileInfoFromNativeTokens(tpmodels.NativeTokens{
+		AccessToken: &accessToken,
+	}, nil)
+	assert.Error(t, err)
+
+	gock.New("https://www.googleapis.com").
+		Get("/oauth2/v1/userinfo").
+		Reply(200).
+		JSON(map[string]interface{}{
+			"id":             "user-1",
+			"email":          "johndoe@gmail.com",
+			"verified_email": "yes",
+		})
+	userInfo, err := providerInfo.GetProfileInfoFromNativeTokens(tpmodels.NativeTokens{
+		AccessToken: &accessToken,
+	}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "user-1", userInfo.ID)
+	assert.Equal(t, "johndoe@gmail.com", userInfo.Email.ID)
+	assert.False(t, userInfo.Email.IsVerified)
+}
+
+func TestMicrosoftOnlyAllowsUsersOfTheConfiguredTenant(t *testing.T) {
+	defer gock.OffAll()
+	defer EndJWKSRefresh()
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	gock.New("https://login.microsoftonline.com").
+		Get("/tenant-1/discovery/v2.0/keys").
+		Persist().
+		Reply(200).
+		JSON(map[string]interface{}{
+			"keys": []map[string]interface{}{jwkFromPrivateKey(privateKey)},
+		})
+
+	tenantID := "tenant-1"
+	providerInfo := Microsoft(tpmodels.MicrosoftConfig{
+		ClientID:     "test",
+		ClientSecret: "test-secret",
+		TenantID:     &tenantID,
+	}).Get(nil, nil, nil)
+
+	claims := jwt.MapClaims{
+		"iss":   "https://login.microsoftonline.com/tenant-1/v2.0",
+		"aud":   "test",
+		"tid":   "tenant-1",
+		"oid":   "user-1",
+		"sub":   "pairwise-sub",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"email": "johndoe@gmail.com",
+	}
+	userInfo, err := providerInfo.GetProfileInfo(map[string]interface{}{
+		"id_token": signOIDCIdToken(t, privateKey, claims),
+	}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "user-1", userInfo.ID)
+	assert.Equal(t, "johndoe@gmail.com", userInfo.Email.ID)
+	assert.False(t, userInfo.Email.IsVerified)
+
+	claims["iss"] = "https://login.microsoftonline.com/tenant-2/v2.0"
+	claims["tid"] = "tenant-2"
+	_, err = providerInfo.GetProfileInfo(map[string]interface{}{
+		"id_token": signOIDCIdToken(t, privateKey, claims),
+	}, nil)
+	assert.Error(t, err)
+}
+
+func TestMicrosoftResolvesATenantDomainToItsTenantID(t *testing.T) {
+	defer gock.OffAll()
+	defer EndJWKSRefresh()
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	gock.New("https://login.microsoftonline.com").
+		Get("/contoso.onmicrosoft.com/v2.0/.well-known/openid-configuration").
+		Reply(200).
+		JSON(map[string]interface{}{
+			"issuer": "https://login.microsoftonline.com/9188040d-6c67-4c5b-b112-36a304b66dad/v2.0",
+		})
+	gock.New("https://login.microsoftonline.com").
+		Get("/contoso.onmicrosoft.com/discovery/v2.0/keys").
+		Persist().
+		Reply(200).
+		JSON(map[string]interface{}{
+			"keys": []map[string]interface{}{jwkFromPrivateKey(privateKey)},
+		})
+
+	tenantID := "contoso.onmicrosoft.com"
+	providerInfo := Microsoft(tpmodels.MicrosoftConfig{
+		ClientID:     "test",
+		ClientSecret: "test-secret",
+		TenantID:     &tenantID,
+	}).Get(nil, nil, nil)
+
+	claims := jwt.MapClaims{
+		"iss":   "https://login.microsoftonline.com/9188040d-6c67-4c5b-b112-36a304b66dad/v2.0",
+		"aud":   "test",
+		"tid":   "9188040d-6c67-4c5b-b112-36a304b66dad",
+		"oid":   "user-1",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"email": "johndoe@contoso.com",
+	}
+	userInfo, err := providerInfo.GetProfileInfo(map[string]interface{}{
+		"id_token": signOIDCIdToken(t, privateKey, claims),
+	}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "user-1", userInfo.ID)
+
+	// the tenant ID is only fetched once
+	claims["iss"] = "https://login.microsoftonline.com/72f988bf-86f1-41af-91ab-2d7cd011db47/v2.0"
+	claims["tid"] = "72f988bf-86f1-41af-91ab-2d7cd011db47"
+	_, err = providerInfo.GetProfileInfo(map[string]interface{}{
+		"id_token": signOIDCIdToken(t, privateKey, claims),
+	}, nil)
+	assert.Error(t, err)
+	assert.False(t, gock.HasUnmatchedRequest())
+}
+
+func TestOktaUsesTheIssuerOfTheAuthorizationServer(t *testing.T) {
+	defer gock.OffAll()
+	mockOIDCIssuer(t, "https://dev-123456.okta.com/oauth2/default")
+
+	provider := Okta(tpmodels.OktaConfig{
+		ClientID:     "test",
+		ClientSecret: "test-secret",
+		OktaDomain:   "dev-123456.okta.com",
+	})
+	assert.Equal(t, "okta", provider.ID)
+
+	providerInfo := provider.Get(nil, nil, nil)
+	assert.Equal(t, "https://dev-123456.okta.com/oauth2/default/oauth2/v1/authorize", providerInfo.AuthorisationRedirect.URL)
+	assert.Equal(t, "https://dev-123456.okta.com/oauth2/default/oauth2/v1/token", providerInfo.AccessTokenAPI.URL)
+}
@@ -23,7 +23,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/MicahParks/keyfunc"
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/supertokens/supertokens-golang/recipe/thirdparty/api"
 	"github.com/supertokens/supertokens-golang/recipe/thirdparty/tpmodels"
@@ -84,30 +83,22 @@ func Apple(config tpmodels.AppleConfig) tpmodels.TypeProvider {
 					Params: authorizationRedirectParams,
 				},
 				GetProfileInfo: func(authCodeResponse interface{}, userContext supertokens.UserContext) (tpmodels.UserInfo, error) {
-					claims, err := verifyAndGetClaimsAppleIdToken(authCodeResponse.(map[string]interface{})["id_token"].(string), api.GetActualClientIdFromDevelopmentClientId(config.ClientID))
+					claims, err := verifyAndGetClaimsAppleIdToken(authCodeResponse.(map[string]interface{})["id_token"].(string), []string{api.GetActualClientIdFromDevelopmentClientId(config.ClientID)})
 					if err != nil {
 						return tpmodels.UserInfo{}, err
 					}
-
-					var email string
-					var isVerified bool
-					var id string
-					for key, val := range claims {
-						if key == "sub" {
-							id = val.(string)
-						} else if key == "email" {
-							email = val.(string)
-						} else if key == "email_verified" {
-							isVerified = val.(string) == "true"
-						}
+					return getUserInfoFromAppleClaims(claims), nil
+				},
+				GetProfileInfoFromNativeTokens: func(tokens tpmodels.NativeTokens, userContext supertokens.UserContext) (tpmodels.UserInfo, error) {
+					if tokens.IDToken == nil {
+						return tpmodels.UserInfo{}, errors.New("please provide the id token returned by Sign in with Apple")
 					}
-					return tpmodels.UserInfo{
-						ID: id,
-						Email: &tpmodels.EmailStruct{
-							ID:         email,
-							IsVerified: isVerified,
-						},
-					}, nil
+					clientIds := append([]string{api.GetActualClientIdFromDevelopmentClientId(config.ClientID)}, config.AdditionalClientIDs...)
+					claims, err := verifyAndGetClaimsAppleIdToken(*tokens.IDToken, clientIds)
+					if err != nil {
+						return tpmodels.UserInfo{}, err
+					}
+					return getUserInfoFromAppleClaims(claims), nil
 				},
 				GetClientId: func(userContext supertokens.UserContext) string {
 					return config.ClientID
@@ -167,24 +158,41 @@ func getECDSPrivateKey(privateKey string) (*ecdsa.PrivateKey, error) {
 	return ecdsaPrivateKey, nil
 }
 
-func verifyAndGetClaimsAppleIdToken(idToken string, clientId string) (jwt.MapClaims, error) {
+func getUserInfoFromAppleClaims(claims jwt.MapClaims) tpmodels.UserInfo {
+	var email string
+	var isVerified bool
+	var id string
+	for key, val := range claims {
+		if key == "sub" {
+			id = val.(string)
+		} else if key == "email" {
+			email = val.(string)
+		} else if key == "email_verified" {
+			// the id tokens given to native apps have email_verified as a bool
+			isVerified = val == "true" || val == true
+		}
+	}
+	return tpmodels.UserInfo{
+		ID: id,
+		Email: &tpmodels.EmailStruct{
+			ID:         email,
+			IsVerified: isVerified,
+		},
+		RawUserInfoFromProvider: tpmodels.RawUserInfoFromProvider{
+			FromIdTokenPayload: claims,
+		},
+	}
+}
+
+func verifyAndGetClaimsAppleIdToken(idToken string, clientIds []string) (jwt.MapClaims, error) {
 	/*
 	   - Verify the JWS E256 signature using the server’s public key
 	   - Verify that the iss field contains https://appleid.apple.com
-	   - Verify that the aud field is the developer’s client_id
+	   - Verify that the aud field is one of the developer’s client_ids
 	   - Verify that the time is earlier than the exp value of the token */
 	claims := jwt.MapClaims{}
-	// Get the JWKS URL.
-	jwksURL := "https://appleid.apple.com/auth/keys"
-
-	// Create the keyfunc options. Refresh the JWKS every hour and log errors.
-	refreshInterval := time.Hour
-	options := keyfunc.Options{
-		RefreshInterval: refreshInterval,
-	}
 
-	// Create the JWKS from the resource at the given URL.
-	jwks, err := keyfunc.Get(jwksURL, options)
+	jwks, err := getJWKS("https://appleid.apple.com/auth/keys")
 	if err != nil {
 		return claims, err
 	}
@@ -204,7 +212,7 @@ func verifyAndGetClaimsAppleIdToken(idToken string, clientId string) (jwt.MapCla
 		return claims, errors.New("invalid iss field in apple token")
 	}
 
-	if claims["aud"].(string) != clientId {
+	if !verifyAudienceIsOneOf(claims, clientIds) {
 		return claims, errors.New("the client for whom this key is for is different than the one provided")
 	}
 
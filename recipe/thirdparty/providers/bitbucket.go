@@ -0,0 +1,166 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package providers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/supertokens/supertokens-golang/recipe/thirdparty/tpmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+const bitbucketID = "bitbucket"
+
+func Bitbucket(config tpmodels.BitbucketConfig) tpmodels.TypeProvider {
+	return tpmodels.TypeProvider{
+		ID: bitbucketID,
+		Get: func(redirectURI, authCodeFromRequest *string, userContext supertokens.UserContext) tpmodels.TypeProviderGetResponse {
+			accessTokenAPIURL := "https://bitbucket.org/site/oauth2/access_token"
+			accessTokenAPIParams := map[string]string{
+				"grant_type": "authorization_code",
+			}
+			if authCodeFromRequest != nil {
+				accessTokenAPIParams["code"] = *authCodeFromRequest
+			}
+			if redirectURI != nil {
+				accessTokenAPIParams["redirect_uri"] = *redirectURI
+			}
+			// bitbucket only accepts the client credentials using basic auth
+			accessTokenAPIHeaders := map[string]string{
+				"Authorization": "Basic " + base64.StdEncoding.EncodeToString([]byte(config.ClientID+":"+config.ClientSecret)),
+			}
+
+			authorisationRedirectURL := "https://bitbucket.org/site/oauth2/authorize"
+			scopes := []string{"account", "email"}
+			if config.Scope != nil {
+				scopes = config.Scope
+			}
+
+			var additionalParams map[string]interface{} = nil
+			if config.AuthorisationRedirect != nil && config.AuthorisationRedirect.Params != nil {
+				additionalParams = config.AuthorisationRedirect.Params
+			}
+
+			authorizationRedirectParams := map[string]interface{}{
+				"scope":         strings.Join(scopes, " "),
+				"response_type": "code",
+				"client_id":     config.ClientID,
+			}
+			for key, value := range additionalParams {
+				authorizationRedirectParams[key] = value
+			}
+
+			return tpmodels.TypeProviderGetResponse{
+				AccessTokenAPI: tpmodels.AccessTokenAPI{
+					URL:     accessTokenAPIURL,
+					Params:  accessTokenAPIParams,
+					Headers: accessTokenAPIHeaders,
+				},
+				AuthorisationRedirect: tpmodels.AuthorisationRedirect{
+					URL:    authorisationRedirectURL,
+					Params: authorizationRedirectParams,
+				},
+				GetProfileInfo: func(authCodeResponse interface{}, userContext supertokens.UserContext) (tpmodels.UserInfo, error) {
+					authCodeResponseJson, err := json.Marshal(authCodeResponse)
+					if err != nil {
+						return tpmodels.UserInfo{}, err
+					}
+					var accessTokenAPIResponse bitbucketGetProfileInfoInput
+					err = json.Unmarshal(authCodeResponseJson, &accessTokenAPIResponse)
+					if err != nil {
+						return tpmodels.UserInfo{}, err
+					}
+					authHeader := "Bearer " + accessTokenAPIResponse.AccessToken
+					response, err := getBitbucketAuthRequest("https://api.bitbucket.org/2.0/user", authHeader)
+					if err != nil {
+						return tpmodels.UserInfo{}, err
+					}
+					userInfo, ok := response.(map[string]interface{})
+					if !ok {
+						return tpmodels.UserInfo{}, errors.New("invalid response from the Bitbucket user API")
+					}
+					ID, ok := userInfo["uuid"].(string)
+					if !ok || ID == "" {
+						return tpmodels.UserInfo{}, errors.New("the Bitbucket user API did not return the uuid of the user")
+					}
+					// the user API does not return emails, so we use the one marked as primary from the emails API
+					emailsInfoResponse, err := getBitbucketAuthRequest("https://api.bitbucket.org/2.0/user/emails", authHeader)
+					if err != nil {
+						return tpmodels.UserInfo{}, err
+					}
+					emailsInfoResponseMap, ok := emailsInfoResponse.(map[string]interface{})
+					if !ok {
+						return tpmodels.UserInfo{}, errors.New("invalid response from the Bitbucket emails API")
+					}
+					emailsInfo, _ := emailsInfoResponseMap["values"].([]interface{})
+					for _, info := range emailsInfo {
+						emailInfoMap, ok := info.(map[string]interface{})
+						if !ok {
+							return tpmodels.UserInfo{}, errors.New("invalid response from the Bitbucket emails API")
+						}
+						if isPrimary, _ := emailInfoMap["is_primary"].(bool); isPrimary {
+							email, ok := emailInfoMap["email"].(string)
+							if !ok || email == "" {
+								return tpmodels.UserInfo{}, errors.New("the Bitbucket emails API did not return the primary email")
+							}
+							isVerified, _ := emailInfoMap["is_confirmed"].(bool)
+							return tpmodels.UserInfo{
+								ID: ID,
+								Email: &tpmodels.EmailStruct{
+									ID:         email,
+									IsVerified: isVerified,
+								},
+								RawUserInfoFromProvider: tpmodels.RawUserInfoFromProvider{
+									FromUserInfoAPI: userInfo,
+								},
+							}, nil
+						}
+					}
+					return tpmodels.UserInfo{
+						ID: ID,
+						RawUserInfoFromProvider: tpmodels.RawUserInfoFromProvider{
+							FromUserInfoAPI: userInfo,
+						},
+					}, nil
+				},
+				GetClientId: func(userContext supertokens.UserContext) string {
+					return config.ClientID
+				},
+			}
+		},
+		IsDefault: config.IsDefault,
+	}
+}
+
+func getBitbucketAuthRequest(url string, authHeader string) (interface{}, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("Authorization", authHeader)
+	return doGetRequest(req)
+}
+
+type bitbucketGetProfileInfoInput struct {
+	AccessToken  string `json:"access_token"`
+	ExpiresIn    int    `json:"expires_in"`
+	TokenType    string `json:"token_type"`
+	RefreshToken string `json:"refresh_token"`
+}
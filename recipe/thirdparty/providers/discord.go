@@ -85,6 +85,9 @@ func Discord(config tpmodels.DiscordConfig) tpmodels.TypeProvider {
 						return tpmodels.UserInfo{
 							ID:    userInfo["id"].(string),
 							Email: nil,
+							RawUserInfoFromProvider: tpmodels.RawUserInfoFromProvider{
+								FromUserInfoAPI: userInfo,
+							},
 						}, nil
 					}
 					return tpmodels.UserInfo{
@@ -93,6 +96,9 @@ func Discord(config tpmodels.DiscordConfig) tpmodels.TypeProvider {
 							ID:         userInfo["email"].(string),
 							IsVerified: userInfo["verified"].(bool),
 						},
+						RawUserInfoFromProvider: tpmodels.RawUserInfoFromProvider{
+							FromUserInfoAPI: userInfo,
+						},
 					}, nil
 				},
 				GetClientId: func(userContext supertokens.UserContext) string {
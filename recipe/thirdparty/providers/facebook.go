@@ -84,6 +84,9 @@ func Facebook(config tpmodels.FacebookConfig) tpmodels.TypeProvider {
 					if email == "" {
 						return tpmodels.UserInfo{
 							ID: ID,
+							RawUserInfoFromProvider: tpmodels.RawUserInfoFromProvider{
+								FromUserInfoAPI: userInfo,
+							},
 						}, nil
 					}
 					isVerified := userInfo["verified_email"].(bool)
@@ -93,6 +96,9 @@ func Facebook(config tpmodels.FacebookConfig) tpmodels.TypeProvider {
 							ID:         email,
 							IsVerified: isVerified,
 						},
+						RawUserInfoFromProvider: tpmodels.RawUserInfoFromProvider{
+							FromUserInfoAPI: userInfo,
+						},
 					}, nil
 				},
 				GetClientId: func(userContext supertokens.UserContext) string {
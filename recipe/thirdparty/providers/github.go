@@ -108,6 +108,9 @@ func Github(config tpmodels.GithubConfig) tpmodels.TypeProvider {
 					if emailInfo == nil {
 						return tpmodels.UserInfo{
 							ID: ID,
+							RawUserInfoFromProvider: tpmodels.RawUserInfoFromProvider{
+								FromUserInfoAPI: userInfo,
+							},
 						}, nil
 					}
 					isVerified := false
@@ -120,6 +123,9 @@ func Github(config tpmodels.GithubConfig) tpmodels.TypeProvider {
 							ID:         emailInfo["email"].(string),
 							IsVerified: isVerified,
 						},
+						RawUserInfoFromProvider: tpmodels.RawUserInfoFromProvider{
+							FromUserInfoAPI: userInfo,
+						},
 					}, nil
 				},
 				GetClientId: func(userContext supertokens.UserContext) string {
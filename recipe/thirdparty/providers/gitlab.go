@@ -0,0 +1,152 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package providers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/supertokens/supertokens-golang/recipe/thirdparty/tpmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+const gitlabID = "gitlab"
+
+func Gitlab(config tpmodels.GitlabConfig) tpmodels.TypeProvider {
+	baseURL := "https://gitlab.com"
+	if config.BaseURL != nil {
+		baseURL = strings.TrimSuffix(*config.BaseURL, "/")
+	}
+
+	return tpmodels.TypeProvider{
+		ID: gitlabID,
+		Get: func(redirectURI, authCodeFromRequest *string, userContext supertokens.UserContext) tpmodels.TypeProviderGetResponse {
+			accessTokenAPIURL := baseURL + "/oauth/token"
+			accessTokenAPIParams := map[string]string{
+				"client_id":     config.ClientID,
+				"client_secret": config.ClientSecret,
+				"grant_type":    "authorization_code",
+			}
+			if authCodeFromRequest != nil {
+				accessTokenAPIParams["code"] = *authCodeFromRequest
+			}
+			if redirectURI != nil {
+				accessTokenAPIParams["redirect_uri"] = *redirectURI
+			}
+
+			authorisationRedirectURL := baseURL + "/oauth/authorize"
+			scopes := []string{"read_user"}
+			if config.Scope != nil {
+				scopes = config.Scope
+			}
+
+			var additionalParams map[string]interface{} = nil
+			if config.AuthorisationRedirect != nil && config.AuthorisationRedirect.Params != nil {
+				additionalParams = config.AuthorisationRedirect.Params
+			}
+
+			authorizationRedirectParams := map[string]interface{}{
+				"scope":         strings.Join(scopes, " "),
+				"response_type": "code",
+				"client_id":     config.ClientID,
+			}
+			for key, value := range additionalParams {
+				authorizationRedirectParams[key] = value
+			}
+
+			return tpmodels.TypeProviderGetResponse{
+				AccessTokenAPI: tpmodels.AccessTokenAPI{
+					URL:    accessTokenAPIURL,
+					Params: accessTokenAPIParams,
+				},
+				AuthorisationRedirect: tpmodels.AuthorisationRedirect{
+					URL:    authorisationRedirectURL,
+					Params: authorizationRedirectParams,
+				},
+				GetProfileInfo: func(authCodeResponse interface{}, userContext supertokens.UserContext) (tpmodels.UserInfo, error) {
+					authCodeResponseJson, err := json.Marshal(authCodeResponse)
+					if err != nil {
+						return tpmodels.UserInfo{}, err
+					}
+					var accessTokenAPIResponse gitlabGetProfileInfoInput
+					err = json.Unmarshal(authCodeResponseJson, &accessTokenAPIResponse)
+					if err != nil {
+						return tpmodels.UserInfo{}, err
+					}
+					authHeader := "Bearer " + accessTokenAPIResponse.AccessToken
+					response, err := getGitlabAuthRequest(baseURL, authHeader)
+					if err != nil {
+						return tpmodels.UserInfo{}, err
+					}
+					userInfo, ok := response.(map[string]interface{})
+					if !ok {
+						return tpmodels.UserInfo{}, errors.New("invalid response from the GitLab user API")
+					}
+					gitlabID, ok := userInfo["id"].(float64) // gitlab userId will be a number
+					if !ok {
+						return tpmodels.UserInfo{}, errors.New("the GitLab user API did not return the id of the user")
+					}
+					ID := fmt.Sprintf("%.0f", gitlabID)
+					email, _ := userInfo["email"].(string)
+					if email == "" {
+						return tpmodels.UserInfo{
+							ID: ID,
+							RawUserInfoFromProvider: tpmodels.RawUserInfoFromProvider{
+								FromUserInfoAPI: userInfo,
+							},
+						}, nil
+					}
+					// the primary email of a gitlab user can only be used once it is confirmed
+					isVerified := userInfo["confirmed_at"] != nil
+					return tpmodels.UserInfo{
+						ID: ID,
+						Email: &tpmodels.EmailStruct{
+							ID:         email,
+							IsVerified: isVerified,
+						},
+						RawUserInfoFromProvider: tpmodels.RawUserInfoFromProvider{
+							FromUserInfoAPI: userInfo,
+						},
+					}, nil
+				},
+				GetClientId: func(userContext supertokens.UserContext) string {
+					return config.ClientID
+				},
+			}
+		},
+		IsDefault: config.IsDefault,
+	}
+}
+
+func getGitlabAuthRequest(baseURL string, authHeader string) (interface{}, error) {
+	url := baseURL + "/api/v4/user"
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("Authorization", authHeader)
+	return doGetRequest(req)
+}
+
+type gitlabGetProfileInfoInput struct {
+	AccessToken  string `json:"access_token"`
+	ExpiresIn    int    `json:"expires_in"`
+	TokenType    string `json:"token_type"`
+	RefreshToken string `json:"refresh_token"`
+}
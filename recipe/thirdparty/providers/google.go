@@ -17,10 +17,13 @@ package providers
 
 import (
 	"encoding/json"
+	"errors"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"strings"
 
+	"github.com/supertokens/supertokens-golang/recipe/thirdparty/api"
 	"github.com/supertokens/supertokens-golang/recipe/thirdparty/tpmodels"
 	"github.com/supertokens/supertokens-golang/supertokens"
 )
@@ -85,28 +88,47 @@ func Google(config tpmodels.GoogleConfig) tpmodels.TypeProvider {
 					if err != nil {
 						return tpmodels.UserInfo{}, err
 					}
-					accessToken := accessTokenAPIResponse.AccessToken
-					authHeader := "Bearer " + accessToken
-					response, err := getGoogleAuthRequest(authHeader)
-					if err != nil {
-						return tpmodels.UserInfo{}, err
-					}
-					userInfo := response.(map[string]interface{})
-					ID := userInfo["id"].(string)
-					email := userInfo["email"].(string)
-					if email == "" {
-						return tpmodels.UserInfo{
+					return getGoogleUserInfo(accessTokenAPIResponse.AccessToken)
+				},
+				GetProfileInfoFromNativeTokens: func(tokens tpmodels.NativeTokens, userContext supertokens.UserContext) (tpmodels.UserInfo, error) {
+					clientIds := append([]string{api.GetActualClientIdFromDevelopmentClientId(config.ClientID)}, config.AdditionalClientIDs...)
+					if tokens.IDToken != nil {
+						claims, err := verifyAndGetClaims(*tokens.IDToken, clientIds)
+						if err != nil {
+							return tpmodels.UserInfo{}, err
+						}
+						ID, _ := claims["sub"].(string)
+						if ID == "" {
+							return tpmodels.UserInfo{}, errors.New("the sub claim is missing from the id_token")
+						}
+						email, _ := claims["email"].(string)
+						isVerified, _ := claims["email_verified"].(bool)
+						userInfo := tpmodels.UserInfo{
 							ID: ID,
-						}, nil
+							RawUserInfoFromProvider: tpmodels.RawUserInfoFromProvider{
+								FromIdTokenPayload: claims,
+							},
+						}
+						if email != "" {
+							userInfo.Email = &tpmodels.EmailStruct{
+								ID:         email,
+								IsVerified: isVerified,
+							}
+						}
+						return userInfo, nil
+					}
+					if tokens.AccessToken != nil {
+						// the user info API accepts access tokens issued to any app, so we first check who it was issued to
+						tokenInfo, err := getGoogleTokenInfo(*tokens.AccessToken)
+						if err != nil {
+							return tpmodels.UserInfo{}, err
+						}
+						if !verifyAudienceIsOneOf(tokenInfo, clientIds) {
+							return tpmodels.UserInfo{}, errors.New("the client for whom this access token is for is different than the one provided")
+						}
+						return getGoogleUserInfo(*tokens.AccessToken)
 					}
-					isVerified := userInfo["verified_email"].(bool)
-					return tpmodels.UserInfo{
-						ID: ID,
-						Email: &tpmodels.EmailStruct{
-							ID:         email,
-							IsVerified: isVerified,
-						},
-					}, nil
+					return tpmodels.UserInfo{}, errors.New("please provide the id token or access token returned by Google Sign-In")
 				},
 				GetClientId: func(userContext supertokens.UserContext) string {
 					return config.ClientID
@@ -117,6 +139,61 @@ func Google(config tpmodels.GoogleConfig) tpmodels.TypeProvider {
 	}
 }
 
+func getGoogleUserInfo(accessToken string) (tpmodels.UserInfo, error) {
+	authHeader := "Bearer " + accessToken
+	response, err := getGoogleAuthRequest(authHeader)
+	if err != nil {
+		return tpmodels.UserInfo{}, err
+	}
+	userInfo, ok := response.(map[string]interface{})
+	if !ok {
+		return tpmodels.UserInfo{}, errors.New("invalid response from the Google userinfo endpoint")
+	}
+	ID, ok := userInfo["id"].(string)
+	if !ok || ID == "" {
+		return tpmodels.UserInfo{}, errors.New("no user ID in the response from the Google userinfo endpoint")
+	}
+	email, _ := userInfo["email"].(string)
+	if email == "" {
+		return tpmodels.UserInfo{
+			ID: ID,
+			RawUserInfoFromProvider: tpmodels.RawUserInfoFromProvider{
+				FromUserInfoAPI: userInfo,
+			},
+		}, nil
+	}
+	isVerified, _ := userInfo["verified_email"].(bool)
+	return tpmodels.UserInfo{
+		ID: ID,
+		Email: &tpmodels.EmailStruct{
+			ID:         email,
+			IsVerified: isVerified,
+		},
+		RawUserInfoFromProvider: tpmodels.RawUserInfoFromProvider{
+			FromUserInfoAPI: userInfo,
+		},
+	}, nil
+}
+
+func getGoogleTokenInfo(accessToken string) (map[string]interface{}, error) {
+	req, err := http.NewRequest("GET", "https://www.googleapis.com/oauth2/v3/tokeninfo?access_token="+url.QueryEscape(accessToken), nil)
+	if err != nil {
+		return nil, err
+	}
+	response, err := doGetRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	tokenInfo, ok := response.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("invalid response from the tokeninfo endpoint")
+	}
+	if errorDescription, ok := tokenInfo["error_description"].(string); ok {
+		return nil, errors.New(errorDescription)
+	}
+	return tokenInfo, nil
+}
+
 func getGoogleAuthRequest(authHeader string) (interface{}, error) {
 	url := "https://www.googleapis.com/oauth2/v1/userinfo?alt=json"
 	req, err := http.NewRequest("GET", url, nil)
@@ -18,9 +18,7 @@ package providers
 import (
 	"errors"
 	"strings"
-	"time"
 
-	"github.com/MicahParks/keyfunc"
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/supertokens/supertokens-golang/recipe/thirdparty/api"
 	"github.com/supertokens/supertokens-golang/recipe/thirdparty/tpmodels"
@@ -85,46 +83,22 @@ func GoogleWorkspaces(config tpmodels.GoogleWorkspacesConfig) tpmodels.TypeProvi
 					Params: authorizationRedirectParams,
 				},
 				GetProfileInfo: func(authCodeResponse interface{}, userContext supertokens.UserContext) (tpmodels.UserInfo, error) {
-					claims, err := verifyAndGetClaims(authCodeResponse.(map[string]interface{})["id_token"].(string), api.GetActualClientIdFromDevelopmentClientId(config.ClientID))
+					claims, err := verifyAndGetClaims(authCodeResponse.(map[string]interface{})["id_token"].(string), []string{api.GetActualClientIdFromDevelopmentClientId(config.ClientID)})
 					if err != nil {
 						return tpmodels.UserInfo{}, err
 					}
-
-					var email string
-					var isVerified bool
-					var id string
-					var hd string
-					for key, val := range claims {
-						if key == "sub" {
-							id = val.(string)
-						} else if key == "email" {
-							email = val.(string)
-						} else if key == "email_verified" {
-							isVerified = val.(bool)
-						} else if key == "hd" {
-							hd = val.(string)
-						}
-					}
-
-					if email == "" {
-						return tpmodels.UserInfo{}, errors.New("Could not get email. Please use a different login method")
-					}
-
-					if hd == "" {
-						return tpmodels.UserInfo{}, errors.New("Please use a Google Workspace ID to login")
+					return getUserInfoFromGoogleWorkspacesClaims(claims, domain)
+				},
+				GetProfileInfoFromNativeTokens: func(tokens tpmodels.NativeTokens, userContext supertokens.UserContext) (tpmodels.UserInfo, error) {
+					if tokens.IDToken == nil {
+						return tpmodels.UserInfo{}, errors.New("please provide the id token returned by Google Sign-In")
 					}
-
-					if !strings.Contains(domain, "*") && hd != domain {
-						return tpmodels.UserInfo{}, errors.New("Please use emails from " + domain + " to login")
+					clientIds := append([]string{api.GetActualClientIdFromDevelopmentClientId(config.ClientID)}, config.AdditionalClientIDs...)
+					claims, err := verifyAndGetClaims(*tokens.IDToken, clientIds)
+					if err != nil {
+						return tpmodels.UserInfo{}, err
 					}
-
-					return tpmodels.UserInfo{
-						ID: id,
-						Email: &tpmodels.EmailStruct{
-							ID:         email,
-							IsVerified: isVerified,
-						},
-					}, nil
+					return getUserInfoFromGoogleWorkspacesClaims(claims, domain)
 				},
 				GetClientId: func(userContext supertokens.UserContext) string {
 					return config.ClientID
@@ -135,19 +109,51 @@ func GoogleWorkspaces(config tpmodels.GoogleWorkspacesConfig) tpmodels.TypeProvi
 	}
 }
 
-func verifyAndGetClaims(idToken string, clientId string) (jwt.MapClaims, error) {
-	claims := jwt.MapClaims{}
-	// Get the JWKS URL.
-	jwksURL := "https://www.googleapis.com/oauth2/v3/certs"
+func getUserInfoFromGoogleWorkspacesClaims(claims jwt.MapClaims, domain string) (tpmodels.UserInfo, error) {
+	var email string
+	var isVerified bool
+	var id string
+	var hd string
+	for key, val := range claims {
+		if key == "sub" {
+			id = val.(string)
+		} else if key == "email" {
+			email = val.(string)
+		} else if key == "email_verified" {
+			isVerified = val.(bool)
+		} else if key == "hd" {
+			hd = val.(string)
+		}
+	}
+
+	if email == "" {
+		return tpmodels.UserInfo{}, errors.New("Could not get email. Please use a different login method")
+	}
 
-	// Create the keyfunc options. Refresh the JWKS every hour and log errors.
-	refreshInterval := time.Hour
-	options := keyfunc.Options{
-		RefreshInterval: refreshInterval,
+	if hd == "" {
+		return tpmodels.UserInfo{}, errors.New("Please use a Google Workspace ID to login")
 	}
 
-	// Create the JWKS from the resource at the given URL.
-	jwks, err := keyfunc.Get(jwksURL, options)
+	if !strings.Contains(domain, "*") && hd != domain {
+		return tpmodels.UserInfo{}, errors.New("Please use emails from " + domain + " to login")
+	}
+
+	return tpmodels.UserInfo{
+		ID: id,
+		Email: &tpmodels.EmailStruct{
+			ID:         email,
+			IsVerified: isVerified,
+		},
+		RawUserInfoFromProvider: tpmodels.RawUserInfoFromProvider{
+			FromIdTokenPayload: claims,
+		},
+	}, nil
+}
+
+func verifyAndGetClaims(idToken string, clientIds []string) (jwt.MapClaims, error) {
+	claims := jwt.MapClaims{}
+
+	jwks, err := getJWKS("https://www.googleapis.com/oauth2/v3/certs")
 	if err != nil {
 		return claims, err
 	}
@@ -167,7 +173,7 @@ func verifyAndGetClaims(idToken string, clientId string) (jwt.MapClaims, error)
 		return claims, errors.New("invalid iss field")
 	}
 
-	if claims["aud"].(string) != clientId {
+	if !verifyAudienceIsOneOf(claims, clientIds) {
 		return claims, errors.New("the client for whom this key is for is different than the one provided")
 	}
 
@@ -0,0 +1,71 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package providers
+
+import (
+	"sync"
+	"time"
+
+	"github.com/MicahParks/keyfunc"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+const (
+	jwksRefreshInterval = time.Hour
+	// a token with an unknown kid causes a refetch, but not more often than this
+	jwksRefreshRateLimit = 5 * time.Minute
+)
+
+var (
+	jwksCacheLock sync.Mutex
+	jwksCache     = map[string]*keyfunc.JWKS{}
+)
+
+// getJWKS returns the keys at jwksURL. They are fetched once per process and then refreshed in the background.
+func getJWKS(jwksURL string) (*keyfunc.JWKS, error) {
+	jwksCacheLock.Lock()
+	defer jwksCacheLock.Unlock()
+
+	if jwks, ok := jwksCache[jwksURL]; ok {
+		return jwks, nil
+	}
+
+	jwks, err := keyfunc.Get(jwksURL, keyfunc.Options{
+		RefreshInterval:   jwksRefreshInterval,
+		RefreshRateLimit:  jwksRefreshRateLimit,
+		RefreshUnknownKID: true,
+		RefreshErrorHandler: func(err error) {
+			supertokens.LogDebugMessage("could not refresh the JWKS at " + jwksURL + ": " + err.Error())
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	jwksCache[jwksURL] = jwks
+	return jwks, nil
+}
+
+// EndJWKSRefresh stops the background refresh of all the cached JWKS and clears the cache.
+// The keys are fetched again the next time they are needed.
+func EndJWKSRefresh() {
+	jwksCacheLock.Lock()
+	defer jwksCacheLock.Unlock()
+
+	for jwksURL, jwks := range jwksCache {
+		jwks.EndBackground()
+		delete(jwksCache, jwksURL)
+	}
+}
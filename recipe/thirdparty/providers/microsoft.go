@@ -0,0 +1,227 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package providers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/supertokens/supertokens-golang/recipe/thirdparty/api"
+	"github.com/supertokens/supertokens-golang/recipe/thirdparty/tpmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+const microsoftID = "microsoft"
+
+func Microsoft(config tpmodels.MicrosoftConfig) tpmodels.TypeProvider {
+	tenantID := "common"
+	if config.TenantID != nil {
+		tenantID = *config.TenantID
+	}
+	baseURL := "https://login.microsoftonline.com/" + tenantID
+
+	var (
+		lock sync.Mutex
+		// the tid claim is always the GUID of the tenant, even if the tenant is configured using one of its domains
+		tenantGUID *string
+	)
+	getTenantGUID := func() (string, error) {
+		lock.Lock()
+		defer lock.Unlock()
+		if tenantGUID != nil {
+			return *tenantGUID, nil
+		}
+		guid := tenantID
+		if strings.Contains(tenantID, ".") {
+			resolved, err := fetchMicrosoftTenantGUID(baseURL)
+			if err != nil {
+				return "", err
+			}
+			guid = resolved
+		}
+		tenantGUID = &guid
+		return guid, nil
+	}
+
+	return tpmodels.TypeProvider{
+		ID: microsoftID,
+		Get: func(redirectURI, authCodeFromRequest *string, userContext supertokens.UserContext) tpmodels.TypeProviderGetResponse {
+			accessTokenAPIURL := baseURL + "/oauth2/v2.0/token"
+			accessTokenAPIParams := map[string]string{
+				"client_id":     config.ClientID,
+				"client_secret": config.ClientSecret,
+				"grant_type":    "authorization_code",
+			}
+			if authCodeFromRequest != nil {
+				accessTokenAPIParams["code"] = *authCodeFromRequest
+			}
+			if redirectURI != nil {
+				accessTokenAPIParams["redirect_uri"] = *redirectURI
+			}
+
+			authorisationRedirectURL := baseURL + "/oauth2/v2.0/authorize"
+			scopes := []string{"openid", "email", "profile"}
+			if config.Scope != nil {
+				scopes = config.Scope
+			}
+
+			var additionalParams map[string]interface{} = nil
+			if config.AuthorisationRedirect != nil && config.AuthorisationRedirect.Params != nil {
+				additionalParams = config.AuthorisationRedirect.Params
+			}
+
+			authorizationRedirectParams := map[string]interface{}{
+				"scope":         strings.Join(scopes, " "),
+				"response_type": "code",
+				"response_mode": "query",
+				"client_id":     config.ClientID,
+			}
+			for key, value := range additionalParams {
+				authorizationRedirectParams[key] = value
+			}
+
+			return tpmodels.TypeProviderGetResponse{
+				AccessTokenAPI: tpmodels.AccessTokenAPI{
+					URL:    accessTokenAPIURL,
+					Params: accessTokenAPIParams,
+				},
+				AuthorisationRedirect: tpmodels.AuthorisationRedirect{
+					URL:    authorisationRedirectURL,
+					Params: authorizationRedirectParams,
+				},
+				GetProfileInfo: func(authCodeResponse interface{}, userContext supertokens.UserContext) (tpmodels.UserInfo, error) {
+					authCodeResponseMap, ok := authCodeResponse.(map[string]interface{})
+					if !ok {
+						return tpmodels.UserInfo{}, errors.New("invalid response from the Microsoft token endpoint")
+					}
+					idToken, ok := authCodeResponseMap["id_token"].(string)
+					if !ok {
+						return tpmodels.UserInfo{}, errors.New("no id_token in the response from Microsoft")
+					}
+					expectedTenantID := ""
+					if tenantID != "common" && tenantID != "organizations" && tenantID != "consumers" {
+						guid, err := getTenantGUID()
+						if err != nil {
+							return tpmodels.UserInfo{}, err
+						}
+						expectedTenantID = guid
+					}
+					claims, err := verifyAndGetClaimsMicrosoftIdToken(idToken, baseURL+"/discovery/v2.0/keys", expectedTenantID, api.GetActualClientIdFromDevelopmentClientId(config.ClientID))
+					if err != nil {
+						return tpmodels.UserInfo{}, err
+					}
+					// oid identifies the user across all the apps of a tenant, unlike sub
+					ID, _ := claims["oid"].(string)
+					if ID == "" {
+						ID, _ = claims["sub"].(string)
+					}
+					if ID == "" {
+						return tpmodels.UserInfo{}, errors.New("the id_token from Microsoft does not have an oid or sub claim")
+					}
+					email, _ := claims["email"].(string)
+					if email == "" {
+						return tpmodels.UserInfo{
+							ID: ID,
+							RawUserInfoFromProvider: tpmodels.RawUserInfoFromProvider{
+								FromIdTokenPayload: claims,
+							},
+						}, nil
+					}
+					// Microsoft does not verify the email claim, it can be set to any value by the tenant admin
+					return tpmodels.UserInfo{
+						ID: ID,
+						Email: &tpmodels.EmailStruct{
+							ID:         email,
+							IsVerified: false,
+						},
+						RawUserInfoFromProvider: tpmodels.RawUserInfoFromProvider{
+							FromIdTokenPayload: claims,
+						},
+					}, nil
+				},
+				GetClientId: func(userContext supertokens.UserContext) string {
+					return config.ClientID
+				},
+			}
+		},
+		IsDefault: config.IsDefault,
+	}
+}
+
+// fetchMicrosoftTenantGUID reads the GUID of a tenant from the issuer in its OpenID discovery document,
+// which has the form https://login.microsoftonline.com/{tenant GUID}/v2.0
+func fetchMicrosoftTenantGUID(baseURL string) (string, error) {
+	req, err := http.NewRequest("GET", baseURL+"/v2.0/.well-known/openid-configuration", nil)
+	if err != nil {
+		return "", err
+	}
+	response, err := doGetRequest(req)
+	if err != nil {
+		return "", errors.New("could not fetch the OpenID discovery document of the Microsoft tenant: " + err.Error())
+	}
+	document, ok := response.(map[string]interface{})
+	if !ok {
+		return "", errors.New("invalid OpenID discovery document of the Microsoft tenant")
+	}
+	issuer, _ := document["issuer"].(string)
+	guid := strings.TrimSuffix(strings.TrimPrefix(issuer, "https://login.microsoftonline.com/"), "/v2.0")
+	if guid == "" || guid == issuer || strings.Contains(guid, "/") {
+		return "", errors.New("unexpected issuer in the OpenID discovery document of the Microsoft tenant")
+	}
+	return guid, nil
+}
+
+// verifyAndGetClaimsMicrosoftIdToken checks that the token belongs to tenantID, unless it is empty
+func verifyAndGetClaimsMicrosoftIdToken(idToken string, jwksURL string, tenantID string, clientId string) (jwt.MapClaims, error) {
+	claims := jwt.MapClaims{}
+
+	jwks, err := getJWKS(jwksURL)
+	if err != nil {
+		return claims, err
+	}
+
+	// Parse the JWT.
+	token, err := jwt.ParseWithClaims(idToken, claims, jwks.Keyfunc)
+	if err != nil {
+		return claims, err
+	}
+
+	// Check if the token is valid.
+	if !token.Valid {
+		return claims, errors.New("invalid id_token supplied")
+	}
+
+	// for the multi tenant endpoints, the issuer contains the tenant of the user
+	tokenTenantID, _ := claims["tid"].(string)
+	if tokenTenantID == "" {
+		return claims, errors.New("id_token does not have a tid claim")
+	}
+	if tenantID != "" && tokenTenantID != tenantID {
+		return claims, errors.New("the user does not belong to the configured tenant")
+	}
+	if !claims.VerifyIssuer("https://login.microsoftonline.com/"+tokenTenantID+"/v2.0", true) {
+		return claims, errors.New("invalid iss field")
+	}
+
+	if !claims.VerifyAudience(clientId, true) {
+		return claims, errors.New("the client for whom this key is for is different than the one provided")
+	}
+
+	return claims, nil
+}
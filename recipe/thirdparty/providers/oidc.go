@@ -0,0 +1,354 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package providers
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/MicahParks/keyfunc"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/supertokens/supertokens-golang/recipe/thirdparty/api"
+	"github.com/supertokens/supertokens-golang/recipe/thirdparty/tpmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+type oidcDiscoveryDocument struct {
+	Issuer                string
+	AuthorizationEndpoint string
+	TokenEndpoint         string
+	UserInfoEndpoint      string
+	JwksURI               string
+}
+
+func OIDC(config tpmodels.OIDCConfig) tpmodels.TypeProvider {
+	claimMapping := tpmodels.OIDCClaimMapping{
+		ID:            "sub",
+		Email:         "email",
+		EmailVerified: "email_verified",
+	}
+	if config.ClaimMapping != nil {
+		if config.ClaimMapping.ID != "" {
+			claimMapping.ID = config.ClaimMapping.ID
+		}
+		if config.ClaimMapping.Email != "" {
+			claimMapping.Email = config.ClaimMapping.Email
+		}
+		if config.ClaimMapping.EmailVerified != "" {
+			claimMapping.EmailVerified = config.ClaimMapping.EmailVerified
+		}
+	}
+
+	var (
+		lock      sync.Mutex
+		discovery *oidcDiscoveryDocument
+	)
+
+	getDiscoveryDocument := func() (oidcDiscoveryDocument, error) {
+		lock.Lock()
+		defer lock.Unlock()
+		if discovery != nil {
+			return *discovery, nil
+		}
+		document := oidcDiscoveryDocument{Issuer: config.Issuer}
+		if config.AuthorizationEndpoint == nil || config.TokenEndpoint == nil || config.JwksURI == nil {
+			fetched, err := fetchOIDCDiscoveryDocument(config.Issuer)
+			if err != nil {
+				return oidcDiscoveryDocument{}, err
+			}
+			document = fetched
+		}
+		if config.AuthorizationEndpoint != nil {
+			document.AuthorizationEndpoint = *config.AuthorizationEndpoint
+		}
+		if config.TokenEndpoint != nil {
+			document.TokenEndpoint = *config.TokenEndpoint
+		}
+		if config.UserInfoEndpoint != nil {
+			document.UserInfoEndpoint = *config.UserInfoEndpoint
+		}
+		if config.JwksURI != nil {
+			document.JwksURI = *config.JwksURI
+		}
+		discovery = &document
+		return document, nil
+	}
+
+	return tpmodels.TypeProvider{
+		ID: config.ThirdPartyID,
+		Get: func(redirectURI, authCodeFromRequest *string, userContext supertokens.UserContext) tpmodels.TypeProviderGetResponse {
+			document, err := getDiscoveryDocument()
+			if err != nil {
+				return tpmodels.TypeProviderGetResponse{
+					Error: errors.New("could not fetch the OpenID discovery document of " + config.ThirdPartyID + ": " + err.Error()),
+				}
+			}
+
+			accessTokenAPIParams := map[string]string{
+				"client_id":     config.ClientID,
+				"client_secret": config.ClientSecret,
+				"grant_type":    "authorization_code",
+			}
+			if authCodeFromRequest != nil {
+				accessTokenAPIParams["code"] = *authCodeFromRequest
+			}
+			if redirectURI != nil {
+				accessTokenAPIParams["redirect_uri"] = *redirectURI
+			}
+
+			scopes := []string{"openid", "email", "profile"}
+			if config.Scope != nil {
+				scopes = config.Scope
+			}
+
+			var additionalParams map[string]interface{} = nil
+			if config.AuthorisationRedirect != nil && config.AuthorisationRedirect.Params != nil {
+				additionalParams = config.AuthorisationRedirect.Params
+			}
+
+			authorizationRedirectParams := map[string]interface{}{
+				"scope":         strings.Join(scopes, " "),
+				"response_type": "code",
+				"client_id":     config.ClientID,
+			}
+			for key, value := range additionalParams {
+				authorizationRedirectParams[key] = value
+			}
+
+			return tpmodels.TypeProviderGetResponse{
+				AccessTokenAPI: tpmodels.AccessTokenAPI{
+					URL:    document.TokenEndpoint,
+					Params: accessTokenAPIParams,
+				},
+				AuthorisationRedirect: tpmodels.AuthorisationRedirect{
+					URL:    document.AuthorizationEndpoint,
+					Params: authorizationRedirectParams,
+				},
+				GetProfileInfo: func(authCodeResponse interface{}, userContext supertokens.UserContext) (tpmodels.UserInfo, error) {
+					authCodeResponseMap, ok := authCodeResponse.(map[string]interface{})
+					if !ok {
+						return tpmodels.UserInfo{}, errors.New("invalid response from the token endpoint")
+					}
+					idToken, ok := authCodeResponseMap["id_token"].(string)
+					if !ok {
+						return tpmodels.UserInfo{}, errors.New("no id_token in the response from the token endpoint")
+					}
+					keys, err := getJWKS(document.JwksURI)
+					if err != nil {
+						return tpmodels.UserInfo{}, err
+					}
+					claims, err := verifyAndGetClaimsOIDCIdToken(idToken, keys, document.Issuer, []string{api.GetActualClientIdFromDevelopmentClientId(config.ClientID)})
+					if err != nil {
+						return tpmodels.UserInfo{}, err
+					}
+					// the nonce is saved with the OAuth state, which SignInUpPOST has already consumed
+					var expectedNonce string
+					if userContext != nil {
+						expectedNonce, _ = (*userContext)[api.OIDCNonceUserContextKey].(string)
+					}
+					if expectedNonce == "" {
+						return tpmodels.UserInfo{}, errors.New("no nonce was found for this sign in. Please enable StateAndPKCE in the SignInAndUpFeature config")
+					}
+					nonce, _ := claims["nonce"].(string)
+					if subtle.ConstantTimeCompare([]byte(nonce), []byte(expectedNonce)) != 1 {
+						return tpmodels.UserInfo{}, errors.New("invalid nonce in id_token")
+					}
+
+					accessToken, _ := authCodeResponseMap["access_token"].(string)
+					return getUserInfoFromOIDCTokens(claims, accessToken, document.UserInfoEndpoint, claimMapping)
+				},
+				GetProfileInfoFromNativeTokens: func(tokens tpmodels.NativeTokens, userContext supertokens.UserContext) (tpmodels.UserInfo, error) {
+					// the id token is needed to check which client the tokens were issued to
+					if tokens.IDToken == nil {
+						return tpmodels.UserInfo{}, errors.New("please provide the id token returned by " + config.ThirdPartyID)
+					}
+					keys, err := getJWKS(document.JwksURI)
+					if err != nil {
+						return tpmodels.UserInfo{}, err
+					}
+					clientIds := append([]string{api.GetActualClientIdFromDevelopmentClientId(config.ClientID)}, config.AdditionalClientIDs...)
+					claims, err := verifyAndGetClaimsOIDCIdToken(*tokens.IDToken, keys, document.Issuer, clientIds)
+					if err != nil {
+						return tpmodels.UserInfo{}, err
+					}
+					// the nonce of native sign ins is generated by the app, not by us
+					accessToken := ""
+					if tokens.AccessToken != nil {
+						accessToken = *tokens.AccessToken
+					}
+					return getUserInfoFromOIDCTokens(claims, accessToken, document.UserInfoEndpoint, claimMapping)
+				},
+				GetClientId: func(userContext supertokens.UserContext) string {
+					return config.ClientID
+				},
+				RequiresNonce: true,
+			}
+		},
+		IsDefault: config.IsDefault,
+	}
+}
+
+func fetchOIDCDiscoveryDocument(issuer string) (oidcDiscoveryDocument, error) {
+	req, err := http.NewRequest("GET", strings.TrimSuffix(issuer, "/")+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return oidcDiscoveryDocument{}, err
+	}
+	response, err := doGetRequest(req)
+	if err != nil {
+		return oidcDiscoveryDocument{}, err
+	}
+	document, ok := response.(map[string]interface{})
+	if !ok {
+		return oidcDiscoveryDocument{}, errors.New("invalid OpenID discovery document")
+	}
+	result := oidcDiscoveryDocument{}
+	result.Issuer, _ = document["issuer"].(string)
+	result.AuthorizationEndpoint, _ = document["authorization_endpoint"].(string)
+	result.TokenEndpoint, _ = document["token_endpoint"].(string)
+	result.UserInfoEndpoint, _ = document["userinfo_endpoint"].(string)
+	result.JwksURI, _ = document["jwks_uri"].(string)
+	if result.Issuer != issuer {
+		return oidcDiscoveryDocument{}, errors.New("the issuer in the OpenID discovery document does not match the configured issuer")
+	}
+	if result.AuthorizationEndpoint == "" || result.TokenEndpoint == "" || result.JwksURI == "" {
+		return oidcDiscoveryDocument{}, errors.New("the OpenID discovery document is missing required endpoints")
+	}
+	return result, nil
+}
+
+func getOIDCUserInfo(userInfoEndpoint string, accessToken string) (map[string]interface{}, error) {
+	req, err := http.NewRequest("GET", userInfoEndpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("Authorization", "Bearer "+accessToken)
+	response, err := doGetRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	userInfo, ok := response.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("invalid response from the userinfo endpoint")
+	}
+	return userInfo, nil
+}
+
+func verifyAndGetClaimsOIDCIdToken(idToken string, jwks *keyfunc.JWKS, issuer string, clientIds []string) (jwt.MapClaims, error) {
+	claims := jwt.MapClaims{}
+
+	// Parse the JWT. This also checks the exp, iat and nbf claims.
+	token, err := jwt.ParseWithClaims(idToken, claims, jwks.Keyfunc)
+	if err != nil {
+		return claims, err
+	}
+
+	// Check if the token is valid.
+	if !token.Valid {
+		return claims, errors.New("invalid id_token supplied")
+	}
+
+	if _, ok := claims["exp"]; !ok {
+		return claims, errors.New("id_token does not have an exp claim")
+	}
+
+	if !claims.VerifyIssuer(issuer, true) {
+		return claims, errors.New("invalid iss field")
+	}
+
+	if !verifyAudienceIsOneOf(claims, clientIds) {
+		return claims, errors.New("the client for whom this key is for is different than the one provided")
+	}
+
+	return claims, nil
+}
+
+// getUserInfoFromOIDCTokens maps the claims of a verified id token to UserInfo, using the userinfo endpoint
+// for the claims that are not in the id token
+func getUserInfoFromOIDCTokens(claims jwt.MapClaims, accessToken string, userInfoEndpoint string, claimMapping tpmodels.OIDCClaimMapping) (tpmodels.UserInfo, error) {
+	idTokenPayload := map[string]interface{}{}
+	for key, value := range claims {
+		idTokenPayload[key] = value
+	}
+
+	// some IdPs only return the email from the userinfo endpoint
+	var userInfoClaims map[string]interface{} = nil
+	if _, ok := claims[claimMapping.Email]; !ok && userInfoEndpoint != "" && accessToken != "" {
+		var err error
+		userInfoClaims, err = getOIDCUserInfo(userInfoEndpoint, accessToken)
+		if err != nil {
+			return tpmodels.UserInfo{}, err
+		}
+		if userInfoClaims["sub"] != claims["sub"] {
+			return tpmodels.UserInfo{}, errors.New("the sub returned by the userinfo endpoint does not match the id_token")
+		}
+		for key, value := range userInfoClaims {
+			if _, exists := claims[key]; !exists {
+				claims[key] = value
+			}
+		}
+	}
+
+	userInfo, err := getUserInfoFromOIDCClaims(claims, claimMapping)
+	if err != nil {
+		return tpmodels.UserInfo{}, err
+	}
+	userInfo.RawUserInfoFromProvider = tpmodels.RawUserInfoFromProvider{
+		FromIdTokenPayload: idTokenPayload,
+		FromUserInfoAPI:    userInfoClaims,
+	}
+	return userInfo, nil
+}
+
+// verifyAudienceIsOneOf checks that the token was issued to one of the client IDs
+func verifyAudienceIsOneOf(claims jwt.MapClaims, clientIds []string) bool {
+	for _, clientId := range clientIds {
+		if claims.VerifyAudience(clientId, true) {
+			return true
+		}
+	}
+	return false
+}
+
+func getUserInfoFromOIDCClaims(claims jwt.MapClaims, claimMapping tpmodels.OIDCClaimMapping) (tpmodels.UserInfo, error) {
+	id, _ := claims[claimMapping.ID].(string)
+	if id == "" {
+		return tpmodels.UserInfo{}, errors.New("the " + claimMapping.ID + " claim is missing from the id_token")
+	}
+	email, _ := claims[claimMapping.Email].(string)
+	if email == "" {
+		return tpmodels.UserInfo{
+			ID: id,
+		}, nil
+	}
+	// some IdPs send email_verified as a string
+	isVerified := false
+	switch verified := claims[claimMapping.EmailVerified].(type) {
+	case bool:
+		isVerified = verified
+	case string:
+		isVerified = verified == "true"
+	}
+	return tpmodels.UserInfo{
+		ID: id,
+		Email: &tpmodels.EmailStruct{
+			ID:         email,
+			IsVerified: isVerified,
+		},
+	}, nil
+}
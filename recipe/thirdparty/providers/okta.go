@@ -0,0 +1,47 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package providers
+
+import (
+	"strings"
+
+	"github.com/supertokens/supertokens-golang/recipe/thirdparty/tpmodels"
+)
+
+const oktaID = "okta"
+
+// Okta uses the OIDC provider with the issuer of the configured authorization server
+func Okta(config tpmodels.OktaConfig) tpmodels.TypeProvider {
+	authorizationServerID := "default"
+	if config.AuthorizationServerID != nil {
+		authorizationServerID = *config.AuthorizationServerID
+	}
+	issuer := "https://" + strings.TrimSuffix(strings.TrimPrefix(config.OktaDomain, "https://"), "/")
+	if authorizationServerID != "" {
+		issuer += "/oauth2/" + authorizationServerID
+	}
+
+	return OIDC(tpmodels.OIDCConfig{
+		ThirdPartyID:          oktaID,
+		Issuer:                issuer,
+		ClientID:              config.ClientID,
+		ClientSecret:          config.ClientSecret,
+		AdditionalClientIDs:   config.AdditionalClientIDs,
+		Scope:                 config.Scope,
+		AuthorisationRedirect: config.AuthorisationRedirect,
+		IsDefault:             config.IsDefault,
+	})
+}
@@ -0,0 +1,519 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package providers
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/beevik/etree"
+	dsig "github.com/russellhaering/goxmldsig"
+	"github.com/russellhaering/goxmldsig/etreeutils"
+	"github.com/supertokens/supertokens-golang/recipe/thirdparty/tpmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+const (
+	samlAssertionNamespace = "urn:oasis:names:tc:SAML:2.0:assertion"
+	samlProtocolNamespace  = "urn:oasis:names:tc:SAML:2.0:protocol"
+	samlDSigNamespace      = "http://www.w3.org/2000/09/xmldsig#"
+	samlRedirectBinding    = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect"
+	samlPOSTBinding        = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST"
+	samlSuccessStatus      = "urn:oasis:names:tc:SAML:2.0:status:Success"
+	samlBearerMethod       = "urn:oasis:names:tc:SAML:2.0:cm:bearer"
+	samlEmailNameIDFormat  = "urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress"
+
+	// the IdP has to respond within this time after the AuthnRequest was created
+	samlRequestLifetime = 10 * time.Minute
+	// the frontend has to call SignInUpPOST within this time after the IdP responded
+	samlCodeLifetime = 5 * time.Minute
+	samlClockSkew    = 3 * time.Minute
+)
+
+// used if no attribute mapping is given for the email
+var samlEmailAttributes = []string{
+	"email",
+	"mail",
+	"http://schemas.xmlsoap.org/ws/2005/05/identity/claims/emailaddress",
+	"urn:oid:0.9.2342.19200300.100.1.3",
+}
+
+type samlIdPMetadata struct {
+	EntityID         string `xml:"entityID,attr"`
+	IDPSSODescriptor struct {
+		KeyDescriptors []struct {
+			Use          string   `xml:"use,attr"`
+			Certificates []string `xml:"KeyInfo>X509Data>X509Certificate"`
+		} `xml:"KeyDescriptor"`
+		SingleSignOnServices []struct {
+			Binding  string `xml:"Binding,attr"`
+			Location string `xml:"Location,attr"`
+		} `xml:"SingleSignOnService"`
+	} `xml:"IDPSSODescriptor"`
+}
+
+type samlIdP struct {
+	entityID     string
+	ssoURL       string
+	certificates []*x509.Certificate
+}
+
+func SAML(config tpmodels.SAMLConfig) tpmodels.TypeProvider {
+	attributeMapping := tpmodels.SAMLAttributeMapping{}
+	if config.AttributeMapping != nil {
+		attributeMapping = *config.AttributeMapping
+	}
+
+	idp, metadataErr := parseSAMLIdPMetadata(config.IdPMetadataXML)
+	if metadataErr != nil {
+		supertokens.LogDebugMessage("SAML provider " + config.ThirdPartyID + ": invalid IdP metadata: " + metadataErr.Error())
+	}
+	// the request and the response of a sign in can be handled by different API instances
+	var store tpmodels.SAMLStore
+	if config.Store != nil {
+		store = *config.Store
+	} else if metadataErr == nil {
+		metadataErr = errors.New("SAML provider " + config.ThirdPartyID + ": please provide a Store that is shared by all your API instances")
+	}
+
+	getLoginRedirectURL := func(acsURL string, relayState string, userContext supertokens.UserContext) (string, error) {
+		if metadataErr != nil {
+			return "", metadataErr
+		}
+		requestID, err := generateSAMLID()
+		if err != nil {
+			return "", err
+		}
+		now := time.Now().UTC()
+		authnRequest := `<samlp:AuthnRequest xmlns:samlp="` + samlProtocolNamespace + `" xmlns:saml="` + samlAssertionNamespace + `"` +
+			` ID="` + escapeSAMLXML(requestID) + `" Version="2.0" IssueInstant="` + now.Format("2006-01-02T15:04:05Z") + `"` +
+			` Destination="` + escapeSAMLXML(idp.ssoURL) + `" AssertionConsumerServiceURL="` + escapeSAMLXML(acsURL) + `"` +
+			` ProtocolBinding="` + samlPOSTBinding + `">` +
+			`<saml:Issuer>` + escapeSAMLXML(config.SPEntityID) + `</saml:Issuer>` +
+			`</samlp:AuthnRequest>`
+
+		// the HTTP-Redirect binding sends the request deflated and base64 encoded
+		var deflated bytes.Buffer
+		writer, err := flate.NewWriter(&deflated, flate.BestCompression)
+		if err != nil {
+			return "", err
+		}
+		_, err = writer.Write([]byte(authnRequest))
+		if err != nil {
+			return "", err
+		}
+		err = writer.Close()
+		if err != nil {
+			return "", err
+		}
+
+		err = store.Save("request:"+config.ThirdPartyID+":"+requestID, "", uint64(now.Add(samlRequestLifetime).UnixNano()/1000000), userContext)
+		if err != nil {
+			return "", err
+		}
+
+		query := url.Values{}
+		query.Set("SAMLRequest", base64.StdEncoding.EncodeToString(deflated.Bytes()))
+		if relayState != "" {
+			query.Set("RelayState", relayState)
+		}
+		separator := "?"
+		if strings.Contains(idp.ssoURL, "?") {
+			separator = "&"
+		}
+		return idp.ssoURL + separator + query.Encode(), nil
+	}
+
+	consumeSAMLResponse := func(acsURL string, samlResponse string, userContext supertokens.UserContext) (tpmodels.ConsumeSAMLResponseResponse, error) {
+		if metadataErr != nil {
+			return tpmodels.ConsumeSAMLResponseResponse{}, metadataErr
+		}
+		invalid := func(msg string) (tpmodels.ConsumeSAMLResponseResponse, error) {
+			return tpmodels.ConsumeSAMLResponseResponse{
+				InvalidSAMLResponseError: &struct{ Msg string }{
+					Msg: msg,
+				},
+			}, nil
+		}
+
+		decodedResponse, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(samlResponse), ""))
+		if err != nil {
+			return invalid("the SAML response is not base64 encoded")
+		}
+		document := etree.NewDocument()
+		err = document.ReadFromBytes(decodedResponse)
+		if err != nil {
+			return invalid("the SAML response is not valid XML")
+		}
+		for _, token := range document.Child {
+			// DTDs are not allowed in SAML messages
+			if _, ok := token.(*etree.Directive); ok {
+				return invalid("the SAML response must not contain a DTD")
+			}
+		}
+		response := document.Root()
+		if response == nil || !isSAMLElement(response, samlProtocolNamespace, "Response") {
+			return invalid("the SAML response has no Response element")
+		}
+
+		// either the whole response or the assertion has to be signed. Only the elements returned by
+		// Validate are used afterwards, so that nothing outside of the signed element is trusted.
+		validationContext := dsig.NewDefaultValidationContext(&dsig.MemoryX509CertificateStore{
+			Roots: idp.certificates,
+		})
+		isSigned := false
+		if getSAMLChild(response, samlDSigNamespace, "Signature") != nil {
+			response, err = validationContext.Validate(response)
+			if err != nil {
+				return invalid("the signature of the SAML response is invalid: " + err.Error())
+			}
+			isSigned = true
+		}
+
+		if destination := getSAMLAttr(response, "Destination"); destination != "" && destination != acsURL {
+			return invalid("the SAML response was sent to another destination")
+		}
+		status := getSAMLChild(response, samlProtocolNamespace, "Status")
+		if status == nil || getSAMLChild(status, samlProtocolNamespace, "StatusCode") == nil ||
+			getSAMLAttr(getSAMLChild(status, samlProtocolNamespace, "StatusCode"), "Value") != samlSuccessStatus {
+			return invalid("the IdP did not sign the user in")
+		}
+		if issuer := getSAMLChild(response, samlAssertionNamespace, "Issuer"); issuer != nil && getSAMLText(issuer) != idp.entityID {
+			return invalid("the SAML response was issued by another IdP")
+		}
+
+		// encrypted assertions are not supported
+		if len(getSAMLChildren(response, samlAssertionNamespace, "EncryptedAssertion")) != 0 {
+			return invalid("encrypted assertions are not supported")
+		}
+		assertion := getSAMLChild(response, samlAssertionNamespace, "Assertion")
+		if assertion == nil {
+			return invalid("the SAML response must contain exactly one assertion")
+		}
+		if getSAMLChild(assertion, samlDSigNamespace, "Signature") != nil {
+			// the namespaces declared on the response are needed to validate the assertion on its own
+			namespaceContext, err := etreeutils.NSBuildParentContext(assertion)
+			if err != nil {
+				return invalid(err.Error())
+			}
+			detachedAssertion, err := etreeutils.NSDetatch(namespaceContext, assertion)
+			if err != nil {
+				return invalid(err.Error())
+			}
+			assertion, err = validationContext.Validate(detachedAssertion)
+			if err != nil {
+				return invalid("the signature of the SAML assertion is invalid: " + err.Error())
+			}
+			isSigned = true
+		}
+		if !isSigned {
+			return invalid("the SAML assertion is not signed")
+		}
+
+		issuer := getSAMLChild(assertion, samlAssertionNamespace, "Issuer")
+		if issuer == nil || getSAMLText(issuer) != idp.entityID {
+			return invalid("the SAML assertion was issued by another IdP")
+		}
+
+		now := time.Now()
+		conditions := getSAMLChild(assertion, samlAssertionNamespace, "Conditions")
+		if conditions == nil {
+			return invalid("the SAML assertion has no conditions")
+		}
+		if !isSAMLTimeBefore(getSAMLAttr(conditions, "NotBefore"), now.Add(samlClockSkew), true) ||
+			!isSAMLTimeAfter(getSAMLAttr(conditions, "NotOnOrAfter"), now.Add(-samlClockSkew), true) {
+			return invalid("the SAML assertion is expired or not yet valid")
+		}
+		audienceRestrictions := getSAMLChildren(conditions, samlAssertionNamespace, "AudienceRestriction")
+		if len(audienceRestrictions) == 0 {
+			return invalid("the SAML assertion has no audience")
+		}
+		for _, audienceRestriction := range audienceRestrictions {
+			isAudience := false
+			for _, audience := range getSAMLChildren(audienceRestriction, samlAssertionNamespace, "Audience") {
+				if getSAMLText(audience) == config.SPEntityID {
+					isAudience = true
+				}
+			}
+			if !isAudience {
+				return invalid("the SAML assertion is meant for another service provider")
+			}
+		}
+
+		subject := getSAMLChild(assertion, samlAssertionNamespace, "Subject")
+		if subject == nil {
+			return invalid("the SAML assertion has no subject")
+		}
+		var requestID string
+		for _, subjectConfirmation := range getSAMLChildren(subject, samlAssertionNamespace, "SubjectConfirmation") {
+			subjectConfirmationData := getSAMLChild(subjectConfirmation, samlAssertionNamespace, "SubjectConfirmationData")
+			if getSAMLAttr(subjectConfirmation, "Method") == samlBearerMethod && subjectConfirmationData != nil &&
+				getSAMLAttr(subjectConfirmationData, "Recipient") == acsURL &&
+				isSAMLTimeAfter(getSAMLAttr(subjectConfirmationData, "NotOnOrAfter"), now.Add(-samlClockSkew), false) {
+				requestID = getSAMLAttr(subjectConfirmationData, "InResponseTo")
+				break
+			}
+		}
+		// IdP initiated sign ins are not supported, so that responses can only be used once
+		if requestID == "" {
+			return invalid("the SAML assertion is not a response to a request of this service provider")
+		}
+		request, err := store.Consume("request:"+config.ThirdPartyID+":"+requestID, userContext)
+		if err != nil {
+			return tpmodels.ConsumeSAMLResponseResponse{}, err
+		}
+		if request == nil {
+			return invalid("the SAML assertion is not a response to a request of this service provider")
+		}
+
+		userInfo, err := getUserInfoFromSAMLAssertion(subject, assertion, attributeMapping, config.IsEmailVerified)
+		if err != nil {
+			return invalid(err.Error())
+		}
+		userInfoJSON, err := json.Marshal(userInfo)
+		if err != nil {
+			return tpmodels.ConsumeSAMLResponseResponse{}, err
+		}
+		code, err := generateSAMLID()
+		if err != nil {
+			return tpmodels.ConsumeSAMLResponseResponse{}, err
+		}
+		err = store.Save("code:"+config.ThirdPartyID+":"+code, string(userInfoJSON), uint64(now.Add(samlCodeLifetime).UnixNano()/1000000), userContext)
+		if err != nil {
+			return tpmodels.ConsumeSAMLResponseResponse{}, err
+		}
+		return tpmodels.ConsumeSAMLResponseResponse{
+			OK: &struct{ Code string }{
+				Code: code,
+			},
+		}, nil
+	}
+
+	return tpmodels.TypeProvider{
+		ID: config.ThirdPartyID,
+		Get: func(redirectURI, authCodeFromRequest *string, userContext supertokens.UserContext) tpmodels.TypeProviderGetResponse {
+			if metadataErr != nil {
+				return tpmodels.TypeProviderGetResponse{
+					Error: metadataErr,
+				}
+			}
+			return tpmodels.TypeProviderGetResponse{
+				// there is no token endpoint, the code is exchanged for the user info saved by the ACS API
+				AccessTokenAPI:        tpmodels.AccessTokenAPI{},
+				AuthorisationRedirect: tpmodels.AuthorisationRedirect{},
+				GetProfileInfo: func(authCodeResponse interface{}, userContext supertokens.UserContext) (tpmodels.UserInfo, error) {
+					if authCodeFromRequest == nil {
+						return tpmodels.UserInfo{}, errors.New("please provide the code returned by the SAML ACS API")
+					}
+					userInfoJSON, err := store.Consume("code:"+config.ThirdPartyID+":"+*authCodeFromRequest, userContext)
+					if err != nil {
+						return tpmodels.UserInfo{}, err
+					}
+					if userInfoJSON == nil {
+						return tpmodels.UserInfo{}, errors.New("the SAML sign in code is invalid or has expired")
+					}
+					var userInfo tpmodels.UserInfo
+					err = json.Unmarshal([]byte(*userInfoJSON), &userInfo)
+					return userInfo, err
+				},
+				GetClientId: func(userContext supertokens.UserContext) string {
+					return config.SPEntityID
+				},
+			}
+		},
+		IsDefault: config.IsDefault,
+		SAML: &tpmodels.SAMLProviderFunctions{
+			GetLoginRedirectURL: getLoginRedirectURL,
+			ConsumeSAMLResponse: consumeSAMLResponse,
+		},
+	}
+}
+
+func parseSAMLIdPMetadata(metadataXML string) (samlIdP, error) {
+	var metadata samlIdPMetadata
+	err := xml.Unmarshal([]byte(metadataXML), &metadata)
+	if err != nil {
+		return samlIdP{}, err
+	}
+	idp := samlIdP{
+		entityID: metadata.EntityID,
+	}
+	for _, service := range metadata.IDPSSODescriptor.SingleSignOnServices {
+		if service.Binding == samlRedirectBinding {
+			idp.ssoURL = service.Location
+		}
+	}
+	for _, keyDescriptor := range metadata.IDPSSODescriptor.KeyDescriptors {
+		if keyDescriptor.Use != "" && keyDescriptor.Use != "signing" {
+			continue
+		}
+		for _, encodedCertificate := range keyDescriptor.Certificates {
+			der, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(encodedCertificate), ""))
+			if err != nil {
+				return samlIdP{}, err
+			}
+			certificate, err := x509.ParseCertificate(der)
+			if err != nil {
+				return samlIdP{}, err
+			}
+			idp.certificates = append(idp.certificates, certificate)
+		}
+	}
+	if idp.entityID == "" || idp.ssoURL == "" || len(idp.certificates) == 0 {
+		return samlIdP{}, errors.New("the IdP metadata must contain the entity ID, a HTTP-Redirect SSO URL and a signing certificate")
+	}
+	return idp, nil
+}
+
+func getUserInfoFromSAMLAssertion(subject *etree.Element, assertion *etree.Element, attributeMapping tpmodels.SAMLAttributeMapping, isEmailVerified bool) (tpmodels.UserInfo, error) {
+	attributes := map[string]string{}
+	rawAttributes := map[string]interface{}{}
+	for _, attributeStatement := range getSAMLChildren(assertion, samlAssertionNamespace, "AttributeStatement") {
+		for _, attribute := range getSAMLChildren(attributeStatement, samlAssertionNamespace, "Attribute") {
+			values := getSAMLChildren(attribute, samlAssertionNamespace, "AttributeValue")
+			if len(values) > 0 {
+				attributes[getSAMLAttr(attribute, "Name")] = getSAMLText(values[0])
+				rawAttributes[getSAMLAttr(attribute, "Name")] = getSAMLText(values[0])
+			}
+		}
+	}
+
+	nameID := getSAMLChild(subject, samlAssertionNamespace, "NameID")
+	ID := ""
+	if attributeMapping.ID != "" {
+		ID = attributes[attributeMapping.ID]
+	} else if nameID != nil {
+		ID = getSAMLText(nameID)
+	}
+	if ID == "" {
+		return tpmodels.UserInfo{}, errors.New("the SAML assertion does not contain the user ID")
+	}
+
+	email := ""
+	if attributeMapping.Email != "" {
+		email = attributes[attributeMapping.Email]
+	} else {
+		for _, name := range samlEmailAttributes {
+			if attributes[name] != "" {
+				email = attributes[name]
+				break
+			}
+		}
+		if email == "" && nameID != nil && getSAMLAttr(nameID, "Format") == samlEmailNameIDFormat {
+			email = getSAMLText(nameID)
+		}
+	}
+	if email == "" {
+		return tpmodels.UserInfo{
+			ID: ID,
+			RawUserInfoFromProvider: tpmodels.RawUserInfoFromProvider{
+				FromUserInfoAPI: rawAttributes,
+			},
+		}, nil
+	}
+	return tpmodels.UserInfo{
+		ID: ID,
+		Email: &tpmodels.EmailStruct{
+			ID:         email,
+			IsVerified: isEmailVerified,
+		},
+		RawUserInfoFromProvider: tpmodels.RawUserInfoFromProvider{
+			FromUserInfoAPI: rawAttributes,
+		},
+	}, nil
+}
+
+// isSAMLTimeBefore checks that the time in value is before limit
+func isSAMLTimeBefore(value string, limit time.Time, optional bool) bool {
+	if value == "" {
+		return optional
+	}
+	parsed, err := time.Parse(time.RFC3339Nano, value)
+	return err == nil && !parsed.After(limit)
+}
+
+// isSAMLTimeAfter checks that the time in value is after limit
+func isSAMLTimeAfter(value string, limit time.Time, optional bool) bool {
+	if value == "" {
+		return optional
+	}
+	parsed, err := time.Parse(time.RFC3339Nano, value)
+	return err == nil && parsed.After(limit)
+}
+
+// SAML IDs must not start with a digit
+func generateSAMLID() (string, error) {
+	randomBytes := make([]byte, 20)
+	_, err := rand.Read(randomBytes)
+	if err != nil {
+		return "", err
+	}
+	return "_" + hex.EncodeToString(randomBytes), nil
+}
+
+func escapeSAMLXML(value string) string {
+	var escaped bytes.Buffer
+	xml.EscapeText(&escaped, []byte(value))
+	return escaped.String()
+}
+
+func isSAMLElement(element *etree.Element, namespace string, tag string) bool {
+	return element.Tag == tag && element.NamespaceURI() == namespace
+}
+
+func getSAMLChildren(element *etree.Element, namespace string, tag string) []*etree.Element {
+	result := []*etree.Element{}
+	for _, child := range element.ChildElements() {
+		if isSAMLElement(child, namespace, tag) {
+			result = append(result, child)
+		}
+	}
+	return result
+}
+
+// getSAMLChild returns the only child element with the name, or nil if there is none or more than one
+func getSAMLChild(element *etree.Element, namespace string, tag string) *etree.Element {
+	children := getSAMLChildren(element, namespace, tag)
+	if len(children) != 1 {
+		return nil
+	}
+	return children[0]
+}
+
+// getSAMLAttr returns the value of an attribute without a namespace prefix, unlike SelectAttrValue
+// which ignores the prefix
+func getSAMLAttr(element *etree.Element, name string) string {
+	for _, attr := range element.Attr {
+		if attr.Space == "" && attr.Key == name {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+func getSAMLText(element *etree.Element) string {
+	return strings.TrimSpace(element.Text())
+}
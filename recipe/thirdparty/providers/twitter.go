@@ -0,0 +1,155 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package providers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/supertokens/supertokens-golang/recipe/thirdparty/tpmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+const twitterID = "twitter"
+
+func Twitter(config tpmodels.TwitterConfig) tpmodels.TypeProvider {
+	return tpmodels.TypeProvider{
+		ID: twitterID,
+		Get: func(redirectURI, authCodeFromRequest *string, userContext supertokens.UserContext) tpmodels.TypeProviderGetResponse {
+			accessTokenAPIURL := "https://api.twitter.com/2/oauth2/token"
+			accessTokenAPIParams := map[string]string{
+				"client_id":  config.ClientID,
+				"grant_type": "authorization_code",
+			}
+			if authCodeFromRequest != nil {
+				accessTokenAPIParams["code"] = *authCodeFromRequest
+			}
+			if redirectURI != nil {
+				accessTokenAPIParams["redirect_uri"] = *redirectURI
+			}
+			// confidential clients have to send their credentials using basic auth
+			accessTokenAPIHeaders := map[string]string{
+				"Authorization": "Basic " + base64.StdEncoding.EncodeToString([]byte(config.ClientID+":"+config.ClientSecret)),
+			}
+
+			authorisationRedirectURL := "https://twitter.com/i/oauth2/authorize"
+			scopes := []string{"users.read", "users.email", "tweet.read"}
+			if config.Scope != nil {
+				scopes = config.Scope
+			}
+
+			var additionalParams map[string]interface{} = nil
+			if config.AuthorisationRedirect != nil && config.AuthorisationRedirect.Params != nil {
+				additionalParams = config.AuthorisationRedirect.Params
+			}
+
+			authorizationRedirectParams := map[string]interface{}{
+				"scope":         strings.Join(scopes, " "),
+				"response_type": "code",
+				"client_id":     config.ClientID,
+			}
+			for key, value := range additionalParams {
+				authorizationRedirectParams[key] = value
+			}
+
+			return tpmodels.TypeProviderGetResponse{
+				AccessTokenAPI: tpmodels.AccessTokenAPI{
+					URL:     accessTokenAPIURL,
+					Params:  accessTokenAPIParams,
+					Headers: accessTokenAPIHeaders,
+				},
+				AuthorisationRedirect: tpmodels.AuthorisationRedirect{
+					URL:    authorisationRedirectURL,
+					Params: authorizationRedirectParams,
+				},
+				GetProfileInfo: func(authCodeResponse interface{}, userContext supertokens.UserContext) (tpmodels.UserInfo, error) {
+					authCodeResponseJson, err := json.Marshal(authCodeResponse)
+					if err != nil {
+						return tpmodels.UserInfo{}, err
+					}
+					var accessTokenAPIResponse twitterGetProfileInfoInput
+					err = json.Unmarshal(authCodeResponseJson, &accessTokenAPIResponse)
+					if err != nil {
+						return tpmodels.UserInfo{}, err
+					}
+					authHeader := "Bearer " + accessTokenAPIResponse.AccessToken
+					response, err := getTwitterAuthRequest(authHeader)
+					if err != nil {
+						return tpmodels.UserInfo{}, err
+					}
+					responseMap, ok := response.(map[string]interface{})
+					if !ok {
+						return tpmodels.UserInfo{}, errors.New("invalid response from the Twitter user API")
+					}
+					userInfo, ok := responseMap["data"].(map[string]interface{})
+					if !ok {
+						return tpmodels.UserInfo{}, errors.New("the Twitter user API did not return the user")
+					}
+					ID, ok := userInfo["id"].(string)
+					if !ok || ID == "" {
+						return tpmodels.UserInfo{}, errors.New("the Twitter user API did not return the id of the user")
+					}
+					// twitter only returns the email if it is confirmed and the users.email scope was granted
+					email, _ := userInfo["confirmed_email"].(string)
+					if email == "" {
+						return tpmodels.UserInfo{
+							ID: ID,
+							RawUserInfoFromProvider: tpmodels.RawUserInfoFromProvider{
+								FromUserInfoAPI: userInfo,
+							},
+						}, nil
+					}
+					return tpmodels.UserInfo{
+						ID: ID,
+						Email: &tpmodels.EmailStruct{
+							ID:         email,
+							IsVerified: true,
+						},
+						RawUserInfoFromProvider: tpmodels.RawUserInfoFromProvider{
+							FromUserInfoAPI: userInfo,
+						},
+					}, nil
+				},
+				GetClientId: func(userContext supertokens.UserContext) string {
+					return config.ClientID
+				},
+			}
+		},
+		IsDefault: config.IsDefault,
+		// twitter rejects authorisation requests without a code challenge
+		RequiresPKCE: true,
+	}
+}
+
+func getTwitterAuthRequest(authHeader string) (interface{}, error) {
+	url := "https://api.twitter.com/2/users/me?user.fields=confirmed_email"
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("Authorization", authHeader)
+	return doGetRequest(req)
+}
+
+type twitterGetProfileInfoInput struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int    `json:"expires_in"`
+	TokenType   string `json:"token_type"`
+	Scope       string `json:"scope"`
+}
@@ -106,6 +106,14 @@ func (r *Recipe) getAPIsHandled() ([]supertokens.APIHandled, error) {
 	if err != nil {
 		return nil, err
 	}
+	samlLoginAPI, err := supertokens.NewNormalisedURLPath(SAMLLoginAPI)
+	if err != nil {
+		return nil, err
+	}
+	samlACSAPI, err := supertokens.NewNormalisedURLPath(SAMLACSAPI)
+	if err != nil {
+		return nil, err
+	}
 	emailverificationAPIhandled, err := r.EmailVerificationRecipe.RecipeModule.GetAPIsHandled()
 	if err != nil {
 		return nil, err
@@ -125,29 +133,69 @@ func (r *Recipe) getAPIsHandled() ([]supertokens.APIHandled, error) {
 		PathWithoutAPIBasePath: appleRedirectHandlerAPI,
 		ID:                     AppleRedirectHandlerAPI,
 		Disabled:               r.APIImpl.AppleRedirectHandlerPOST == nil,
+	}, {
+		Method:                 http.MethodGet,
+		PathWithoutAPIBasePath: samlLoginAPI,
+		ID:                     SAMLLoginAPI,
+		Disabled:               r.APIImpl.SAMLLoginGET == nil,
+	}, {
+		Method:                 http.MethodPost,
+		PathWithoutAPIBasePath: samlACSAPI,
+		ID:                     SAMLACSAPI,
+		Disabled:               r.APIImpl.SAMLACSPOST == nil,
 	}}, emailverificationAPIhandled...), nil
 }
 
 func (r *Recipe) handleAPIRequest(id string, req *http.Request, res http.ResponseWriter, theirHandler http.HandlerFunc, path supertokens.NormalisedURLPath, method string) error {
+	if id != SignInUpAPI && id != AuthorisationAPI && id != AppleRedirectHandlerAPI && id != SAMLLoginAPI && id != SAMLACSAPI {
+		return r.EmailVerificationRecipe.RecipeModule.HandleAPIRequest(id, req, res, theirHandler, path, method)
+	}
+
+	userContext := &map[string]interface{}{}
+	providers, err := r.GetProviders(req, userContext)
+	if err != nil {
+		return err
+	}
 	options := tpmodels.APIOptions{
 		Config:                                r.Config,
 		OtherHandler:                          theirHandler,
 		RecipeID:                              r.RecipeModule.GetRecipeID(),
 		RecipeImplementation:                  r.RecipeImpl,
 		EmailVerificationRecipeImplementation: r.EmailVerificationRecipe.RecipeImpl,
-		Providers:                             r.Providers,
+		Providers:                             providers,
 		Req:                                   req,
 		Res:                                   res,
 		AppInfo:                               r.RecipeModule.GetAppInfo(),
 	}
 	if id == SignInUpAPI {
-		return api.SignInUpAPI(r.APIImpl, options)
+		return api.SignInUpAPI(r.APIImpl, options, userContext)
 	} else if id == AuthorisationAPI {
-		return api.AuthorisationUrlAPI(r.APIImpl, options)
+		return api.AuthorisationUrlAPI(r.APIImpl, options, userContext)
 	} else if id == AppleRedirectHandlerAPI {
-		return api.AppleRedirectHandler(r.APIImpl, options)
+		return api.AppleRedirectHandler(r.APIImpl, options, userContext)
+	} else if id == SAMLLoginAPI {
+		return api.SAMLLoginAPI(r.APIImpl, options, userContext)
+	} else if id == SAMLACSAPI {
+		return api.SAMLACSAPI(r.APIImpl, options, userContext)
 	}
-	return r.EmailVerificationRecipe.RecipeModule.HandleAPIRequest(id, req, res, theirHandler, path, method)
+	return errors.New("should never come here")
+}
+
+// GetProviders returns the providers to use for the request, which are resolved using
+// SignInAndUpFeature.GetProviders if it is set. req is nil outside of API requests.
+func (r *Recipe) GetProviders(req *http.Request, userContext supertokens.UserContext) ([]tpmodels.TypeProvider, error) {
+	if r.Config.SignInAndUpFeature.GetProviders == nil {
+		return r.Providers, nil
+	}
+	providers, err := r.Config.SignInAndUpFeature.GetProviders(req, userContext)
+	if err != nil {
+		return nil, err
+	}
+	err = validateProviders(providers)
+	if err != nil {
+		return nil, err
+	}
+	return providers, nil
 }
 
 func (r *Recipe) getAllCORSHeaders() []string {
@@ -169,6 +217,17 @@ func (r *Recipe) getEmailForUserId(userID string, userContext supertokens.UserCo
 	return userInfo.Email, nil
 }
 
+func (r *Recipe) getTimeJoinedForUserId(userID string, userContext supertokens.UserContext) (*uint64, error) {
+	userInfo, err := (*r.RecipeImpl.GetUserByID)(userID, userContext)
+	if err != nil {
+		return nil, err
+	}
+	if userInfo == nil {
+		return nil, nil
+	}
+	return &userInfo.TimeJoined, nil
+}
+
 func ResetForTest() {
 	singletonInstance = nil
 }
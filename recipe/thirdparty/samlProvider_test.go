@@ -0,0 +1,312 @@
+/*
+ * Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package thirdparty
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"io/ioutil"
+	"math/big"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/beevik/etree"
+	dsig "github.com/russellhaering/goxmldsig"
+	"github.com/russellhaering/goxmldsig/etreeutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/thirdparty/tpmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+const (
+	testSAMLIdPEntityID = "https://idp.example.com/metadata"
+	testSAMLSPEntityID  = "https://api.supertokens.io/saml"
+	testSAMLACSURL      = "https://api.supertokens.io/auth/saml/acs?thirdPartyId=saml"
+)
+
+// testSAMLKeyStore is the key and certificate that the test IdP signs its responses with
+type testSAMLKeyStore struct {
+	privateKey  *rsa.PrivateKey
+	certificate []byte
+}
+
+func (ks *testSAMLKeyStore) GetKeyPair() (*rsa.PrivateKey, []byte, error) {
+	return ks.privateKey, ks.certificate, nil
+}
+
+func makeInMemorySAMLStoreForTest() tpmodels.SAMLStore {
+	var lock sync.Mutex
+	type storedValue struct {
+		value  string
+		expiry uint64
+	}
+	values := map[string]storedValue{}
+
+	return tpmodels.SAMLStore{
+		Save: func(key string, value string, expiry uint64, userContext supertokens.UserContext) error {
+			lock.Lock()
+			defer lock.Unlock()
+			values[key] = storedValue{value: value, expiry: expiry}
+			return nil
+		},
+		Consume: func(key string, userContext supertokens.UserContext) (*string, error) {
+			lock.Lock()
+			defer lock.Unlock()
+			stored, ok := values[key]
+			if !ok {
+				return nil, nil
+			}
+			delete(values, key)
+			if stored.expiry <= uint64(time.Now().UnixNano()/1000000) {
+				return nil, nil
+			}
+			return &stored.value, nil
+		},
+	}
+}
+
+func makeTestSAMLMetadata(t *testing.T) (string, *testSAMLKeyStore) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "idp.example.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	certificate, err := x509.CreateCertificate(rand.Reader, &template, &template, &privateKey.PublicKey, privateKey)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	metadata := `<md:EntityDescriptor xmlns:md="urn:oasis:names:tc:SAML:2.0:metadata" xmlns:ds="http://www.w3.org/2000/09/xmldsig#" entityID="` + testSAMLIdPEntityID + `">
+	<md:IDPSSODescriptor protocolSupportEnumeration="urn:oasis:names:tc:SAML:2.0:protocol">
+		<md:KeyDescriptor use="signing">
+			<ds:KeyInfo><ds:X509Data><ds:X509Certificate>` + base64.StdEncoding.EncodeToString(certificate) + `</ds:X509Certificate></ds:X509Data></ds:KeyInfo>
+		</md:KeyDescriptor>
+		<md:SingleSignOnService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect" Location="https://idp.example.com/sso"/>
+	</md:IDPSSODescriptor>
+</md:EntityDescriptor>`
+	return metadata, &testSAMLKeyStore{privateKey: privateKey, certificate: certificate}
+}
+
+func makeTestSAMLProvider(t *testing.T) (tpmodels.TypeProvider, *testSAMLKeyStore) {
+	metadata, keyStore := makeTestSAMLMetadata(t)
+	store := makeInMemorySAMLStoreForTest()
+	provider := SAML(tpmodels.SAMLConfig{
+		ThirdPartyID:   "saml",
+		IdPMetadataXML: metadata,
+		SPEntityID:     testSAMLSPEntityID,
+		Store:          &store,
+	})
+	return provider, keyStore
+}
+
+// getTestSAMLRequestID starts a sign in and returns the ID of the AuthnRequest sent to the IdP
+func getTestSAMLRequestID(t *testing.T, provider tpmodels.TypeProvider) string {
+	redirectURL, err := provider.SAML.GetLoginRedirectURL(testSAMLACSURL, "some-state", &map[string]interface{}{})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	parsedURL, err := url.Parse(redirectURL)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	assert.Equal(t, "idp.example.com", parsedURL.Host)
+	assert.Equal(t, "some-state", parsedURL.Query().Get("RelayState"))
+
+	deflated, err := base64.StdEncoding.DecodeString(parsedURL.Query().Get("SAMLRequest"))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	authnRequest, err := ioutil.ReadAll(flate.NewReader(bytes.NewReader(deflated)))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	assert.Contains(t, string(authnRequest), `AssertionConsumerServiceURL="https://api.supertokens.io/auth/saml/acs?thirdPartyId=saml"`)
+	return regexp.MustCompile(` ID="([^"]+)"`).FindStringSubmatch(string(authnRequest))[1]
+}
+
+func makeTestSAMLAssertion(requestID string, audience string, email string) string {
+	now := time.Now().UTC()
+	notBefore := now.Add(-time.Minute).Format(time.RFC3339)
+	notOnOrAfter := now.Add(5 * time.Minute).Format(time.RFC3339)
+
+	return `<saml:Assertion ID="_assertion" IssueInstant="` + now.Format(time.RFC3339) + `" Version="2.0">` +
+		`<saml:Issuer>` + testSAMLIdPEntityID + `</saml:Issuer>` +
+		`<saml:Subject><saml:NameID Format="urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress">user-1</saml:NameID>` +
+		`<saml:SubjectConfirmation Method="urn:oasis:names:tc:SAML:2.0:cm:bearer"><saml:SubjectConfirmationData InResponseTo="` + requestID + `" NotOnOrAfter="` + notOnOrAfter + `" Recipient="https://api.supertokens.io/auth/saml/acs?thirdPartyId=saml"></saml:SubjectConfirmationData></saml:SubjectConfirmation></saml:Subject>` +
+		`<saml:Conditions NotBefore="` + notBefore + `" NotOnOrAfter="` + notOnOrAfter + `"><saml:AudienceRestriction><saml:Audience>` + audience + `</saml:Audience></saml:AudienceRestriction></saml:Conditions>` +
+		`<saml:AttributeStatement><saml:Attribute Name="email"><saml:AttributeValue>` + email + `</saml:AttributeValue></saml:Attribute></saml:AttributeStatement>` +
+		`</saml:Assertion>`
+}
+
+// makeTestSAMLResponse returns a response with an assertion that is signed by the IdP. The saml prefix is
+// declared on the response, so that it has to be taken into account when the assertion is validated.
+func makeTestSAMLResponse(t *testing.T, keyStore *testSAMLKeyStore, requestID string, audience string, email string) (string, string) {
+	document := etree.NewDocument()
+	err := document.ReadFromString(`<samlp:Response xmlns:samlp="urn:oasis:names:tc:SAML:2.0:protocol" xmlns:saml="urn:oasis:names:tc:SAML:2.0:assertion" ID="_response" Version="2.0" Destination="https://api.supertokens.io/auth/saml/acs?thirdPartyId=saml">` +
+		`<samlp:Status><samlp:StatusCode Value="urn:oasis:names:tc:SAML:2.0:status:Success"/></samlp:Status>` +
+		makeTestSAMLAssertion(requestID, audience, email) +
+		`</samlp:Response>`)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	response := document.Root()
+	assertion := response.SelectElement("saml:Assertion")
+
+	// the signer does not look at the namespaces declared on the parents of the element
+	namespaceContext, err := etreeutils.NSBuildParentContext(assertion)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	detachedAssertion, err := etreeutils.NSDetatch(namespaceContext, assertion)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	signingContext := dsig.NewDefaultSigningContext(keyStore)
+	signingContext.Canonicalizer = dsig.MakeC14N10ExclusiveCanonicalizerWithPrefixList("")
+	signature, err := signingContext.ConstructSignature(detachedAssertion, true)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	// the signature has to follow the issuer of the assertion
+	assertion.InsertChild(assertion.ChildElements()[1], signature)
+
+	serialised, err := document.WriteToString()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	return base64.StdEncoding.EncodeToString([]byte(serialised)), serialised
+}
+
+func TestSAMLProviderSignsInUsersWithAValidResponse(t *testing.T) {
+	provider, keyStore := makeTestSAMLProvider(t)
+	requestID := getTestSAMLRequestID(t, provider)
+	samlResponse, _ := makeTestSAMLResponse(t, keyStore, requestID, testSAMLSPEntityID, "test@example.com")
+
+	result, err := provider.SAML.ConsumeSAMLResponse(testSAMLACSURL, samlResponse, &map[string]interface{}{})
+	if err != nil {
+		t.Error(err.Error())
+	}
+	assert.Nil(t, result.InvalidSAMLResponseError)
+	assert.NotNil(t, result.OK)
+
+	providerInfo := provider.Get(nil, &result.OK.Code, &map[string]interface{}{})
+	assert.Equal(t, "", providerInfo.AccessTokenAPI.URL)
+	userInfo, err := providerInfo.GetProfileInfo(map[string]interface{}{}, &map[string]interface{}{})
+	if err != nil {
+		t.Error(err.Error())
+	}
+	assert.Equal(t, "user-1", userInfo.ID)
+	assert.Equal(t, "test@example.com", userInfo.Email.ID)
+	assert.False(t, userInfo.Email.IsVerified)
+
+	// the code and the response can only be used once
+	_, err = providerInfo.GetProfileInfo(map[string]interface{}{}, &map[string]interface{}{})
+	assert.NotNil(t, err)
+	result, err = provider.SAML.ConsumeSAMLResponse(testSAMLACSURL, samlResponse, &map[string]interface{}{})
+	if err != nil {
+		t.Error(err.Error())
+	}
+	assert.NotNil(t, result.InvalidSAMLResponseError)
+}
+
+func TestSAMLProviderRejectsTamperedResponses(t *testing.T) {
+	provider, keyStore := makeTestSAMLProvider(t)
+	requestID := getTestSAMLRequestID(t, provider)
+	_, response := makeTestSAMLResponse(t, keyStore, requestID, testSAMLSPEntityID, "test@example.com")
+	tampered := strings.Replace(response, "test@example.com", "admin@example.com", 1)
+
+	result, err := provider.SAML.ConsumeSAMLResponse(testSAMLACSURL, base64.StdEncoding.EncodeToString([]byte(tampered)), &map[string]interface{}{})
+	if err != nil {
+		t.Error(err.Error())
+	}
+	assert.Nil(t, result.OK)
+	assert.True(t, strings.HasPrefix(result.InvalidSAMLResponseError.Msg, "the signature of the SAML assertion is invalid"))
+}
+
+func TestSAMLProviderRejectsUnsignedAssertionsNextToSignedOnes(t *testing.T) {
+	provider, keyStore := makeTestSAMLProvider(t)
+	requestID := getTestSAMLRequestID(t, provider)
+	_, response := makeTestSAMLResponse(t, keyStore, requestID, testSAMLSPEntityID, "test@example.com")
+	wrapped := strings.Replace(response, "</samlp:Response>", makeTestSAMLAssertion(requestID, testSAMLSPEntityID, "admin@example.com")+"</samlp:Response>", 1)
+
+	result, err := provider.SAML.ConsumeSAMLResponse(testSAMLACSURL, base64.StdEncoding.EncodeToString([]byte(wrapped)), &map[string]interface{}{})
+	if err != nil {
+		t.Error(err.Error())
+	}
+	assert.Nil(t, result.OK)
+	assert.NotNil(t, result.InvalidSAMLResponseError)
+
+	unsigned := regexp.MustCompile(`<ds:Signature[\s\S]*</ds:Signature>`).ReplaceAllString(response, "")
+	result, err = provider.SAML.ConsumeSAMLResponse(testSAMLACSURL, base64.StdEncoding.EncodeToString([]byte(unsigned)), &map[string]interface{}{})
+	if err != nil {
+		t.Error(err.Error())
+	}
+	assert.Nil(t, result.OK)
+	assert.Equal(t, "the SAML assertion is not signed", result.InvalidSAMLResponseError.Msg)
+}
+
+func TestSAMLProviderRequiresAStore(t *testing.T) {
+	metadata, _ := makeTestSAMLMetadata(t)
+	provider := SAML(tpmodels.SAMLConfig{
+		ThirdPartyID:   "saml",
+		IdPMetadataXML: metadata,
+		SPEntityID:     testSAMLSPEntityID,
+	})
+
+	_, err := provider.SAML.GetLoginRedirectURL(testSAMLACSURL, "some-state", &map[string]interface{}{})
+	assert.Error(t, err)
+	assert.Error(t, provider.Get(nil, nil, &map[string]interface{}{}).Error)
+}
+
+func TestSAMLProviderRejectsResponsesForOtherServiceProviders(t *testing.T) {
+	provider, keyStore := makeTestSAMLProvider(t)
+	requestID := getTestSAMLRequestID(t, provider)
+	samlResponse, _ := makeTestSAMLResponse(t, keyStore, requestID, "https://other.example.com", "test@example.com")
+
+	result, err := provider.SAML.ConsumeSAMLResponse(testSAMLACSURL, samlResponse, &map[string]interface{}{})
+	if err != nil {
+		t.Error(err.Error())
+	}
+	assert.Nil(t, result.OK)
+	assert.Equal(t, "the SAML assertion is meant for another service provider", result.InvalidSAMLResponseError.Msg)
+}
+
+func TestSAMLProviderRejectsResponsesToUnknownRequests(t *testing.T) {
+	provider, keyStore := makeTestSAMLProvider(t)
+	samlResponse, _ := makeTestSAMLResponse(t, keyStore, "_unknown", testSAMLSPEntityID, "test@example.com")
+
+	result, err := provider.SAML.ConsumeSAMLResponse(testSAMLACSURL, samlResponse, &map[string]interface{}{})
+	if err != nil {
+		t.Error(err.Error())
+	}
+	assert.Nil(t, result.OK)
+	assert.NotNil(t, result.InvalidSAMLResponseError)
+}
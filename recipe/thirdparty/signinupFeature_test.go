@@ -915,3 +915,180 @@ func TestGetUserByThirdPartyInfoWhenUserDoesNotExist(t *testing.T) {
 	assert.Equal(t, userInfoAfterSignup.ID, user["ID"].(string))
 	assert.Equal(t, userInfoAfterSignup.Email, user["Email"].(string))
 }
+
+func TestEmailPolicyIsCheckedBeforeSignInUp(t *testing.T) {
+	configValue := supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: "http://localhost:8080",
+		},
+		AppInfo: supertokens.AppInfo{
+			APIDomain:     "api.supertokens.io",
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{
+			session.Init(nil),
+			Init(
+				&tpmodels.TypeInput{
+					SignInAndUpFeature: tpmodels.TypeInputSignInAndUp{
+						Providers: []tpmodels.TypeProvider{
+							customProvider2,
+						},
+						EmailPolicy: &tpmodels.TypeInputEmailPolicy{
+							BlockedDomains: []string{"Blocked.com"},
+							AllowedProvidersForDomain: map[string][]string{
+								"company.com": {"google-workspaces"},
+							},
+						},
+					},
+				},
+			),
+		},
+	}
+
+	BeforeEach()
+	unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+	err := supertokens.Init(configValue)
+
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	mux := http.NewServeMux()
+	testServer := httptest.NewServer(supertokens.Middleware(mux))
+	defer testServer.Close()
+
+	getResult := func(email string) map[string]interface{} {
+		resp, err := unittesting.SigninupCustomRequest(testServer.URL, email, "user-id")
+		if err != nil {
+			t.Error(err.Error())
+		}
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		dataInBytes, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Error(err.Error())
+		}
+		resp.Body.Close()
+		var result map[string]interface{}
+		err = json.Unmarshal(dataInBytes, &result)
+		if err != nil {
+			t.Error(err.Error())
+		}
+		return result
+	}
+
+	result := getResult("test@blocked.com")
+	assert.Equal(t, "FIELD_ERROR", result["status"])
+	assert.Equal(t, "Signing in with emails of the domain blocked.com is not allowed", result["error"])
+
+	result = getResult("test@company.com")
+	assert.Equal(t, "FIELD_ERROR", result["status"])
+	assert.Equal(t, "Emails of the domain company.com can only be used to sign in with: google-workspaces", result["error"])
+
+	result = getResult("test@example.com")
+	assert.Equal(t, "OK", result["status"])
+}
+
+func TestProfileIsMappedFromTheRawUserInfoAndAddedToTheAccessTokenPayload(t *testing.T) {
+	var profile tpmodels.Profile
+	var accessTokenPayload map[string]interface{}
+	configValue := supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: "http://localhost:8080",
+		},
+		AppInfo: supertokens.AppInfo{
+			APIDomain:     "api.supertokens.io",
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{
+			session.Init(nil),
+			Init(
+				&tpmodels.TypeInput{
+					SignInAndUpFeature: tpmodels.TypeInputSignInAndUp{
+						Providers: []tpmodels.TypeProvider{{
+							ID: "custom",
+							Get: func(redirectURI, authCodeFromRequest *string, userContext supertokens.UserContext) tpmodels.TypeProviderGetResponse {
+								return tpmodels.TypeProviderGetResponse{
+									AccessTokenAPI: tpmodels.AccessTokenAPI{
+										URL: "https://test.com/oauth/token",
+									},
+									GetProfileInfo: func(authCodeResponse interface{}, userContext supertokens.UserContext) (tpmodels.UserInfo, error) {
+										return tpmodels.UserInfo{
+											ID: authCodeResponse.(map[string]interface{})["id"].(string),
+											Email: &tpmodels.EmailStruct{
+												ID:         authCodeResponse.(map[string]interface{})["email"].(string),
+												IsVerified: true,
+											},
+											RawUserInfoFromProvider: tpmodels.RawUserInfoFromProvider{
+												FromIdTokenPayload: map[string]interface{}{
+													"name": "John Doe",
+												},
+												FromUserInfoAPI: map[string]interface{}{
+													"name":       "johndoe",
+													"avatar_url": "https://test.com/johndoe.png",
+													"teams":      []interface{}{"admins", "developers"},
+												},
+											},
+										}, nil
+									},
+									GetClientId: func(userContext supertokens.UserContext) string {
+										return "supertokens"
+									},
+								}
+							},
+						}},
+						ProfileClaimMapping: &tpmodels.ProfileClaimMapping{
+							Groups: []string{"teams"},
+						},
+						GetAccessTokenPayload: func(user tpmodels.User, profile tpmodels.Profile, rawUserInfo tpmodels.RawUserInfoFromProvider, userContext supertokens.UserContext) (map[string]interface{}, error) {
+							return map[string]interface{}{
+								"groups": profile.Groups,
+							}, nil
+						},
+					},
+					Override: &tpmodels.OverrideStruct{
+						APIs: func(originalImplementation tpmodels.APIInterface) tpmodels.APIInterface {
+							originalSignInUpPOST := *originalImplementation.SignInUpPOST
+							*originalImplementation.SignInUpPOST = func(provider tpmodels.TypeProvider, code string, authCodeResponse interface{}, redirectURI string, options tpmodels.APIOptions, userContext supertokens.UserContext) (tpmodels.SignInUpPOSTResponse, error) {
+								res, err := originalSignInUpPOST(provider, code, authCodeResponse, redirectURI, options, userContext)
+								if err != nil {
+									return res, err
+								}
+								profile = res.OK.Profile
+								accessTokenPayload = res.OK.Session.GetAccessTokenPayload()
+								return res, nil
+							}
+							return originalImplementation
+						},
+					},
+				},
+			),
+		},
+	}
+
+	BeforeEach()
+	unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+	err := supertokens.Init(configValue)
+
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	mux := http.NewServeMux()
+	testServer := httptest.NewServer(supertokens.Middleware(mux))
+	defer testServer.Close()
+
+	resp, err := unittesting.SigninupCustomRequest(testServer.URL, "johndoe@gmail.com", "user-id")
+	if err != nil {
+		t.Error(err.Error())
+	}
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	assert.Equal(t, "John Doe", profile.Name)
+	assert.Equal(t, "https://test.com/johndoe.png", profile.Picture)
+	assert.Equal(t, []string{"admins", "developers"}, profile.Groups)
+	assert.Equal(t, []interface{}{"admins", "developers"}, accessTokenPayload["groups"])
+}
@@ -0,0 +1,206 @@
+/*
+ * Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package thirdparty
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/thirdparty/api"
+	"github.com/supertokens/supertokens-golang/recipe/thirdparty/tpmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func makeInMemoryProviderTokenStoreForTest() tpmodels.ProviderTokenStore {
+	type tokensKey struct {
+		thirdPartyID string
+		userID       string
+	}
+	var lock sync.Mutex
+	tokens := map[tokensKey]tpmodels.ProviderTokens{}
+
+	save := func(thirdPartyID string, userID string, providerTokens tpmodels.ProviderTokens, userContext supertokens.UserContext) error {
+		lock.Lock()
+		defer lock.Unlock()
+		tokens[tokensKey{thirdPartyID, userID}] = providerTokens
+		return nil
+	}
+
+	get := func(thirdPartyID string, userID string, userContext supertokens.UserContext) (*tpmodels.ProviderTokens, error) {
+		lock.Lock()
+		defer lock.Unlock()
+		providerTokens, ok := tokens[tokensKey{thirdPartyID, userID}]
+		if !ok {
+			return nil, nil
+		}
+		return &providerTokens, nil
+	}
+
+	return tpmodels.ProviderTokenStore{
+		Save: save,
+		Get:  get,
+	}
+}
+
+func makeTestTokenVault(t *testing.T) *tpmodels.TypeNormalisedInputTokenVault {
+	store := makeInMemoryProviderTokenStoreForTest()
+	tokenVault, err := validateAndNormaliseTokenVaultConfig(&tpmodels.TypeInputTokenVault{
+		Store: &store,
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	return tokenVault
+}
+
+func TestTokenVaultRequiresAStore(t *testing.T) {
+	_, err := validateAndNormaliseTokenVaultConfig(&tpmodels.TypeInputTokenVault{})
+	assert.Error(t, err)
+}
+
+func TestProviderAccessTokenIsReturnedUntilItExpires(t *testing.T) {
+	provider := Github(tpmodels.GithubConfig{
+		ClientID:     "test",
+		ClientSecret: "test-secret",
+	})
+	tokenVault := makeTestTokenVault(t)
+	err := tokenVault.Store.Save("github", "user-1", tpmodels.ProviderTokens{
+		AccessToken: "access-token",
+		Expiry:      uint64(time.Now().Add(time.Hour).UnixNano() / 1000000),
+	}, &map[string]interface{}{})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	accessToken, err := api.GetProviderAccessToken([]tpmodels.TypeProvider{provider}, tokenVault, "user-1", "github", &map[string]interface{}{})
+	if err != nil {
+		t.Error(err.Error())
+	}
+	assert.Equal(t, "access-token", *accessToken)
+
+	accessToken, err = api.GetProviderAccessToken([]tpmodels.TypeProvider{provider}, tokenVault, "user-2", "github", &map[string]interface{}{})
+	if err != nil {
+		t.Error(err.Error())
+	}
+	assert.Nil(t, accessToken)
+}
+
+func TestExpiredProviderAccessTokenIsRefreshed(t *testing.T) {
+	defer gock.OffAll()
+	gock.New("https://github.com").
+		Post("/login/oauth/access_token").
+		AddMatcher(func(req *http.Request, _ *gock.Request) (bool, error) {
+			err := req.ParseForm()
+			return err == nil && req.PostForm.Get("grant_type") == "refresh_token" &&
+				req.PostForm.Get("refresh_token") == "refresh-token" && req.PostForm.Get("client_secret") == "test-secret" &&
+				req.PostForm.Get("code") == "", err
+		}).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"access_token": "new-access-token",
+			"expires_in":   3600,
+		})
+
+	provider := Github(tpmodels.GithubConfig{
+		ClientID:     "test",
+		ClientSecret: "test-secret",
+	})
+	tokenVault := makeTestTokenVault(t)
+	err := tokenVault.Store.Save("github", "user-1", tpmodels.ProviderTokens{
+		AccessToken:  "access-token",
+		RefreshToken: "refresh-token",
+		Expiry:       uint64(time.Now().Add(-time.Minute).UnixNano() / 1000000),
+		ClientID:     "test",
+	}, &map[string]interface{}{})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	accessToken, err := api.GetProviderAccessToken([]tpmodels.TypeProvider{provider}, tokenVault, "user-1", "github", &map[string]interface{}{})
+	if err != nil {
+		t.Error(err.Error())
+	}
+	assert.Equal(t, "new-access-token", *accessToken)
+	assert.True(t, gock.IsDone())
+
+	// the refresh token is kept if the provider does not return a new one
+	tokens, err := tokenVault.Store.Get("github", "user-1", &map[string]interface{}{})
+	if err != nil {
+		t.Error(err.Error())
+	}
+	assert.Equal(t, "new-access-token", tokens.AccessToken)
+	assert.Equal(t, "refresh-token", tokens.RefreshToken)
+	assert.Greater(t, tokens.Expiry, uint64(time.Now().UnixNano()/1000000))
+}
+
+func TestConcurrentProviderAccessTokenRequestsRefreshOnce(t *testing.T) {
+	defer gock.OffAll()
+	// the mock only matches once, so a second refresh would fail
+	gock.New("https://github.com").
+		Post("/login/oauth/access_token").
+		Reply(200).
+		JSON(map[string]interface{}{
+			"access_token":  "new-access-token",
+			"refresh_token": "new-refresh-token",
+			"expires_in":    3600,
+		})
+
+	provider := Github(tpmodels.GithubConfig{
+		ClientID:     "test",
+		ClientSecret: "test-secret",
+	})
+	tokenVault := makeTestTokenVault(t)
+	err := tokenVault.Store.Save("github", "user-1", tpmodels.ProviderTokens{
+		AccessToken:  "access-token",
+		RefreshToken: "refresh-token",
+		Expiry:       uint64(time.Now().Add(-time.Minute).UnixNano() / 1000000),
+		ClientID:     "test",
+	}, &map[string]interface{}{})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	var wg sync.WaitGroup
+	accessTokens := make([]*string, 10)
+	errs := make([]error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			accessTokens[i], errs[i] = api.GetProviderAccessToken([]tpmodels.TypeProvider{provider}, tokenVault, "user-1", "github", &map[string]interface{}{})
+		}(i)
+	}
+	wg.Wait()
+
+	for i := 0; i < 10; i++ {
+		assert.NoError(t, errs[i])
+		if assert.NotNil(t, accessTokens[i]) {
+			assert.Equal(t, "new-access-token", *accessTokens[i])
+		}
+	}
+	assert.True(t, gock.IsDone())
+
+	tokens, err := tokenVault.Store.Get("github", "user-1", &map[string]interface{}{})
+	if err != nil {
+		t.Error(err.Error())
+	}
+	assert.Equal(t, "new-refresh-token", tokens.RefreshToken)
+}
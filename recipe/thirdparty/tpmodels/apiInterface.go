@@ -26,22 +26,32 @@ import (
 type APIInterface struct {
 	AuthorisationUrlGET      *func(provider TypeProvider, options APIOptions, userContext supertokens.UserContext) (AuthorisationUrlGETResponse, error)
 	SignInUpPOST             *func(provider TypeProvider, code string, authCodeResponse interface{}, redirectURI string, options APIOptions, userContext supertokens.UserContext) (SignInUpPOSTResponse, error)
+	NativeSignInUpPOST       *func(provider TypeProvider, tokens NativeTokens, options APIOptions, userContext supertokens.UserContext) (SignInUpPOSTResponse, error)
 	AppleRedirectHandlerPOST *func(code string, state string, options APIOptions, userContext supertokens.UserContext) error
+	SAMLLoginGET             *func(provider TypeProvider, relayState string, options APIOptions, userContext supertokens.UserContext) (SAMLLoginGETResponse, error)
+	SAMLACSPOST              *func(provider TypeProvider, samlResponse string, relayState string, options APIOptions, userContext supertokens.UserContext) error
 }
 
 type AuthorisationUrlGETResponse struct {
 	OK *struct{ Url string }
 }
 
+type SAMLLoginGETResponse struct {
+	OK *struct{ RedirectURL string }
+}
+
 type SignInUpPOSTResponse struct {
 	OK *struct {
-		CreatedNewUser   bool
-		User             User
-		Session          sessmodels.SessionContainer
-		AuthCodeResponse interface{}
+		CreatedNewUser          bool
+		User                    User
+		Session                 sessmodels.SessionContainer
+		AuthCodeResponse        interface{}
+		Profile                 Profile
+		RawUserInfoFromProvider RawUserInfoFromProvider
 	}
 	NoEmailGivenByProviderError *struct{}
 	FieldError                  *struct{ ErrorMsg string }
+	InvalidStateError           *struct{}
 }
 
 type APIOptions struct {
@@ -54,4 +64,7 @@ type APIOptions struct {
 	Res                                   http.ResponseWriter
 	OtherHandler                          http.HandlerFunc
 	AppInfo                               supertokens.NormalisedAppinfo
+	// State is the OAuth state sent by the frontend to the sign in up API. It is checked by
+	// SignInUpPOST if StateAndPKCE is enabled.
+	State string
 }
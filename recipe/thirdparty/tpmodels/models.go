@@ -16,6 +16,8 @@
 package tpmodels
 
 import (
+	"net/http"
+
 	"github.com/supertokens/supertokens-golang/recipe/emailverification/evmodels"
 	"github.com/supertokens/supertokens-golang/supertokens"
 )
@@ -23,6 +25,42 @@ import (
 type UserInfo struct {
 	ID    string
 	Email *EmailStruct
+	// RawUserInfoFromProvider contains everything that the provider returned about the user
+	RawUserInfoFromProvider RawUserInfoFromProvider
+}
+
+type RawUserInfoFromProvider struct {
+	// FromIdTokenPayload contains the claims of the id token, for providers that return one
+	FromIdTokenPayload map[string]interface{}
+	// FromUserInfoAPI contains the response of the user info API of the provider, or the attributes of a SAML assertion
+	FromUserInfoAPI map[string]interface{}
+}
+
+// Profile contains the details of the user that are mapped from the raw user info of the provider
+type Profile struct {
+	Name       string
+	GivenName  string
+	FamilyName string
+	Picture    string
+	Locale     string
+	Groups     []string
+}
+
+// ProfileClaimMapping contains the names of the claims used to fill Profile. The first claim that has
+// a value is used, and the id token payload is checked before the response of the user info API.
+type ProfileClaimMapping struct {
+	// Name defaults to name, username and login
+	Name []string
+	// GivenName defaults to given_name, first_name and localizedFirstName
+	GivenName []string
+	// FamilyName defaults to family_name, last_name and localizedLastName
+	FamilyName []string
+	// Picture defaults to picture and avatar_url
+	Picture []string
+	// Locale defaults to locale
+	Locale []string
+	// Groups defaults to groups
+	Groups []string
 }
 
 type EmailStruct struct {
@@ -36,11 +74,29 @@ type TypeProviderGetResponse struct {
 	GetProfileInfo        func(authCodeResponse interface{}, userContext supertokens.UserContext) (UserInfo, error)
 	GetClientId           func(userContext supertokens.UserContext) string
 	GetRedirectURI        func(userContext supertokens.UserContext) (string, error)
+	// GetProfileInfoFromNativeTokens verifies the tokens that a native app got from the SDK of the provider,
+	// including that they were issued to one of the client IDs of the provider. It is nil for providers
+	// that do not support native sign in.
+	GetProfileInfoFromNativeTokens func(tokens NativeTokens, userContext supertokens.UserContext) (UserInfo, error)
+	// RequiresNonce makes AuthorisationUrlGET add a nonce to the authorisation URL, which is saved with the
+	// OAuth state. SignInUpPOST passes it to GetProfileInfo in the user context, so StateAndPKCE must be enabled.
+	RequiresNonce bool
+	// Error is set if the provider could not be set up, for example because its discovery document could
+	// not be fetched. The APIs return it instead of using the other fields.
+	Error error
+}
+
+// NativeTokens are the tokens that a native app got from the SDK of a provider
+type NativeTokens struct {
+	IDToken     *string
+	AccessToken *string
 }
 
 type AccessTokenAPI struct {
 	URL    string
 	Params map[string]string
+	// Headers are added to the request, for providers that need the client credentials in an Authorization header
+	Headers map[string]string
 }
 
 type AuthorisationRedirect struct {
@@ -52,6 +108,24 @@ type TypeProvider struct {
 	ID        string
 	Get       func(redirectURI *string, authCodeFromRequest *string, userContext supertokens.UserContext) TypeProviderGetResponse
 	IsDefault bool
+	// RequiresPKCE is set by providers that do not work without PKCE, which need StateAndPKCE to be enabled
+	RequiresPKCE bool
+	// SAML is only set for SAML providers. Their sign in starts at the SAML login API instead of the
+	// authorisation URL and the IdP sends its response to the SAML ACS API.
+	SAML *SAMLProviderFunctions
+}
+
+type SAMLProviderFunctions struct {
+	// GetLoginRedirectURL returns the SSO URL of the IdP with a new AuthnRequest
+	GetLoginRedirectURL func(acsURL string, relayState string, userContext supertokens.UserContext) (string, error)
+	// ConsumeSAMLResponse validates the response of the IdP and returns a one time code that
+	// is used instead of an OAuth code in SignInUpPOST
+	ConsumeSAMLResponse func(acsURL string, samlResponse string, userContext supertokens.UserContext) (ConsumeSAMLResponseResponse, error)
+}
+
+type ConsumeSAMLResponseResponse struct {
+	OK                       *struct{ Code string }
+	InvalidSAMLResponseError *struct{ Msg string }
 }
 
 type User struct {
@@ -67,14 +141,132 @@ type User struct {
 type TypeInputEmailVerificationFeature struct {
 	GetEmailVerificationURL  func(user User, userContext supertokens.UserContext) (string, error)
 	CreateAndSendCustomEmail func(user User, emailVerificationURLWithToken string, userContext supertokens.UserContext)
+	// ResendCooldown is the minimum time in milliseconds between two verification emails sent to a user.
+	// It requires a ResendCooldownStore.
+	ResendCooldown      *uint64
+	ResendCooldownStore *evmodels.ResendCooldownStore
+	// GracePeriodAfterSignUp is the time in milliseconds after sign up after which VerifySession
+	// rejects sessions of users that have not verified their email. GetSession does not check it.
+	GracePeriodAfterSignUp *uint64
+	// Mode is either "LINK" (the default) or "CODE". In the "CODE" mode, a numeric code is sent using
+	// CreateAndSendEmailWithCode instead of a link.
+	Mode                       string
+	CreateAndSendEmailWithCode func(user User, code string, codeLifetime uint64, userContext supertokens.UserContext)
+	CodeLifetime               *uint64
+	MaximumCodeInputAttempts   *int
+	// CodeStore is required in the "CODE" mode
+	CodeStore *evmodels.EmailVerificationCodeStore
 }
 
 type TypeInputSignInAndUp struct {
 	Providers []TypeProvider
+	// GetProviders returns the providers to use for a request, for example based on the tenant that the
+	// request is for. If set, it is used instead of Providers. req is nil if the providers are needed
+	// outside of an API request, like in GetProviderAccessToken.
+	GetProviders func(req *http.Request, userContext supertokens.UserContext) ([]TypeProvider, error)
+	// StateAndPKCE makes AuthorisationUrlGET generate the OAuth state and a PKCE code challenge,
+	// which are then checked by SignInUpPOST. SignInUpPOST does not accept an authCodeResponse
+	// while it is enabled, since the code must be exchanged by the backend. It is disabled if nil.
+	StateAndPKCE *TypeInputStateAndPKCE
+	// TokenVault saves the tokens returned by the providers during sign in, so that
+	// GetProviderAccessToken can be used to call their APIs later. It is disabled if nil.
+	TokenVault *TypeInputTokenVault
+	// EmailPolicy restricts the emails that can be used to sign in. It is checked before SignInUp
+	EmailPolicy *TypeInputEmailPolicy
+	// ProfileClaimMapping is used to map the raw user info of the providers to the Profile returned by
+	// SignInUpPOST. The claims that are not set use the defaults.
+	ProfileClaimMapping *ProfileClaimMapping
+	// GetAccessTokenPayload returns the claims that SignInUpPOST adds to the access token payload of the
+	// new session, for example the groups of the user.
+	GetAccessTokenPayload func(user User, profile Profile, rawUserInfo RawUserInfoFromProvider, userContext supertokens.UserContext) (map[string]interface{}, error)
 }
 
 type TypeNormalisedInputSignInAndUp struct {
-	Providers []TypeProvider
+	Providers             []TypeProvider
+	GetProviders          func(req *http.Request, userContext supertokens.UserContext) ([]TypeProvider, error)
+	StateAndPKCE          *TypeNormalisedInputStateAndPKCE
+	TokenVault            *TypeNormalisedInputTokenVault
+	EmailPolicy           *TypeNormalisedInputEmailPolicy
+	ProfileClaimMapping   ProfileClaimMapping
+	GetAccessTokenPayload func(user User, profile Profile, rawUserInfo RawUserInfoFromProvider, userContext supertokens.UserContext) (map[string]interface{}, error)
+}
+
+type TypeInputStateAndPKCE struct {
+	// StateLifetime is the time in milliseconds for which a generated state can be used. Defaults to 10 minutes
+	StateLifetime *uint64
+	// DisablePKCE only generates the state, for providers that do not support PKCE
+	DisablePKCE bool
+	// StateStore is used to save the generated states. It is required, and must be shared by all the
+	// instances of your API since the provider can redirect the user to any of them.
+	StateStore *OAuthStateStore
+}
+
+type TypeNormalisedInputStateAndPKCE struct {
+	StateLifetime uint64
+	DisablePKCE   bool
+	StateStore    OAuthStateStore
+}
+
+type OAuthStateInfo struct {
+	State        string
+	ThirdPartyID string
+	CodeVerifier string
+	// Nonce is only set for providers that require one
+	Nonce  string
+	Expiry uint64
+}
+
+// OAuthStateStore entries are only needed until their Expiry, so they can be expired after that.
+type OAuthStateStore struct {
+	Save func(stateInfo OAuthStateInfo, userContext supertokens.UserContext) error
+	Get  func(state string, userContext supertokens.UserContext) (*OAuthStateInfo, error)
+	// Consume must atomically remove the state and return it, or return nil if there is no such state,
+	// so that a state can only be used once even by parallel requests.
+	Consume func(state string, userContext supertokens.UserContext) (*OAuthStateInfo, error)
+}
+
+type TypeInputEmailPolicy struct {
+	// AllowedDomains are the only email domains that can be used to sign in, if it is not empty
+	AllowedDomains []string
+	// BlockedDomains are email domains that cannot be used to sign in
+	BlockedDomains []string
+	// RequireVerifiedEmail rejects users whose email is not verified by the provider
+	RequireVerifiedEmail bool
+	// AllowedProvidersForDomain maps an email domain to the IDs of the only providers that can be used
+	// to sign in with emails of that domain, for example "example.com" to []string{"google-workspaces"}
+	AllowedProvidersForDomain map[string][]string
+}
+
+type TypeNormalisedInputEmailPolicy struct {
+	AllowedDomains            map[string]bool
+	BlockedDomains            map[string]bool
+	RequireVerifiedEmail      bool
+	AllowedProvidersForDomain map[string][]string
+}
+
+type TypeInputTokenVault struct {
+	// Store is used to save the provider tokens of each user. It is required and must be persistent and
+	// shared by all your API instances. The tokens, including refresh tokens, are passed to it in
+	// plaintext, so it should encrypt them before saving them.
+	Store *ProviderTokenStore
+}
+
+type TypeNormalisedInputTokenVault struct {
+	Store ProviderTokenStore
+}
+
+type ProviderTokens struct {
+	AccessToken  string
+	RefreshToken string
+	// Expiry is the time in milliseconds at which the access token expires, or 0 if the provider did not say
+	Expiry uint64
+	// ClientID identifies the provider that issued the tokens if there are several with the same ID
+	ClientID string
+}
+
+type ProviderTokenStore struct {
+	Save func(thirdPartyID string, userID string, tokens ProviderTokens, userContext supertokens.UserContext) error
+	Get  func(thirdPartyID string, userID string, userContext supertokens.UserContext) (*ProviderTokens, error)
 }
 
 type TypeInput struct {
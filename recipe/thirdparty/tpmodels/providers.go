@@ -15,9 +15,16 @@
 
 package tpmodels
 
+import (
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
 type GoogleConfig struct {
-	ClientID              string
-	ClientSecret          string
+	ClientID     string
+	ClientSecret string
+	// AdditionalClientIDs are the client IDs of native apps, for example for iOS and Android, whose
+	// id tokens are accepted when signing in with an id token
+	AdditionalClientIDs   []string
 	Scope                 []string
 	AuthorisationRedirect *struct {
 		Params map[string]interface{}
@@ -28,6 +35,7 @@ type GoogleConfig struct {
 type GoogleWorkspacesConfig struct {
 	ClientID              string
 	ClientSecret          string
+	AdditionalClientIDs   []string
 	Scope                 []string
 	Domain                *string
 	AuthorisationRedirect *struct {
@@ -66,6 +74,7 @@ type FacebookConfig struct {
 type AppleConfig struct {
 	ClientID              string
 	ClientSecret          AppleClientSecret
+	AdditionalClientIDs   []string
 	Scope                 []string
 	AuthorisationRedirect *struct {
 		Params map[string]interface{}
@@ -78,3 +87,142 @@ type AppleClientSecret struct {
 	PrivateKey string
 	TeamId     string
 }
+
+type OIDCConfig struct {
+	// ThirdPartyID is the ID of the provider as used by the frontend, for example "okta" or "keycloak"
+	ThirdPartyID string
+	// Issuer is used to fetch <Issuer>/.well-known/openid-configuration and must match the iss claim of id tokens
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	// AdditionalClientIDs are the client IDs of native apps, for example for iOS and Android, whose
+	// id tokens are accepted when signing in with an id token
+	AdditionalClientIDs []string
+	// Scope defaults to openid, email and profile
+	Scope []string
+	// the endpoints below are only needed if they should not be taken from the discovery document
+	AuthorizationEndpoint *string
+	TokenEndpoint         *string
+	UserInfoEndpoint      *string
+	JwksURI               *string
+	ClaimMapping          *OIDCClaimMapping
+	AuthorisationRedirect *struct {
+		Params map[string]interface{}
+	}
+	IsDefault bool
+}
+
+// OIDCClaimMapping contains the names of the claims used to fill UserInfo
+type OIDCClaimMapping struct {
+	// ID defaults to sub
+	ID string
+	// Email defaults to email
+	Email string
+	// EmailVerified defaults to email_verified
+	EmailVerified string
+}
+
+type MicrosoftConfig struct {
+	ClientID     string
+	ClientSecret string
+	Scope        []string
+	// TenantID is the directory (tenant) ID of an Azure AD tenant, to only allow its users. It can also be one
+	// of the verified domains of the tenant (like contoso.onmicrosoft.com), whose tenant ID is then read from
+	// the OpenID discovery document of the tenant. Defaults to "common", which allows any work, school or
+	// personal Microsoft account
+	TenantID              *string
+	AuthorisationRedirect *struct {
+		Params map[string]interface{}
+	}
+	IsDefault bool
+}
+
+type GitlabConfig struct {
+	ClientID     string
+	ClientSecret string
+	Scope        []string
+	// BaseURL of a self-hosted GitLab instance. Defaults to https://gitlab.com
+	BaseURL               *string
+	AuthorisationRedirect *struct {
+		Params map[string]interface{}
+	}
+	IsDefault bool
+}
+
+type BitbucketConfig struct {
+	ClientID              string
+	ClientSecret          string
+	Scope                 []string
+	AuthorisationRedirect *struct {
+		Params map[string]interface{}
+	}
+	IsDefault bool
+}
+
+type LinkedinConfig struct {
+	ClientID              string
+	ClientSecret          string
+	Scope                 []string
+	AuthorisationRedirect *struct {
+		Params map[string]interface{}
+	}
+	IsDefault bool
+}
+
+// TwitterConfig is used for Twitter (X). Twitter requires PKCE, so the recipe cannot be initialised unless
+// SignInAndUpFeature.StateAndPKCE is set.
+type TwitterConfig struct {
+	ClientID              string
+	ClientSecret          string
+	Scope                 []string
+	AuthorisationRedirect *struct {
+		Params map[string]interface{}
+	}
+	IsDefault bool
+}
+
+type OktaConfig struct {
+	ClientID            string
+	ClientSecret        string
+	AdditionalClientIDs []string
+	Scope               []string
+	// OktaDomain is the domain of the Okta org, for example dev-123456.okta.com
+	OktaDomain string
+	// AuthorizationServerID defaults to "default". Set it to an empty string to use the org authorization server
+	AuthorizationServerID *string
+	AuthorisationRedirect *struct {
+		Params map[string]interface{}
+	}
+	IsDefault bool
+}
+
+type SAMLConfig struct {
+	// ThirdPartyID is the ID of the provider as used by the frontend, for example "okta-saml"
+	ThirdPartyID string
+	// IdPMetadataXML is the metadata of the IdP. It contains its entity ID, SSO URL and signing certificates
+	IdPMetadataXML string
+	// SPEntityID is the entity ID of this service provider, as configured in the IdP
+	SPEntityID       string
+	AttributeMapping *SAMLAttributeMapping
+	// IsEmailVerified marks the emails returned by the IdP as verified. Only set this if the IdP verifies them
+	IsEmailVerified bool
+	// Store is used to remember AuthnRequests and sign in codes across requests. It is required and must be
+	// shared by all your API instances, since the IdP response can reach another instance than the one that
+	// created the request.
+	Store     *SAMLStore
+	IsDefault bool
+}
+
+// SAMLAttributeMapping contains the names of the attributes used to fill UserInfo
+type SAMLAttributeMapping struct {
+	// ID defaults to the NameID of the subject
+	ID string
+	// Email defaults to the first of the commonly used email attributes, or the NameID if it is an email
+	Email string
+}
+
+type SAMLStore struct {
+	Save func(key string, value string, expiry uint64, userContext supertokens.UserContext) error
+	// Consume returns the value saved for the key if it has not expired, and removes it
+	Consume func(key string, userContext supertokens.UserContext) (*string, error)
+}
@@ -18,6 +18,7 @@ package thirdparty
 import (
 	"encoding/json"
 	"errors"
+	"strings"
 
 	"github.com/supertokens/supertokens-golang/recipe/emailverification/evmodels"
 	"github.com/supertokens/supertokens-golang/recipe/thirdparty/tpmodels"
@@ -68,8 +69,9 @@ func makeTypeNormalisedInput(recipeInstance *Recipe) tpmodels.TypeNormalisedInpu
 
 func validateAndNormaliseEmailVerificationConfig(recipeInstance *Recipe, config *tpmodels.TypeInput) evmodels.TypeInput {
 	emailverificationTypeInput := evmodels.TypeInput{
-		GetEmailForUserID: recipeInstance.getEmailForUserId,
-		Override:          nil,
+		GetEmailForUserID:      recipeInstance.getEmailForUserId,
+		GetTimeJoinedForUserID: recipeInstance.getTimeJoinedForUserId,
+		Override:               nil,
 	}
 
 	if config != nil {
@@ -77,6 +79,27 @@ func validateAndNormaliseEmailVerificationConfig(recipeInstance *Recipe, config
 			emailverificationTypeInput.Override = config.Override.EmailVerificationFeature
 		}
 		if config.EmailVerificationFeature != nil {
+			emailverificationTypeInput.ResendCooldown = config.EmailVerificationFeature.ResendCooldown
+			emailverificationTypeInput.ResendCooldownStore = config.EmailVerificationFeature.ResendCooldownStore
+			emailverificationTypeInput.GracePeriodAfterSignUp = config.EmailVerificationFeature.GracePeriodAfterSignUp
+			emailverificationTypeInput.Mode = config.EmailVerificationFeature.Mode
+			emailverificationTypeInput.CodeLifetime = config.EmailVerificationFeature.CodeLifetime
+			emailverificationTypeInput.MaximumCodeInputAttempts = config.EmailVerificationFeature.MaximumCodeInputAttempts
+			emailverificationTypeInput.CodeStore = config.EmailVerificationFeature.CodeStore
+
+			if config.EmailVerificationFeature.CreateAndSendEmailWithCode != nil {
+				emailverificationTypeInput.CreateAndSendEmailWithCode = func(user evmodels.User, code string, codeLifetime uint64, userContext supertokens.UserContext) {
+					userInfo, err := (*recipeInstance.RecipeImpl.GetUserByID)(user.ID, userContext)
+					if err != nil {
+						return
+					}
+					if userInfo == nil {
+						return
+					}
+					config.EmailVerificationFeature.CreateAndSendEmailWithCode(*userInfo, code, codeLifetime, userContext)
+				}
+			}
+
 			if config.EmailVerificationFeature.CreateAndSendCustomEmail != nil {
 				emailverificationTypeInput.CreateAndSendCustomEmail = func(user evmodels.User, link string, userContext supertokens.UserContext) {
 					userInfo, err := (*recipeInstance.RecipeImpl.GetUserByID)(user.ID, userContext)
@@ -110,10 +133,99 @@ func validateAndNormaliseEmailVerificationConfig(recipeInstance *Recipe, config
 
 func validateAndNormaliseSignInAndUpConfig(config tpmodels.TypeInputSignInAndUp) (tpmodels.TypeNormalisedInputSignInAndUp, error) {
 	providers := config.Providers
-	if len(providers) == 0 {
+	if len(providers) == 0 && config.GetProviders == nil {
 		return tpmodels.TypeNormalisedInputSignInAndUp{}, supertokens.BadInputError{Msg: "thirdparty recipe requires at least 1 provider to be passed in signInAndUpFeature.providers config"}
 	}
 
+	err := validateProviders(providers)
+	if err != nil {
+		return tpmodels.TypeNormalisedInputSignInAndUp{}, err
+	}
+	for _, provider := range providers {
+		if provider.RequiresPKCE && (config.StateAndPKCE == nil || config.StateAndPKCE.DisablePKCE) {
+			return tpmodels.TypeNormalisedInputSignInAndUp{}, errors.New("The third party provider " + provider.ID + " requires PKCE. Please set StateAndPKCE in the SignInAndUpFeature config without DisablePKCE")
+		}
+	}
+
+	stateAndPKCE, err := validateAndNormaliseStateAndPKCEConfig(config.StateAndPKCE)
+	if err != nil {
+		return tpmodels.TypeNormalisedInputSignInAndUp{}, err
+	}
+
+	tokenVault, err := validateAndNormaliseTokenVaultConfig(config.TokenVault)
+	if err != nil {
+		return tpmodels.TypeNormalisedInputSignInAndUp{}, err
+	}
+
+	return tpmodels.TypeNormalisedInputSignInAndUp{
+		Providers:             providers,
+		GetProviders:          config.GetProviders,
+		StateAndPKCE:          stateAndPKCE,
+		TokenVault:            tokenVault,
+		EmailPolicy:           validateAndNormaliseEmailPolicyConfig(config.EmailPolicy),
+		ProfileClaimMapping:   validateAndNormaliseProfileClaimMappingConfig(config.ProfileClaimMapping),
+		GetAccessTokenPayload: config.GetAccessTokenPayload,
+	}, nil
+}
+
+func validateAndNormaliseProfileClaimMappingConfig(config *tpmodels.ProfileClaimMapping) tpmodels.ProfileClaimMapping {
+	claimMapping := tpmodels.ProfileClaimMapping{
+		Name:       []string{"name", "username", "login"},
+		GivenName:  []string{"given_name", "first_name", "localizedFirstName"},
+		FamilyName: []string{"family_name", "last_name", "localizedLastName"},
+		Picture:    []string{"picture", "avatar_url"},
+		Locale:     []string{"locale"},
+		Groups:     []string{"groups"},
+	}
+	if config == nil {
+		return claimMapping
+	}
+	if len(config.Name) > 0 {
+		claimMapping.Name = config.Name
+	}
+	if len(config.GivenName) > 0 {
+		claimMapping.GivenName = config.GivenName
+	}
+	if len(config.FamilyName) > 0 {
+		claimMapping.FamilyName = config.FamilyName
+	}
+	if len(config.Picture) > 0 {
+		claimMapping.Picture = config.Picture
+	}
+	if len(config.Locale) > 0 {
+		claimMapping.Locale = config.Locale
+	}
+	if len(config.Groups) > 0 {
+		claimMapping.Groups = config.Groups
+	}
+	return claimMapping
+}
+
+func validateAndNormaliseEmailPolicyConfig(config *tpmodels.TypeInputEmailPolicy) *tpmodels.TypeNormalisedInputEmailPolicy {
+	if config == nil {
+		return nil
+	}
+	normalisedConfig := tpmodels.TypeNormalisedInputEmailPolicy{
+		AllowedDomains:            map[string]bool{},
+		BlockedDomains:            map[string]bool{},
+		RequireVerifiedEmail:      config.RequireVerifiedEmail,
+		AllowedProvidersForDomain: map[string][]string{},
+	}
+	// domains are compared case insensitively
+	for _, domain := range config.AllowedDomains {
+		normalisedConfig.AllowedDomains[strings.ToLower(strings.TrimSpace(domain))] = true
+	}
+	for _, domain := range config.BlockedDomains {
+		normalisedConfig.BlockedDomains[strings.ToLower(strings.TrimSpace(domain))] = true
+	}
+	for domain, providerIDs := range config.AllowedProvidersForDomain {
+		normalisedConfig.AllowedProvidersForDomain[strings.ToLower(strings.TrimSpace(domain))] = providerIDs
+	}
+	return &normalisedConfig
+}
+
+// validateProviders checks that there is exactly one default provider for each third party ID
+func validateProviders(providers []tpmodels.TypeProvider) error {
 	isDefaultProvidersSet := map[string]bool{}
 	allProvidersSet := map[string]bool{}
 
@@ -135,19 +247,17 @@ func validateAndNormaliseSignInAndUpConfig(config tpmodels.TypeInputSignInAndUp)
 
 		if isDefault {
 			if isDefaultProvidersSet[id] {
-				return tpmodels.TypeNormalisedInputSignInAndUp{}, supertokens.BadInputError{Msg: "You have provided multiple third party providers that have the id: " + providers[i].ID + " and are marked as 'IsDefault: true'. Please only mark one of them as isDefault"}
+				return supertokens.BadInputError{Msg: "You have provided multiple third party providers that have the id: " + providers[i].ID + " and are marked as 'IsDefault: true'. Please only mark one of them as isDefault"}
 			}
 			isDefaultProvidersSet[id] = true
 		}
 	}
 
 	if len(isDefaultProvidersSet) != len(allProvidersSet) {
-		return tpmodels.TypeNormalisedInputSignInAndUp{}, supertokens.BadInputError{Msg: "The providers array has multiple entries for the same third party provider. Please mark one of them as the default one by using 'IsDefault: true'"}
+		return supertokens.BadInputError{Msg: "The providers array has multiple entries for the same third party provider. Please mark one of them as the default one by using 'IsDefault: true'"}
 	}
 
-	return tpmodels.TypeNormalisedInputSignInAndUp{
-		Providers: providers,
-	}, nil
+	return nil
 }
 
 func parseUser(value interface{}) (*tpmodels.User, error) {
@@ -0,0 +1,138 @@
+/*
+ * Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package thirdpartyemailpassword
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/accountlinking"
+	"github.com/supertokens/supertokens-golang/recipe/accountlinking/almodels"
+	"github.com/supertokens/supertokens-golang/recipe/thirdparty/tpmodels"
+	"github.com/supertokens/supertokens-golang/recipe/thirdpartyemailpassword/tpepmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+	"github.com/supertokens/supertokens-golang/test/unittesting"
+)
+
+func makeAccountLinkingStoreForTest() almodels.AccountLinkingStore {
+	var lock sync.Mutex
+	primaryUserIDs := map[string]string{}
+	return almodels.AccountLinkingStore{
+		GetPrimaryUserID: func(recipeUserID string, userContext supertokens.UserContext) (*string, error) {
+			lock.Lock()
+			defer lock.Unlock()
+			primaryUserID, ok := primaryUserIDs[recipeUserID]
+			if !ok {
+				return nil, nil
+			}
+			return &primaryUserID, nil
+		},
+		GetRecipeUserIDs: func(primaryUserID string, userContext supertokens.UserContext) ([]string, error) {
+			lock.Lock()
+			defer lock.Unlock()
+			recipeUserIDs := []string{}
+			for recipeUserID, linkedPrimaryUserID := range primaryUserIDs {
+				if linkedPrimaryUserID == primaryUserID {
+					recipeUserIDs = append(recipeUserIDs, recipeUserID)
+				}
+			}
+			return recipeUserIDs, nil
+		},
+		Link: func(primaryUserID string, recipeUserID string, userContext supertokens.UserContext) error {
+			lock.Lock()
+			defer lock.Unlock()
+			primaryUserIDs[recipeUserID] = primaryUserID
+			return nil
+		},
+		Unlink: func(recipeUserID string, userContext supertokens.UserContext) error {
+			lock.Lock()
+			defer lock.Unlock()
+			delete(primaryUserIDs, recipeUserID)
+			return nil
+		},
+	}
+}
+
+func TestLinkedThirdPartySignInReturnsThePrimaryUser(t *testing.T) {
+	store := makeAccountLinkingStoreForTest()
+	configValue := supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: "http://localhost:8080",
+		},
+		AppInfo: supertokens.AppInfo{
+			APIDomain:     "api.supertokens.io",
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{
+			Init(&tpepmodels.TypeInput{}),
+			accountlinking.Init(&almodels.TypeInput{
+				ShouldDoAutomaticAccountLinking: func(newAccount almodels.AccountInfo, primaryUserID string, userContext supertokens.UserContext) (bool, error) {
+					return true, nil
+				},
+				Store: &store,
+			}),
+		},
+	}
+
+	BeforeEach()
+	unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+	err := supertokens.Init(configValue)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	signUpResponse, err := EmailPasswordSignUp("johndoe@gmail.com", "validpass123")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	primaryUser := signUpResponse.OK.User
+	tokenResponse, err := CreateEmailVerificationToken(primaryUser.ID)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	_, err = VerifyEmailUsingToken(tokenResponse.OK.Token)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	signInUpResponse, err := ThirdPartySignInUp("google", "google-user", tpepmodels.EmailStruct{
+		ID:         "johndoe@gmail.com",
+		IsVerified: true,
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	assert.Equal(t, primaryUser.ID, signInUpResponse.OK.User.ID)
+	assert.Equal(t, "johndoe@gmail.com", signInUpResponse.OK.User.Email)
+	assert.Nil(t, signInUpResponse.OK.User.ThirdParty)
+
+	thirdPartyUser, err := GetUserByThirdPartyInfo("google", "google-user", tpmodels.EmailStruct{})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	assert.NotEqual(t, primaryUser.ID, thirdPartyUser.ID)
+
+	user, err := GetUserById(thirdPartyUser.ID)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	assert.Equal(t, primaryUser.ID, user.ID)
+	assert.Nil(t, user.ThirdParty)
+}
@@ -107,36 +107,16 @@ func MakeAPIImplementation() tpepmodels.APIInterface {
 		if err != nil {
 			return tpepmodels.ThirdPartyOutput{}, err
 		}
-		if response.FieldError != nil {
-			return tpepmodels.ThirdPartyOutput{
-				FieldError: &struct{ ErrorMsg string }{
-					ErrorMsg: response.FieldError.ErrorMsg,
-				},
-			}, nil
-		} else if response.NoEmailGivenByProviderError != nil {
-			return tpepmodels.ThirdPartyOutput{
-				NoEmailGivenByProviderError: &struct{}{},
-			}, nil
-		} else {
-			return tpepmodels.ThirdPartyOutput{
-				OK: &struct {
-					CreatedNewUser   bool
-					User             tpepmodels.User
-					AuthCodeResponse interface{}
-					Session          sessmodels.SessionContainer
-				}{
-					CreatedNewUser:   response.OK.CreatedNewUser,
-					AuthCodeResponse: response.OK.AuthCodeResponse,
-					User: tpepmodels.User{
-						ID:         response.OK.User.ID,
-						TimeJoined: response.OK.User.TimeJoined,
-						Email:      response.OK.User.Email,
-						ThirdParty: &response.OK.User.ThirdParty,
-					},
-					Session: response.OK.Session,
-				},
-			}, nil
+		return makeThirdPartyOutput(response), nil
+	}
+
+	ogNativeSignInUpPOST := *thirdPartyImplementation.NativeSignInUpPOST
+	thirdPartyNativeSignInUpPOST := func(provider tpmodels.TypeProvider, tokens tpmodels.NativeTokens, options tpmodels.APIOptions, userContext supertokens.UserContext) (tpepmodels.ThirdPartyOutput, error) {
+		response, err := ogNativeSignInUpPOST(provider, tokens, options, userContext)
+		if err != nil {
+			return tpepmodels.ThirdPartyOutput{}, err
 		}
+		return makeThirdPartyOutput(response), nil
 	}
 
 	ogAuthorisationUrlGET := *thirdPartyImplementation.AuthorisationUrlGET
@@ -148,15 +128,28 @@ func MakeAPIImplementation() tpepmodels.APIInterface {
 	appleRedirectHandlerPOST := func(code string, state string, options tpmodels.APIOptions, userContext supertokens.UserContext) error {
 		return ogAppleRedirectHandlerPOST(code, state, options, userContext)
 	}
+
+	ogSAMLLoginGET := *thirdPartyImplementation.SAMLLoginGET
+	samlLoginGET := func(provider tpmodels.TypeProvider, relayState string, options tpmodels.APIOptions, userContext supertokens.UserContext) (tpmodels.SAMLLoginGETResponse, error) {
+		return ogSAMLLoginGET(provider, relayState, options, userContext)
+	}
+
+	ogSAMLACSPOST := *thirdPartyImplementation.SAMLACSPOST
+	samlACSPOST := func(provider tpmodels.TypeProvider, samlResponse string, relayState string, options tpmodels.APIOptions, userContext supertokens.UserContext) error {
+		return ogSAMLACSPOST(provider, samlResponse, relayState, options, userContext)
+	}
 	result := tpepmodels.APIInterface{
 		AuthorisationUrlGET:            &authorisationUrlGET,
 		EmailPasswordEmailExistsGET:    &emailExistsGET,
 		GeneratePasswordResetTokenPOST: &generatePasswordResetTokenPOST,
 		PasswordResetPOST:              &passwordResetPOST,
 		ThirdPartySignInUpPOST:         &thirdPartySignInUpPOST,
+		ThirdPartyNativeSignInUpPOST:   &thirdPartyNativeSignInUpPOST,
 		EmailPasswordSignInPOST:        &emailPasswordSignInPOST,
 		EmailPasswordSignUpPOST:        &emailPasswordSignUpPOST,
 		AppleRedirectHandlerPOST:       &appleRedirectHandlerPOST,
+		SAMLLoginGET:                   &samlLoginGET,
+		SAMLACSPOST:                    &samlACSPOST,
 	}
 
 	modifiedEP := GetEmailPasswordIterfaceImpl(result)
@@ -169,7 +162,51 @@ func MakeAPIImplementation() tpepmodels.APIInterface {
 	modifiedTP := GetThirdPartyIterfaceImpl(result)
 	(*thirdPartyImplementation.AuthorisationUrlGET) = *modifiedTP.AuthorisationUrlGET
 	(*thirdPartyImplementation.SignInUpPOST) = *modifiedTP.SignInUpPOST
+	(*thirdPartyImplementation.NativeSignInUpPOST) = *modifiedTP.NativeSignInUpPOST
 	(*thirdPartyImplementation.AppleRedirectHandlerPOST) = *modifiedTP.AppleRedirectHandlerPOST
+	(*thirdPartyImplementation.SAMLLoginGET) = *modifiedTP.SAMLLoginGET
+	(*thirdPartyImplementation.SAMLACSPOST) = *modifiedTP.SAMLACSPOST
 
 	return result
 }
+
+func makeThirdPartyOutput(response tpmodels.SignInUpPOSTResponse) tpepmodels.ThirdPartyOutput {
+	if response.FieldError != nil {
+		return tpepmodels.ThirdPartyOutput{
+			FieldError: &struct{ ErrorMsg string }{
+				ErrorMsg: response.FieldError.ErrorMsg,
+			},
+		}
+	} else if response.NoEmailGivenByProviderError != nil {
+		return tpepmodels.ThirdPartyOutput{
+			NoEmailGivenByProviderError: &struct{}{},
+		}
+	} else if response.InvalidStateError != nil {
+		return tpepmodels.ThirdPartyOutput{
+			InvalidStateError: &struct{}{},
+		}
+	} else {
+		return tpepmodels.ThirdPartyOutput{
+			OK: &struct {
+				CreatedNewUser          bool
+				User                    tpepmodels.User
+				AuthCodeResponse        interface{}
+				Session                 sessmodels.SessionContainer
+				Profile                 tpmodels.Profile
+				RawUserInfoFromProvider tpmodels.RawUserInfoFromProvider
+			}{
+				CreatedNewUser:          response.OK.CreatedNewUser,
+				AuthCodeResponse:        response.OK.AuthCodeResponse,
+				Profile:                 response.OK.Profile,
+				RawUserInfoFromProvider: response.OK.RawUserInfoFromProvider,
+				User: tpepmodels.User{
+					ID:         response.OK.User.ID,
+					TimeJoined: response.OK.User.TimeJoined,
+					Email:      response.OK.User.Email,
+					ThirdParty: &response.OK.User.ThirdParty,
+				},
+				Session: response.OK.Session,
+			},
+		}
+	}
+}
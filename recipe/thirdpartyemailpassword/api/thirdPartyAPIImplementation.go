@@ -23,11 +23,26 @@ import (
 )
 
 func GetThirdPartyIterfaceImpl(apiImplmentation tpepmodels.APIInterface) tpmodels.APIInterface {
+	var nativeSignInUpPOST *func(provider tpmodels.TypeProvider, tokens tpmodels.NativeTokens, options tpmodels.APIOptions, userContext supertokens.UserContext) (tpmodels.SignInUpPOSTResponse, error)
+	if apiImplmentation.ThirdPartyNativeSignInUpPOST != nil && (*apiImplmentation.ThirdPartyNativeSignInUpPOST) != nil {
+		nativeSignInUp := func(provider tpmodels.TypeProvider, tokens tpmodels.NativeTokens, options tpmodels.APIOptions, userContext supertokens.UserContext) (tpmodels.SignInUpPOSTResponse, error) {
+			result, err := (*apiImplmentation.ThirdPartyNativeSignInUpPOST)(provider, tokens, options, userContext)
+			if err != nil {
+				return tpmodels.SignInUpPOSTResponse{}, err
+			}
+			return makeSignInUpPOSTResponse(result), nil
+		}
+		nativeSignInUpPOST = &nativeSignInUp
+	}
+
 	if apiImplmentation.ThirdPartySignInUpPOST == nil || (*apiImplmentation.ThirdPartySignInUpPOST) == nil {
 		return tpmodels.APIInterface{
 			AuthorisationUrlGET:      apiImplmentation.AuthorisationUrlGET,
 			AppleRedirectHandlerPOST: apiImplmentation.AppleRedirectHandlerPOST,
+			SAMLLoginGET:             apiImplmentation.SAMLLoginGET,
+			SAMLACSPOST:              apiImplmentation.SAMLACSPOST,
 			SignInUpPOST:             nil,
+			NativeSignInUpPOST:       nativeSignInUpPOST,
 		}
 	}
 
@@ -36,41 +51,55 @@ func GetThirdPartyIterfaceImpl(apiImplmentation tpepmodels.APIInterface) tpmodel
 		if err != nil {
 			return tpmodels.SignInUpPOSTResponse{}, err
 		}
-
-		if result.OK != nil {
-			return tpmodels.SignInUpPOSTResponse{
-				OK: &struct {
-					CreatedNewUser   bool
-					User             tpmodels.User
-					Session          sessmodels.SessionContainer
-					AuthCodeResponse interface{}
-				}{
-					CreatedNewUser: result.OK.CreatedNewUser,
-					User: tpmodels.User{
-						ID:         result.OK.User.ID,
-						TimeJoined: result.OK.User.TimeJoined,
-						Email:      result.OK.User.Email,
-						ThirdParty: *result.OK.User.ThirdParty,
-					},
-					Session: result.OK.Session,
-				},
-			}, nil
-		} else if result.NoEmailGivenByProviderError != nil {
-			return tpmodels.SignInUpPOSTResponse{
-				NoEmailGivenByProviderError: &struct{}{},
-			}, nil
-		} else {
-			return tpmodels.SignInUpPOSTResponse{
-				FieldError: &struct{ ErrorMsg string }{
-					ErrorMsg: result.FieldError.ErrorMsg,
-				},
-			}, nil
-		}
+		return makeSignInUpPOSTResponse(result), nil
 	}
 
 	return tpmodels.APIInterface{
 		AuthorisationUrlGET:      apiImplmentation.AuthorisationUrlGET,
 		AppleRedirectHandlerPOST: apiImplmentation.AppleRedirectHandlerPOST,
+		SAMLLoginGET:             apiImplmentation.SAMLLoginGET,
+		SAMLACSPOST:              apiImplmentation.SAMLACSPOST,
 		SignInUpPOST:             &signInUpPOST,
+		NativeSignInUpPOST:       nativeSignInUpPOST,
+	}
+}
+
+func makeSignInUpPOSTResponse(result tpepmodels.ThirdPartyOutput) tpmodels.SignInUpPOSTResponse {
+	if result.OK != nil {
+		return tpmodels.SignInUpPOSTResponse{
+			OK: &struct {
+				CreatedNewUser          bool
+				User                    tpmodels.User
+				Session                 sessmodels.SessionContainer
+				AuthCodeResponse        interface{}
+				Profile                 tpmodels.Profile
+				RawUserInfoFromProvider tpmodels.RawUserInfoFromProvider
+			}{
+				CreatedNewUser: result.OK.CreatedNewUser,
+				User: tpmodels.User{
+					ID:         result.OK.User.ID,
+					TimeJoined: result.OK.User.TimeJoined,
+					Email:      result.OK.User.Email,
+					ThirdParty: *result.OK.User.ThirdParty,
+				},
+				Session:                 result.OK.Session,
+				Profile:                 result.OK.Profile,
+				RawUserInfoFromProvider: result.OK.RawUserInfoFromProvider,
+			},
+		}
+	} else if result.NoEmailGivenByProviderError != nil {
+		return tpmodels.SignInUpPOSTResponse{
+			NoEmailGivenByProviderError: &struct{}{},
+		}
+	} else if result.InvalidStateError != nil {
+		return tpmodels.SignInUpPOSTResponse{
+			InvalidStateError: &struct{}{},
+		}
+	} else {
+		return tpmodels.SignInUpPOSTResponse{
+			FieldError: &struct{ ErrorMsg string }{
+				ErrorMsg: result.FieldError.ErrorMsg,
+			},
+		}
 	}
 }
@@ -20,6 +20,7 @@ import (
 
 	"github.com/supertokens/supertokens-golang/recipe/emailpassword/epmodels"
 	"github.com/supertokens/supertokens-golang/recipe/emailverification/evmodels"
+	"github.com/supertokens/supertokens-golang/recipe/thirdparty/api"
 	"github.com/supertokens/supertokens-golang/recipe/thirdparty/tpmodels"
 	"github.com/supertokens/supertokens-golang/recipe/thirdpartyemailpassword/tpepmodels"
 	"github.com/supertokens/supertokens-golang/supertokens"
@@ -101,6 +102,22 @@ func UpdateEmailOrPasswordWithContext(userId string, email *string, password *st
 	return (*instance.RecipeImpl.UpdateEmailOrPassword)(userId, email, password, userContext)
 }
 
+func CreateEmailChangeTokenWithContext(userID string, newEmail string, userContext supertokens.UserContext) (epmodels.CreateEmailChangeTokenResponse, error) {
+	instance, err := getRecipeInstanceOrThrowError()
+	if err != nil {
+		return epmodels.CreateEmailChangeTokenResponse{}, err
+	}
+	return (*instance.RecipeImpl.CreateEmailChangeToken)(userID, newEmail, userContext)
+}
+
+func ChangeEmailUsingTokenWithContext(token string, userContext supertokens.UserContext) (epmodels.ChangeEmailUsingTokenResponse, error) {
+	instance, err := getRecipeInstanceOrThrowError()
+	if err != nil {
+		return epmodels.ChangeEmailUsingTokenResponse{}, err
+	}
+	return (*instance.RecipeImpl.ChangeEmailUsingToken)(token, userContext)
+}
+
 func CreateEmailVerificationTokenWithContext(userID string, userContext supertokens.UserContext) (evmodels.CreateEmailVerificationTokenResponse, error) {
 	instance, err := getRecipeInstanceOrThrowError()
 	if err != nil {
@@ -164,6 +181,21 @@ func UnverifyEmailWithContext(userID string, userContext supertokens.UserContext
 	return (*instance.EmailVerificationRecipe.RecipeImpl.UnverifyEmail)(userID, email, userContext)
 }
 
+func GetProviderAccessTokenWithContext(userID string, thirdPartyID string, userContext supertokens.UserContext) (*string, error) {
+	instance, err := getRecipeInstanceOrThrowError()
+	if err != nil {
+		return nil, err
+	}
+	if instance.thirdPartyRecipe == nil {
+		return nil, errors.New("please configure at least one third party provider to use GetProviderAccessToken")
+	}
+	providers, err := instance.thirdPartyRecipe.GetProviders(nil, userContext)
+	if err != nil {
+		return nil, err
+	}
+	return api.GetProviderAccessToken(providers, instance.thirdPartyRecipe.Config.SignInAndUpFeature.TokenVault, userID, thirdPartyID, userContext)
+}
+
 func ThirdPartySignInUp(thirdPartyID string, thirdPartyUserID string, email tpepmodels.EmailStruct) (tpepmodels.SignInUpResponse, error) {
 	return ThirdPartySignInUpWithContext(thirdPartyID, thirdPartyUserID, email, &map[string]interface{}{})
 }
@@ -200,6 +232,14 @@ func UpdateEmailOrPassword(userId string, email *string, password *string) (epmo
 	return UpdateEmailOrPasswordWithContext(userId, email, password, &map[string]interface{}{})
 }
 
+func CreateEmailChangeToken(userID string, newEmail string) (epmodels.CreateEmailChangeTokenResponse, error) {
+	return CreateEmailChangeTokenWithContext(userID, newEmail, &map[string]interface{}{})
+}
+
+func ChangeEmailUsingToken(token string) (epmodels.ChangeEmailUsingTokenResponse, error) {
+	return ChangeEmailUsingTokenWithContext(token, &map[string]interface{}{})
+}
+
 func CreateEmailVerificationToken(userID string) (evmodels.CreateEmailVerificationTokenResponse, error) {
 	return CreateEmailVerificationTokenWithContext(userID, &map[string]interface{}{})
 }
@@ -219,3 +259,7 @@ func RevokeEmailVerificationTokens(userID string) (evmodels.RevokeEmailVerificat
 func UnverifyEmail(userID string) (evmodels.UnverifyEmailResponse, error) {
 	return UnverifyEmailWithContext(userID, &map[string]interface{}{})
 }
+
+func GetProviderAccessToken(userID string, thirdPartyID string) (*string, error) {
+	return GetProviderAccessTokenWithContext(userID, thirdPartyID, &map[string]interface{}{})
+}
@@ -53,6 +53,10 @@ func MakeRecipe(recipeId string, appInfo supertokens.NormalisedAppinfo, config *
 		return Recipe{}, err
 	}
 	r.Config = verifiedConfig
+
+	// the email change functions are implemented by the emailpassword recipe, before our override of
+	// its functions is applied.
+	var emailPasswordRecipeImplementation epmodels.RecipeInterface
 	{
 		emailpasswordquerierInstance, err := supertokens.GetNewQuerierInstanceOrThrowError(emailpassword.RECIPE_ID)
 		if err != nil {
@@ -63,7 +67,14 @@ func MakeRecipe(recipeId string, appInfo supertokens.NormalisedAppinfo, config *
 			return Recipe{}, err
 		}
 
-		r.RecipeImpl = verifiedConfig.Override.Functions(recipeimplementation.MakeRecipeImplementation(*emailpasswordquerierInstance, thirdpartyquerierInstance))
+		isEmailVerified := func(userID string, email string, userContext supertokens.UserContext) (bool, error) {
+			return (*r.EmailVerificationRecipe.RecipeImpl.IsEmailVerified)(userID, email, userContext)
+		}
+		getEmailPasswordRecipeImplementation := func() epmodels.RecipeInterface {
+			return emailPasswordRecipeImplementation
+		}
+		recipeImplementation := recipeimplementation.MakeAccountLinkingRecipeImplementation(recipeimplementation.MakeRecipeImplementation(*emailpasswordquerierInstance, thirdpartyquerierInstance), isEmailVerified)
+		r.RecipeImpl = verifiedConfig.Override.Functions(recipeimplementation.MakeChangeEmailRecipeImplementation(recipeImplementation, getEmailPasswordRecipeImplementation))
 	}
 	r.APIImpl = verifiedConfig.Override.APIs(api.MakeAPIImplementation())
 
@@ -83,8 +94,10 @@ func MakeRecipe(recipeId string, appInfo supertokens.NormalisedAppinfo, config *
 		emailPasswordConfig := &epmodels.TypeInput{
 			SignUpFeature:                  verifiedConfig.SignUpFeature,
 			ResetPasswordUsingTokenFeature: verifiedConfig.ResetPasswordUsingTokenFeature,
+			ChangeEmailFeature:             verifiedConfig.ChangeEmailFeature,
 			Override: &epmodels.OverrideStruct{
-				Functions: func(_ epmodels.RecipeInterface) epmodels.RecipeInterface {
+				Functions: func(originalImplementation epmodels.RecipeInterface) epmodels.RecipeInterface {
+					emailPasswordRecipeImplementation = originalImplementation
 					return recipeimplementation.MakeEmailPasswordRecipeImplementation(r.RecipeImpl)
 				},
 				APIs: func(_ epmodels.APIInterface) epmodels.APIInterface {
@@ -100,13 +113,20 @@ func MakeRecipe(recipeId string, appInfo supertokens.NormalisedAppinfo, config *
 		r.emailPasswordRecipe = &emailPasswordRecipe
 	} else {
 		r.emailPasswordRecipe = emailPasswordInstance
+		emailPasswordRecipeImplementation = emailPasswordInstance.RecipeImpl
 	}
 
-	if len(verifiedConfig.Providers) > 0 {
+	if len(verifiedConfig.Providers) > 0 || verifiedConfig.GetProviders != nil {
 		if thirdPartyInstance == nil {
 			thirdPartyConfig := &tpmodels.TypeInput{
 				SignInAndUpFeature: tpmodels.TypeInputSignInAndUp{
-					Providers: verifiedConfig.Providers,
+					Providers:             verifiedConfig.Providers,
+					GetProviders:          verifiedConfig.GetProviders,
+					StateAndPKCE:          verifiedConfig.StateAndPKCE,
+					TokenVault:            verifiedConfig.TokenVault,
+					EmailPolicy:           verifiedConfig.EmailPolicy,
+					ProfileClaimMapping:   verifiedConfig.ProfileClaimMapping,
+					GetAccessTokenPayload: verifiedConfig.GetAccessTokenPayload,
 				},
 				Override: &tpmodels.OverrideStruct{
 					Functions: func(_ tpmodels.RecipeInterface) tpmodels.RecipeInterface {
@@ -202,18 +222,18 @@ func (r *Recipe) getAllCORSHeaders() []string {
 	return corsHeaders
 }
 
-func (r *Recipe) handleError(err error, req *http.Request, res http.ResponseWriter) (bool, error) {
-	handleError, err := r.emailPasswordRecipe.RecipeModule.HandleError(err, req, res)
+func (r *Recipe) handleError(originalError error, req *http.Request, res http.ResponseWriter) (bool, error) {
+	handleError, err := r.emailPasswordRecipe.RecipeModule.HandleError(originalError, req, res)
 	if err != nil || handleError {
 		return handleError, err
 	}
 	if r.thirdPartyRecipe != nil {
-		handleError, err = r.thirdPartyRecipe.RecipeModule.HandleError(err, req, res)
+		handleError, err = r.thirdPartyRecipe.RecipeModule.HandleError(originalError, req, res)
 		if err != nil || handleError {
 			return handleError, err
 		}
 	}
-	return r.EmailVerificationRecipe.RecipeModule.HandleError(err, req, res)
+	return r.EmailVerificationRecipe.RecipeModule.HandleError(originalError, req, res)
 }
 
 func (r *Recipe) getEmailForUserId(userID string, userContext supertokens.UserContext) (string, error) {
@@ -227,6 +247,17 @@ func (r *Recipe) getEmailForUserId(userID string, userContext supertokens.UserCo
 	return userInfo.Email, nil
 }
 
+func (r *Recipe) getTimeJoinedForUserId(userID string, userContext supertokens.UserContext) (*uint64, error) {
+	userInfo, err := (*r.RecipeImpl.GetUserByID)(userID, userContext)
+	if err != nil {
+		return nil, err
+	}
+	if userInfo == nil {
+		return nil, nil
+	}
+	return &userInfo.TimeJoined, nil
+}
+
 func ResetForTest() {
 	singletonInstance = nil
 }
@@ -0,0 +1,149 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package recipeimplementation
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/supertokens/supertokens-golang/recipe/accountlinking"
+	"github.com/supertokens/supertokens-golang/recipe/accountlinking/almodels"
+	"github.com/supertokens/supertokens-golang/recipe/thirdpartyemailpassword/tpepmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// MakeAccountLinkingRecipeImplementation makes the sign in and sign up functions return the primary
+// user of the user that signs in, so that sessions are created for the primary user. GetUserByID
+// returns the primary user of the user ID as well. Nothing is looked up when the accountlinking
+// recipe is not initialised.
+func MakeAccountLinkingRecipeImplementation(originalImplementation tpepmodels.RecipeInterface, isEmailVerified func(userID string, email string, userContext supertokens.UserContext) (bool, error)) tpepmodels.RecipeInterface {
+	getPrimaryUserID := func(user tpepmodels.User, isVerified bool, userContext supertokens.UserContext) (string, error) {
+		candidateUserIDs := []string{}
+		if isVerified {
+			users, err := (*originalImplementation.GetUsersByEmail)(user.Email, userContext)
+			if err != nil {
+				return "", err
+			}
+			sort.Slice(users, func(i, j int) bool {
+				return users[i].TimeJoined < users[j].TimeJoined
+			})
+			for _, candidate := range users {
+				if candidate.ID == user.ID {
+					continue
+				}
+				verified, err := isEmailVerified(candidate.ID, candidate.Email, userContext)
+				if err != nil {
+					return "", err
+				}
+				if verified {
+					candidateUserIDs = append(candidateUserIDs, candidate.ID)
+				}
+			}
+		}
+		email := user.Email
+		return accountlinking.GetPrimaryUserIDForSignInUp(almodels.AccountInfo{
+			RecipeUserID: user.ID,
+			Email:        &email,
+			ThirdParty:   user.ThirdParty,
+			IsVerified:   isVerified,
+		}, candidateUserIDs, userContext)
+	}
+
+	ogGetUserByID := *originalImplementation.GetUserByID
+	getPrimaryUser := func(user tpepmodels.User, isVerified bool, userContext supertokens.UserContext) (*tpepmodels.User, error) {
+		primaryUserID, err := getPrimaryUserID(user, isVerified, userContext)
+		if err != nil {
+			return nil, err
+		}
+		if primaryUserID == user.ID {
+			return &user, nil
+		}
+		primaryUser, err := ogGetUserByID(primaryUserID, userContext)
+		if err != nil {
+			return nil, err
+		}
+		if primaryUser == nil {
+			return nil, errors.New("the primary user " + primaryUserID + " of the user " + user.ID + " does not exist")
+		}
+		return primaryUser, nil
+	}
+
+	// a new function is used so that the recipe functions that call GetUserByID still get the recipe user
+	getUserByID := func(userID string, userContext supertokens.UserContext) (*tpepmodels.User, error) {
+		primaryUserID, err := accountlinking.GetPrimaryUserIDOfRecipeUser(userID, userContext)
+		if err != nil {
+			return nil, err
+		}
+		return ogGetUserByID(primaryUserID, userContext)
+	}
+	originalImplementation.GetUserByID = &getUserByID
+
+	ogThirdPartySignInUp := *originalImplementation.ThirdPartySignInUp
+	(*originalImplementation.ThirdPartySignInUp) = func(thirdPartyID string, thirdPartyUserID string, email tpepmodels.EmailStruct, userContext supertokens.UserContext) (tpepmodels.SignInUpResponse, error) {
+		response, err := ogThirdPartySignInUp(thirdPartyID, thirdPartyUserID, email, userContext)
+		if err != nil || response.OK == nil || !accountlinking.IsInitialised() {
+			return response, err
+		}
+		isVerified := email.IsVerified
+		if !isVerified {
+			isVerified, err = isEmailVerified(response.OK.User.ID, response.OK.User.Email, userContext)
+			if err != nil {
+				return tpepmodels.SignInUpResponse{}, err
+			}
+		}
+		primaryUser, err := getPrimaryUser(response.OK.User, isVerified, userContext)
+		if err != nil {
+			return tpepmodels.SignInUpResponse{}, err
+		}
+		response.OK.User = *primaryUser
+		return response, nil
+	}
+
+	ogEmailPasswordSignUp := *originalImplementation.EmailPasswordSignUp
+	(*originalImplementation.EmailPasswordSignUp) = func(email string, password string, userContext supertokens.UserContext) (tpepmodels.SignUpResponse, error) {
+		response, err := ogEmailPasswordSignUp(email, password, userContext)
+		if err != nil || response.OK == nil || !accountlinking.IsInitialised() {
+			return response, err
+		}
+		// the email of a new email password user is never verified, so it is not linked until it is
+		primaryUser, err := getPrimaryUser(response.OK.User, false, userContext)
+		if err != nil {
+			return tpepmodels.SignUpResponse{}, err
+		}
+		response.OK.User = *primaryUser
+		return response, nil
+	}
+
+	ogEmailPasswordSignIn := *originalImplementation.EmailPasswordSignIn
+	(*originalImplementation.EmailPasswordSignIn) = func(email string, password string, userContext supertokens.UserContext) (tpepmodels.SignInResponse, error) {
+		response, err := ogEmailPasswordSignIn(email, password, userContext)
+		if err != nil || response.OK == nil || !accountlinking.IsInitialised() {
+			return response, err
+		}
+		isVerified, err := isEmailVerified(response.OK.User.ID, response.OK.User.Email, userContext)
+		if err != nil {
+			return tpepmodels.SignInResponse{}, err
+		}
+		primaryUser, err := getPrimaryUser(response.OK.User, isVerified, userContext)
+		if err != nil {
+			return tpepmodels.SignInResponse{}, err
+		}
+		response.OK.User = *primaryUser
+		return response, nil
+	}
+
+	return originalImplementation
+}
@@ -0,0 +1,40 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package recipeimplementation
+
+import (
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/epmodels"
+	"github.com/supertokens/supertokens-golang/recipe/thirdpartyemailpassword/tpepmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// MakeChangeEmailRecipeImplementation adds the email change functions to the recipe interface. They
+// are implemented by the emailpassword recipe, which is only created after this recipe implementation,
+// so it is fetched lazily. getEmailPasswordRecipeImplementation must not return the implementation made
+// by MakeEmailPasswordRecipeImplementation, since that calls back into these functions.
+func MakeChangeEmailRecipeImplementation(originalImplementation tpepmodels.RecipeInterface, getEmailPasswordRecipeImplementation func() epmodels.RecipeInterface) tpepmodels.RecipeInterface {
+	createEmailChangeToken := func(userID string, newEmail string, userContext supertokens.UserContext) (epmodels.CreateEmailChangeTokenResponse, error) {
+		return (*getEmailPasswordRecipeImplementation().CreateEmailChangeToken)(userID, newEmail, userContext)
+	}
+
+	changeEmailUsingToken := func(token string, userContext supertokens.UserContext) (epmodels.ChangeEmailUsingTokenResponse, error) {
+		return (*getEmailPasswordRecipeImplementation().ChangeEmailUsingToken)(token, userContext)
+	}
+
+	originalImplementation.CreateEmailChangeToken = &createEmailChangeToken
+	originalImplementation.ChangeEmailUsingToken = &changeEmailUsingToken
+	return originalImplementation
+}
@@ -110,6 +110,14 @@ func MakeEmailPasswordRecipeImplementation(recipeImplementation tpepmodels.Recip
 		return (*recipeImplementation.UpdateEmailOrPassword)(userId, email, password, userContext)
 	}
 
+	createEmailChangeToken := func(userID string, newEmail string, userContext supertokens.UserContext) (epmodels.CreateEmailChangeTokenResponse, error) {
+		return (*recipeImplementation.CreateEmailChangeToken)(userID, newEmail, userContext)
+	}
+
+	changeEmailUsingToken := func(token string, userContext supertokens.UserContext) (epmodels.ChangeEmailUsingTokenResponse, error) {
+		return (*recipeImplementation.ChangeEmailUsingToken)(token, userContext)
+	}
+
 	return epmodels.RecipeInterface{
 		SignUp:                   &signUp,
 		SignIn:                   &signIn,
@@ -118,5 +126,7 @@ func MakeEmailPasswordRecipeImplementation(recipeImplementation tpepmodels.Recip
 		CreateResetPasswordToken: &createResetPasswordToken,
 		ResetPasswordUsingToken:  &resetPasswordUsingToken,
 		UpdateEmailOrPassword:    &updateEmailOrPassword,
+		CreateEmailChangeToken:   &createEmailChangeToken,
+		ChangeEmailUsingToken:    &changeEmailUsingToken,
 	}
 }
@@ -55,18 +55,22 @@ func MakeThirdPartyRecipeImplementation(recipeImplementation tpepmodels.RecipeIn
 			}, nil
 		}
 
+		user := tpmodels.User{
+			ID:         result.OK.User.ID,
+			Email:      result.OK.User.Email,
+			TimeJoined: result.OK.User.TimeJoined,
+		}
+		// the user is an email password user if the third party user is linked to one
+		if result.OK.User.ThirdParty != nil {
+			user.ThirdParty = *result.OK.User.ThirdParty
+		}
 		return tpmodels.SignInUpResponse{
 			OK: &struct {
 				CreatedNewUser bool
 				User           tpmodels.User
 			}{
 				CreatedNewUser: result.OK.CreatedNewUser,
-				User: tpmodels.User{
-					ID:         result.OK.User.ID,
-					Email:      result.OK.User.Email,
-					TimeJoined: result.OK.User.TimeJoined,
-					ThirdParty: *result.OK.User.ThirdParty,
-				},
+				User:           user,
 			},
 		}, nil
 	}
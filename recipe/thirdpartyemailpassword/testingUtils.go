@@ -16,6 +16,7 @@
 package thirdpartyemailpassword
 
 import (
+	"github.com/supertokens/supertokens-golang/recipe/accountlinking"
 	"github.com/supertokens/supertokens-golang/recipe/session"
 	"github.com/supertokens/supertokens-golang/recipe/thirdparty/tpmodels"
 	"github.com/supertokens/supertokens-golang/supertokens"
@@ -26,6 +27,7 @@ func resetAll() {
 	supertokens.ResetForTest()
 	ResetForTest()
 	session.ResetForTest()
+	accountlinking.ResetForTest()
 }
 
 func BeforeEach() {
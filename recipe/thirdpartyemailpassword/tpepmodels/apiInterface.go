@@ -25,10 +25,13 @@ import (
 type APIInterface struct {
 	AuthorisationUrlGET            *func(provider tpmodels.TypeProvider, options tpmodels.APIOptions, userContext supertokens.UserContext) (tpmodels.AuthorisationUrlGETResponse, error)
 	AppleRedirectHandlerPOST       *func(code string, state string, options tpmodels.APIOptions, userContext supertokens.UserContext) error
+	SAMLLoginGET                   *func(provider tpmodels.TypeProvider, relayState string, options tpmodels.APIOptions, userContext supertokens.UserContext) (tpmodels.SAMLLoginGETResponse, error)
+	SAMLACSPOST                    *func(provider tpmodels.TypeProvider, samlResponse string, relayState string, options tpmodels.APIOptions, userContext supertokens.UserContext) error
 	EmailPasswordEmailExistsGET    *func(email string, options epmodels.APIOptions, userContext supertokens.UserContext) (epmodels.EmailExistsGETResponse, error)
 	GeneratePasswordResetTokenPOST *func(formFields []epmodels.TypeFormField, options epmodels.APIOptions, userContext supertokens.UserContext) (epmodels.GeneratePasswordResetTokenPOSTResponse, error)
 	PasswordResetPOST              *func(formFields []epmodels.TypeFormField, token string, options epmodels.APIOptions, userContext supertokens.UserContext) (epmodels.ResetPasswordUsingTokenResponse, error)
 	ThirdPartySignInUpPOST         *func(provider tpmodels.TypeProvider, code string, authCodeResponse interface{}, redirectURI string, options tpmodels.APIOptions, userContext supertokens.UserContext) (ThirdPartyOutput, error)
+	ThirdPartyNativeSignInUpPOST   *func(provider tpmodels.TypeProvider, tokens tpmodels.NativeTokens, options tpmodels.APIOptions, userContext supertokens.UserContext) (ThirdPartyOutput, error)
 	EmailPasswordSignInPOST        *func(formFields []epmodels.TypeFormField, options epmodels.APIOptions, userContext supertokens.UserContext) (SignInPOSTResponse, error)
 	EmailPasswordSignUpPOST        *func(formFields []epmodels.TypeFormField, options epmodels.APIOptions, userContext supertokens.UserContext) (SignUpPOSTResponse, error)
 }
@@ -71,11 +74,14 @@ type EmailpasswordOutput struct {
 
 type ThirdPartyOutput struct {
 	OK *struct {
-		CreatedNewUser   bool
-		User             User
-		AuthCodeResponse interface{}
-		Session          sessmodels.SessionContainer
+		CreatedNewUser          bool
+		User                    User
+		AuthCodeResponse        interface{}
+		Session                 sessmodels.SessionContainer
+		Profile                 tpmodels.Profile
+		RawUserInfoFromProvider tpmodels.RawUserInfoFromProvider
 	}
 	NoEmailGivenByProviderError *struct{}
 	FieldError                  *struct{ ErrorMsg string }
+	InvalidStateError           *struct{}
 }
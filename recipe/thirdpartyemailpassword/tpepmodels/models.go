@@ -16,6 +16,8 @@
 package tpepmodels
 
 import (
+	"net/http"
+
 	"github.com/supertokens/supertokens-golang/recipe/emailpassword/epmodels"
 	"github.com/supertokens/supertokens-golang/recipe/emailverification/evmodels"
 	"github.com/supertokens/supertokens-golang/recipe/thirdparty/tpmodels"
@@ -40,12 +42,34 @@ type TypeContext struct {
 type TypeInputEmailVerificationFeature struct {
 	GetEmailVerificationURL  func(user User, userContext supertokens.UserContext) (string, error)
 	CreateAndSendCustomEmail func(user User, emailVerificationURLWithToken string, userContext supertokens.UserContext)
+	// ResendCooldown is the minimum time in milliseconds between two verification emails sent to a user.
+	// It requires a ResendCooldownStore.
+	ResendCooldown      *uint64
+	ResendCooldownStore *evmodels.ResendCooldownStore
+	// GracePeriodAfterSignUp is the time in milliseconds after sign up after which VerifySession
+	// rejects sessions of users that have not verified their email. GetSession does not check it.
+	GracePeriodAfterSignUp *uint64
+	// Mode is either "LINK" (the default) or "CODE". In the "CODE" mode, a numeric code is sent using
+	// CreateAndSendEmailWithCode instead of a link.
+	Mode                       string
+	CreateAndSendEmailWithCode func(user User, code string, codeLifetime uint64, userContext supertokens.UserContext)
+	CodeLifetime               *uint64
+	MaximumCodeInputAttempts   *int
+	// CodeStore is required in the "CODE" mode
+	CodeStore *evmodels.EmailVerificationCodeStore
 }
 
 type TypeInput struct {
 	SignUpFeature                  *epmodels.TypeInputSignUp
 	Providers                      []tpmodels.TypeProvider
+	GetProviders                   func(req *http.Request, userContext supertokens.UserContext) ([]tpmodels.TypeProvider, error)
+	StateAndPKCE                   *tpmodels.TypeInputStateAndPKCE
+	TokenVault                     *tpmodels.TypeInputTokenVault
+	EmailPolicy                    *tpmodels.TypeInputEmailPolicy
+	ProfileClaimMapping            *tpmodels.ProfileClaimMapping
+	GetAccessTokenPayload          func(user tpmodels.User, profile tpmodels.Profile, rawUserInfo tpmodels.RawUserInfoFromProvider, userContext supertokens.UserContext) (map[string]interface{}, error)
 	ResetPasswordUsingTokenFeature *epmodels.TypeInputResetPasswordUsingTokenFeature
+	ChangeEmailFeature             *epmodels.TypeInputChangeEmailFeature
 	EmailVerificationFeature       *TypeInputEmailVerificationFeature
 	Override                       *OverrideStruct
 }
@@ -53,7 +77,14 @@ type TypeInput struct {
 type TypeNormalisedInput struct {
 	SignUpFeature                  *epmodels.TypeInputSignUp
 	Providers                      []tpmodels.TypeProvider
+	GetProviders                   func(req *http.Request, userContext supertokens.UserContext) ([]tpmodels.TypeProvider, error)
+	StateAndPKCE                   *tpmodels.TypeInputStateAndPKCE
+	TokenVault                     *tpmodels.TypeInputTokenVault
+	EmailPolicy                    *tpmodels.TypeInputEmailPolicy
+	ProfileClaimMapping            *tpmodels.ProfileClaimMapping
+	GetAccessTokenPayload          func(user tpmodels.User, profile tpmodels.Profile, rawUserInfo tpmodels.RawUserInfoFromProvider, userContext supertokens.UserContext) (map[string]interface{}, error)
 	ResetPasswordUsingTokenFeature *epmodels.TypeInputResetPasswordUsingTokenFeature
+	ChangeEmailFeature             *epmodels.TypeInputChangeEmailFeature
 	EmailVerificationFeature       evmodels.TypeInput
 	Override                       OverrideStruct
 }
@@ -30,6 +30,8 @@ type RecipeInterface struct {
 	CreateResetPasswordToken *func(userID string, userContext supertokens.UserContext) (epmodels.CreateResetPasswordTokenResponse, error)
 	ResetPasswordUsingToken  *func(token string, newPassword string, userContext supertokens.UserContext) (epmodels.ResetPasswordUsingTokenResponse, error)
 	UpdateEmailOrPassword    *func(userId string, email *string, password *string, userContext supertokens.UserContext) (epmodels.UpdateEmailOrPasswordResponse, error)
+	CreateEmailChangeToken   *func(userID string, newEmail string, userContext supertokens.UserContext) (epmodels.CreateEmailChangeTokenResponse, error)
+	ChangeEmailUsingToken    *func(token string, userContext supertokens.UserContext) (epmodels.ChangeEmailUsingTokenResponse, error)
 }
 
 type SignInUpResponse struct {
@@ -34,12 +34,25 @@ func validateAndNormaliseUserInput(recipeInstance *Recipe, appInfo supertokens.N
 		typeNormalisedInput.Providers = config.Providers
 	}
 
+	if config != nil {
+		typeNormalisedInput.GetProviders = config.GetProviders
+		typeNormalisedInput.StateAndPKCE = config.StateAndPKCE
+		typeNormalisedInput.TokenVault = config.TokenVault
+		typeNormalisedInput.EmailPolicy = config.EmailPolicy
+		typeNormalisedInput.ProfileClaimMapping = config.ProfileClaimMapping
+		typeNormalisedInput.GetAccessTokenPayload = config.GetAccessTokenPayload
+	}
+
 	typeNormalisedInput.EmailVerificationFeature = validateAndNormaliseEmailVerificationConfig(recipeInstance, config)
 
 	if config != nil && config.ResetPasswordUsingTokenFeature != nil {
 		typeNormalisedInput.ResetPasswordUsingTokenFeature = config.ResetPasswordUsingTokenFeature
 	}
 
+	if config != nil && config.ChangeEmailFeature != nil {
+		typeNormalisedInput.ChangeEmailFeature = config.ChangeEmailFeature
+	}
+
 	if config != nil && config.Override != nil {
 		if config.Override.Functions != nil {
 			typeNormalisedInput.Override.Functions = config.Override.Functions
@@ -75,8 +88,9 @@ func makeTypeNormalisedInput(recipeInstance *Recipe) tpepmodels.TypeNormalisedIn
 
 func validateAndNormaliseEmailVerificationConfig(recipeInstance *Recipe, config *tpepmodels.TypeInput) evmodels.TypeInput {
 	emailverificationTypeInput := evmodels.TypeInput{
-		GetEmailForUserID: recipeInstance.getEmailForUserId,
-		Override:          nil,
+		GetEmailForUserID:      recipeInstance.getEmailForUserId,
+		GetTimeJoinedForUserID: recipeInstance.getTimeJoinedForUserId,
+		Override:               nil,
 	}
 
 	if config != nil {
@@ -84,6 +98,27 @@ func validateAndNormaliseEmailVerificationConfig(recipeInstance *Recipe, config
 			emailverificationTypeInput.Override = config.Override.EmailVerificationFeature
 		}
 		if config.EmailVerificationFeature != nil {
+			emailverificationTypeInput.ResendCooldown = config.EmailVerificationFeature.ResendCooldown
+			emailverificationTypeInput.ResendCooldownStore = config.EmailVerificationFeature.ResendCooldownStore
+			emailverificationTypeInput.GracePeriodAfterSignUp = config.EmailVerificationFeature.GracePeriodAfterSignUp
+			emailverificationTypeInput.Mode = config.EmailVerificationFeature.Mode
+			emailverificationTypeInput.CodeLifetime = config.EmailVerificationFeature.CodeLifetime
+			emailverificationTypeInput.MaximumCodeInputAttempts = config.EmailVerificationFeature.MaximumCodeInputAttempts
+			emailverificationTypeInput.CodeStore = config.EmailVerificationFeature.CodeStore
+
+			if config.EmailVerificationFeature.CreateAndSendEmailWithCode != nil {
+				emailverificationTypeInput.CreateAndSendEmailWithCode = func(user evmodels.User, code string, codeLifetime uint64, userContext supertokens.UserContext) {
+					userInfo, err := (*recipeInstance.RecipeImpl.GetUserByID)(user.ID, userContext)
+					if err != nil {
+						return
+					}
+					if userInfo == nil {
+						return
+					}
+					config.EmailVerificationFeature.CreateAndSendEmailWithCode(*userInfo, code, codeLifetime, userContext)
+				}
+			}
+
 			if config.EmailVerificationFeature.CreateAndSendCustomEmail != nil {
 				emailverificationTypeInput.CreateAndSendCustomEmail = func(user evmodels.User, link string, userContext supertokens.UserContext) {
 					userInfo, err := (*recipeInstance.RecipeImpl.GetUserByID)(user.ID, userContext)
@@ -35,37 +35,16 @@ func MakeAPIImplementation() tplmodels.APIInterface {
 		if err != nil {
 			return tplmodels.ThirdPartySignInUpOutput{}, err
 		}
-		if response.FieldError != nil {
-			return tplmodels.ThirdPartySignInUpOutput{
-				FieldError: &struct{ ErrorMsg string }{
-					ErrorMsg: response.FieldError.ErrorMsg,
-				},
-			}, nil
-		} else if response.NoEmailGivenByProviderError != nil {
-			return tplmodels.ThirdPartySignInUpOutput{
-				NoEmailGivenByProviderError: &struct{}{},
-			}, nil
-		} else {
-			return tplmodels.ThirdPartySignInUpOutput{
-				OK: &struct {
-					CreatedNewUser   bool
-					User             tplmodels.User
-					AuthCodeResponse interface{}
-					Session          sessmodels.SessionContainer
-				}{
-					CreatedNewUser:   response.OK.CreatedNewUser,
-					AuthCodeResponse: response.OK.AuthCodeResponse,
-					User: tplmodels.User{
-						ID:          response.OK.User.ID,
-						TimeJoined:  response.OK.User.TimeJoined,
-						Email:       &response.OK.User.Email,
-						PhoneNumber: nil,
-						ThirdParty:  &response.OK.User.ThirdParty,
-					},
-					Session: response.OK.Session,
-				},
-			}, nil
+		return makeThirdPartyOutput(response), nil
+	}
+
+	ogNativeSignInUpPOST := *thirdPartyImplementation.NativeSignInUpPOST
+	thirdPartyNativeSignInUpPOST := func(provider tpmodels.TypeProvider, tokens tpmodels.NativeTokens, options tpmodels.APIOptions, userContext supertokens.UserContext) (tplmodels.ThirdPartySignInUpOutput, error) {
+		response, err := ogNativeSignInUpPOST(provider, tokens, options, userContext)
+		if err != nil {
+			return tplmodels.ThirdPartySignInUpOutput{}, err
 		}
+		return makeThirdPartyOutput(response), nil
 	}
 
 	ogAuthorisationUrlGET := *thirdPartyImplementation.AuthorisationUrlGET
@@ -78,6 +57,16 @@ func MakeAPIImplementation() tplmodels.APIInterface {
 		return ogAppleRedirectHandlerPOST(code, state, options, userContext)
 	}
 
+	ogSAMLLoginGET := *thirdPartyImplementation.SAMLLoginGET
+	samlLoginGET := func(provider tpmodels.TypeProvider, relayState string, options tpmodels.APIOptions, userContext supertokens.UserContext) (tpmodels.SAMLLoginGETResponse, error) {
+		return ogSAMLLoginGET(provider, relayState, options, userContext)
+	}
+
+	ogSAMLACSPOST := *thirdPartyImplementation.SAMLACSPOST
+	samlACSPOST := func(provider tpmodels.TypeProvider, samlResponse string, relayState string, options tpmodels.APIOptions, userContext supertokens.UserContext) error {
+		return ogSAMLACSPOST(provider, samlResponse, relayState, options, userContext)
+	}
+
 	ogConsumeCodePOST := *passwordlessImplementation.ConsumeCodePOST
 	consumeCodePOST := func(userInput *plessmodels.UserInputCodeWithDeviceID, linkCode *string, preAuthSessionID string, options plessmodels.APIOptions, userContext supertokens.UserContext) (tplmodels.ConsumeCodePOSTResponse, error) {
 		resp, err := ogConsumeCodePOST(userInput, linkCode, preAuthSessionID, options, userContext)
@@ -114,6 +103,10 @@ func MakeAPIImplementation() tplmodels.APIInterface {
 			return tplmodels.ConsumeCodePOSTResponse{
 				RestartFlowError: &struct{}{},
 			}, nil
+		} else if resp.CrossDeviceLinkError != nil {
+			return tplmodels.ConsumeCodePOSTResponse{
+				CrossDeviceLinkError: resp.CrossDeviceLinkError,
+			}, nil
 		} else {
 			return tplmodels.ConsumeCodePOSTResponse{
 				GeneralError: resp.GeneralError,
@@ -141,15 +134,36 @@ func MakeAPIImplementation() tplmodels.APIInterface {
 		return ogResendCodePOST(deviceID, preAuthSessionID, options, userContext)
 	}
 
+	ogConfirmCrossDeviceSignInPOST := *passwordlessImplementation.ConfirmCrossDeviceSignInPOST
+	confirmCrossDeviceSignInPOST := func(preAuthSessionID string, options plessmodels.APIOptions, userContext supertokens.UserContext) (plessmodels.ConfirmCrossDeviceSignInPOSTResponse, error) {
+		return ogConfirmCrossDeviceSignInPOST(preAuthSessionID, options, userContext)
+	}
+
+	ogCreateSecondFactorCodePOST := *passwordlessImplementation.CreateSecondFactorCodePOST
+	createSecondFactorCodePOST := func(options plessmodels.APIOptions, userContext supertokens.UserContext) (plessmodels.CreateSecondFactorCodePOSTResponse, error) {
+		return ogCreateSecondFactorCodePOST(options, userContext)
+	}
+
+	ogConsumeSecondFactorCodePOST := *passwordlessImplementation.ConsumeSecondFactorCodePOST
+	consumeSecondFactorCodePOST := func(userInputCode string, options plessmodels.APIOptions, userContext supertokens.UserContext) (plessmodels.ConsumeSecondFactorCodePOSTResponse, error) {
+		return ogConsumeSecondFactorCodePOST(userInputCode, options, userContext)
+	}
+
 	result := tplmodels.APIInterface{
 		AuthorisationUrlGET:              &authorisationUrlGET,
 		ThirdPartySignInUpPOST:           &thirdPartySignInUpPOST,
+		ThirdPartyNativeSignInUpPOST:     &thirdPartyNativeSignInUpPOST,
 		AppleRedirectHandlerPOST:         &appleRedirectHandlerPOST,
+		SAMLLoginGET:                     &samlLoginGET,
+		SAMLACSPOST:                      &samlACSPOST,
 		CreateCodePOST:                   &createCodePOST,
 		ResendCodePOST:                   &resendCodePOST,
 		ConsumeCodePOST:                  &consumeCodePOST,
 		PasswordlessEmailExistsGET:       &passwordlessEmailExistsGET,
 		PasswordlessPhoneNumberExistsGET: &passwordlessPhoneNumberExistsGET,
+		ConfirmCrossDeviceSignInPOST:     &confirmCrossDeviceSignInPOST,
+		CreateSecondFactorCodePOST:       &createSecondFactorCodePOST,
+		ConsumeSecondFactorCodePOST:      &consumeSecondFactorCodePOST,
 	}
 
 	modifiedPwdless := GetPasswordlessIterfaceImpl(result)
@@ -158,11 +172,59 @@ func MakeAPIImplementation() tplmodels.APIInterface {
 	(*passwordlessImplementation.EmailExistsGET) = *modifiedPwdless.EmailExistsGET
 	(*passwordlessImplementation.PhoneNumberExistsGET) = *modifiedPwdless.PhoneNumberExistsGET
 	(*passwordlessImplementation.ResendCodePOST) = *modifiedPwdless.ResendCodePOST
+	(*passwordlessImplementation.ConfirmCrossDeviceSignInPOST) = *modifiedPwdless.ConfirmCrossDeviceSignInPOST
+	(*passwordlessImplementation.CreateSecondFactorCodePOST) = *modifiedPwdless.CreateSecondFactorCodePOST
+	(*passwordlessImplementation.ConsumeSecondFactorCodePOST) = *modifiedPwdless.ConsumeSecondFactorCodePOST
 
 	modifiedTP := GetThirdPartyIterfaceImpl(result)
 	(*thirdPartyImplementation.AuthorisationUrlGET) = *modifiedTP.AuthorisationUrlGET
 	(*thirdPartyImplementation.SignInUpPOST) = *modifiedTP.SignInUpPOST
+	(*thirdPartyImplementation.NativeSignInUpPOST) = *modifiedTP.NativeSignInUpPOST
 	(*thirdPartyImplementation.AppleRedirectHandlerPOST) = *modifiedTP.AppleRedirectHandlerPOST
+	(*thirdPartyImplementation.SAMLLoginGET) = *modifiedTP.SAMLLoginGET
+	(*thirdPartyImplementation.SAMLACSPOST) = *modifiedTP.SAMLACSPOST
 
 	return result
 }
+
+func makeThirdPartyOutput(response tpmodels.SignInUpPOSTResponse) tplmodels.ThirdPartySignInUpOutput {
+	if response.FieldError != nil {
+		return tplmodels.ThirdPartySignInUpOutput{
+			FieldError: &struct{ ErrorMsg string }{
+				ErrorMsg: response.FieldError.ErrorMsg,
+			},
+		}
+	} else if response.NoEmailGivenByProviderError != nil {
+		return tplmodels.ThirdPartySignInUpOutput{
+			NoEmailGivenByProviderError: &struct{}{},
+		}
+	} else if response.InvalidStateError != nil {
+		return tplmodels.ThirdPartySignInUpOutput{
+			InvalidStateError: &struct{}{},
+		}
+	} else {
+		return tplmodels.ThirdPartySignInUpOutput{
+			OK: &struct {
+				CreatedNewUser          bool
+				User                    tplmodels.User
+				AuthCodeResponse        interface{}
+				Session                 sessmodels.SessionContainer
+				Profile                 tpmodels.Profile
+				RawUserInfoFromProvider tpmodels.RawUserInfoFromProvider
+			}{
+				CreatedNewUser:          response.OK.CreatedNewUser,
+				AuthCodeResponse:        response.OK.AuthCodeResponse,
+				Profile:                 response.OK.Profile,
+				RawUserInfoFromProvider: response.OK.RawUserInfoFromProvider,
+				User: tplmodels.User{
+					ID:          response.OK.User.ID,
+					TimeJoined:  response.OK.User.TimeJoined,
+					Email:       &response.OK.User.Email,
+					PhoneNumber: nil,
+					ThirdParty:  &response.OK.User.ThirdParty,
+				},
+				Session: response.OK.Session,
+			},
+		}
+	}
+}
@@ -25,11 +25,14 @@ import (
 func GetPasswordlessIterfaceImpl(apiImplmentation tplmodels.APIInterface) plessmodels.APIInterface {
 
 	result := plessmodels.APIInterface{
-		CreateCodePOST:       apiImplmentation.CreateCodePOST,
-		ResendCodePOST:       apiImplmentation.ResendCodePOST,
-		EmailExistsGET:       apiImplmentation.PasswordlessEmailExistsGET,
-		PhoneNumberExistsGET: apiImplmentation.PasswordlessPhoneNumberExistsGET,
-		ConsumeCodePOST:      nil,
+		CreateCodePOST:               apiImplmentation.CreateCodePOST,
+		ResendCodePOST:               apiImplmentation.ResendCodePOST,
+		EmailExistsGET:               apiImplmentation.PasswordlessEmailExistsGET,
+		PhoneNumberExistsGET:         apiImplmentation.PasswordlessPhoneNumberExistsGET,
+		ConfirmCrossDeviceSignInPOST: apiImplmentation.ConfirmCrossDeviceSignInPOST,
+		CreateSecondFactorCodePOST:   apiImplmentation.CreateSecondFactorCodePOST,
+		ConsumeSecondFactorCodePOST:  apiImplmentation.ConsumeSecondFactorCodePOST,
+		ConsumeCodePOST:              nil,
 	}
 
 	if apiImplmentation.ConsumeCodePOST != nil && (*apiImplmentation.ConsumeCodePOST) != nil {
@@ -65,6 +68,10 @@ func GetPasswordlessIterfaceImpl(apiImplmentation tplmodels.APIInterface) plessm
 				return plessmodels.ConsumeCodePOSTResponse{
 					RestartFlowError: &struct{}{},
 				}, nil
+			} else if result.CrossDeviceLinkError != nil {
+				return plessmodels.ConsumeCodePOSTResponse{
+					CrossDeviceLinkError: result.CrossDeviceLinkError,
+				}, nil
 			} else {
 				return plessmodels.ConsumeCodePOSTResponse{
 					GeneralError: result.GeneralError,
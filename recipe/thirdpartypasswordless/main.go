@@ -20,6 +20,7 @@ import (
 
 	"github.com/supertokens/supertokens-golang/recipe/emailverification/evmodels"
 	"github.com/supertokens/supertokens-golang/recipe/passwordless/plessmodels"
+	"github.com/supertokens/supertokens-golang/recipe/thirdparty/api"
 	"github.com/supertokens/supertokens-golang/recipe/thirdparty/tpmodels"
 	"github.com/supertokens/supertokens-golang/recipe/thirdpartypasswordless/tplmodels"
 	"github.com/supertokens/supertokens-golang/supertokens"
@@ -124,6 +125,21 @@ func UnverifyEmail(userID string, userContext supertokens.UserContext) (evmodels
 	return (*instance.EmailVerificationRecipe.RecipeImpl.UnverifyEmail)(userID, email, userContext)
 }
 
+func GetProviderAccessToken(userID string, thirdPartyID string, userContext supertokens.UserContext) (*string, error) {
+	instance, err := getRecipeInstanceOrThrowError()
+	if err != nil {
+		return nil, err
+	}
+	if instance.thirdPartyRecipe == nil {
+		return nil, errors.New("please configure at least one third party provider to use GetProviderAccessToken")
+	}
+	providers, err := instance.thirdPartyRecipe.GetProviders(nil, userContext)
+	if err != nil {
+		return nil, err
+	}
+	return api.GetProviderAccessToken(providers, instance.thirdPartyRecipe.Config.SignInAndUpFeature.TokenVault, userID, thirdPartyID, userContext)
+}
+
 func CreateCodeWithEmail(email string, userInputCode *string, userContext supertokens.UserContext) (plessmodels.CreateCodeResponse, error) {
 	instance, err := getRecipeInstanceOrThrowError()
 	if err != nil {
@@ -64,7 +64,10 @@ func MakeRecipe(recipeId string, appInfo supertokens.NormalisedAppinfo, config t
 			return Recipe{}, err
 		}
 
-		r.RecipeImpl = verifiedConfig.Override.Functions(recipeimplementation.MakeRecipeImplementation(*passwordlessquerierInstance, thirdpartyquerierInstance))
+		isEmailVerified := func(userID string, email string, userContext supertokens.UserContext) (bool, error) {
+			return (*r.EmailVerificationRecipe.RecipeImpl.IsEmailVerified)(userID, email, userContext)
+		}
+		r.RecipeImpl = verifiedConfig.Override.Functions(recipeimplementation.MakeAccountLinkingRecipeImplementation(recipeimplementation.MakeRecipeImplementation(*passwordlessquerierInstance, thirdpartyquerierInstance), isEmailVerified))
 	}
 	r.APIImpl = verifiedConfig.Override.APIs(api.MakeAPIImplementation())
 
@@ -137,8 +140,17 @@ func MakeRecipe(recipeId string, appInfo supertokens.NormalisedAppinfo, config t
 			ContactMethodEmail:        verifiedConfig.ContactMethodEmail,
 			ContactMethodEmailOrPhone: verifiedConfig.ContactMethodEmailOrPhone,
 			FlowType:                  verifiedConfig.FlowType,
+			GetFlowType:               verifiedConfig.GetFlowType,
+			AllowedFlowTypes:          verifiedConfig.AllowedFlowTypes,
+			FlowTypeStore:             verifiedConfig.FlowTypeStore,
 			GetLinkDomainAndPath:      verifiedConfig.GetLinkDomainAndPath,
 			GetCustomUserInputCode:    verifiedConfig.GetCustomUserInputCode,
+			RateLimit:                 verifiedConfig.RateLimit,
+			CrossDeviceProtection:     verifiedConfig.CrossDeviceProtection,
+			PhoneNumberPolicy:         verifiedConfig.PhoneNumberPolicy,
+			ShouldAllowSignUp:         verifiedConfig.ShouldAllowSignUp,
+			RevealSignUpNotAllowed:    verifiedConfig.RevealSignUpNotAllowed,
+			SecondFactor:              verifiedConfig.SecondFactor,
 			Override: &plessmodels.OverrideStruct{
 				Functions: func(originalImplementation plessmodels.RecipeInterface) plessmodels.RecipeInterface {
 					return recipeimplementation.MakePasswordlessRecipeImplementation(r.RecipeImpl)
@@ -156,12 +168,20 @@ func MakeRecipe(recipeId string, appInfo supertokens.NormalisedAppinfo, config t
 	} else {
 		r.passwordlessRecipe = passwordlessInstance
 	}
+	// the default region is taken from the passwordless recipe since it validates the phone number policy
+	r.RecipeImpl = recipeimplementation.MakePhoneNumberNormalisingRecipeImplementation(r.RecipeImpl, r.passwordlessRecipe.Config.PhoneNumberPolicy.DefaultRegion)
 
-	if len(verifiedConfig.Providers) > 0 {
+	if len(verifiedConfig.Providers) > 0 || verifiedConfig.GetProviders != nil {
 		if thirdPartyInstance == nil {
 			thirdPartyConfig := &tpmodels.TypeInput{
 				SignInAndUpFeature: tpmodels.TypeInputSignInAndUp{
-					Providers: verifiedConfig.Providers,
+					Providers:             verifiedConfig.Providers,
+					GetProviders:          verifiedConfig.GetProviders,
+					StateAndPKCE:          verifiedConfig.StateAndPKCE,
+					TokenVault:            verifiedConfig.TokenVault,
+					EmailPolicy:           verifiedConfig.EmailPolicy,
+					ProfileClaimMapping:   verifiedConfig.ProfileClaimMapping,
+					GetAccessTokenPayload: verifiedConfig.GetAccessTokenPayload,
 				},
 				Override: &tpmodels.OverrideStruct{
 					Functions: func(_ tpmodels.RecipeInterface) tpmodels.RecipeInterface {
@@ -257,18 +277,18 @@ func (r *Recipe) getAllCORSHeaders() []string {
 	return corsHeaders
 }
 
-func (r *Recipe) handleError(err error, req *http.Request, res http.ResponseWriter) (bool, error) {
-	handleError, err := r.passwordlessRecipe.RecipeModule.HandleError(err, req, res)
+func (r *Recipe) handleError(originalError error, req *http.Request, res http.ResponseWriter) (bool, error) {
+	handleError, err := r.passwordlessRecipe.RecipeModule.HandleError(originalError, req, res)
 	if err != nil || handleError {
 		return handleError, err
 	}
 	if r.thirdPartyRecipe != nil {
-		handleError, err = r.thirdPartyRecipe.RecipeModule.HandleError(err, req, res)
+		handleError, err = r.thirdPartyRecipe.RecipeModule.HandleError(originalError, req, res)
 		if err != nil || handleError {
 			return handleError, err
 		}
 	}
-	return r.EmailVerificationRecipe.RecipeModule.HandleError(err, req, res)
+	return r.EmailVerificationRecipe.RecipeModule.HandleError(originalError, req, res)
 }
 
 func (r *Recipe) getEmailForUserIdForEmailVerification(userID string, userContext supertokens.UserContext) (string, error) {
@@ -288,6 +308,17 @@ func (r *Recipe) getEmailForUserIdForEmailVerification(userID string, userContex
 	return *userInfo.Email, nil
 }
 
+func (r *Recipe) getTimeJoinedForUserId(userID string, userContext supertokens.UserContext) (*uint64, error) {
+	userInfo, err := (*r.RecipeImpl.GetUserByID)(userID, userContext)
+	if err != nil {
+		return nil, err
+	}
+	if userInfo == nil {
+		return nil, nil
+	}
+	return &userInfo.TimeJoined, nil
+}
+
 func ResetForTest() {
 	singletonInstance = nil
 }
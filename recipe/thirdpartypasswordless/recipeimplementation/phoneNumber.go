@@ -0,0 +1,62 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package recipeimplementation
+
+import (
+	plessapi "github.com/supertokens/supertokens-golang/recipe/passwordless/api"
+	"github.com/supertokens/supertokens-golang/recipe/passwordless/plessmodels"
+	"github.com/supertokens/supertokens-golang/recipe/thirdpartypasswordless/tplmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// MakePhoneNumberNormalisingRecipeImplementation formats the phone numbers passed to the passwordless
+// functions in the E.164 format, like the passwordless recipe does.
+func MakePhoneNumberNormalisingRecipeImplementation(originalImplementation tplmodels.RecipeInterface, defaultRegion string) tplmodels.RecipeInterface {
+	normalise := func(phoneNumber *string) *string {
+		if phoneNumber == nil {
+			return nil
+		}
+		normalisedPhoneNumber := plessapi.NormalisePhoneNumber(*phoneNumber, defaultRegion)
+		return &normalisedPhoneNumber
+	}
+
+	ogCreateCode := *originalImplementation.CreateCode
+	(*originalImplementation.CreateCode) = func(email *string, phoneNumber *string, userInputCode *string, userContext supertokens.UserContext) (plessmodels.CreateCodeResponse, error) {
+		return ogCreateCode(email, normalise(phoneNumber), userInputCode, userContext)
+	}
+
+	ogGetUserByPhoneNumber := *originalImplementation.GetUserByPhoneNumber
+	(*originalImplementation.GetUserByPhoneNumber) = func(phoneNumber string, userContext supertokens.UserContext) (*tplmodels.User, error) {
+		return ogGetUserByPhoneNumber(*normalise(&phoneNumber), userContext)
+	}
+
+	ogUpdatePasswordlessUser := *originalImplementation.UpdatePasswordlessUser
+	(*originalImplementation.UpdatePasswordlessUser) = func(userID string, email *string, phoneNumber *string, userContext supertokens.UserContext) (plessmodels.UpdateUserResponse, error) {
+		return ogUpdatePasswordlessUser(userID, email, normalise(phoneNumber), userContext)
+	}
+
+	ogRevokeAllCodes := *originalImplementation.RevokeAllCodes
+	(*originalImplementation.RevokeAllCodes) = func(email *string, phoneNumber *string, userContext supertokens.UserContext) error {
+		return ogRevokeAllCodes(email, normalise(phoneNumber), userContext)
+	}
+
+	ogListCodesByPhoneNumber := *originalImplementation.ListCodesByPhoneNumber
+	(*originalImplementation.ListCodesByPhoneNumber) = func(phoneNumber string, userContext supertokens.UserContext) ([]plessmodels.DeviceType, error) {
+		return ogListCodesByPhoneNumber(*normalise(&phoneNumber), userContext)
+	}
+
+	return originalImplementation
+}
@@ -55,18 +55,24 @@ func MakeThirdPartyRecipeImplementation(recipeImplementation tplmodels.RecipeInt
 			}, nil
 		}
 
+		user := tpmodels.User{
+			ID:         result.OK.User.ID,
+			TimeJoined: result.OK.User.TimeJoined,
+		}
+		// the user is a passwordless user if the third party user is linked to one
+		if result.OK.User.Email != nil {
+			user.Email = *result.OK.User.Email
+		}
+		if result.OK.User.ThirdParty != nil {
+			user.ThirdParty = *result.OK.User.ThirdParty
+		}
 		return tpmodels.SignInUpResponse{
 			OK: &struct {
 				CreatedNewUser bool
 				User           tpmodels.User
 			}{
 				CreatedNewUser: result.OK.CreatedNewUser,
-				User: tpmodels.User{
-					ID:         result.OK.User.ID,
-					Email:      *result.OK.User.Email,
-					TimeJoined: result.OK.User.TimeJoined,
-					ThirdParty: *result.OK.User.ThirdParty,
-				},
+				User:           user,
 			},
 		}, nil
 	}
@@ -0,0 +1,235 @@
+/*
+ * Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package thirdpartypasswordless
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/accountlinking"
+	"github.com/supertokens/supertokens-golang/recipe/accountlinking/almodels"
+	"github.com/supertokens/supertokens-golang/recipe/passwordless/plessmodels"
+	"github.com/supertokens/supertokens-golang/recipe/session"
+	"github.com/supertokens/supertokens-golang/recipe/thirdpartypasswordless/tplmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+	"github.com/supertokens/supertokens-golang/test/unittesting"
+)
+
+func makeAccountLinkingStoreForTest() almodels.AccountLinkingStore {
+	var lock sync.Mutex
+	primaryUserIDs := map[string]string{}
+	return almodels.AccountLinkingStore{
+		GetPrimaryUserID: func(recipeUserID string, userContext supertokens.UserContext) (*string, error) {
+			lock.Lock()
+			defer lock.Unlock()
+			primaryUserID, ok := primaryUserIDs[recipeUserID]
+			if !ok {
+				return nil, nil
+			}
+			return &primaryUserID, nil
+		},
+		GetRecipeUserIDs: func(primaryUserID string, userContext supertokens.UserContext) ([]string, error) {
+			lock.Lock()
+			defer lock.Unlock()
+			recipeUserIDs := []string{}
+			for recipeUserID, linkedPrimaryUserID := range primaryUserIDs {
+				if linkedPrimaryUserID == primaryUserID {
+					recipeUserIDs = append(recipeUserIDs, recipeUserID)
+				}
+			}
+			return recipeUserIDs, nil
+		},
+		Link: func(primaryUserID string, recipeUserID string, userContext supertokens.UserContext) error {
+			lock.Lock()
+			defer lock.Unlock()
+			primaryUserIDs[recipeUserID] = primaryUserID
+			return nil
+		},
+		Unlink: func(recipeUserID string, userContext supertokens.UserContext) error {
+			lock.Lock()
+			defer lock.Unlock()
+			delete(primaryUserIDs, recipeUserID)
+			return nil
+		},
+	}
+}
+
+func makeSecondFactorCodeStoreForTest() plessmodels.SecondFactorCodeStore {
+	var lock sync.Mutex
+	codes := map[string]plessmodels.SecondFactorCodeInfo{}
+	return plessmodels.SecondFactorCodeStore{
+		Save: func(codeInfo plessmodels.SecondFactorCodeInfo, userContext supertokens.UserContext) error {
+			lock.Lock()
+			defer lock.Unlock()
+			codes[codeInfo.SessionHandle] = codeInfo
+			return nil
+		},
+		IncrementAttemptCount: func(sessionHandle string, userContext supertokens.UserContext) (*plessmodels.SecondFactorCodeInfo, error) {
+			lock.Lock()
+			defer lock.Unlock()
+			codeInfo, ok := codes[sessionHandle]
+			if !ok {
+				return nil, nil
+			}
+			codeInfo.FailedCodeInputAttemptCount++
+			codes[sessionHandle] = codeInfo
+			return &codeInfo, nil
+		},
+		Remove: func(sessionHandle string, userContext supertokens.UserContext) error {
+			lock.Lock()
+			defer lock.Unlock()
+			delete(codes, sessionHandle)
+			return nil
+		},
+	}
+}
+
+func postForTest(t *testing.T, client *http.Client, url string, body map[string]interface{}) map[string]interface{} {
+	bodyBytes, err := json.Marshal(body)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(bodyBytes))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer resp.Body.Close()
+	dataInBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	var data map[string]interface{}
+	err = json.Unmarshal(dataInBytes, &data)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	return data
+}
+
+func TestSecondFactorLeavesNoUserOrLinkBehind(t *testing.T) {
+	accountLinkingStore := makeAccountLinkingStoreForTest()
+	codeStore := makeSecondFactorCodeStoreForTest()
+	var sentUserInputCode string
+	configValue := supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: "http://localhost:8080",
+		},
+		AppInfo: supertokens.AppInfo{
+			APIDomain:     "api.supertokens.io",
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{
+			session.Init(nil),
+			Init(tplmodels.TypeInput{
+				FlowType: plessmodels.FlowTypeUserInputCode,
+				ContactMethodEmail: plessmodels.NewContactMethodEmailConfig(func(email string, userInputCode *string, urlWithLinkCode *string, codeLifetime uint64, preAuthSessionId string, userContext supertokens.UserContext) error {
+					sentUserInputCode = *userInputCode
+					return nil
+				}),
+				SecondFactor: &plessmodels.TypeInputSecondFactor{
+					GetContactInfo: func(userID string, userContext supertokens.UserContext) (*string, *string, error) {
+						email := "johndoe@gmail.com"
+						return &email, nil, nil
+					},
+					CodeStore: &codeStore,
+				},
+			}),
+			accountlinking.Init(&almodels.TypeInput{
+				ShouldDoAutomaticAccountLinking: func(newAccount almodels.AccountInfo, primaryUserID string, userContext supertokens.UserContext) (bool, error) {
+					return true, nil
+				},
+				Store: &accountLinkingStore,
+			}),
+		},
+	}
+
+	BeforeEach()
+	unittesting.StartUpST("localhost", "8080")
+	defer AfterEach()
+	err := supertokens.Init(configValue)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	// a verified third party user, that a passwordless user with the same email would be linked to
+	signInUpResponse, err := ThirdPartySignInUp("google", "google-user", tplmodels.EmailStruct{
+		ID:         "johndoe@gmail.com",
+		IsVerified: true,
+	}, &map[string]interface{}{})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	user := signInUpResponse.OK.User
+	tokenResponse, err := CreateEmailVerificationToken(user.ID, &map[string]interface{}{})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if tokenResponse.OK != nil {
+		_, err = VerifyEmailUsingToken(tokenResponse.OK.Token, &map[string]interface{}{})
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login", func(rw http.ResponseWriter, r *http.Request) {
+		_, err := session.CreateNewSession(rw, user.ID, nil, nil)
+		if err != nil {
+			rw.WriteHeader(500)
+		}
+	})
+	testServer := httptest.NewServer(supertokens.Middleware(mux))
+	defer testServer.Close()
+
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	device := &http.Client{Jar: jar}
+	_, err = device.Post(testServer.URL+"/login", "application/json", nil)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	data := postForTest(t, device, testServer.URL+"/auth/secondfactor/code", map[string]interface{}{})
+	assert.Equal(t, "OK", data["status"])
+	data = postForTest(t, device, testServer.URL+"/auth/secondfactor/code/consume", map[string]interface{}{
+		"userInputCode": sentUserInputCode,
+	})
+	assert.Equal(t, "OK", data["status"])
+
+	users, err := GetUsersByEmail("johndoe@gmail.com", &map[string]interface{}{})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	assert.Equal(t, 1, len(users))
+	assert.Equal(t, user.ID, users[0].ID)
+
+	linkedUserIDs, err := accountlinking.GetLinkedUserIDs(user.ID)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	assert.Equal(t, []string{user.ID}, linkedUserIDs)
+}
@@ -26,8 +26,11 @@ type APIInterface struct {
 	AuthorisationUrlGET *func(provider tpmodels.TypeProvider, options tpmodels.APIOptions, userContext supertokens.UserContext) (tpmodels.AuthorisationUrlGETResponse, error)
 
 	AppleRedirectHandlerPOST *func(code string, state string, options tpmodels.APIOptions, userContext supertokens.UserContext) error
+	SAMLLoginGET             *func(provider tpmodels.TypeProvider, relayState string, options tpmodels.APIOptions, userContext supertokens.UserContext) (tpmodels.SAMLLoginGETResponse, error)
+	SAMLACSPOST              *func(provider tpmodels.TypeProvider, samlResponse string, relayState string, options tpmodels.APIOptions, userContext supertokens.UserContext) error
 
-	ThirdPartySignInUpPOST *func(provider tpmodels.TypeProvider, code string, authCodeResponse interface{}, redirectURI string, options tpmodels.APIOptions, userContext supertokens.UserContext) (ThirdPartySignInUpOutput, error)
+	ThirdPartySignInUpPOST       *func(provider tpmodels.TypeProvider, code string, authCodeResponse interface{}, redirectURI string, options tpmodels.APIOptions, userContext supertokens.UserContext) (ThirdPartySignInUpOutput, error)
+	ThirdPartyNativeSignInUpPOST *func(provider tpmodels.TypeProvider, tokens tpmodels.NativeTokens, options tpmodels.APIOptions, userContext supertokens.UserContext) (ThirdPartySignInUpOutput, error)
 
 	CreateCodePOST *func(email *string, phoneNumber *string, options plessmodels.APIOptions, userContext supertokens.UserContext) (plessmodels.CreateCodePOSTResponse, error)
 
@@ -38,6 +41,12 @@ type APIInterface struct {
 	PasswordlessEmailExistsGET *func(email string, options plessmodels.APIOptions, userContext supertokens.UserContext) (plessmodels.EmailExistsGETResponse, error)
 
 	PasswordlessPhoneNumberExistsGET *func(email string, options plessmodels.APIOptions, userContext supertokens.UserContext) (plessmodels.PhoneNumberExistsGETResponse, error)
+
+	ConfirmCrossDeviceSignInPOST *func(preAuthSessionID string, options plessmodels.APIOptions, userContext supertokens.UserContext) (plessmodels.ConfirmCrossDeviceSignInPOSTResponse, error)
+
+	CreateSecondFactorCodePOST *func(options plessmodels.APIOptions, userContext supertokens.UserContext) (plessmodels.CreateSecondFactorCodePOSTResponse, error)
+
+	ConsumeSecondFactorCodePOST *func(userInputCode string, options plessmodels.APIOptions, userContext supertokens.UserContext) (plessmodels.ConsumeSecondFactorCodePOSTResponse, error)
 }
 
 type ConsumeCodePOSTResponse struct {
@@ -54,19 +63,25 @@ type ConsumeCodePOSTResponse struct {
 		FailedCodeInputAttemptCount int
 		MaximumCodeInputAttempts    int
 	}
-	RestartFlowError *struct{}
-	GeneralError     *struct {
+	RestartFlowError     *struct{}
+	CrossDeviceLinkError *struct {
+		ConfirmationRequired bool
+	}
+	GeneralError *struct {
 		Message string
 	}
 }
 
 type ThirdPartySignInUpOutput struct {
 	OK *struct {
-		CreatedNewUser   bool
-		User             User
-		AuthCodeResponse interface{}
-		Session          sessmodels.SessionContainer
+		CreatedNewUser          bool
+		User                    User
+		AuthCodeResponse        interface{}
+		Session                 sessmodels.SessionContainer
+		Profile                 tpmodels.Profile
+		RawUserInfoFromProvider tpmodels.RawUserInfoFromProvider
 	}
 	NoEmailGivenByProviderError *struct{}
 	FieldError                  *struct{ ErrorMsg string }
+	InvalidStateError           *struct{}
 }
@@ -16,6 +16,8 @@
 package tplmodels
 
 import (
+	"net/http"
+
 	"github.com/supertokens/supertokens-golang/recipe/emailverification/evmodels"
 	"github.com/supertokens/supertokens-golang/recipe/passwordless/plessmodels"
 	"github.com/supertokens/supertokens-golang/recipe/thirdparty/tpmodels"
@@ -36,16 +38,46 @@ type User struct {
 type TypeInputEmailVerificationFeature struct {
 	GetEmailVerificationURL  func(user User, userContext supertokens.UserContext) (string, error)
 	CreateAndSendCustomEmail func(user User, emailVerificationURLWithToken string, userContext supertokens.UserContext)
+	// ResendCooldown is the minimum time in milliseconds between two verification emails sent to a user.
+	// It requires a ResendCooldownStore.
+	ResendCooldown      *uint64
+	ResendCooldownStore *evmodels.ResendCooldownStore
+	// GracePeriodAfterSignUp is the time in milliseconds after sign up after which VerifySession
+	// rejects sessions of users that have not verified their email. GetSession does not check it.
+	GracePeriodAfterSignUp *uint64
+	// Mode is either "LINK" (the default) or "CODE". In the "CODE" mode, a numeric code is sent using
+	// CreateAndSendEmailWithCode instead of a link.
+	Mode                       string
+	CreateAndSendEmailWithCode func(user User, code string, codeLifetime uint64, userContext supertokens.UserContext)
+	CodeLifetime               *uint64
+	MaximumCodeInputAttempts   *int
+	// CodeStore is required in the "CODE" mode
+	CodeStore *evmodels.EmailVerificationCodeStore
 }
 
 type TypeInput struct {
 	ContactMethodPhone        plessmodels.ContactMethodPhoneConfig
 	ContactMethodEmail        plessmodels.ContactMethodEmailConfig
 	ContactMethodEmailOrPhone plessmodels.ContactMethodEmailOrPhoneConfig
-	FlowType                  string
+	FlowType                  plessmodels.FlowType
+	GetFlowType               func(req *http.Request, contactMethod string, userContext supertokens.UserContext) (plessmodels.FlowType, error)
+	AllowedFlowTypes          []plessmodels.FlowType
+	FlowTypeStore             *plessmodels.FlowTypeStore
 	GetLinkDomainAndPath      func(email *string, phoneNumber *string, userContext supertokens.UserContext) (string, error)
 	GetCustomUserInputCode    func(userContext supertokens.UserContext) (string, error)
+	RateLimit                 *plessmodels.TypeInputRateLimit
+	CrossDeviceProtection     *plessmodels.TypeInputCrossDeviceProtection
+	PhoneNumberPolicy         *plessmodels.TypeInputPhoneNumberPolicy
+	ShouldAllowSignUp         func(email *string, phoneNumber *string, userContext supertokens.UserContext) (bool, error)
+	RevealSignUpNotAllowed    bool
+	SecondFactor              *plessmodels.TypeInputSecondFactor
 	Providers                 []tpmodels.TypeProvider
+	GetProviders              func(req *http.Request, userContext supertokens.UserContext) ([]tpmodels.TypeProvider, error)
+	StateAndPKCE              *tpmodels.TypeInputStateAndPKCE
+	TokenVault                *tpmodels.TypeInputTokenVault
+	EmailPolicy               *tpmodels.TypeInputEmailPolicy
+	ProfileClaimMapping       *tpmodels.ProfileClaimMapping
+	GetAccessTokenPayload     func(user tpmodels.User, profile tpmodels.Profile, rawUserInfo tpmodels.RawUserInfoFromProvider, userContext supertokens.UserContext) (map[string]interface{}, error)
 	EmailVerificationFeature  *TypeInputEmailVerificationFeature
 	Override                  *OverrideStruct
 }
@@ -54,10 +86,25 @@ type TypeNormalisedInput struct {
 	ContactMethodPhone        plessmodels.ContactMethodPhoneConfig
 	ContactMethodEmail        plessmodels.ContactMethodEmailConfig
 	ContactMethodEmailOrPhone plessmodels.ContactMethodEmailOrPhoneConfig
-	FlowType                  string
+	FlowType                  plessmodels.FlowType
+	GetFlowType               func(req *http.Request, contactMethod string, userContext supertokens.UserContext) (plessmodels.FlowType, error)
+	AllowedFlowTypes          []plessmodels.FlowType
+	FlowTypeStore             *plessmodels.FlowTypeStore
 	GetLinkDomainAndPath      func(email *string, phoneNumber *string, userContext supertokens.UserContext) (string, error)
 	GetCustomUserInputCode    func(userContext supertokens.UserContext) (string, error)
+	RateLimit                 *plessmodels.TypeInputRateLimit
+	CrossDeviceProtection     *plessmodels.TypeInputCrossDeviceProtection
+	PhoneNumberPolicy         *plessmodels.TypeInputPhoneNumberPolicy
+	ShouldAllowSignUp         func(email *string, phoneNumber *string, userContext supertokens.UserContext) (bool, error)
+	RevealSignUpNotAllowed    bool
+	SecondFactor              *plessmodels.TypeInputSecondFactor
 	Providers                 []tpmodels.TypeProvider
+	GetProviders              func(req *http.Request, userContext supertokens.UserContext) ([]tpmodels.TypeProvider, error)
+	StateAndPKCE              *tpmodels.TypeInputStateAndPKCE
+	TokenVault                *tpmodels.TypeInputTokenVault
+	EmailPolicy               *tpmodels.TypeInputEmailPolicy
+	ProfileClaimMapping       *tpmodels.ProfileClaimMapping
+	GetAccessTokenPayload     func(user tpmodels.User, profile tpmodels.Profile, rawUserInfo tpmodels.RawUserInfoFromProvider, userContext supertokens.UserContext) (map[string]interface{}, error)
 	EmailVerificationFeature  evmodels.TypeInput
 	Override                  OverrideStruct
 }
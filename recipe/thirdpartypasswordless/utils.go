@@ -46,12 +46,27 @@ func validateAndNormaliseUserInput(recipeInstance *Recipe, appInfo supertokens.N
 func makeTypeNormalisedInput(recipeInstance *Recipe, inputConfig tplmodels.TypeInput) tplmodels.TypeNormalisedInput {
 	return tplmodels.TypeNormalisedInput{
 		Providers:                 inputConfig.Providers,
+		GetProviders:              inputConfig.GetProviders,
+		StateAndPKCE:              inputConfig.StateAndPKCE,
+		TokenVault:                inputConfig.TokenVault,
+		EmailPolicy:               inputConfig.EmailPolicy,
+		ProfileClaimMapping:       inputConfig.ProfileClaimMapping,
+		GetAccessTokenPayload:     inputConfig.GetAccessTokenPayload,
 		ContactMethodPhone:        inputConfig.ContactMethodPhone,
 		ContactMethodEmail:        inputConfig.ContactMethodEmail,
 		ContactMethodEmailOrPhone: inputConfig.ContactMethodEmailOrPhone,
 		FlowType:                  inputConfig.FlowType,
+		GetFlowType:               inputConfig.GetFlowType,
+		AllowedFlowTypes:          inputConfig.AllowedFlowTypes,
+		FlowTypeStore:             inputConfig.FlowTypeStore,
 		GetLinkDomainAndPath:      inputConfig.GetLinkDomainAndPath,
 		GetCustomUserInputCode:    inputConfig.GetCustomUserInputCode,
+		RateLimit:                 inputConfig.RateLimit,
+		CrossDeviceProtection:     inputConfig.CrossDeviceProtection,
+		PhoneNumberPolicy:         inputConfig.PhoneNumberPolicy,
+		ShouldAllowSignUp:         inputConfig.ShouldAllowSignUp,
+		RevealSignUpNotAllowed:    inputConfig.RevealSignUpNotAllowed,
+		SecondFactor:              inputConfig.SecondFactor,
 		EmailVerificationFeature:  validateAndNormaliseEmailVerificationConfig(recipeInstance, inputConfig),
 		Override: tplmodels.OverrideStruct{
 			Functions: func(originalImplementation tplmodels.RecipeInterface) tplmodels.RecipeInterface {
@@ -67,8 +82,9 @@ func makeTypeNormalisedInput(recipeInstance *Recipe, inputConfig tplmodels.TypeI
 
 func validateAndNormaliseEmailVerificationConfig(recipeInstance *Recipe, config tplmodels.TypeInput) evmodels.TypeInput {
 	emailverificationTypeInput := evmodels.TypeInput{
-		GetEmailForUserID: recipeInstance.getEmailForUserIdForEmailVerification,
-		Override:          nil,
+		GetEmailForUserID:      recipeInstance.getEmailForUserIdForEmailVerification,
+		GetTimeJoinedForUserID: recipeInstance.getTimeJoinedForUserId,
+		Override:               nil,
 	}
 
 	if config.Override != nil {
@@ -76,6 +92,27 @@ func validateAndNormaliseEmailVerificationConfig(recipeInstance *Recipe, config
 	}
 
 	if config.EmailVerificationFeature != nil {
+		emailverificationTypeInput.ResendCooldown = config.EmailVerificationFeature.ResendCooldown
+		emailverificationTypeInput.ResendCooldownStore = config.EmailVerificationFeature.ResendCooldownStore
+		emailverificationTypeInput.GracePeriodAfterSignUp = config.EmailVerificationFeature.GracePeriodAfterSignUp
+		emailverificationTypeInput.Mode = config.EmailVerificationFeature.Mode
+		emailverificationTypeInput.CodeLifetime = config.EmailVerificationFeature.CodeLifetime
+		emailverificationTypeInput.MaximumCodeInputAttempts = config.EmailVerificationFeature.MaximumCodeInputAttempts
+		emailverificationTypeInput.CodeStore = config.EmailVerificationFeature.CodeStore
+
+		if config.EmailVerificationFeature.CreateAndSendEmailWithCode != nil {
+			emailverificationTypeInput.CreateAndSendEmailWithCode = func(user evmodels.User, code string, codeLifetime uint64, userContext supertokens.UserContext) {
+				userInfo, err := (*recipeInstance.RecipeImpl.GetUserByID)(user.ID, userContext)
+				if err != nil {
+					return
+				}
+				if userInfo == nil {
+					return
+				}
+				config.EmailVerificationFeature.CreateAndSendEmailWithCode(*userInfo, code, codeLifetime, userContext)
+			}
+		}
+
 		if config.EmailVerificationFeature.CreateAndSendCustomEmail != nil {
 			emailverificationTypeInput.CreateAndSendCustomEmail = func(user evmodels.User, link string, userContext supertokens.UserContext) {
 				userInfo, err := (*recipeInstance.RecipeImpl.GetUserByID)(user.ID, userContext)
@@ -23,3 +23,13 @@ type BadInputError struct {
 func (err BadInputError) Error() string {
 	return err.Msg
 }
+
+// CoreError is returned by the querier when the core responds with a status code other than 200
+type CoreError struct {
+	StatusCode int
+	Msg        string
+}
+
+func (err CoreError) Error() string {
+	return err.Msg
+}
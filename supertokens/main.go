@@ -62,3 +62,10 @@ func GetUsersNewestFirst(paginationToken *string, limit *int, includeRecipeIds *
 func DeleteUser(userId string) error {
 	return deleteUser(userId)
 }
+
+// AddBeforeUserDeletedHook registers a function that is called by DeleteUser before the user is
+// removed from the core, for recipes that keep data about users outside of the core. If it returns
+// an error, the user is not deleted. Adding a hook again for the same recipeID replaces the previous one.
+func AddBeforeUserDeletedHook(recipeID string, hook func(userID string) error) {
+	beforeUserDeletedHooks[recipeID] = hook
+}
@@ -36,6 +36,8 @@ var IsTestFlag = false
 
 var superTokensInstance *superTokens
 
+var beforeUserDeletedHooks = map[string]func(userID string) error{}
+
 func supertokensInit(config TypeInput) error {
 	if superTokensInstance != nil {
 		return nil
@@ -390,6 +392,13 @@ func deleteUser(userId string) error {
 	}
 
 	if maxVersion(cdiVersion, "2.10") == cdiVersion {
+		for _, hook := range beforeUserDeletedHooks {
+			err = hook(userId)
+			if err != nil {
+				return err
+			}
+		}
+
 		_, err = querier.SendPostRequest("/user/remove", map[string]interface{}{
 			"userId": userId,
 		})
@@ -407,6 +416,7 @@ func deleteUser(userId string) error {
 func ResetForTest() {
 	ResetQuerierForTest()
 	superTokensInstance = nil
+	beforeUserDeletedHooks = map[string]func(userID string) error{}
 }
 
 func IsRunningInTestMode() bool {
@@ -91,7 +91,7 @@ func callSTInit(passwordlessConfig *plessmodels.TypeInput) {
 				Enabled:                        true,
 				CreateAndSendCustomTextMessage: saveCode,
 			},
-			FlowType: "USER_INPUT_CODE_AND_MAGIC_LINK",
+			FlowType: plessmodels.FlowTypeUserInputCodeAndMagicLink,
 		}
 	}
 
@@ -404,7 +404,7 @@ func reInitST(w http.ResponseWriter, r *http.Request) {
 	var readBody map[string]interface{}
 	json.Unmarshal(body, &readBody)
 	config := &plessmodels.TypeInput{
-		FlowType: readBody["flowType"].(string),
+		FlowType: plessmodels.FlowType(readBody["flowType"].(string)),
 	}
 	if readBody["contactMethod"].(string) == "PHONE" {
 		config.ContactMethodPhone = plessmodels.ContactMethodPhoneConfig{